@@ -0,0 +1,246 @@
+// Command proxmox-token-helper bootstraps the credentials of the provider. It
+// logs in to the Proxmox VE API interactively, answering the TOTP challenge of
+// users with two-factor authentication, creates an API token of the user and
+// prints it as shell exports the provider reads:
+//
+//	eval "$(proxmox-token-helper -host https://pve.example.com:8006 -username terraform@pve)"
+//
+// The password is read from PROXMOX_PASSWORD or prompted for, the TOTP code
+// from PROXMOX_OTP or prompted for when the login asks for it.
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stdin reads the prompted secrets, buffered once so that secrets piped in
+// on consecutive lines are not lost between prompts.
+var stdin = bufio.NewReader(os.Stdin)
+
+// ticketResponse is the response of the access ticket endpoint.
+type ticketResponse struct {
+	Data struct {
+		Ticket              string `json:"ticket"`
+		CSRFPreventionToken string `json:"CSRFPreventionToken"`
+		NeedTFA             int    `json:"NeedTFA"`
+	} `json:"data"`
+}
+
+// tokenResponse is the response of the token creation endpoint.
+type tokenResponse struct {
+	Data struct {
+		FullTokenID string `json:"full-tokenid"`
+		Value       string `json:"value"`
+	} `json:"data"`
+}
+
+// tokenOptions are the settings of the created token.
+type tokenOptions struct {
+	name    string
+	comment string
+	privsep bool
+	expire  time.Time
+}
+
+// session is a logged in session of the API.
+type session struct {
+	client *http.Client
+	host   string
+	ticket string
+	csrf   string
+}
+
+// post posts form to path of the API and decodes the response into v.
+func (s *session) post(ctx context.Context, path string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.host+"/api2/json"+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if s.ticket != "" {
+		req.AddCookie(&http.Cookie{Name: "PVEAuthCookie", Value: s.ticket})
+		req.Header.Set("CSRFPreventionToken", s.csrf)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// login logs in as username and returns the session. otp is only called for
+// users with two-factor authentication and returns their current TOTP code.
+func login(ctx context.Context, client *http.Client, host, username, password string, otp func() (string, error)) (*session, error) {
+	s := &session{client: client, host: strings.TrimSuffix(host, "/")}
+
+	var ticket ticketResponse
+	if err := s.post(ctx, "/access/ticket", url.Values{"username": {username}, "password": {password}}, &ticket); err != nil {
+		return nil, fmt.Errorf("logging in: %w", err)
+	}
+
+	if ticket.Data.NeedTFA == 1 {
+		code, err := otp()
+		if err != nil {
+			return nil, err
+		}
+
+		form := url.Values{
+			"username":      {username},
+			"tfa-challenge": {ticket.Data.Ticket},
+			"password":      {"totp:" + code},
+		}
+		if err := s.post(ctx, "/access/ticket", form, &ticket); err != nil {
+			return nil, fmt.Errorf("answering the TOTP challenge: %w", err)
+		}
+	}
+
+	s.ticket, s.csrf = ticket.Data.Ticket, ticket.Data.CSRFPreventionToken
+	return s, nil
+}
+
+// createToken creates an API token of the user userid and returns it in the
+// form USER@REALM!TOKENID=SECRET.
+func (s *session) createToken(ctx context.Context, userid string, opts tokenOptions) (string, error) {
+	form := url.Values{
+		"privsep": {"0"},
+		"comment": {opts.comment},
+	}
+	if opts.privsep {
+		form.Set("privsep", "1")
+	}
+	if !opts.expire.IsZero() {
+		form.Set("expire", strconv.FormatInt(opts.expire.Unix(), 10))
+	}
+
+	var token tokenResponse
+	path := fmt.Sprintf("/access/users/%s/token/%s", url.PathEscape(userid), url.PathEscape(opts.name))
+	if err := s.post(ctx, path, form, &token); err != nil {
+		return "", fmt.Errorf("creating API token %s: %w", opts.name, err)
+	}
+	if token.Data.FullTokenID == "" || token.Data.Value == "" {
+		return "", errors.New("creating API token: the response has no token")
+	}
+
+	return token.Data.FullTokenID + "=" + token.Data.Value, nil
+}
+
+// shellQuote quotes value for POSIX shells.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// readSecret prompts for a secret on stderr and reads it from stdin, without
+// echoing it when stdin is a terminal with stty.
+func readSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		stty := func(arg string) error {
+			cmd := exec.Command("stty", arg)
+			cmd.Stdin = os.Stdin
+			return cmd.Run()
+		}
+		if stty("-echo") == nil {
+			defer func() {
+				_ = stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("reading %s: %w", strings.TrimSuffix(prompt, ": "), err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func main() {
+	insecure, _ := strconv.ParseBool(os.Getenv("PROXMOX_INSECURE"))
+
+	var host, username string
+	var opts tokenOptions
+	var expire time.Duration
+	flag.StringVar(&host, "host", os.Getenv("PROXMOX_HOST"), "URI of the Proxmox VE API, e.g. https://pve.example.com:8006 (PROXMOX_HOST)")
+	flag.StringVar(&username, "username", os.Getenv("PROXMOX_USERNAME"), "user to log in as and to create the token for, e.g. terraform@pve (PROXMOX_USERNAME)")
+	flag.StringVar(&opts.name, "token-name", "terraform", "ID of the token, unique per user")
+	flag.StringVar(&opts.comment, "comment", "Terraform provider, created by proxmox-token-helper", "comment of the token")
+	flag.BoolVar(&opts.privsep, "privsep", true, "separate the privileges of the token from the user, which then need to be granted to the token")
+	flag.DurationVar(&expire, "expire", 0, "lifetime of the token, e.g. 720h, the token does not expire when 0")
+	flag.BoolVar(&insecure, "insecure", insecure, "skip the verification of the TLS certificate of the API (PROXMOX_INSECURE)")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("proxmox-token-helper: ")
+	if host == "" || username == "" {
+		log.Fatal("set -host and -username or PROXMOX_HOST and PROXMOX_USERNAME")
+	}
+	if expire > 0 {
+		opts.expire = time.Now().Add(expire)
+	}
+
+	password := os.Getenv("PROXMOX_PASSWORD")
+	if password == "" {
+		var err error
+		if password, err = readSecret(fmt.Sprintf("Password of %s: ", username)); err != nil {
+			log.Fatal(err)
+		}
+	}
+	otp := func() (string, error) {
+		if code := os.Getenv("PROXMOX_OTP"); code != "" {
+			return code, nil
+		}
+		return readSecret("TOTP code: ")
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+		},
+	}
+
+	ctx := context.Background()
+	s, err := login(ctx, client, host, username, password, otp)
+	if err != nil {
+		log.Fatal(err)
+	}
+	token, err := s.createToken(ctx, username, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if opts.privsep {
+		tokenID, _, _ := strings.Cut(token, "=")
+		fmt.Fprintf(os.Stderr, "The token has no privileges of its own yet, grant them with e.g.\n"+
+			"  pveum acl modify / --tokens '%s' --roles PVEAdmin\n", tokenID)
+	}
+	fmt.Printf("export PROXMOX_HOST=%s\n", shellQuote(host))
+	fmt.Printf("export PROXMOX_API_TOKEN=%s\n", shellQuote(token))
+	fmt.Println("unset PROXMOX_USERNAME PROXMOX_PASSWORD PROXMOX_OTP")
+}
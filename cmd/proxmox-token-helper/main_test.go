@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoginAndCreateToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/api2/json/access/ticket" && r.Form.Get("password") == "secret":
+			_, _ = w.Write([]byte(`{"data": {"ticket": "PVE:challenge", "NeedTFA": 1}}`))
+		case r.URL.Path == "/api2/json/access/ticket" && r.Form.Get("tfa-challenge") == "PVE:challenge" && r.Form.Get("password") == "totp:123456":
+			_, _ = w.Write([]byte(`{"data": {"ticket": "PVE:ticket", "CSRFPreventionToken": "csrf"}}`))
+		case r.URL.Path == "/api2/json/access/users/terraform@pve/token/ci":
+			cookie, err := r.Cookie("PVEAuthCookie")
+			if err != nil || cookie.Value != "PVE:ticket" || r.Header.Get("CSRFPreventionToken") != "csrf" || r.Form.Get("privsep") != "1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"full-tokenid": "terraform@pve!ci", "value": "8a3b1c2d"}}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	var prompted bool
+	otp := func() (string, error) {
+		prompted = true
+		return "123456", nil
+	}
+
+	s, err := login(context.Background(), server.Client(), server.URL+"/", "terraform@pve", "secret", otp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prompted {
+		t.Error("expected a TOTP prompt")
+	}
+
+	token, err := s.createToken(context.Background(), "terraform@pve", tokenOptions{name: "ci", privsep: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "terraform@pve!ci=8a3b1c2d" {
+		t.Errorf("got token %q", token)
+	}
+
+	if _, err := login(context.Background(), server.Client(), server.URL, "terraform@pve", "wrong", otp); err == nil {
+		t.Error("expected an error for a wrong password")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got, want := shellQuote("it's"), `'it'\''s'`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,15 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_apt_updates" "all" {}
+
+output "nodes_needing_reboot" {
+  value = [for node in data.proxmox_apt_updates.all.nodes : node.node if coalesce(node.kernel_update, false)]
+}
@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_cloudinit_config" "web" {
+  hostname = "web01"
+
+  users = [{
+    name                = "ops"
+    groups              = ["sudo"]
+    sudo                = "ALL=(ALL) NOPASSWD:ALL"
+    ssh_authorized_keys = ["ssh-ed25519 AAAA... ops@example.com"]
+  }]
+
+  package_update = true
+  packages       = ["qemu-guest-agent"]
+  runcmd         = ["systemctl enable --now qemu-guest-agent"]
+
+  ethernets = [{
+    name        = "eth0"
+    addresses   = ["10.0.0.10/24"]
+    nameservers = ["10.0.0.1"]
+    routes = [{
+      to  = "default"
+      via = "10.0.0.1"
+    }]
+  }]
+}
+
+output "user_data" {
+  value = data.proxmox_cloudinit_config.web.user_data
+}
@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_cluster_corosync" "this" {}
+
+output "link0_addresses" {
+  value = {
+    for node in data.proxmox_cluster_corosync.this.nodes : node.name => node.links["0"]
+  }
+}
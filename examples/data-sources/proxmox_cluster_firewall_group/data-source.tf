@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Audit a security group owned by the network team.
+data "proxmox_cluster_firewall_group" "network" {
+  group = "network-base"
+}
+
+output "network_base_open_ports" {
+  value = [
+    for rule in data.proxmox_cluster_firewall_group.network.rules :
+    rule.dport if rule.enabled && rule.action == "ACCEPT" && rule.dport != null
+  ]
+}
@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_cluster_firewall_group_usage" "webservers" {
+  group = "webservers"
+}
+
+output "webservers_in_use" {
+  value = data.proxmox_cluster_firewall_group_usage.webservers.in_use
+}
@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_current_user" "me" {}
+
+# Refuse to run as anything but the automation account.
+resource "terraform_data" "guard" {
+  lifecycle {
+    precondition {
+      condition     = data.proxmox_current_user.me.user_id == "terraform@pve" && !contains(data.proxmox_current_user.me.privileges, "Sys.Modify")
+      error_message = "Run as terraform@pve without Sys.Modify, not ${data.proxmox_current_user.me.user_id}."
+    }
+  }
+}
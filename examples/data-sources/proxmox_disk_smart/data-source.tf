@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_disk_smart" "sdb" {
+  node = "proxmox"
+  disk = "/dev/sdb"
+}
+
+resource "terraform_data" "pool" {
+  lifecycle {
+    precondition {
+      condition     = data.proxmox_disk_smart.sdb.health == "PASSED" && coalesce(data.proxmox_disk_smart.sdb.reallocated_sectors, 0) == 0
+      error_message = "/dev/sdb is failing, refusing to build storage on it."
+    }
+  }
+}
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_endpoint_health" "this" {}
+
+resource "terraform_data" "cluster" {
+  lifecycle {
+    precondition {
+      condition     = data.proxmox_endpoint_health.this.authenticated
+      error_message = "Proxmox VE API is not usable: ${data.proxmox_endpoint_health.this.error}"
+    }
+  }
+}
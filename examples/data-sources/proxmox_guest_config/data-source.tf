@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_guest_config" "web" {
+  node  = "proxmox"
+  vm_id = 100
+}
+
+output "web_net0" {
+  value = data.proxmox_guest_config.web.config["net0"]
+}
@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_node_certificates" "pve" {
+  node = "proxmox"
+}
+
+output "expiry" {
+  value = {
+    for cert in data.proxmox_node_certificates.pve.certificates : cert.filename => cert.not_after
+  }
+}
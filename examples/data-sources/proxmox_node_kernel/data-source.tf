@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_nodes" "all" {}
+
+data "proxmox_node_kernel" "this" {
+  for_each = toset([for node in data.proxmox_nodes.all.data : node.node])
+
+  node = each.value
+}
+
+output "nodes_on_old_kernel" {
+  value = [for name, kernel in data.proxmox_node_kernel.this : name if kernel.kernel_release != "6.8.12-4-pve"]
+}
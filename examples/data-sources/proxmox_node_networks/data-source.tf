@@ -15,3 +15,13 @@ data "proxmox_node_networks" "proxmox" {
 output "proxmox_node_networks" {
   value = data.proxmox_node_networks.proxmox
 }
+
+# VLAN aware bridges with the VLAN IDs they trunk, e.g. to check them in a
+# precondition of an SDN vnet.
+output "proxmox_vlan_bridges" {
+  value = {
+    for network in data.proxmox_node_networks.proxmox.networks :
+    network.iface => network.bridge_vids
+    if network.bridge_vlan_aware
+  }
+}
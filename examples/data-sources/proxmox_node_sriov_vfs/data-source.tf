@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_node_sriov_vfs" "intel" {
+  node   = "proxmox"
+  vendor = "0x8086"
+}
+
+output "free_virtual_functions" {
+  value = [for vf in data.proxmox_node_sriov_vfs.intel.virtual_functions : vf.id if vf.available]
+}
@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {
+  alias        = "prod"
+  cluster_name = "prod"
+}
+
+provider "proxmox" {
+  alias = "lab"
+}
+
+data "proxmox_provider_metadata" "prod" {
+  provider = proxmox.prod
+}
+
+data "proxmox_provider_metadata" "lab" {
+  provider = proxmox.lab
+}
+
+output "clusters" {
+  value = {
+    prod = data.proxmox_provider_metadata.prod.cluster_name
+    lab  = data.proxmox_provider_metadata.lab.cluster_name
+  }
+}
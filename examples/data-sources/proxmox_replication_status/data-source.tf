@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_replication_status" "pve1" {
+  node = "pve1"
+}
+
+output "failing_jobs" {
+  value = [for job in data.proxmox_replication_status.pve1.jobs : job.id if job.fail_count > 0]
+}
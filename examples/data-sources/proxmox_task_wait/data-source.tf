@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Wait for a nightly backup of guest 100 to release its lock
+data "proxmox_task_wait" "backup" {
+  node    = "proxmox"
+  vm_id   = 100
+  timeout = "1h"
+}
+
+# Wait for a specific task started outside of Terraform
+data "proxmox_task_wait" "task" {
+  upid = "UPID:proxmox:000F4240:0E4A6B2C:66A0F3E1:vzdump:100:root@pam:"
+}
@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Verify the credential of the provider before managing SDN objects.
+data "proxmox_token_permissions" "terraform" {
+  required_privileges = {
+    "/sdn" = ["SDN.Allocate", "SDN.Audit"]
+  }
+}
+
+resource "terraform_data" "sdn" {
+  lifecycle {
+    precondition {
+      condition     = data.proxmox_token_permissions.terraform.satisfied
+      error_message = "Missing privileges: ${join(", ", data.proxmox_token_permissions.terraform.missing)}"
+    }
+  }
+}
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_vm_agent_file" "hostname" {
+  node  = "proxmox"
+  vm_id = 100
+  path  = "/etc/hostname"
+}
+
+output "hostname" {
+  value     = data.proxmox_vm_agent_file.hostname.content
+  sensitive = true
+}
@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_vm_agent_info" "web" {
+  node  = "proxmox"
+  vm_id = 100
+}
+
+output "os" {
+  value = data.proxmox_vm_agent_info.web.os_pretty_name
+}
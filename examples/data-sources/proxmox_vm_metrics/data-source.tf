@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_vm_metrics" "web" {
+  node      = "proxmox"
+  vm_id     = 100
+  timeframe = "day"
+}
+
+output "peak_cpu" {
+  value = max([for point in data.proxmox_vm_metrics.web.data : coalesce(point.cpu, 0)]...)
+}
@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+locals {
+  # "local-lvm:32,discard=on,iothread=1"
+  virtio0 = provider::proxmox::format_options({
+    file     = "local-lvm:32"
+    iothread = "1"
+    discard  = "on"
+  }, "file")
+}
@@ -0,0 +1,14 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+locals {
+  # "12G", the disk grown by 4 GiB
+  disk_size = provider::proxmox::format_size(provider::proxmox::parse_size("8G") + 4 * 1024 * 1024 * 1024)
+}
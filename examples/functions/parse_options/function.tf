@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+locals {
+  # { file = "local-lvm:vm-100-disk-0", iothread = "1", size = "32G" }
+  disk = provider::proxmox::parse_options("local-lvm:vm-100-disk-0,iothread=1,size=32G", "file")
+}
+
+output "disk_size" {
+  value = local.disk.size
+}
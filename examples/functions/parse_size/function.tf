@@ -0,0 +1,14 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+locals {
+  # 8589934592
+  disk_bytes = provider::proxmox::parse_size("8G")
+}
@@ -0,0 +1,14 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_anti_affinity" "database" {
+  name   = "database"
+  vm_ids = [110, 111]
+}
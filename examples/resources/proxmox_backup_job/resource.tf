@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_backup_job" "nightly" {
+  id             = "nightly"
+  schedule       = "02:00"
+  storage        = "pbs"
+  vm_ids         = [100, 101]
+  mode           = "snapshot"
+  notes_template = "{{guestname}} on {{node}}"
+  protected      = true
+}
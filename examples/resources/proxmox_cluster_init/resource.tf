@@ -0,0 +1,37 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+# The first node of the cluster.
+provider "proxmox" {}
+
+# A standalone node joining the cluster.
+provider "proxmox" {
+  alias    = "pve2"
+  host     = "https://10.0.0.2:8006"
+  username = "root@pam"
+  password = var.root_password
+}
+
+variable "root_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "proxmox_cluster_init" "lab" {
+  cluster_name = "lab"
+  links        = ["10.0.0.1"]
+}
+
+resource "proxmox_cluster_join" "pve2" {
+  provider = proxmox.pve2
+
+  peer_address = proxmox_cluster_init.lab.address
+  fingerprint  = proxmox_cluster_init.lab.fingerprint
+  password     = var.root_password
+  links        = ["10.0.0.2"]
+}
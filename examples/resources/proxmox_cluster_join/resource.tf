@@ -0,0 +1,37 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+# Member of the existing cluster.
+provider "proxmox" {}
+
+# The standalone node to join.
+provider "proxmox" {
+  alias    = "pve2"
+  host     = "https://10.0.0.2:8006"
+  username = "root@pam"
+  password = var.root_password
+}
+
+variable "root_password" {
+  type      = string
+  sensitive = true
+}
+
+variable "cluster_fingerprint" {
+  type        = string
+  description = "Output of `pvenode cert info` on pve1"
+}
+
+resource "proxmox_cluster_join" "pve2" {
+  provider = proxmox.pve2
+
+  peer_address = "10.0.0.1"
+  fingerprint  = var.cluster_fingerprint
+  password     = var.root_password
+  links        = ["10.0.0.2", "10.1.0.2"]
+}
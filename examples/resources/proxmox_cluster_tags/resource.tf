@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_cluster_tags" "this" {
+  registered_tags = ["prod", "backup"]
+
+  color_map = {
+    prod = "c0392b:ffffff"
+    dev  = "27ae60"
+  }
+  ordering = "alphabetical"
+
+  user_allow      = "list"
+  user_allow_list = ["dev", "test"]
+}
@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+variable "esxi_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "proxmox_esxi_storage" "esxi1" {
+  storage                = "esxi1"
+  server                 = "esxi1.example.com"
+  username               = "root"
+  password               = var.esxi_password
+  skip_cert_verification = true
+}
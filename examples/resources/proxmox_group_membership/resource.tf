@@ -0,0 +1,14 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_group_membership" "ops" {
+  group = "ops"
+  users = ["alice@pve", "bob@example.com"]
+}
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Pass options the provider does not model yet straight to VM 100, which is
+# not managed by Terraform. Guests managed with proxmox_vm or proxmox_lxc take
+# them in their extra_config attribute instead.
+resource "proxmox_guest_extra_config" "web" {
+  node  = "proxmox"
+  vm_id = 100
+
+  # Roll back with `qm rollback 100 <last_snapshot>` after a bad apply.
+  snapshot_before_update = true
+
+  extra_config = {
+    args     = "-device intel-iommu"
+    affinity = "0-3"
+  }
+}
+
+output "rollback_snapshot" {
+  value = proxmox_guest_extra_config.web.last_snapshot
+}
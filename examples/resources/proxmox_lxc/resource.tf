@@ -0,0 +1,49 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {
+  default_node = "proxmox"
+}
+
+# Container on the default_node of the provider, started and checked to keep
+# running for a minute after its start, and backed up before it is destroyed.
+resource "proxmox_lxc" "web" {
+  os_template = "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"
+  hostname    = "web"
+  unique_name = true
+
+  start                   = true
+  wait_for_status         = "running"
+  wait_for_status_timeout = 60
+
+  backup_before_destroy = true
+  backup_storage        = "pbs"
+
+  # Options the provider does not model yet, applied verbatim.
+  extra_config = {
+    tty = "4"
+  }
+}
+
+# Workers spread across two of the nodes, each created on the node running
+# the fewest guests.
+resource "proxmox_lxc" "worker" {
+  count = 4
+
+  os_template = "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"
+  hostname    = "worker-${count.index}"
+
+  placement = {
+    strategy = "spread"
+    nodes    = ["proxmox", "proxmox2"]
+  }
+}
+
+output "current_node" {
+  value = proxmox_lxc.web.current_node
+}
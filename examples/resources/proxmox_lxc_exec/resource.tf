@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Install nginx in container 200, whose console mode is set to shell.
+resource "proxmox_lxc_exec" "nginx" {
+  node    = "proxmox"
+  vm_id   = 200
+  command = "apt-get update && apt-get install -y nginx"
+  timeout = "10m"
+}
+
+output "install_log" {
+  value = proxmox_lxc_exec.nginx.output
+}
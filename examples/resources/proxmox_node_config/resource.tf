@@ -0,0 +1,16 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_node_config" "pve2" {
+  node                  = "pve2"
+  wakeonlan             = "aa:bb:cc:dd:ee:ff"
+  startall_onboot_delay = 30
+  description           = "Lab node, powered off outside of working hours"
+}
@@ -0,0 +1,16 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_node_directory_storage" "backup" {
+  node       = "proxmox"
+  name       = "backup"
+  device     = "/dev/sdb"
+  filesystem = "xfs"
+}
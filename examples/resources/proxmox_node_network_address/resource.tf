@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Storage network on a second bridge of a multi-homed node.
+resource "proxmox_node_network_address" "storage" {
+  node     = "pve"
+  iface    = "vmbr1"
+  cidr     = "10.10.0.11/24"
+  mtu      = 9000
+  comments = "Storage network"
+}
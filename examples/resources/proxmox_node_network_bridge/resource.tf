@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# VLAN aware bridge on the second uplink of each node, trunking the tags of
+# the vnets of an SDN vlan zone.
+resource "proxmox_node_network_bridge" "guests" {
+  for_each = toset(["pve1", "pve2"])
+
+  node              = each.key
+  iface             = "vmbr1"
+  bridge_ports      = "eno2"
+  bridge_vlan_aware = true
+  bridge_vids       = "10 20 100-199"
+  comments          = "Guest VLANs"
+}
+
+resource "proxmox_sdn_zone" "guests" {
+  zone   = "guests"
+  type   = "vlan"
+  bridge = "vmbr1"
+
+  depends_on = [proxmox_node_network_bridge.guests]
+}
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_node_config" "pve2" {
+  node      = "pve2"
+  wakeonlan = "aa:bb:cc:dd:ee:ff"
+}
+
+# Wake pve2 before provisioning guests on it.
+resource "proxmox_node_wakeonlan" "pve2" {
+  node    = proxmox_node_config.pve2.node
+  timeout = "5m"
+}
@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_node_wipe_disk" "sdb" {
+  node    = "proxmox"
+  disk    = "/dev/sdb"
+  confirm = "WIPE-/dev/sdb"
+}
+
+resource "proxmox_node_directory_storage" "scratch" {
+  node   = proxmox_node_wipe_disk.sdb.node
+  name   = "scratch"
+  device = proxmox_node_wipe_disk.sdb.disk
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+variable "pbs_password" {
+  type      = string
+  sensitive = true
+}
+
+variable "pbs_encryption_key" {
+  type      = string
+  sensitive = true
+}
+
+resource "proxmox_pbs_storage" "pbs" {
+  storage        = "pbs"
+  server         = "pbs.example.com"
+  datastore      = "store1"
+  username       = "backup@pbs!pve"
+  password       = var.pbs_password
+  fingerprint    = "d2:6f:0e:aa:ec:5a:1c:42:3b:2c:8e:8f:97:ea:4e:8f:18:4f:4b:5b:4a:2f:c0:c1:4b:bb:a9:5f:7e:3a:1e:0b"
+  namespace      = "pve"
+  encryption_key = var.pbs_encryption_key
+}
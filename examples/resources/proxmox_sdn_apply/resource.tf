@@ -0,0 +1,47 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_sdn_zone" "lab" {
+  zone = "lab"
+  type = "simple"
+}
+
+resource "proxmox_sdn_vnet" "lab" {
+  vnet = "labnet"
+  zone = proxmox_sdn_zone.lab.zone
+}
+
+# Changes the refresh finds pending, e.g. made outside of Terraform, are
+# applied on the next run by themselves. Changes of the managed zones and
+# vnets in the same run are not pending yet when it is planned, their
+# digests in triggers apply them as well.
+resource "proxmox_sdn_apply" "this" {
+  triggers = {
+    zone = proxmox_sdn_zone.lab.digest
+    vnet = proxmox_sdn_vnet.lab.digest
+  }
+}
+
+# Without digests, e.g. for zones of other modules, replace_triggered_by
+# applies the configuration again whenever they change.
+resource "proxmox_sdn_apply" "shared" {
+  lifecycle {
+    replace_triggered_by = [
+      proxmox_sdn_zone.lab,
+      proxmox_sdn_vnet.lab,
+    ]
+  }
+}
+
+# Guests attached to labnet reference the applied vnets, so that they are
+# only created once the bridge exists on the nodes.
+output "bridges" {
+  value = proxmox_sdn_apply.this.vnets
+}
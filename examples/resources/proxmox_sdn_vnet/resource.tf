@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_sdn_zone" "example" {
+  zone = "example"
+  type = "simple"
+}
+
+resource "proxmox_sdn_vnet" "example" {
+  vnet  = "tenant1"
+  zone  = proxmox_sdn_zone.example.zone
+  alias = "Tenant 1"
+
+  isolate_ports = true
+}
+
+output "sdn_vnet" {
+  value = proxmox_sdn_vnet.example
+}
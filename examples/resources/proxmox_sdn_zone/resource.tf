@@ -16,6 +16,10 @@ resource "proxmox_sdn_zone" "example" {
   type   = "vlan"
   bridge = "vmbr0"
 
+  # Only deploy the zone to the nodes wired to the VLAN trunk. Guests on its
+  # vnets are rejected at plan time on other nodes.
+  nodes = ["pve1", "pve2"]
+
   #dns    = "192.168.2.201"
 }
 
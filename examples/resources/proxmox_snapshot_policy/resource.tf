@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+    time = {
+      source = "hashicorp/time"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "time_rotating" "daily" {
+  rotation_days = 1
+}
+
+# Take a snapshot of the databases on the first apply of each day and keep
+# the last week of snapshots.
+resource "proxmox_snapshot_policy" "databases" {
+  vm_ids = [110, 111]
+  prefix = "daily"
+  retain = 7
+
+  triggers = {
+    rotation = time_rotating.daily.id
+  }
+}
@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+variable "alice_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "proxmox_user" "alice" {
+  user_id    = "alice@pve"
+  password   = var.alice_password
+  first_name = "Alice"
+  email      = "alice@example.com"
+  # Accounts with an initial password expire, Proxmox VE cannot force a change.
+  expire = 1798761600
+}
+
+resource "proxmox_group_membership" "admins" {
+  group = "admins"
+  users = [proxmox_user.alice.user_id]
+}
@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_user_tfa" "alice_totp" {
+  user_id     = "alice@pve"
+  type        = "totp"
+  description = "Phone"
+}
+
+resource "proxmox_user_tfa" "alice_recovery" {
+  user_id = "alice@pve"
+  type    = "recovery"
+}
+
+output "alice_otpauth_url" {
+  value     = proxmox_user_tfa.alice_totp.otpauth_url
+  sensitive = true
+}
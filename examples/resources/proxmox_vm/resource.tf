@@ -0,0 +1,335 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+variable "windows_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "proxmox_vm" "web" {
+  node        = "proxmox"
+  name        = "web"
+  unique_name = true
+  memory      = 2048
+
+  cpu = {
+    cores = 2
+    type  = "x86-64-v2-AES"
+  }
+
+  disks = {
+    scsi0 = {
+      storage  = "local-lvm"
+      size     = 32
+      discard  = true
+      ssd      = true
+      iothread = true
+    }
+  }
+
+  network_devices = {
+    net0 = {
+      bridge = "vmbr0"
+      vlan   = 10
+      queues = 2
+    }
+    # Trunk port for the VLANs of the guest, limited to 50 MB/s, with a
+    # fixed MAC address for DHCP reservations.
+    net1 = {
+      bridge   = "vmbr1"
+      trunks   = [20, 30]
+      firewall = true
+      mtu      = 9000
+      rate     = 50
+      mac      = "BC:24:11:5E:00:01"
+    }
+  }
+
+  # Options the provider does not model yet, applied verbatim.
+  extra_config = {
+    affinity = "0-3"
+  }
+}
+
+# Keep a protected backup of the database VM when it is destroyed or
+# replaced, snapshot it before config changes and leave its placement to the
+# HA manager.
+resource "proxmox_vm" "db" {
+  node   = "proxmox"
+  name   = "db"
+  memory = 4096
+
+  disks = {
+    scsi0 = {
+      storage = "local-lvm"
+      size    = 16
+    }
+    # Database files on a separate disk, backed up by the database itself.
+    virtio1 = {
+      storage = "local-lvm"
+      size    = 200
+      cache   = "none"
+      backup  = false
+      serial  = "pgdata"
+    }
+  }
+
+  backup_before_destroy = true
+  backup_storage        = "pbs"
+  ha_managed            = true
+
+  # Roll back with `qm rollback <vm_id> <last_snapshot>` after a bad apply.
+  snapshot_before_update = true
+}
+
+# Clone a cloud image template onto another node and grow its disk.
+resource "proxmox_vm" "worker" {
+  node   = "proxmox2"
+  name   = "worker"
+  memory = 8192
+
+  # Two sockets of four cores on the host CPU model, with six vCPUs plugged
+  # at start.
+  cpu = {
+    cores   = 4
+    sockets = 2
+    vcpus   = 6
+    type    = "host"
+    flags   = ["+aes"]
+  }
+
+  clone = {
+    template_name  = "debian-12-cloud"
+    target_storage = "local-lvm"
+  }
+
+  disks = {
+    scsi0 = {
+      size = 40
+    }
+  }
+
+  network_devices = {
+    net0 = {
+      bridge = "vmbr0"
+    }
+  }
+
+  cloud_init = {
+    storage  = "local-lvm"
+    user     = "debian"
+    ssh_keys = [file("~/.ssh/id_ed25519.pub")]
+
+    ip_config = [{
+      ipv4    = "10.0.0.20/24"
+      gateway = "10.0.0.1"
+    }]
+
+    # Written to the snippets of the local storage over the ssh block of
+    # the provider.
+    snippets_storage = "local"
+    custom_data = {
+      vendor = file("${path.module}/vendor-data.yaml")
+    }
+  }
+
+  # The template enables the guest agent, which runs the commands once the
+  # worker is up and again when the version changes.
+  start = true
+  provisioning = {
+    commands = [
+      "apt-get update",
+      "apt-get install -y nginx",
+      "systemctl enable --now nginx",
+    ]
+    timeout = "10m"
+    triggers = {
+      version = "1"
+    }
+  }
+}
+
+# Import a cloud image pre-staged on a shared storage as the root disk and
+# grow it.
+resource "proxmox_vm" "imported" {
+  node = "proxmox"
+  name = "imported"
+
+  disks = {
+    scsi0 = {
+      storage     = "local-lvm"
+      size        = 20
+      import_from = "nfs:import/debian-12-generic-amd64.qcow2"
+    }
+  }
+
+  network_devices = {
+    net0 = {
+      bridge = "vmbr0"
+    }
+  }
+}
+
+# Windows gaming VM whose GPU driver refuses to run virtualized, with the
+# hypervisor hidden from the guest, a shared memory segment for Looking Glass
+# and no balloon device, which does not work with GPU passthrough.
+resource "proxmox_vm" "gaming" {
+  node   = "proxmox"
+  name   = "gaming"
+  memory = 16384
+
+  ivshmem_size = 64
+  ivshmem_name = "looking-glass"
+  balloon      = false
+
+  cpu = {
+    cores  = 8
+    type   = "host"
+    hidden = true
+  }
+
+  disks = {
+    scsi0 = {
+      storage = "local-lvm"
+      size    = 256
+    }
+  }
+}
+
+# ARM VM fully emulated on an x86_64 node.
+resource "proxmox_vm" "arm" {
+  node = "proxmox"
+  name = "arm"
+  arch = "aarch64"
+  kvm  = false
+
+  cpu = {
+    type = "max"
+  }
+
+  disks = {
+    scsi0 = {
+      storage = "local-lvm"
+      size    = 16
+    }
+  }
+}
+
+# VM on the local disks of its node, hibernated to a shared storage instead
+# of migrated live when it is moved back to its node.
+resource "proxmox_vm" "cache" {
+  node   = "proxmox"
+  name   = "cache"
+  memory = 32768
+
+  vmstatestorage       = "ceph"
+  migrate_back         = true
+  hibernate_on_migrate = true
+
+  disks = {
+    scsi0 = {
+      storage = "local-zfs"
+      size    = 32
+    }
+  }
+}
+
+# Windows VM cloned from a template with cloudbase-init, configured from a
+# configdrive2 cloud-init drive and keeping its clock in local time.
+resource "proxmox_vm" "windows" {
+  node    = "proxmox"
+  name    = "windows"
+  memory  = 8192
+  os_type = "win11"
+
+  clone = {
+    template_name = "windows-2022-cloudbase"
+  }
+
+  cloud_init = {
+    storage  = "local-lvm"
+    type     = "configdrive2"
+    user     = "Administrator"
+    password = var.windows_password
+
+    ip_config = [{
+      ipv4    = "10.0.0.30/24"
+      gateway = "10.0.0.1"
+    }]
+  }
+}
+
+# Diskless worker of a PXE provisioned fleet, booting over the network from
+# net0 every time it starts.
+resource "proxmox_vm" "pxe" {
+  count = 3
+
+  node       = "proxmox"
+  name       = "pxe-${count.index}"
+  memory     = 4096
+  boot_order = ["net0"]
+
+  network_devices = {
+    net0 = {
+      bridge = "vmbr0"
+      vlan   = 40
+    }
+  }
+}
+
+# Build runner created on the node with the lowest share of used memory.
+resource "proxmox_vm" "runner" {
+  name   = "runner"
+  memory = 16384
+
+  placement = {
+    strategy = "least-memory"
+  }
+
+  disks = {
+    scsi0 = {
+      storage = "local-lvm"
+      size    = 64
+    }
+  }
+}
+
+# Router VM backed by 1 GiB hugepages, with each of its NUMA nodes bound to
+# the matching NUMA node of the host. The memory of the NUMA nodes adds up
+# to the memory of the VM.
+resource "proxmox_vm" "router" {
+  node   = "proxmox"
+  name   = "router"
+  memory = 8192
+
+  cpu = {
+    cores   = 4
+    sockets = 2
+    type    = "host"
+  }
+
+  hugepages      = "1024"
+  keep_hugepages = true
+
+  numa_nodes = [
+    {
+      cpus       = "0-3"
+      memory     = 4096
+      host_nodes = "0"
+      policy     = "bind"
+    },
+    {
+      cpus       = "4-7"
+      memory     = 4096
+      host_nodes = "1"
+      policy     = "bind"
+    },
+  ]
+}
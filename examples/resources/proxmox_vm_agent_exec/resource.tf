@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Run commands in VM 100, which is not managed by Terraform. VMs managed with
+# proxmox_vm take them in their provisioning attribute instead.
+resource "proxmox_vm_agent_exec" "web" {
+  node  = "proxmox"
+  vm_id = 100
+
+  commands = [
+    "apt-get update",
+    "apt-get install -y nginx",
+    "systemctl enable --now nginx",
+  ]
+
+  retries = 5
+  timeout = "10m"
+}
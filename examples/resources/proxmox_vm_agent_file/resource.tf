@@ -0,0 +1,16 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+resource "proxmox_vm_agent_file" "motd" {
+  node    = "proxmox"
+  vm_id   = 100
+  path    = "/etc/motd"
+  content = "Managed by Terraform\n"
+}
@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Download a firewall appliance to the local storage and deploy it with its
+# network interfaces connected to vmbr1.
+resource "proxmox_vm_appliance" "firewall" {
+  node           = "proxmox"
+  storage        = "local"
+  file_name      = "firewall.ova"
+  url            = "https://example.com/appliances/firewall.ova"
+  target_storage = "local-lvm"
+  bridge         = "vmbr1"
+
+  name   = "firewall"
+  cores  = 2
+  memory = 4096
+
+  start           = true
+  wait_for_status = "running"
+}
+
+output "firewall_vm_id" {
+  value = proxmox_vm_appliance.firewall.vm_id
+}
@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+data "proxmox_cloudinit_config" "web" {
+  hostname = "web01"
+  packages = ["qemu-guest-agent"]
+}
+
+# Regenerate the cloud-init drive of VM 100 whenever the rendered
+# user-data, uploaded as snippet referenced by the VM's cicustom, changes.
+resource "proxmox_vm_cloudinit" "web" {
+  node  = "proxmox"
+  vm_id = 100
+
+  triggers = {
+    user_data = sha256(data.proxmox_cloudinit_config.web.user_data)
+  }
+}
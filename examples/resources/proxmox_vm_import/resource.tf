@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Import the VM "web" from the ESXi host connected as storage esxi1, with
+# its data disk on a separate storage, and fail the apply if it does not
+# keep running for a minute after starting.
+resource "proxmox_vm_import" "web" {
+  node           = "proxmox"
+  source         = "esxi1:ha-datacenter/datastore1/web/web.vmx"
+  target_storage = "local-lvm"
+
+  disk_storage = {
+    scsi1 = "ceph"
+  }
+
+  start                   = true
+  wait_for_status         = "running"
+  wait_for_status_timeout = 60
+
+  # Destroying the resource destroys the imported VM, unless it is kept.
+  keep_on_destroy = false
+}
+
+output "web_vm_id" {
+  value = proxmox_vm_import.web.vm_id
+}
@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Install Debian unattended on VM 130 from the netinst ISO, with the preseed
+# file on a second CD-ROM. Once installed, set ide2 to "none", drop ide3 and
+# boot from scsi0 only.
+resource "proxmox_vm_install_media" "debian" {
+  node  = "proxmox"
+  vm_id = 130
+
+  cdroms = {
+    ide2 = "local:iso/debian-12-netinst.iso"
+    ide3 = "local:iso/preseed.iso"
+  }
+  boot_order = ["scsi0", "ide2"]
+}
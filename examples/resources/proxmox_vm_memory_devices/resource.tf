@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Give VM 100, which is not managed by Terraform, a 64 MiB shared memory
+# segment for Looking Glass and remove its balloon device, which does not
+# work with GPU passthrough. VMs managed with proxmox_vm take these devices
+# in their own attributes instead.
+resource "proxmox_vm_memory_devices" "gaming" {
+  node  = "proxmox"
+  vm_id = 100
+
+  ivshmem_size = 64
+  ivshmem_name = "looking-glass"
+  balloon      = false
+}
@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    proxmox = {
+      source = "cbcoutinho/proxmox"
+    }
+  }
+}
+
+provider "proxmox" {}
+
+# Back the memory of VM 120, which is not managed by Terraform, with 1 GiB
+# hugepages and bind each of its NUMA nodes to the matching NUMA node of the
+# host. VMs managed with proxmox_vm take these settings in their own
+# attributes instead.
+resource "proxmox_vm_numa" "router" {
+  node  = "proxmox"
+  vm_id = 120
+
+  hugepages      = "1024"
+  keep_hugepages = true
+
+  numa_nodes = [
+    {
+      cpus       = "0-3"
+      memory     = 4096
+      host_nodes = "0"
+      policy     = "bind"
+    },
+    {
+      cpus       = "4-7"
+      memory     = 4096
+      host_nodes = "1"
+      policy     = "bind"
+    },
+  ]
+}
@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &antiAffinityResource{}
+	_ resource.ResourceWithConfigure  = &antiAffinityResource{}
+	_ resource.ResourceWithModifyPlan = &antiAffinityResource{}
+)
+
+// NewAntiAffinityResource is a helper function to simplify the provider implementation.
+func NewAntiAffinityResource() resource.Resource {
+	return &antiAffinityResource{}
+}
+
+// antiAffinityResource validates that a set of guests runs on distinct nodes.
+type antiAffinityResource struct {
+	client *apiClient
+}
+
+// antiAffinityResourceModel maps the resource schema data.
+type antiAffinityResourceModel struct {
+	Name  types.String `tfsdk:"name"`
+	VMIDs types.Set    `tfsdk:"vm_ids"`
+	Nodes types.Map    `tfsdk:"nodes"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *antiAffinityResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *antiAffinityResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_anti_affinity"
+}
+
+// Schema defines the schema for the resource.
+func (r *antiAffinityResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Validates that a set of guests, e.g. an HA database pair, runs on pairwise distinct nodes. " +
+			"Plans and applies fail while two of the guests share a node.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the anti-affinity rule",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_ids": schema.SetAttribute{
+				ElementType: types.Int64Type,
+				Required:    true,
+				Description: "IDs of the guests that must not share a node",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(2),
+				},
+			},
+			"nodes": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Node each guest currently runs on, keyed by guest ID",
+			},
+		},
+	}
+}
+
+// guestNodes returns the node each of the given guests currently runs on.
+func (r *antiAffinityResource) guestNodes(ctx context.Context, vmids []int64) (map[int64]string, error) {
+	cluster, err := r.client.Cluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := cluster.Resources(ctx, "vm")
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[int64]string{}
+	for _, vmid := range vmids {
+		for _, res := range resources {
+			if int64(res.VMID) == vmid {
+				nodes[vmid] = res.Node
+			}
+		}
+		if _, ok := nodes[vmid]; !ok {
+			return nil, fmt.Errorf("guest %d does not exist", vmid)
+		}
+	}
+
+	return nodes, nil
+}
+
+// antiAffinityConflicts describes every node that runs more than one of the
+// given guests, in a stable order.
+func antiAffinityConflicts(nodes map[int64]string) []string {
+	byNode := map[string][]string{}
+	for vmid, node := range nodes {
+		byNode[node] = append(byNode[node], strconv.FormatInt(vmid, 10))
+	}
+
+	var conflicts []string
+	for node, vmids := range byNode {
+		if len(vmids) > 1 {
+			sort.Strings(vmids)
+			conflicts = append(conflicts, fmt.Sprintf("guests %s all run on node %s", strings.Join(vmids, ", "), node))
+		}
+	}
+	sort.Strings(conflicts)
+
+	return conflicts
+}
+
+// check resolves the placement of the guests in model, stores it in the
+// model and reports conflicts as errors.
+func (r *antiAffinityResource) check(ctx context.Context, model *antiAffinityResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var vmids []int64
+	diags.Append(model.VMIDs.ElementsAs(ctx, &vmids, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	nodes, err := r.guestNodes(ctx, vmids)
+	if err != nil {
+		diags.AddError(
+			"Unable to Read Proxmox Guest Placement",
+			apiErrorDetail(err),
+		)
+		return diags
+	}
+
+	for _, conflict := range antiAffinityConflicts(nodes) {
+		diags.AddError(
+			"Anti-Affinity Rule Violated",
+			fmt.Sprintf("Anti-affinity rule %q is violated: %s. Migrate one of the guests to another node.",
+				model.Name.ValueString(), conflict),
+		)
+	}
+
+	placement := map[string]string{}
+	for vmid, node := range nodes {
+		placement[strconv.FormatInt(vmid, 10)] = node
+	}
+	nodesValue, d := types.MapValueFrom(ctx, types.StringType, placement)
+	diags.Append(d...)
+	model.Nodes = nodesValue
+
+	return diags
+}
+
+// ModifyPlan fails the plan when the guests currently share a node.
+func (r *antiAffinityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate when the resource is destroyed.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan antiAffinityResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || plan.VMIDs.IsUnknown() {
+		return
+	}
+
+	ctx = r.client.logContext(ctx)
+	tflog.SubsystemDebug(ctx, logVM, "Validating anti-affinity rule", map[string]interface{}{
+		"rule": plan.Name.ValueString(),
+	})
+
+	resp.Diagnostics.Append(r.check(ctx, &plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *antiAffinityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan antiAffinityResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.check(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *antiAffinityResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state antiAffinityResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Conflicts are reported by the plan, a refresh only records the
+	// current placement.
+	for _, d := range r.check(ctx, &state) {
+		if d.Summary() == "Anti-Affinity Rule Violated" {
+			resp.Diagnostics.AddWarning(d.Summary(), d.Detail())
+			continue
+		}
+		resp.Diagnostics.Append(d)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *antiAffinityResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan antiAffinityResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.check(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *antiAffinityResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The rule only exists in the Terraform state.
+}
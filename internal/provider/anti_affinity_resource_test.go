@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAntiAffinityConflicts(t *testing.T) {
+	cases := map[string]struct {
+		nodes map[int64]string
+		want  []string
+	}{
+		"distinct nodes": {
+			nodes: map[int64]string{100: "pve1", 101: "pve2"},
+			want:  nil,
+		},
+		"shared node": {
+			nodes: map[int64]string{100: "pve1", 101: "pve1", 102: "pve2"},
+			want:  []string{"guests 100, 101 all run on node pve1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := antiAffinityConflicts(tc.nodes)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
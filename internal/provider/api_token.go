@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseAPIToken splits an API token of the form USER@REALM!TOKENID=SECRET,
+// the format of the Authorization header of the API, into the ID of the
+// token and its secret.
+func parseAPIToken(token string) (id, secret string, err error) {
+	id, secret, ok := strings.Cut(token, "=")
+	if !ok || secret == "" {
+		return "", "", fmt.Errorf("the API token has no secret, expected USER@REALM!TOKENID=SECRET")
+	}
+
+	principal, name, ok := strings.Cut(id, "!")
+	if !ok || name == "" || !strings.Contains(principal, "@") {
+		return "", "", fmt.Errorf("the API token ID %q is not of the form USER@REALM!TOKENID", id)
+	}
+
+	return id, secret, nil
+}
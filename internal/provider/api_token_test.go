@@ -0,0 +1,26 @@
+package provider
+
+import "testing"
+
+func TestParseAPIToken(t *testing.T) {
+	tests := []struct {
+		token      string
+		wantID     string
+		wantSecret string
+		wantErr    bool
+	}{
+		{token: "terraform@pve!ci=8a3b1c2d-0000-4000-8000-000000000000", wantID: "terraform@pve!ci", wantSecret: "8a3b1c2d-0000-4000-8000-000000000000"},
+		{token: "ops@example.com@ad!ci=secret", wantID: "ops@example.com@ad!ci", wantSecret: "secret"},
+		{token: "terraform@pve!ci", wantErr: true},
+		{token: "terraform@pve!ci=", wantErr: true},
+		{token: "terraform@pve=secret", wantErr: true},
+		{token: "terraform!ci=secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		id, secret, err := parseAPIToken(tt.token)
+		if (err != nil) != tt.wantErr || id != tt.wantID || secret != tt.wantSecret {
+			t.Errorf("parseAPIToken(%q) = %q, %q, %v", tt.token, id, secret, err)
+		}
+	}
+}
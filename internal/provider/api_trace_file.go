@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// traceFiles are the open API trace files by path, shared by the provider
+// instances of the process so that their lines do not interleave.
+var traceFiles = struct {
+	mu    sync.Mutex
+	files map[string]*traceWriter
+}{files: map[string]*traceWriter{}}
+
+// traceWriter writes the lines of an API trace file one at a time.
+type traceWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// write appends entry to the trace as a JSON line.
+func (t *traceWriter) write(entry traceEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(append(line, '\n'))
+}
+
+// openTraceFile returns the writer of the API trace file at path, which is
+// created readable only by its owner and appended to.
+func openTraceFile(path string) (*traceWriter, error) {
+	traceFiles.mu.Lock()
+	defer traceFiles.mu.Unlock()
+
+	if trace, ok := traceFiles.files[path]; ok {
+		return trace, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	trace := &traceWriter{w: file}
+	traceFiles.files[path] = trace
+
+	return trace, nil
+}
+
+// traceEntry is a line of the API trace file, a request and its response.
+type traceEntry struct {
+	Time       string `json:"time"`
+	Method     string `json:"method"`
+	Host       string `json:"host"`
+	Path       string `json:"path"`
+	Query      string `json:"query,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+	UPID       string `json:"upid,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Request    string `json:"request,omitempty"`
+	Response   string `json:"response,omitempty"`
+}
+
+// traceUPID returns the UPID of the task a request started or queried,
+// either the data of its response or a segment of its path.
+func traceUPID(path string, response []byte) string {
+	var body struct {
+		Data interface{} `json:"data"`
+	}
+	if json.Unmarshal(response, &body) == nil {
+		if upid, ok := body.Data.(string); ok && strings.HasPrefix(upid, "UPID:") {
+			return upid
+		}
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "UPID:") {
+			return segment
+		}
+	}
+
+	return ""
+}
+
+// fileTraceTransport writes each API request and its response to a trace
+// file as a JSON line, with sensitive fields redacted, for support bundles.
+// Headers are never written, as they carry the credentials.
+type fileTraceTransport struct {
+	base  http.RoundTripper
+	trace *traceWriter
+	now   func() time.Time
+}
+
+// newFileTraceTransport returns a fileTraceTransport writing to trace.
+func newFileTraceTransport(base http.RoundTripper, trace *traceWriter) *fileTraceTransport {
+	return &fileTraceTransport{base: base, trace: trace, now: time.Now}
+}
+
+// RoundTrip sends req and writes it to the trace together with its response.
+func (t *fileTraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.now()
+	entry := traceEntry{
+		Time:   start.UTC().Format(time.RFC3339Nano),
+		Method: req.Method,
+		Host:   req.URL.Host,
+		Path:   req.URL.Path,
+		Query:  redactBody([]byte(req.URL.RawQuery)),
+		UPID:   traceUPID(req.URL.Path, nil),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			entry.Request = redactBody(data)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		entry.DurationMS = t.now().Sub(start).Milliseconds()
+		entry.Error = err.Error()
+		t.trace.write(entry)
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	entry.DurationMS = t.now().Sub(start).Milliseconds()
+	entry.Status = resp.StatusCode
+	if err != nil {
+		entry.Error = err.Error()
+		t.trace.write(entry)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	entry.UPID = traceUPID(req.URL.Path, data)
+	entry.Response = redactBody(data)
+	t.trace.write(entry)
+
+	return resp, nil
+}
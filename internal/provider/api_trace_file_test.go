@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is an http.RoundTripper calling itself.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFileTraceTransport(t *testing.T) {
+	var buf bytes.Buffer
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/status/start") {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"data":"UPID:pve:0001:qmstart:100:root@pam:"}`)),
+			}, nil
+		}
+		return nil, errors.New("connection refused")
+	})
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	transport := newFileTraceTransport(base, &traceWriter{w: &buf})
+	transport.now = func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post("https://pve:8006/api2/json/nodes/pve/qemu/100/status/start", "application/x-www-form-urlencoded", strings.NewReader("password=hunter2&timeout=30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "UPID:") {
+		t.Errorf("the response body was not passed on: %s", body)
+	}
+	if _, err := client.Get("https://pve:8006/api2/json/nodes/pve/tasks/UPID:pve:0001:qmstart:100:root@pam:/status"); err == nil {
+		t.Error("expected the error of the base transport")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %s", len(lines), buf.String())
+	}
+
+	var entries []traceEntry
+	for _, line := range lines {
+		var entry traceEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	want := traceEntry{
+		Time:       "2024-05-01T12:00:00.25Z",
+		Method:     "POST",
+		Host:       "pve:8006",
+		Path:       "/api2/json/nodes/pve/qemu/100/status/start",
+		Status:     200,
+		DurationMS: 250,
+		UPID:       "UPID:pve:0001:qmstart:100:root@pam:",
+		Request:    "password=REDACTED&timeout=30",
+		Response:   `{"data":"UPID:pve:0001:qmstart:100:root@pam:"}`,
+	}
+	if entries[0] != want {
+		t.Errorf("got %+v, want %+v", entries[0], want)
+	}
+	if entries[1].UPID != "UPID:pve:0001:qmstart:100:root@pam:" || entries[1].Status != 0 || !strings.Contains(entries[1].Error, "connection refused") {
+		t.Errorf("got %+v for a failed task status request", entries[1])
+	}
+}
+
+func TestFileTraceTransportTFA(t *testing.T) {
+	var buf bytes.Buffer
+	base := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"data":{"id":"totp-1","recovery":["1234-abcd","5678-efgh"],"challenge":"xyz"}}`)),
+		}, nil
+	})
+	client := &http.Client{Transport: newFileTraceTransport(base, &traceWriter{w: &buf})}
+
+	resp, err := client.Post("https://pve:8006/api2/json/access/tfa/alice@pve", "application/x-www-form-urlencoded",
+		strings.NewReader("type=totp&description=phone&totp=otpauth%3A%2F%2Ftotp%2Fpve&value=123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	var entry traceEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if want := "description=phone&totp=REDACTED&type=totp&value=REDACTED"; entry.Request != want {
+		t.Errorf("got request %s, want %s", entry.Request, want)
+	}
+	if want := `{"data":{"challenge":"REDACTED","id":"totp-1","recovery":"REDACTED"}}`; entry.Response != want {
+		t.Errorf("got response %s, want %s", entry.Response, want)
+	}
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &aptUpdatesDataSource{}
+	_ datasource.DataSourceWithConfigure = &aptUpdatesDataSource{}
+)
+
+func NewAptUpdatesDataSource() datasource.DataSource {
+	return &aptUpdatesDataSource{}
+}
+
+type aptUpdatesDataSource struct {
+	client *apiClient
+}
+
+type aptUpdatesDataSourceModel struct {
+	Nodes []nodeAptUpdatesModel `tfsdk:"nodes"`
+}
+
+type nodeAptUpdatesModel struct {
+	Node         types.String     `tfsdk:"node"`
+	Online       types.Bool       `tfsdk:"online"`
+	UpdateCount  types.Int64      `tfsdk:"update_count"`
+	KernelUpdate types.Bool       `tfsdk:"kernel_update"`
+	Updates      []aptUpdateModel `tfsdk:"updates"`
+}
+
+type aptUpdateModel struct {
+	Package    types.String `tfsdk:"package"`
+	OldVersion types.String `tfsdk:"old_version"`
+	Version    types.String `tfsdk:"version"`
+	Origin     types.String `tfsdk:"origin"`
+	Priority   types.String `tfsdk:"priority"`
+}
+
+// aptUpdate is an entry of the node apt update endpoint.
+type aptUpdate struct {
+	Package    string `json:"Package"`
+	OldVersion string `json:"OldVersion"`
+	Version    string `json:"Version"`
+	Origin     string `json:"Origin"`
+	Priority   string `json:"Priority"`
+}
+
+func (d *aptUpdatesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *aptUpdatesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_apt_updates"
+}
+
+func (d *aptUpdatesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Summarizes the pending package updates of every node of the cluster, as found by the last package database refresh, " +
+			"e.g. to decide which nodes need a maintenance window.",
+		Attributes: map[string]schema.Attribute{
+			"nodes": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"node": schema.StringAttribute{
+							Computed: true,
+						},
+						"online": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the node is online, the updates of offline nodes are unknown",
+						},
+						"update_count": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Number of packages with a pending update",
+						},
+						"kernel_update": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether a kernel package is updated, which requires a reboot of the node",
+						},
+						"updates": schema.ListNestedAttribute{
+							Computed: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"package": schema.StringAttribute{
+										Computed: true,
+									},
+									"old_version": schema.StringAttribute{
+										Computed:    true,
+										Description: "Installed version of the package",
+									},
+									"version": schema.StringAttribute{
+										Computed:    true,
+										Description: "Version the package is updated to",
+									},
+									"origin": schema.StringAttribute{
+										Computed:    true,
+										Description: "Origin of the repository the update comes from",
+									},
+									"priority": schema.StringAttribute{
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// hasKernelUpdate reports whether one of the updates is a kernel package.
+func hasKernelUpdate(updates []aptUpdate) bool {
+	for _, update := range updates {
+		if _, ok := kernelRelease(update.Package); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *aptUpdatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state aptUpdatesDataSourceModel
+
+	cluster, err := d.client.Cluster(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	nodes := cluster.Nodes
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	state.Nodes = []nodeAptUpdatesModel{}
+	for _, node := range nodes {
+		nodeState := nodeAptUpdatesModel{
+			Node:         types.StringValue(node.Name),
+			Online:       proxmoxtf.Bool(node.Online),
+			UpdateCount:  types.Int64Null(),
+			KernelUpdate: types.BoolNull(),
+		}
+
+		if node.Online == 1 {
+			nodeCtx := tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node.Name)
+			tflog.SubsystemDebug(nodeCtx, logAPI, "Reading pending package updates")
+
+			var updates []aptUpdate
+			err := d.client.Get(nodeCtx, fmt.Sprintf("/nodes/%s/apt/update", node.Name), &updates)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Read Proxmox Node Package Updates",
+					apiErrorDetail(err),
+				)
+				return
+			}
+
+			nodeState.UpdateCount = types.Int64Value(int64(len(updates)))
+			nodeState.KernelUpdate = types.BoolValue(hasKernelUpdate(updates))
+			nodeState.Updates = []aptUpdateModel{}
+			for _, update := range updates {
+				nodeState.Updates = append(nodeState.Updates, aptUpdateModel{
+					Package:    types.StringValue(update.Package),
+					OldVersion: types.StringValue(update.OldVersion),
+					Version:    types.StringValue(update.Version),
+					Origin:     types.StringValue(update.Origin),
+					Priority:   types.StringValue(update.Priority),
+				})
+			}
+		}
+
+		state.Nodes = append(state.Nodes, nodeState)
+	}
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,22 @@
+package provider
+
+import "testing"
+
+func TestHasKernelUpdate(t *testing.T) {
+	cases := map[string]struct {
+		updates []aptUpdate
+		want    bool
+	}{
+		"none":   {updates: nil, want: false},
+		"meta":   {updates: []aptUpdate{{Package: "proxmox-kernel-6.8"}, {Package: "pve-manager"}}, want: false},
+		"kernel": {updates: []aptUpdate{{Package: "pve-manager"}, {Package: "proxmox-kernel-6.8.12-5-pve-signed"}}, want: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := hasKernelUpdate(tc.updates); got != tc.want {
+				t.Errorf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
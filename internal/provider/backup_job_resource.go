@@ -0,0 +1,367 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &backupJobResource{}
+	_ resource.ResourceWithConfigure = &backupJobResource{}
+)
+
+// NewBackupJobResource is a helper function to simplify the provider implementation.
+func NewBackupJobResource() resource.Resource {
+	return &backupJobResource{}
+}
+
+// backupJobResource schedules backups of guests to a storage.
+type backupJobResource struct {
+	client *apiClient
+}
+
+// backupJobResourceModel maps the resource schema data.
+type backupJobResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Schedule      types.String `tfsdk:"schedule"`
+	Storage       types.String `tfsdk:"storage"`
+	VMIDs         types.Set    `tfsdk:"vm_ids"`
+	Mode          types.String `tfsdk:"mode"`
+	NotesTemplate types.String `tfsdk:"notes_template"`
+	Protected     types.Bool   `tfsdk:"protected"`
+	Enabled       types.Bool   `tfsdk:"enabled"`
+	Comment       types.String `tfsdk:"comment"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *backupJobResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *backupJobResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_backup_job"
+}
+
+// Schema defines the schema for the resource.
+func (r *backupJobResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Schedules backups of guests to a storage. Combined with a proxmox_pbs_storage with an " +
+			"encryption key, the backups are encrypted on the client side.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the backup job",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schedule": schema.StringAttribute{
+				Required:    true,
+				Description: "Calendar event of the backups, e.g. `daily` or `sat 02:00`",
+			},
+			"storage": schema.StringAttribute{
+				Required:    true,
+				Description: "Storage to store the backups on",
+			},
+			"vm_ids": schema.SetAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "IDs of the guests to back up. All guests of the cluster are backed up when not set",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"mode": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("snapshot"),
+				Description: "Backup mode, one of snapshot, suspend or stop. Defaults to snapshot",
+				Validators: []validator.String{
+					stringvalidator.OneOf("snapshot", "suspend", "stop"),
+				},
+			},
+			"notes_template": schema.StringAttribute{
+				Optional: true,
+				Description: "Template of the notes of the backups, which may contain the variables " +
+					"`{{cluster}}`, `{{guestname}}`, `{{node}}` and `{{vmid}}`",
+			},
+			"protected": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Mark the backups as protected, so that they are not pruned or removed",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether the job runs on its schedule",
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Description of the backup job",
+			},
+		},
+	}
+}
+
+// backupJobRequest maps the plan onto the parameters of the backup job.
+// Without guest IDs all guests are backed up. Unset values are removed
+// through the `delete` parameter, which is dropped on creation.
+func backupJobRequest(ctx context.Context, plan backupJobResourceModel) (pveapi.BackupJobRequest, diag.Diagnostics) {
+	params := pveapi.BackupJobRequest{
+		ID:            plan.ID.ValueString(),
+		Schedule:      plan.Schedule.ValueString(),
+		Storage:       plan.Storage.ValueString(),
+		Mode:          plan.Mode.ValueString(),
+		NotesTemplate: plan.NotesTemplate.ValueString(),
+		Protected:     proxmoxtf.BoolToInt(plan.Protected.ValueBool()),
+		Enabled:       proxmoxtf.BoolToInt(plan.Enabled.ValueBool()),
+		Comment:       plan.Comment.ValueString(),
+	}
+
+	var remove []string
+	if plan.VMIDs.IsNull() {
+		params.All = 1
+		remove = append(remove, "vmid")
+	} else {
+		var vmids []int64
+		diags := plan.VMIDs.ElementsAs(ctx, &vmids, false)
+		if diags.HasError() {
+			return params, diags
+		}
+		sort.Slice(vmids, func(i, j int) bool { return vmids[i] < vmids[j] })
+
+		ids := make([]string, len(vmids))
+		for i, vmid := range vmids {
+			ids[i] = strconv.FormatInt(vmid, 10)
+		}
+		params.VMID = strings.Join(ids, ",")
+	}
+	if plan.NotesTemplate.IsNull() {
+		remove = append(remove, "notes-template")
+	}
+	if plan.Comment.IsNull() {
+		remove = append(remove, "comment")
+	}
+	params.Delete = strings.Join(remove, ",")
+
+	return params, nil
+}
+
+// parseBackupJobVMIDs returns the guest IDs of a backup job, or null when it
+// backs up all guests.
+func parseBackupJobVMIDs(ctx context.Context, job *pveapi.BackupJob) (types.Set, error) {
+	if job.All == 1 || job.VMID == "" {
+		return types.SetNull(types.Int64Type), nil
+	}
+
+	var vmids []int64
+	for _, id := range strings.Split(job.VMID, ",") {
+		vmid, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		if err != nil {
+			return types.SetNull(types.Int64Type), fmt.Errorf("invalid guest ID %q in backup job %s", id, job.ID)
+		}
+		vmids = append(vmids, vmid)
+	}
+
+	set, diags := types.SetValueFrom(ctx, types.Int64Type, vmids)
+	if diags.HasError() {
+		return types.SetNull(types.Int64Type), fmt.Errorf("converting guest IDs of backup job %s", job.ID)
+	}
+
+	return set, nil
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *backupJobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan backupJobResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params, diags := backupJobRequest(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Creating backup job", map[string]interface{}{
+		"id":      plan.ID.ValueString(),
+		"storage": plan.Storage.ValueString(),
+	})
+
+	err := r.client.api.CreateBackupJob(ctx, params)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox Backup Job",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *backupJobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state backupJobResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	job, err := r.client.api.BackupJob(ctx, state.ID.ValueString())
+	if err != nil {
+		// The job was removed outside of Terraform.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Backup Job",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	vmids, err := parseBackupJobVMIDs(ctx, job)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Backup Job",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Schedule = types.StringValue(job.Schedule)
+	state.Storage = types.StringValue(job.Storage)
+	state.VMIDs = vmids
+	state.Mode = types.StringValue(job.Mode)
+	if job.Mode == "" {
+		state.Mode = types.StringValue("snapshot")
+	}
+	state.NotesTemplate = proxmoxtf.StringOrNull(job.NotesTemplate)
+	state.Protected = proxmoxtf.Bool(job.Protected)
+	// Jobs are enabled unless disabled explicitly.
+	state.Enabled = types.BoolValue(job.Enabled == nil || *job.Enabled == 1)
+	state.Comment = proxmoxtf.StringOrNull(job.Comment)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *backupJobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan backupJobResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params, diags := backupJobRequest(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Updating backup job", map[string]interface{}{
+		"id": plan.ID.ValueString(),
+	})
+
+	err := r.client.api.UpdateBackupJob(ctx, plan.ID.ValueString(), params)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Backup Job",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *backupJobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state backupJobResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Deleting backup job", map[string]interface{}{
+		"id": state.ID.ValueString(),
+	})
+
+	err := r.client.api.DeleteBackupJob(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox Backup Job",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestBackupJobRequest(t *testing.T) {
+	vmids, _ := types.SetValueFrom(context.Background(), types.Int64Type, []int64{102, 100})
+
+	tests := map[string]struct {
+		model backupJobResourceModel
+		want  pveapi.BackupJobRequest
+	}{
+		"all guests": {
+			model: backupJobResourceModel{
+				ID:            types.StringValue("nightly"),
+				Schedule:      types.StringValue("02:00"),
+				Storage:       types.StringValue("pbs"),
+				VMIDs:         types.SetNull(types.Int64Type),
+				Mode:          types.StringValue("snapshot"),
+				NotesTemplate: types.StringNull(),
+				Protected:     types.BoolValue(false),
+				Enabled:       types.BoolValue(true),
+				Comment:       types.StringNull(),
+			},
+			want: pveapi.BackupJobRequest{
+				ID:       "nightly",
+				Schedule: "02:00",
+				Storage:  "pbs",
+				All:      1,
+				Mode:     "snapshot",
+				Enabled:  1,
+				Delete:   "vmid,notes-template,comment",
+			},
+		},
+		"annotated": {
+			model: backupJobResourceModel{
+				ID:            types.StringValue("weekly"),
+				Schedule:      types.StringValue("sat 03:00"),
+				Storage:       types.StringValue("pbs"),
+				VMIDs:         vmids,
+				Mode:          types.StringValue("stop"),
+				NotesTemplate: types.StringValue("{{guestname}}"),
+				Protected:     types.BoolValue(true),
+				Enabled:       types.BoolValue(false),
+				Comment:       types.StringValue("weekly"),
+			},
+			want: pveapi.BackupJobRequest{
+				ID:            "weekly",
+				Schedule:      "sat 03:00",
+				Storage:       "pbs",
+				VMID:          "100,102",
+				Mode:          "stop",
+				NotesTemplate: "{{guestname}}",
+				Protected:     1,
+				Comment:       "weekly",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := backupJobRequest(context.Background(), tt.model)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBackupJobVMIDs(t *testing.T) {
+	ctx := context.Background()
+
+	got, err := parseBackupJobVMIDs(ctx, &pveapi.BackupJob{ID: "a", VMID: "100, 101"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := types.SetValueFrom(ctx, types.Int64Type, []int64{100, 101})
+	if !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	got, err = parseBackupJobVMIDs(ctx, &pveapi.BackupJob{ID: "b", All: 1})
+	if err != nil || !got.IsNull() {
+		t.Errorf("all guests: got %s, %v", got, err)
+	}
+
+	if _, err = parseBackupJobVMIDs(ctx, &pveapi.BackupJob{ID: "c", VMID: "100,x"}); err == nil {
+		t.Error("expected an error for an invalid guest ID")
+	}
+}
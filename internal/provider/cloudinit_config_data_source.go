@@ -0,0 +1,340 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ datasource.DataSource = &cloudinitConfigDataSource{}
+)
+
+func NewCloudinitConfigDataSource() datasource.DataSource {
+	return &cloudinitConfigDataSource{}
+}
+
+// cloudinitConfigDataSource renders cloud-init documents locally, it does not
+// talk to the Proxmox VE API.
+type cloudinitConfigDataSource struct{}
+
+type cloudinitConfigDataSourceModel struct {
+	Hostname      types.String             `tfsdk:"hostname"`
+	Users         []cloudinitUserModel     `tfsdk:"users"`
+	PackageUpdate types.Bool               `tfsdk:"package_update"`
+	Packages      []types.String           `tfsdk:"packages"`
+	Runcmd        []types.String           `tfsdk:"runcmd"`
+	Ethernets     []cloudinitEthernetModel `tfsdk:"ethernets"`
+	UserData      types.String             `tfsdk:"user_data"`
+	NetworkConfig types.String             `tfsdk:"network_config"`
+}
+
+type cloudinitUserModel struct {
+	Name              types.String   `tfsdk:"name"`
+	Groups            []types.String `tfsdk:"groups"`
+	Shell             types.String   `tfsdk:"shell"`
+	Sudo              types.String   `tfsdk:"sudo"`
+	LockPasswd        types.Bool     `tfsdk:"lock_passwd"`
+	SSHAuthorizedKeys []types.String `tfsdk:"ssh_authorized_keys"`
+}
+
+type cloudinitEthernetModel struct {
+	Name          types.String          `tfsdk:"name"`
+	MacAddress    types.String          `tfsdk:"mac_address"`
+	DHCP4         types.Bool            `tfsdk:"dhcp4"`
+	DHCP6         types.Bool            `tfsdk:"dhcp6"`
+	Addresses     []types.String        `tfsdk:"addresses"`
+	Nameservers   []types.String        `tfsdk:"nameservers"`
+	SearchDomains []types.String        `tfsdk:"search_domains"`
+	Routes        []cloudinitRouteModel `tfsdk:"routes"`
+}
+
+type cloudinitRouteModel struct {
+	To     types.String `tfsdk:"to"`
+	Via    types.String `tfsdk:"via"`
+	Metric types.Int64  `tfsdk:"metric"`
+}
+
+// cloudConfig is the rendered #cloud-config user-data document. The field
+// order determines the key order of the rendered YAML.
+type cloudConfig struct {
+	Hostname      string            `yaml:"hostname,omitempty"`
+	Users         []cloudConfigUser `yaml:"users,omitempty"`
+	PackageUpdate bool              `yaml:"package_update,omitempty"`
+	Packages      []string          `yaml:"packages,omitempty"`
+	Runcmd        []string          `yaml:"runcmd,omitempty"`
+}
+
+type cloudConfigUser struct {
+	Name              string   `yaml:"name"`
+	Groups            string   `yaml:"groups,omitempty"`
+	Shell             string   `yaml:"shell,omitempty"`
+	Sudo              string   `yaml:"sudo,omitempty"`
+	LockPasswd        *bool    `yaml:"lock_passwd,omitempty"`
+	SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys,omitempty"`
+}
+
+// networkConfig is the rendered version 2 network-config document.
+type networkConfig struct {
+	Version   int                              `yaml:"version"`
+	Ethernets map[string]networkConfigEthernet `yaml:"ethernets"`
+}
+
+type networkConfigEthernet struct {
+	Match       *networkConfigMatch       `yaml:"match,omitempty"`
+	SetName     string                    `yaml:"set-name,omitempty"`
+	DHCP4       bool                      `yaml:"dhcp4,omitempty"`
+	DHCP6       bool                      `yaml:"dhcp6,omitempty"`
+	Addresses   []string                  `yaml:"addresses,omitempty"`
+	Nameservers *networkConfigNameservers `yaml:"nameservers,omitempty"`
+	Routes      []networkConfigRoute      `yaml:"routes,omitempty"`
+}
+
+type networkConfigMatch struct {
+	MacAddress string `yaml:"macaddress"`
+}
+
+type networkConfigNameservers struct {
+	Addresses []string `yaml:"addresses,omitempty"`
+	Search    []string `yaml:"search,omitempty"`
+}
+
+type networkConfigRoute struct {
+	To     string `yaml:"to"`
+	Via    string `yaml:"via"`
+	Metric int64  `yaml:"metric,omitempty"`
+}
+
+func (d *cloudinitConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cloudinit_config"
+}
+
+func (d *cloudinitConfigDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	stringList := func(description string) schema.ListAttribute {
+		return schema.ListAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+			Description: description,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Renders cloud-init user-data and network-config documents, e.g. for upload as snippets.",
+		Attributes: map[string]schema.Attribute{
+			"hostname": schema.StringAttribute{
+				Optional: true,
+			},
+			"users": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required: true,
+						},
+						"groups": stringList("Supplementary groups of the user"),
+						"shell": schema.StringAttribute{
+							Optional: true,
+						},
+						"sudo": schema.StringAttribute{
+							Optional:    true,
+							Description: "Sudo rule of the user, e.g. ALL=(ALL) NOPASSWD:ALL",
+						},
+						"lock_passwd": schema.BoolAttribute{
+							Optional: true,
+						},
+						"ssh_authorized_keys": stringList("SSH public keys allowed to log in as the user"),
+					},
+				},
+			},
+			"package_update": schema.BoolAttribute{
+				Optional: true,
+			},
+			"packages": stringList("Packages to install on first boot"),
+			"runcmd":   stringList("Commands to run on first boot"),
+			"ethernets": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the interface, e.g. eth0",
+						},
+						"mac_address": schema.StringAttribute{
+							Optional:    true,
+							Description: "Match the interface by MAC address and rename it to name",
+						},
+						"dhcp4": schema.BoolAttribute{
+							Optional: true,
+						},
+						"dhcp6": schema.BoolAttribute{
+							Optional: true,
+						},
+						"addresses":      stringList("Static addresses in CIDR notation"),
+						"nameservers":    stringList("DNS servers"),
+						"search_domains": stringList("DNS search domains"),
+						"routes": schema.ListNestedAttribute{
+							Optional: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"to": schema.StringAttribute{
+										Required:    true,
+										Description: "Destination network in CIDR notation, or default",
+									},
+									"via": schema.StringAttribute{
+										Required: true,
+									},
+									"metric": schema.Int64Attribute{
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"user_data": schema.StringAttribute{
+				Computed:    true,
+				Description: "Rendered #cloud-config user-data",
+			},
+			"network_config": schema.StringAttribute{
+				Computed:    true,
+				Description: "Rendered version 2 network-config, empty when no ethernets are configured",
+			},
+		},
+	}
+}
+
+func (d *cloudinitConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state cloudinitConfigDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userData, err := renderUserData(state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Render Cloud-Init User Data",
+			err.Error(),
+		)
+		return
+	}
+
+	networkConfig, err := renderNetworkConfig(state.Ethernets)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Render Cloud-Init Network Config",
+			err.Error(),
+		)
+		return
+	}
+
+	state.UserData = types.StringValue(userData)
+	state.NetworkConfig = types.StringValue(networkConfig)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// renderUserData renders the #cloud-config user-data document of the model.
+func renderUserData(model cloudinitConfigDataSourceModel) (string, error) {
+	config := cloudConfig{
+		Hostname:      model.Hostname.ValueString(),
+		PackageUpdate: model.PackageUpdate.ValueBool(),
+		Packages:      stringValues(model.Packages),
+		Runcmd:        stringValues(model.Runcmd),
+	}
+
+	for _, user := range model.Users {
+		u := cloudConfigUser{
+			Name:              user.Name.ValueString(),
+			Shell:             user.Shell.ValueString(),
+			Sudo:              user.Sudo.ValueString(),
+			SSHAuthorizedKeys: stringValues(user.SSHAuthorizedKeys),
+		}
+		if groups := stringValues(user.Groups); len(groups) > 0 {
+			u.Groups = strings.Join(groups, ", ")
+		}
+		if !user.LockPasswd.IsNull() {
+			lock := user.LockPasswd.ValueBool()
+			u.LockPasswd = &lock
+		}
+		config.Users = append(config.Users, u)
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return "#cloud-config\n" + string(out), nil
+}
+
+// renderNetworkConfig renders a version 2 network-config document, or an
+// empty string when there are no interfaces.
+func renderNetworkConfig(ethernets []cloudinitEthernetModel) (string, error) {
+	if len(ethernets) == 0 {
+		return "", nil
+	}
+
+	config := networkConfig{
+		Version:   2,
+		Ethernets: map[string]networkConfigEthernet{},
+	}
+
+	for _, ethernet := range ethernets {
+		e := networkConfigEthernet{
+			DHCP4:     ethernet.DHCP4.ValueBool(),
+			DHCP6:     ethernet.DHCP6.ValueBool(),
+			Addresses: stringValues(ethernet.Addresses),
+		}
+		if !ethernet.MacAddress.IsNull() {
+			e.Match = &networkConfigMatch{MacAddress: strings.ToLower(ethernet.MacAddress.ValueString())}
+			e.SetName = ethernet.Name.ValueString()
+		}
+		if len(ethernet.Nameservers) > 0 || len(ethernet.SearchDomains) > 0 {
+			e.Nameservers = &networkConfigNameservers{
+				Addresses: stringValues(ethernet.Nameservers),
+				Search:    stringValues(ethernet.SearchDomains),
+			}
+		}
+		for _, route := range ethernet.Routes {
+			e.Routes = append(e.Routes, networkConfigRoute{
+				To:     route.To.ValueString(),
+				Via:    route.Via.ValueString(),
+				Metric: route.Metric.ValueInt64(),
+			})
+		}
+		config.Ethernets[ethernet.Name.ValueString()] = e
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// stringValues converts a list of framework strings into Go strings, skipping
+// null and unknown elements.
+func stringValues(values []types.String) []string {
+	var out []string
+	for _, value := range values {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		out = append(out, value.ValueString())
+	}
+
+	return out
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRenderUserData(t *testing.T) {
+	model := cloudinitConfigDataSourceModel{
+		Hostname: types.StringValue("web01"),
+		Users: []cloudinitUserModel{
+			{
+				Name:              types.StringValue("ops"),
+				Groups:            []types.String{types.StringValue("sudo"), types.StringValue("adm")},
+				LockPasswd:        types.BoolValue(true),
+				SSHAuthorizedKeys: []types.String{types.StringValue("ssh-ed25519 AAAA ops")},
+			},
+		},
+		Packages: []types.String{types.StringValue("qemu-guest-agent")},
+		Runcmd:   []types.String{types.StringValue("systemctl enable --now qemu-guest-agent")},
+	}
+
+	got, err := renderUserData(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `#cloud-config
+hostname: web01
+users:
+    - name: ops
+      groups: sudo, adm
+      lock_passwd: true
+      ssh_authorized_keys:
+        - ssh-ed25519 AAAA ops
+packages:
+    - qemu-guest-agent
+runcmd:
+    - systemctl enable --now qemu-guest-agent
+`
+	if got != want {
+		t.Errorf("unexpected user data:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderNetworkConfig(t *testing.T) {
+	got, err := renderNetworkConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("expected empty network config without ethernets, got %q", got)
+	}
+
+	got, err = renderNetworkConfig([]cloudinitEthernetModel{
+		{
+			Name:        types.StringValue("eth0"),
+			MacAddress:  types.StringValue("BC:24:11:00:00:01"),
+			Addresses:   []types.String{types.StringValue("10.0.0.10/24")},
+			Nameservers: []types.String{types.StringValue("10.0.0.1")},
+			Routes: []cloudinitRouteModel{
+				{To: types.StringValue("default"), Via: types.StringValue("10.0.0.1")},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `version: 2
+ethernets:
+    eth0:
+        match:
+            macaddress: bc:24:11:00:00:01
+        set-name: eth0
+        addresses:
+            - 10.0.0.10/24
+        nameservers:
+            addresses:
+                - 10.0.0.1
+        routes:
+            - to: default
+              via: 10.0.0.1
+`
+	if got != want {
+		t.Errorf("unexpected network config:\n%s\nwant:\n%s", got, want)
+	}
+}
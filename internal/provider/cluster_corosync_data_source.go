@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &clusterCorosyncDataSource{}
+	_ datasource.DataSourceWithConfigure = &clusterCorosyncDataSource{}
+)
+
+// corosyncLinkAddr matches the link address keys of a corosync node entry.
+var corosyncLinkAddr = regexp.MustCompile(`^ring(\d+)_addr$`)
+
+func NewClusterCorosyncDataSource() datasource.DataSource {
+	return &clusterCorosyncDataSource{}
+}
+
+type clusterCorosyncDataSource struct {
+	client *apiClient
+}
+
+type clusterCorosyncDataSourceModel struct {
+	ClusterName   types.String        `tfsdk:"cluster_name"`
+	ConfigVersion types.String        `tfsdk:"config_version"`
+	IPVersion     types.String        `tfsdk:"ip_version"`
+	LinkMode      types.String        `tfsdk:"link_mode"`
+	SecAuth       types.String        `tfsdk:"secauth"`
+	Links         types.List          `tfsdk:"links"`
+	Nodes         []corosyncNodeModel `tfsdk:"nodes"`
+}
+
+type corosyncNodeModel struct {
+	Name        types.String `tfsdk:"name"`
+	NodeID      types.String `tfsdk:"node_id"`
+	QuorumVotes types.String `tfsdk:"quorum_votes"`
+	Links       types.Map    `tfsdk:"links"`
+}
+
+func (d *clusterCorosyncDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *clusterCorosyncDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_corosync"
+}
+
+func (d *clusterCorosyncDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the corosync totem and link configuration of the cluster, so that network changes can be " +
+			"validated against the cluster interconnect before bridges are modified.",
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Computed: true,
+			},
+			"config_version": schema.StringAttribute{
+				Computed: true,
+			},
+			"ip_version": schema.StringAttribute{
+				Computed: true,
+			},
+			"link_mode": schema.StringAttribute{
+				Computed: true,
+			},
+			"secauth": schema.StringAttribute{
+				Computed: true,
+			},
+			"links": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Numbers of the configured corosync links",
+			},
+			"nodes": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"node_id": schema.StringAttribute{
+							Computed: true,
+						},
+						"quorum_votes": schema.StringAttribute{
+							Computed: true,
+						},
+						"links": schema.MapAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "Address of the node per link number",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// corosyncNodeLinks returns the link addresses of a corosync node entry,
+// keyed by link number.
+func corosyncNodeLinks(node map[string]interface{}) map[string]string {
+	links := map[string]string{}
+	for key, value := range node {
+		if match := corosyncLinkAddr.FindStringSubmatch(key); match != nil {
+			links[match[1]] = proxmoxtf.ConfigValue(value)
+		}
+	}
+
+	return links
+}
+
+func (d *clusterCorosyncDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logAPI, "Reading corosync totem configuration")
+	var totem map[string]interface{}
+	err := d.client.Get(ctx, "/cluster/config/totem", &totem)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Corosync Configuration",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, logAPI, "Reading corosync nodes")
+	var nodes []map[string]interface{}
+	err = d.client.Get(ctx, "/cluster/config/nodes", &nodes)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Corosync Nodes",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	totemValue := func(key string) types.String {
+		if value, ok := totem[key]; ok {
+			return types.StringValue(proxmoxtf.ConfigValue(value))
+		}
+		return types.StringNull()
+	}
+
+	state := clusterCorosyncDataSourceModel{
+		ClusterName:   totemValue("cluster_name"),
+		ConfigVersion: totemValue("config_version"),
+		IPVersion:     totemValue("ip_version"),
+		LinkMode:      totemValue("link_mode"),
+		SecAuth:       totemValue("secauth"),
+		Nodes:         []corosyncNodeModel{},
+	}
+
+	var links []string
+	if interfaces, ok := totem["interface"].(map[string]interface{}); ok {
+		for link := range interfaces {
+			links = append(links, link)
+		}
+	}
+	sort.Strings(links)
+	linksValue, diags := types.ListValueFrom(ctx, types.StringType, links)
+	resp.Diagnostics.Append(diags...)
+	state.Links = linksValue
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return proxmoxtf.ConfigValue(nodes[i]["name"]) < proxmoxtf.ConfigValue(nodes[j]["name"])
+	})
+	for _, node := range nodes {
+		nodeLinks, diags := types.MapValueFrom(ctx, types.StringType, corosyncNodeLinks(node))
+		resp.Diagnostics.Append(diags...)
+
+		state.Nodes = append(state.Nodes, corosyncNodeModel{
+			Name:        types.StringValue(proxmoxtf.ConfigValue(node["name"])),
+			NodeID:      types.StringValue(proxmoxtf.ConfigValue(node["nodeid"])),
+			QuorumVotes: types.StringValue(proxmoxtf.ConfigValue(node["quorum_votes"])),
+			Links:       nodeLinks,
+		})
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCorosyncNodeLinks(t *testing.T) {
+	node := map[string]interface{}{
+		"name":         "pve1",
+		"nodeid":       "1",
+		"quorum_votes": "1",
+		"ring0_addr":   "10.0.0.1",
+		"ring1_addr":   "10.1.0.1",
+	}
+
+	got := corosyncNodeLinks(node)
+	want := map[string]string{"0": "10.0.0.1", "1": "10.1.0.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
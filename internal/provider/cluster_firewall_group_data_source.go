@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+var (
+	_ datasource.DataSource              = &clusterFirewallGroupDataSource{}
+	_ datasource.DataSourceWithConfigure = &clusterFirewallGroupDataSource{}
+)
+
+func NewClusterFirewallGroupDataSource() datasource.DataSource {
+	return &clusterFirewallGroupDataSource{}
+}
+
+type clusterFirewallGroupDataSource struct {
+	client *apiClient
+}
+
+type clusterFirewallGroupDataSourceModel struct {
+	Group   types.String                   `tfsdk:"group"`
+	Comment types.String                   `tfsdk:"comment"`
+	Rules   []firewallGroupRuleDetailModel `tfsdk:"rules"`
+}
+
+type firewallGroupRuleDetailModel struct {
+	Pos      types.Int64  `tfsdk:"pos"`
+	Type     types.String `tfsdk:"type"`
+	Action   types.String `tfsdk:"action"`
+	Enabled  types.Bool   `tfsdk:"enabled"`
+	Macro    types.String `tfsdk:"macro"`
+	Source   types.String `tfsdk:"source"`
+	Dest     types.String `tfsdk:"dest"`
+	Proto    types.String `tfsdk:"proto"`
+	Sport    types.String `tfsdk:"sport"`
+	Dport    types.String `tfsdk:"dport"`
+	IcmpType types.String `tfsdk:"icmp_type"`
+	Iface    types.String `tfsdk:"iface"`
+	Log      types.String `tfsdk:"log"`
+	Comment  types.String `tfsdk:"comment"`
+}
+
+// firewallRuleDetails maps the rules of a group onto the model, ordered by
+// their position.
+func firewallRuleDetails(rules []pveapi.FirewallRule) []firewallGroupRuleDetailModel {
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Pos < rules[j].Pos })
+
+	details := []firewallGroupRuleDetailModel{}
+	for _, rule := range rules {
+		details = append(details, firewallGroupRuleDetailModel{
+			Pos:      types.Int64Value(int64(rule.Pos)),
+			Type:     types.StringValue(rule.Type),
+			Action:   types.StringValue(rule.Action),
+			Enabled:  proxmoxtf.Bool(rule.Enable),
+			Macro:    proxmoxtf.StringOrNull(rule.Macro),
+			Source:   proxmoxtf.StringOrNull(rule.Source),
+			Dest:     proxmoxtf.StringOrNull(rule.Dest),
+			Proto:    proxmoxtf.StringOrNull(rule.Proto),
+			Sport:    proxmoxtf.StringOrNull(rule.Sport),
+			Dport:    proxmoxtf.StringOrNull(rule.Dport),
+			IcmpType: proxmoxtf.StringOrNull(rule.IcmpType),
+			Iface:    proxmoxtf.StringOrNull(rule.Iface),
+			Log:      proxmoxtf.StringOrNull(rule.Log),
+			Comment:  proxmoxtf.StringOrNull(rule.Comment),
+		})
+	}
+
+	return details
+}
+
+func (d *clusterFirewallGroupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *clusterFirewallGroupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_firewall_group"
+}
+
+func (d *clusterFirewallGroupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the rules of an existing security group, e.g. to compose or audit groups managed by " +
+			"another team without importing them.",
+		Attributes: map[string]schema.Attribute{
+			"group": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the security group",
+			},
+			"comment": schema.StringAttribute{
+				Computed:    true,
+				Description: "Comment of the security group",
+			},
+			"rules": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Rules of the group in the order they are evaluated",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"pos": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Position of the rule in the group",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Direction of the rule, in or out",
+						},
+						"action": schema.StringAttribute{
+							Computed:    true,
+							Description: "Action of the rule, e.g. ACCEPT, DROP or REJECT",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the rule is enabled",
+						},
+						"macro": schema.StringAttribute{
+							Computed:    true,
+							Description: "Macro the rule uses, e.g. SSH",
+						},
+						"source": schema.StringAttribute{
+							Computed:    true,
+							Description: "Source address, range, alias or IP set",
+						},
+						"dest": schema.StringAttribute{
+							Computed:    true,
+							Description: "Destination address, range, alias or IP set",
+						},
+						"proto": schema.StringAttribute{
+							Computed:    true,
+							Description: "IP protocol, e.g. tcp",
+						},
+						"sport": schema.StringAttribute{
+							Computed:    true,
+							Description: "Source ports",
+						},
+						"dport": schema.StringAttribute{
+							Computed:    true,
+							Description: "Destination ports",
+						},
+						"icmp_type": schema.StringAttribute{
+							Computed:    true,
+							Description: "ICMP type for icmp and ipv6-icmp rules",
+						},
+						"iface": schema.StringAttribute{
+							Computed:    true,
+							Description: "Network interface the rule applies to",
+						},
+						"log": schema.StringAttribute{
+							Computed:    true,
+							Description: "Log level of the rule",
+						},
+						"comment": schema.StringAttribute{
+							Computed:    true,
+							Description: "Comment of the rule",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *clusterFirewallGroupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state clusterFirewallGroupDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logFirewall, "group", state.Group.ValueString())
+	tflog.SubsystemDebug(ctx, logFirewall, "Reading firewall group rules")
+
+	group, err := d.client.api.FirewallGroup(ctx, state.Group.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Firewall Group",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	rules, err := d.client.api.FirewallGroupRules(ctx, state.Group.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Firewall Group Rules",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Comment = types.StringValue(group.Comment)
+	state.Rules = firewallRuleDetails(rules)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"testing"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestFirewallRuleDetails(t *testing.T) {
+	details := firewallRuleDetails([]pveapi.FirewallRule{
+		{Pos: 1, Type: "in", Action: "DROP"},
+		{Pos: 0, Type: "in", Action: "ACCEPT", Enable: 1, Macro: "SSH", Source: "+admins"},
+	})
+
+	if len(details) != 2 {
+		t.Fatalf("got %d rules", len(details))
+	}
+	if details[0].Pos.ValueInt64() != 0 || details[0].Macro.ValueString() != "SSH" || !details[0].Enabled.ValueBool() {
+		t.Errorf("unexpected first rule %+v", details[0])
+	}
+	if details[1].Action.ValueString() != "DROP" || details[1].Enabled.ValueBool() || !details[1].Dport.IsNull() {
+		t.Errorf("unexpected second rule %+v", details[1])
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -24,13 +26,13 @@ func NewClusterFirewallGroupResource() resource.Resource {
 
 // clusterFirewallGroupResource is the resource implementation.
 type clusterFirewallGroupResource struct {
-	client *proxmox.Client
+	client *apiClient
 }
 
 // clusterFirewallGroupResourceModel maps the resource schema data.
 type clusterFirewallGroupResourceModel struct {
-	Group types.String `tfsdk:"group"`
-	//Comment       types.String                    `tfsdk:"comment"`
+	Group         types.String                    `tfsdk:"group"`
+	Comment       types.String                    `tfsdk:"comment"`
 	FirewallRules []clusterFirewallGroupRuleModel `tfsdk:"rules"`
 }
 
@@ -47,12 +49,12 @@ func (r *clusterFirewallGroupResource) Configure(_ context.Context, req resource
 		return
 	}
 
-	client, ok := req.ProviderData.(*proxmox.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *proxmox.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -73,11 +75,10 @@ func (r *clusterFirewallGroupResource) Schema(_ context.Context, _ resource.Sche
 			"group": schema.StringAttribute{
 				Required: true,
 			},
-			// TODO: The proxmox go API doesn't support comments
-			//"comment": schema.StringAttribute{
-			//Optional: true,
-			//Computed: true, // Allow switch from `nil` to `""`
-			//},
+			"comment": schema.StringAttribute{
+				Optional: true,
+				Computed: true, // Allow switch from `nil` to `""`
+			},
 			"rules": schema.ListNestedAttribute{
 				Optional: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -95,22 +96,64 @@ func (r *clusterFirewallGroupResource) Schema(_ context.Context, _ resource.Sche
 	}
 }
 
+// setComment sets the comment of the group in plan. go-proxmox neither sends
+// nor returns group comments, so they go through the pveapi client.
+func (r *clusterFirewallGroupResource) setComment(ctx context.Context, plan clusterFirewallGroupResourceModel) error {
+	if plan.Comment.IsNull() || plan.Comment.IsUnknown() {
+		return nil
+	}
+
+	return r.client.api.CreateFirewallGroup(ctx, pveapi.FirewallGroupRequest{
+		Group:   plan.Group.ValueString(),
+		Rename:  plan.Group.ValueString(),
+		Comment: plan.Comment.ValueString(),
+	})
+}
+
+// readComment returns the comment of a group.
+func (r *clusterFirewallGroupResource) readComment(ctx context.Context, group string) (types.String, error) {
+	fwGroup, err := r.client.api.FirewallGroup(ctx, group)
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	return types.StringValue(fwGroup.Comment), nil
+}
+
+// readAfterWrite reads the group in model back together with its comment
+// after it was written, retrying while it has not propagated yet.
+func (r *clusterFirewallGroupResource) readAfterWrite(ctx context.Context, cluster *proxmox.Cluster, model *clusterFirewallGroupResourceModel) (*proxmox.FirewallSecurityGroup, error) {
+	return readAfterWrite(ctx, "", func(ctx context.Context) (*proxmox.FirewallSecurityGroup, string, error) {
+		fwGroup, err := cluster.FWGroup(ctx, model.Group.ValueString())
+		if err != nil {
+			return nil, "", err
+		}
+
+		model.Comment, err = r.readComment(ctx, model.Group.ValueString())
+		return fwGroup, "", err
+	})
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *clusterFirewallGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
 	// Retrieve values from plan
 	var plan clusterFirewallGroupResourceModel
-	tflog.Info(ctx, "Getting data from plan for proxmox_cluster_firewall_group")
+	tflog.SubsystemDebug(ctx, logFirewall, "Getting data from plan for proxmox_cluster_firewall_group")
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx = tflog.SubsystemSetField(ctx, logFirewall, "group", plan.Group.ValueString())
+
 	cluster, err := r.client.Cluster(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Proxmox Cluster",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
@@ -123,38 +166,35 @@ func (r *clusterFirewallGroupResource) Create(ctx context.Context, req resource.
 		})
 	}
 
-	//comment := "" // TODO: Move defaults somewhere else
-	//if !plan.Comment.IsNull() {
-	//comment = plan.Comment.ValueString()
-	//}
-
 	fwGroupN := proxmox.FirewallSecurityGroup{
 		Group: plan.Group.ValueString(),
-		//Comment: comment,
 		Rules: rules,
 	}
 
+	tflog.SubsystemInfo(ctx, logFirewall, "Creating cluster firewall group")
 	err = cluster.NewFWGroup(ctx, &fwGroupN)
+	if err == nil {
+		err = r.setComment(ctx, plan)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create Proxmox Cluster Firewall Group",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	tflog.Info(ctx, "Retrieving latest status on firewall group")
-	fwGroup, err := cluster.FWGroup(ctx, fwGroupN.Group)
+	tflog.SubsystemDebug(ctx, logFirewall, "Retrieving latest status on firewall group")
+	fwGroup, err := r.readAfterWrite(ctx, cluster, &plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to retrieve Proxmox Cluster Firewall Group",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
 	plan.Group = types.StringValue(fwGroup.Group)
-	//plan.Comment = types.StringValue(fwGroup.Comment)
 	for index, rule := range fwGroup.Rules {
 		plan.FirewallRules[index] = clusterFirewallGroupRuleModel{
 			Action: types.StringValue(rule.Action),
@@ -172,6 +212,7 @@ func (r *clusterFirewallGroupResource) Create(ctx context.Context, req resource.
 
 // Read refreshes the Terraform state with the latest data.
 func (r *clusterFirewallGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
 
 	// Get current state
 	var state clusterFirewallGroupResourceModel
@@ -185,22 +226,24 @@ func (r *clusterFirewallGroupResource) Read(ctx context.Context, req resource.Re
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Proxmox Cluster",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
 	fwGroup, err := cluster.FWGroup(ctx, state.Group.ValueString())
+	if err == nil {
+		state.Comment, err = r.readComment(ctx, state.Group.ValueString())
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to retrieve Proxmox Cluster Firewall Group",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
 	state.Group = types.StringValue(fwGroup.Group)
-	//state.Comment = types.StringValue(fwGroup.Comment)
 	for index, rule := range fwGroup.Rules {
 		state.FirewallRules[index] = clusterFirewallGroupRuleModel{
 			Action: types.StringValue(rule.Action),
@@ -219,6 +262,8 @@ func (r *clusterFirewallGroupResource) Read(ctx context.Context, req resource.Re
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *clusterFirewallGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
 	// Retrieve values from plan
 	var plan clusterFirewallGroupResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -227,24 +272,26 @@ func (r *clusterFirewallGroupResource) Update(ctx context.Context, req resource.
 		return
 	}
 
+	ctx = tflog.SubsystemSetField(ctx, logFirewall, "group", plan.Group.ValueString())
+
 	cluster, err := r.client.Cluster(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Proxmox Cluster",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	tflog.Info(ctx, "Creating request body value")
+	tflog.SubsystemDebug(ctx, logFirewall, "Creating request body value")
 
 	// TODO: Update existing firewall group instead of recreating it
-	tflog.Info(ctx, "Fetching latest state from Proxmox")
+	tflog.SubsystemInfo(ctx, logFirewall, "Recreating cluster firewall group")
 	fwGroup, err := cluster.FWGroup(ctx, plan.Group.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to retrieve Proxmox Cluster Firewall Group",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
@@ -252,7 +299,7 @@ func (r *clusterFirewallGroupResource) Update(ctx context.Context, req resource.
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Proxmox Cluster",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
@@ -266,40 +313,36 @@ func (r *clusterFirewallGroupResource) Update(ctx context.Context, req resource.
 		})
 	}
 
-	//comment := "" // TODO: Move defaults somewhere else
-	//if !plan.Comment.IsNull() {
-	//comment = plan.Comment.ValueString()
-	//}
-
 	fwGroupN := proxmox.FirewallSecurityGroup{
 		Group: plan.Group.ValueString(),
-		//Comment: comment,
 		Rules: rules,
 	}
 
 	err = cluster.NewFWGroup(ctx, &fwGroupN)
+	if err == nil {
+		err = r.setComment(ctx, plan)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create Proxmox Cluster Firewall Group",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	tflog.Info(ctx, "Fetching latest state from Proxmox")
-	fwGroup, err = cluster.FWGroup(ctx, fwGroupN.Group)
+	tflog.SubsystemDebug(ctx, logFirewall, "Fetching latest state from Proxmox")
+	fwGroup, err = r.readAfterWrite(ctx, cluster, &plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to retrieve Proxmox Cluster Firewall Group",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	tflog.Info(ctx, "Overwriting local state using response")
+	tflog.SubsystemDebug(ctx, logFirewall, "Overwriting local state using response")
 
 	plan.Group = types.StringValue(fwGroup.Group)
-	//plan.Comment = types.StringValue(fwGroup.Comment)
 	for index, rule := range fwGroup.Rules {
 		plan.FirewallRules[index] = clusterFirewallGroupRuleModel{
 			Action: types.StringValue(rule.Action),
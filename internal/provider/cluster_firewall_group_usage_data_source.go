@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+var (
+	_ datasource.DataSource              = &clusterFirewallGroupUsageDataSource{}
+	_ datasource.DataSourceWithConfigure = &clusterFirewallGroupUsageDataSource{}
+)
+
+func NewClusterFirewallGroupUsageDataSource() datasource.DataSource {
+	return &clusterFirewallGroupUsageDataSource{}
+}
+
+type clusterFirewallGroupUsageDataSource struct {
+	client *apiClient
+}
+
+type clusterFirewallGroupUsageDataSourceModel struct {
+	Group   types.String                 `tfsdk:"group"`
+	Cluster types.Bool                   `tfsdk:"cluster"`
+	Nodes   types.List                   `tfsdk:"nodes"`
+	Guests  []firewallGroupGuestUseModel `tfsdk:"guests"`
+	InUse   types.Bool                   `tfsdk:"in_use"`
+}
+
+type firewallGroupGuestUseModel struct {
+	Node types.String `tfsdk:"node"`
+	VMID types.Int64  `tfsdk:"vm_id"`
+	Type types.String `tfsdk:"type"`
+}
+
+func (d *clusterFirewallGroupUsageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *clusterFirewallGroupUsageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_firewall_group_usage"
+}
+
+func (d *clusterFirewallGroupUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports which firewall rule sets reference a cluster firewall security group, " +
+			"e.g. to refuse deleting a group that is still in use.",
+		Attributes: map[string]schema.Attribute{
+			"group": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the security group",
+			},
+			"cluster": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the cluster firewall rules reference the group",
+			},
+			"nodes": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Nodes whose firewall rules reference the group",
+			},
+			"guests": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Guests whose firewall rules reference the group",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"node": schema.StringAttribute{
+							Computed: true,
+						},
+						"vm_id": schema.Int64Attribute{
+							Computed: true,
+						},
+						"type": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"in_use": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether any rule set references the group",
+			},
+		},
+	}
+}
+
+// rulesReferenceGroup reports whether a rule set inserts the security group.
+func rulesReferenceGroup(rules []*proxmox.FirewallRule, group string) bool {
+	for _, rule := range rules {
+		if rule.Type == "group" && rule.Action == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+// referencesGroup reads the firewall rules at path and reports whether they
+// insert the security group.
+func (d *clusterFirewallGroupUsageDataSource) referencesGroup(ctx context.Context, path, group string) (bool, error) {
+	var rules []*proxmox.FirewallRule
+	if err := d.client.Get(ctx, path+"/firewall/rules", &rules); err != nil {
+		return false, err
+	}
+
+	return rulesReferenceGroup(rules, group), nil
+}
+
+func (d *clusterFirewallGroupUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state clusterFirewallGroupUsageDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	group := state.Group.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logFirewall, "group", group)
+
+	inCluster, err := d.referencesGroup(ctx, "/cluster", group)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Firewall Rules",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	cluster, err := d.client.Cluster(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	resources, err := cluster.Resources(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Resources",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	nodes := []string{}
+	state.Guests = []firewallGroupGuestUseModel{}
+	for _, res := range resources {
+		var path string
+		switch res.Type {
+		case "node":
+			path = fmt.Sprintf("/nodes/%s", res.Node)
+		case "qemu", "lxc":
+			path = guestPath(res.Node, res.Type, int64(res.VMID))
+		default:
+			continue
+		}
+
+		tflog.SubsystemDebug(ctx, logFirewall, "Reading firewall rules", map[string]interface{}{
+			"path": path,
+		})
+		used, err := d.referencesGroup(ctx, path, group)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Proxmox Firewall Rules",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		if !used {
+			continue
+		}
+
+		if res.Type == "node" {
+			nodes = append(nodes, res.Node)
+		} else {
+			state.Guests = append(state.Guests, firewallGroupGuestUseModel{
+				Node: types.StringValue(res.Node),
+				VMID: types.Int64Value(int64(res.VMID)),
+				Type: types.StringValue(res.Type),
+			})
+		}
+	}
+
+	nodesValue, diags := types.ListValueFrom(ctx, types.StringType, nodes)
+	resp.Diagnostics.Append(diags...)
+	state.Nodes = nodesValue
+	state.Cluster = types.BoolValue(inCluster)
+	state.InUse = types.BoolValue(inCluster || len(nodes) > 0 || len(state.Guests) > 0)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,21 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/luthermonson/go-proxmox"
+)
+
+func TestRulesReferenceGroup(t *testing.T) {
+	rules := []*proxmox.FirewallRule{
+		{Type: "in", Action: "ACCEPT"},
+		{Type: "group", Action: "webservers"},
+	}
+
+	if !rulesReferenceGroup(rules, "webservers") {
+		t.Error("expected webservers to be referenced")
+	}
+	if rulesReferenceGroup(rules, "ACCEPT") {
+		t.Error("expected actions of regular rules not to match")
+	}
+}
@@ -0,0 +1,335 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &clusterInitResource{}
+	_ resource.ResourceWithConfigure = &clusterInitResource{}
+)
+
+// clusterNamePattern matches the cluster names accepted by Proxmox VE.
+var clusterNamePattern = regexp.MustCompile(`^[A-Za-z0-9-]{1,15}$`)
+
+// NewClusterInitResource is a helper function to simplify the provider implementation.
+func NewClusterInitResource() resource.Resource {
+	return &clusterInitResource{}
+}
+
+// clusterInitResource creates a cluster on the node the provider is
+// connected to.
+type clusterInitResource struct {
+	client *apiClient
+}
+
+// clusterInitResourceModel maps the resource schema data.
+type clusterInitResourceModel struct {
+	ClusterName types.String `tfsdk:"cluster_name"`
+	Links       types.List   `tfsdk:"links"`
+	NodeID      types.Int64  `tfsdk:"node_id"`
+	Votes       types.Int64  `tfsdk:"votes"`
+	Timeout     types.String `tfsdk:"timeout"`
+	Node        types.String `tfsdk:"node"`
+	Address     types.String `tfsdk:"address"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *clusterInitResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *clusterInitResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_init"
+}
+
+// Schema defines the schema for the resource.
+func (r *clusterInitResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates a cluster on the standalone node the provider is connected to, the first node of the " +
+			"cluster. Further nodes join with proxmox_cluster_join using the address and fingerprint of this resource. " +
+			"Destroying the resource does not dissolve the cluster.",
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the cluster, up to 15 letters, digits and dashes",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(clusterNamePattern, "must be up to 15 letters, digits and dashes"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"links": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Corosync link addresses of the node, link0 first. Defaults to the address of the node",
+				Validators: []validator.List{
+					listvalidator.SizeBetween(1, 8),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"node_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Corosync node ID of the node. Defaults to 1",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"votes": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of quorum votes of the node. Defaults to 1",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("5m"),
+				Description: "Maximum time to wait for the cluster to become quorate as a Go duration. Defaults to 5m",
+			},
+			"node": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the first node",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"address": schema.StringAttribute{
+				Computed:    true,
+				Description: "Address of the first node, the peer_address to join the cluster",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"fingerprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "Fingerprint of the TLS certificate of the first node, the fingerprint to join the cluster",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// joinMember returns the member named node in the join information.
+func joinMember(info *pveapi.ClusterJoinInfo, node string) (pveapi.ClusterJoinMember, bool) {
+	for _, member := range info.Nodes {
+		if member.Name == node {
+			return member, true
+		}
+	}
+
+	return pveapi.ClusterJoinMember{}, false
+}
+
+// refresh sets the cluster name and the join information of the node.
+func (r *clusterInitResource) refresh(ctx context.Context, model *clusterInitResourceModel, cluster, node string) error {
+	info, err := r.client.api.ClusterJoinInfo(ctx)
+	if err != nil {
+		return err
+	}
+	member, ok := joinMember(info, node)
+	if !ok {
+		return fmt.Errorf("node %s is missing in the join information of cluster %s", node, cluster)
+	}
+
+	model.ClusterName = types.StringValue(cluster)
+	model.Node = types.StringValue(node)
+	model.Address = types.StringValue(member.Address)
+	model.Fingerprint = types.StringValue(member.Fingerprint)
+
+	return nil
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *clusterInitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan clusterInitResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, err := time.ParseDuration(plan.Timeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			err.Error(),
+		)
+		return
+	}
+
+	var links []string
+	resp.Diagnostics.Append(plan.Links.ElementsAs(ctx, &links, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Creating cluster", map[string]interface{}{
+		"cluster": plan.ClusterName.ValueString(),
+	})
+	upid, err := r.client.api.CreateCluster(ctx, pveapi.ClusterCreateRequest{
+		ClusterName: plan.ClusterName.ValueString(),
+		Links:       links,
+		NodeID:      plan.NodeID.ValueInt64(),
+		Votes:       plan.Votes.ValueInt64(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox Cluster",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), timeout)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox Cluster",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	cluster, node, err := r.client.waitForClusterMember(ctx, timeout)
+	if err == nil {
+		err = r.refresh(ctx, &plan, cluster, node)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Proxmox Cluster Did Not Become Quorate",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *clusterInitResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state clusterInitResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var status []clusterStatusEntry
+	if err := r.client.Get(ctx, "/cluster/status", &status); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Status",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// A standalone node has no cluster to manage.
+	cluster, node, _ := clusterMembership(status)
+	if cluster == "" {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err := r.refresh(ctx, &state, cluster, node); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Join Information",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *clusterInitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only timeout can change in place, it is only used on creation.
+	var plan clusterInitResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *clusterInitResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state clusterInitResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API cannot dissolve a cluster, it stays in place.
+	resp.Diagnostics.AddWarning(
+		"Proxmox Cluster Not Dissolved",
+		fmt.Sprintf("Cluster %s still exists. Separating its nodes requires stopping the cluster services on each node, see the Proxmox VE documentation.",
+			state.ClusterName.ValueString()),
+	)
+}
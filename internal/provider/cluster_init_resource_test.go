@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestJoinMember(t *testing.T) {
+	info := &pveapi.ClusterJoinInfo{
+		PreferredNode: "pve1",
+		Nodes: []pveapi.ClusterJoinMember{
+			{Name: "pve1", Address: "10.0.0.1", Fingerprint: "AA:BB"},
+			{Name: "pve2", Address: "10.0.0.2", Fingerprint: "CC:DD"},
+		},
+	}
+
+	member, ok := joinMember(info, "pve2")
+	if !ok || member.Address != "10.0.0.2" || member.Fingerprint != "CC:DD" {
+		t.Errorf("got %+v, %t", member, ok)
+	}
+
+	if _, ok := joinMember(info, "pve3"); ok {
+		t.Error("expected no member for pve3")
+	}
+}
+
+func TestClusterNamePattern(t *testing.T) {
+	for name, want := range map[string]bool{
+		"lab":              true,
+		"prod-01":          true,
+		"lab_1":            false,
+		"a-very-long-name": false,
+		"":                 false,
+	} {
+		if got := clusterNamePattern.MatchString(name); got != want {
+			t.Errorf("%q: got %t, want %t", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &clusterJoinResource{}
+	_ resource.ResourceWithConfigure = &clusterJoinResource{}
+)
+
+// NewClusterJoinResource is a helper function to simplify the provider implementation.
+func NewClusterJoinResource() resource.Resource {
+	return &clusterJoinResource{}
+}
+
+// clusterJoinResource joins the node the provider is connected to to an
+// existing cluster.
+type clusterJoinResource struct {
+	client *apiClient
+}
+
+// clusterJoinResourceModel maps the resource schema data.
+type clusterJoinResourceModel struct {
+	PeerAddress types.String `tfsdk:"peer_address"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+	Password    types.String `tfsdk:"password"`
+	Links       types.List   `tfsdk:"links"`
+	NodeID      types.Int64  `tfsdk:"node_id"`
+	Votes       types.Int64  `tfsdk:"votes"`
+	Force       types.Bool   `tfsdk:"force"`
+	Timeout     types.String `tfsdk:"timeout"`
+	Node        types.String `tfsdk:"node"`
+	ClusterName types.String `tfsdk:"cluster_name"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *clusterJoinResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *clusterJoinResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_join"
+}
+
+// Schema defines the schema for the resource.
+func (r *clusterJoinResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Joins the standalone node the provider is connected to to an existing cluster and waits until " +
+			"it is a quorate member. Use a provider alias for the new node. The provider must log in with the root " +
+			"password and without TOTP, as joining replaces the key that signs session tickets. Destroying the resource " +
+			"does not remove the node from the cluster, which requires `pvecm delnode` on a remaining member.",
+		Attributes: map[string]schema.Attribute{
+			"peer_address": schema.StringAttribute{
+				Required:    true,
+				Description: "Address of a member of the cluster to join",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"fingerprint": schema.StringAttribute{
+				Required:    true,
+				Description: "SHA-256 fingerprint of the TLS certificate of the peer",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password of root@pam on the peer. Only used to join",
+			},
+			"links": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Corosync link addresses of the node, link0 first. Defaults to the address of the node",
+				Validators: []validator.List{
+					listvalidator.SizeBetween(1, 8),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"node_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Corosync node ID of the node. Defaults to the next free ID",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"votes": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of quorum votes of the node. Defaults to 1",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"force": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Join even if the node has guests or a node of the same name is already a member. Defaults to false",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("10m"),
+				Description: "Maximum time to wait for the node to join as a Go duration. Defaults to 10m",
+			},
+			"node": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the node that joined",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cluster_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the cluster the node joined",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// clusterMembership returns the cluster and local node in the cluster status
+// and whether the local node is an online member of a quorate cluster. The
+// cluster name is empty for a standalone node.
+func clusterMembership(status []clusterStatusEntry) (cluster, node string, joined bool) {
+	var quorate, online bool
+	for _, entry := range status {
+		switch {
+		case entry.Type == "cluster":
+			cluster, quorate = entry.Name, entry.Quorate == 1
+		case entry.Type == "node" && entry.Local == 1:
+			node, online = entry.Name, entry.Online == 1
+		}
+	}
+
+	return cluster, node, cluster != "" && quorate && online
+}
+
+// waitForClusterMember polls the cluster status until the node is an online
+// member of a quorate cluster or the timeout expired. Errors are retried, as
+// the API of the node is unavailable while it restarts its cluster services.
+func (c *apiClient) waitForClusterMember(ctx context.Context, timeout time.Duration) (cluster, node string, err error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var status []clusterStatusEntry
+		err = c.Get(ctx, "/cluster/status", &status)
+		if err == nil {
+			var joined bool
+			if cluster, node, joined = clusterMembership(status); joined {
+				return cluster, node, nil
+			}
+		} else {
+			tflog.SubsystemDebug(ctx, logAPI, "Cluster status unavailable", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return "", "", fmt.Errorf("node is not a cluster member after %s: %w", timeout, err)
+			}
+			return "", "", fmt.Errorf("node is not a member of a quorate cluster after %s", timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *clusterJoinResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan clusterJoinResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, err := time.ParseDuration(plan.Timeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			err.Error(),
+		)
+		return
+	}
+
+	var links []string
+	resp.Diagnostics.Append(plan.Links.ElementsAs(ctx, &links, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Joining cluster", map[string]interface{}{
+		"peer": plan.PeerAddress.ValueString(),
+	})
+	upid, err := r.client.api.JoinCluster(ctx, pveapi.ClusterJoinRequest{
+		Hostname:    plan.PeerAddress.ValueString(),
+		Fingerprint: plan.Fingerprint.ValueString(),
+		Password:    plan.Password.ValueString(),
+		Links:       links,
+		NodeID:      plan.NodeID.ValueInt64(),
+		Votes:       plan.Votes.ValueInt64(),
+		Force:       plan.Force.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Join Proxmox Cluster",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// The task fails early on wrong credentials or a conflicting node. Once
+	// the cluster services restart, its status may be unavailable for a
+	// while, so only a failed task is an error here.
+	task, err := r.client.waitForTask(ctx, proxmox.UPID(upid), timeout)
+	if err != nil {
+		if task != nil && task.IsFailed {
+			resp.Diagnostics.AddError(
+				"Unable to Join Proxmox Cluster",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		tflog.SubsystemDebug(ctx, logTask, "Join task status unavailable", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	cluster, node, err := r.client.waitForClusterMember(ctx, timeout)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Proxmox Node Did Not Join Cluster",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	plan.ClusterName = types.StringValue(cluster)
+	plan.Node = types.StringValue(node)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *clusterJoinResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state clusterJoinResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var status []clusterStatusEntry
+	if err := r.client.Get(ctx, "/cluster/status", &status); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Status",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// A node that left the cluster is standalone again and joins anew.
+	cluster, node, _ := clusterMembership(status)
+	if cluster == "" {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.ClusterName = types.StringValue(cluster)
+	state.Node = types.StringValue(node)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *clusterJoinResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only password and timeout can change in place, they are only used
+	// when joining.
+	var plan clusterJoinResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *clusterJoinResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state clusterJoinResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API cannot remove a node from a cluster, the node stays a member.
+	resp.Diagnostics.AddWarning(
+		"Proxmox Node Not Removed From Cluster",
+		fmt.Sprintf("Node %s is still a member of cluster %s. Shut it down and run `pvecm delnode %s` on a remaining member to remove it.",
+			state.Node.ValueString(), state.ClusterName.ValueString(), state.Node.ValueString()),
+	)
+}
@@ -0,0 +1,46 @@
+package provider
+
+import "testing"
+
+func TestClusterMembership(t *testing.T) {
+	tests := map[string]struct {
+		status                []clusterStatusEntry
+		wantCluster, wantNode string
+		wantJoined            bool
+	}{
+		"standalone": {
+			status: []clusterStatusEntry{
+				{Type: "node", Name: "pve2", Local: 1, Online: 1},
+			},
+			wantNode: "pve2",
+		},
+		"joining": {
+			status: []clusterStatusEntry{
+				{Type: "cluster", Name: "lab", Quorate: 0},
+				{Type: "node", Name: "pve1", Online: 1},
+				{Type: "node", Name: "pve2", Local: 1, Online: 1},
+			},
+			wantCluster: "lab",
+			wantNode:    "pve2",
+		},
+		"joined": {
+			status: []clusterStatusEntry{
+				{Type: "cluster", Name: "lab", Quorate: 1},
+				{Type: "node", Name: "pve1", Online: 1},
+				{Type: "node", Name: "pve2", Local: 1, Online: 1},
+			},
+			wantCluster: "lab",
+			wantNode:    "pve2",
+			wantJoined:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cluster, node, joined := clusterMembership(tc.status)
+			if cluster != tc.wantCluster || node != tc.wantNode || joined != tc.wantJoined {
+				t.Errorf("got %q, %q, %t, want %q, %q, %t", cluster, node, joined, tc.wantCluster, tc.wantNode, tc.wantJoined)
+			}
+		})
+	}
+}
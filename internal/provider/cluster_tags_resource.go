@@ -0,0 +1,391 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &clusterTagsResource{}
+	_ resource.ResourceWithConfigure = &clusterTagsResource{}
+)
+
+// tagColor matches a tag color, a background color optionally followed by
+// a text color, both as hex RGB values.
+var tagColor = regexp.MustCompile(`^[0-9a-fA-F]{6}(:[0-9a-fA-F]{6})?$`)
+
+// NewClusterTagsResource is a helper function to simplify the provider implementation.
+func NewClusterTagsResource() resource.Resource {
+	return &clusterTagsResource{}
+}
+
+// clusterTagsResource manages the tag settings of the datacenter.
+type clusterTagsResource struct {
+	client *apiClient
+}
+
+// clusterTagsResourceModel maps the resource schema data.
+type clusterTagsResourceModel struct {
+	RegisteredTags types.Set    `tfsdk:"registered_tags"`
+	ColorMap       types.Map    `tfsdk:"color_map"`
+	Ordering       types.String `tfsdk:"ordering"`
+	UserAllow      types.String `tfsdk:"user_allow"`
+	UserAllowList  types.Set    `tfsdk:"user_allow_list"`
+}
+
+// clusterTagSettings holds the tag settings of the datacenter.
+type clusterTagSettings struct {
+	RegisteredTags []string
+	ColorMap       map[string]string
+	Ordering       string
+	UserAllow      string
+	UserAllowList  []string
+}
+
+// clusterTagOptions is the API representation of the tag related
+// datacenter options.
+type clusterTagOptions struct {
+	RegisteredTags []string `json:"registered-tags"`
+	TagStyle       *struct {
+		ColorMap string `json:"color-map"`
+		Ordering string `json:"ordering"`
+	} `json:"tag-style"`
+	UserTagAccess *struct {
+		UserAllow     string   `json:"user-allow"`
+		UserAllowList []string `json:"user-allow-list"`
+	} `json:"user-tag-access"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *clusterTagsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *clusterTagsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_tags"
+}
+
+// Schema defines the schema for the resource.
+func (r *clusterTagsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the registered tags, the tag style and the tag access of the datacenter. " +
+			"Only one instance should exist per cluster, destroying the resource resets the settings.",
+		Attributes: map[string]schema.Attribute{
+			"registered_tags": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Tags that only users with Sys.Modify on / may set or remove",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"color_map": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Colors of the tags, keyed by tag. Each color is a hex background color, optionally followed by `:` and a hex text color, e.g. `ff0000:ffffff`",
+				Validators: []validator.Map{
+					mapvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(tagColor, "must be a hex color, optionally followed by a colon and a hex text color"),
+					),
+				},
+			},
+			"ordering": schema.StringAttribute{
+				Optional:    true,
+				Description: "Order of the tags in the web UI, either config or alphabetical",
+				Validators: []validator.String{
+					stringvalidator.OneOf("config", "alphabetical"),
+				},
+			},
+			"user_allow": schema.StringAttribute{
+				Optional:    true,
+				Description: "Tags users may set on guests they can configure: none, list, existing or free",
+				Validators: []validator.String{
+					stringvalidator.OneOf("none", "list", "existing", "free"),
+				},
+			},
+			"user_allow_list": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Tags users may always set, in addition to the ones allowed by user_allow",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+// colorMapString formats the color map the way it is stored in the tag style.
+func colorMapString(colorMap map[string]string) string {
+	tags := make([]string, 0, len(colorMap))
+	for tag := range colorMap {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	entries := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		entries = append(entries, tag+":"+colorMap[tag])
+	}
+
+	return strings.Join(entries, ";")
+}
+
+// parseColorMap parses the color map of the tag style.
+func parseColorMap(value string) map[string]string {
+	colorMap := map[string]string{}
+	for _, entry := range strings.Split(value, ";") {
+		tag, color, ok := strings.Cut(entry, ":")
+		if !ok || tag == "" {
+			continue
+		}
+		colorMap[tag] = color
+	}
+
+	return colorMap
+}
+
+// clusterTagParams maps the settings onto the parameters of the datacenter
+// options update. Unset options are removed through the `delete` parameter.
+func clusterTagParams(settings clusterTagSettings) map[string]interface{} {
+	params := map[string]interface{}{}
+
+	var remove []string
+	if len(settings.RegisteredTags) == 0 {
+		remove = append(remove, "registered-tags")
+	} else {
+		params["registered-tags"] = proxmoxtf.FormatTags(settings.RegisteredTags)
+	}
+
+	var style []string
+	if len(settings.ColorMap) > 0 {
+		style = append(style, "color-map="+colorMapString(settings.ColorMap))
+	}
+	if settings.Ordering != "" {
+		style = append(style, "ordering="+settings.Ordering)
+	}
+	if len(style) == 0 {
+		remove = append(remove, "tag-style")
+	} else {
+		params["tag-style"] = strings.Join(style, ",")
+	}
+
+	var access []string
+	if settings.UserAllow != "" {
+		access = append(access, "user-allow="+settings.UserAllow)
+	}
+	if len(settings.UserAllowList) > 0 {
+		access = append(access, "user-allow-list="+proxmoxtf.FormatTags(settings.UserAllowList))
+	}
+	if len(access) == 0 {
+		remove = append(remove, "user-tag-access")
+	} else {
+		params["user-tag-access"] = strings.Join(access, ",")
+	}
+
+	if len(remove) > 0 {
+		params["delete"] = strings.Join(remove, ",")
+	}
+
+	return params
+}
+
+// settings returns the tag settings of the model.
+func (m clusterTagsResourceModel) settings(ctx context.Context) (clusterTagSettings, diag.Diagnostics) {
+	var settings clusterTagSettings
+	var diags diag.Diagnostics
+
+	diags.Append(m.RegisteredTags.ElementsAs(ctx, &settings.RegisteredTags, false)...)
+	diags.Append(m.ColorMap.ElementsAs(ctx, &settings.ColorMap, false)...)
+	diags.Append(m.UserAllowList.ElementsAs(ctx, &settings.UserAllowList, false)...)
+	settings.Ordering = m.Ordering.ValueString()
+	settings.UserAllow = m.UserAllow.ValueString()
+
+	return settings, diags
+}
+
+// readClusterTags refreshes the model from the datacenter options.
+func (r *clusterTagsResource) readClusterTags(ctx context.Context, model *clusterTagsResourceModel) error {
+	var options clusterTagOptions
+	if err := r.client.Get(ctx, "/cluster/options", &options); err != nil {
+		return err
+	}
+
+	model.RegisteredTags = types.SetNull(types.StringType)
+	if len(options.RegisteredTags) > 0 {
+		model.RegisteredTags, _ = types.SetValueFrom(ctx, types.StringType, options.RegisteredTags)
+	}
+
+	model.ColorMap = types.MapNull(types.StringType)
+	model.Ordering = types.StringNull()
+	if options.TagStyle != nil {
+		if options.TagStyle.ColorMap != "" {
+			model.ColorMap, _ = types.MapValueFrom(ctx, types.StringType, parseColorMap(options.TagStyle.ColorMap))
+		}
+		if options.TagStyle.Ordering != "" {
+			model.Ordering = types.StringValue(options.TagStyle.Ordering)
+		}
+	}
+
+	model.UserAllow = types.StringNull()
+	model.UserAllowList = types.SetNull(types.StringType)
+	if options.UserTagAccess != nil {
+		if options.UserTagAccess.UserAllow != "" {
+			model.UserAllow = types.StringValue(options.UserTagAccess.UserAllow)
+		}
+		if len(options.UserTagAccess.UserAllowList) > 0 {
+			model.UserAllowList, _ = types.SetValueFrom(ctx, types.StringType, options.UserTagAccess.UserAllowList)
+		}
+	}
+
+	return nil
+}
+
+// apply writes the planned settings.
+func (r *clusterTagsResource) apply(ctx context.Context, settings clusterTagSettings) error {
+	tflog.SubsystemInfo(ctx, logAPI, "Updating datacenter tag settings")
+
+	return r.client.Put(ctx, "/cluster/options", clusterTagParams(settings), nil)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *clusterTagsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan clusterTagsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, diags := plan.settings(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Tag Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *clusterTagsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state clusterTagsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.readClusterTags(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Tag Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *clusterTagsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan clusterTagsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, diags := plan.settings(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Tag Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *clusterTagsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	tflog.SubsystemInfo(ctx, logAPI, "Resetting datacenter tag settings")
+	err := r.client.Put(ctx, "/cluster/options", clusterTagParams(clusterTagSettings{}), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Reset Proxmox Tag Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
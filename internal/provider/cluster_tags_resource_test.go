@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClusterTagParams(t *testing.T) {
+	settings := clusterTagSettings{
+		RegisteredTags: []string{"prod", "backup"},
+		ColorMap:       map[string]string{"prod": "ff0000:ffffff", "dev": "00ff00"},
+		Ordering:       "alphabetical",
+	}
+
+	got := clusterTagParams(settings)
+	want := map[string]interface{}{
+		"registered-tags": "backup;prod",
+		"tag-style":       "color-map=dev:00ff00;prod:ff0000:ffffff,ordering=alphabetical",
+		"delete":          "user-tag-access",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseColorMap(t *testing.T) {
+	got := parseColorMap("dev:00ff00;prod:ff0000:ffffff")
+	want := map[string]string{"dev": "00ff00", "prod": "ff0000:ffffff"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fileCredentials are the connection settings of a credentials file.
+type fileCredentials struct {
+	Host     string `json:"host"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	APIToken string `json:"api_token"`
+}
+
+// set assigns value to the setting key of a credentials file.
+func (c *fileCredentials) set(key, value string) error {
+	switch key {
+	case "host":
+		c.Host = value
+	case "username":
+		c.Username = value
+	case "password":
+		c.Password = value
+	case "api_token":
+		c.APIToken = value
+	case "token", "token_id", "token_secret":
+		return fmt.Errorf("%s: set the API token as api_token = USER@REALM!TOKENID=SECRET instead", key)
+	default:
+		return fmt.Errorf("unknown setting %q, expected host, username, password or api_token", key)
+	}
+
+	return nil
+}
+
+// parseCredentialsFile parses a credentials file, either a JSON object or
+// INI style `key = value` lines. Comments start with # or ;, and section
+// headers are ignored so that the file may be shared with other tools.
+func parseCredentialsFile(data []byte) (fileCredentials, error) {
+	var creds fileCredentials
+
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		var settings map[string]string
+		if err := json.Unmarshal(trimmed, &settings); err != nil {
+			return creds, fmt.Errorf("parsing JSON: %w", err)
+		}
+		for key, value := range settings {
+			if err := creds.set(key, value); err != nil {
+				return creds, err
+			}
+		}
+		return creds, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "[") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return creds, fmt.Errorf("line %d: expected key = value", line)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if err := creds.set(strings.TrimSpace(key), value); err != nil {
+			return creds, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return creds, scanner.Err()
+}
+
+// readCredentialsFile reads the credentials file at path.
+func readCredentialsFile(path string) (fileCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileCredentials{}, err
+	}
+
+	creds, err := parseCredentialsFile(data)
+	if err != nil {
+		return creds, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return creds, nil
+}
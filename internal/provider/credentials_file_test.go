@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCredentialsFile(t *testing.T) {
+	want := fileCredentials{Host: "https://pve:8006", Username: "terraform@pve", Password: "s3cret=="}
+
+	tests := map[string]string{
+		"json": `{"host": "https://pve:8006", "username": "terraform@pve", "password": "s3cret=="}`,
+		"ini": `# Proxmox VE
+[proxmox]
+host = https://pve:8006
+username = terraform@pve
+; values may be quoted
+password = "s3cret=="
+`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseCredentialsFile([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	got, err := parseCredentialsFile([]byte("host = https://pve:8006\napi_token = terraform@pve!ci=abc\n"))
+	if err != nil || got.APIToken != "terraform@pve!ci=abc" {
+		t.Errorf("got %+v, %v for an API token", got, err)
+	}
+}
+
+func TestParseCredentialsFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want string
+	}{
+		"token":       {data: "token = terraform@pve!ci=abc", want: "line 1: token: set the API token as api_token"},
+		"unknown":     {data: `{"endpoint": "https://pve:8006"}`, want: `unknown setting "endpoint"`},
+		"no equals":   {data: "host https://pve:8006", want: "line 1: expected key = value"},
+		"broken json": {data: `{"host": `, want: "parsing JSON"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCredentialsFile([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &currentUserDataSource{}
+	_ datasource.DataSourceWithConfigure = &currentUserDataSource{}
+)
+
+func NewCurrentUserDataSource() datasource.DataSource {
+	return &currentUserDataSource{}
+}
+
+type currentUserDataSource struct {
+	client *apiClient
+}
+
+type currentUserDataSourceModel struct {
+	UserID     types.String `tfsdk:"user_id"`
+	Username   types.String `tfsdk:"username"`
+	Realm      types.String `tfsdk:"realm"`
+	TokenName  types.String `tfsdk:"token_name"`
+	Privileges types.Set    `tfsdk:"privileges"`
+}
+
+// splitUserID splits a user or API token ID of the form user@realm!token
+// into its parts. token is empty for users.
+func splitUserID(userID string) (user, realm, token string) {
+	principal, token, _ := strings.Cut(userID, "!")
+
+	// User names may contain @, the realm follows the last one.
+	at := strings.LastIndex(principal, "@")
+	if at < 0 {
+		return principal, "", token
+	}
+
+	return principal[:at], principal[at+1:], token
+}
+
+// aggregatePrivileges returns the privileges granted on any path, sorted by
+// name.
+func aggregatePrivileges(permissions proxmox.Permissions) []string {
+	seen := map[string]bool{}
+	privileges := []string{}
+	for _, permission := range permissions {
+		for _, privilege := range grantedPrivileges(permission) {
+			if !seen[privilege] {
+				seen[privilege] = true
+				privileges = append(privileges, privilege)
+			}
+		}
+	}
+	sort.Strings(privileges)
+
+	return privileges
+}
+
+func (d *currentUserDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *currentUserDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current_user"
+}
+
+func (d *currentUserDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exports the principal the provider authenticates as and the privileges granted to it, so that " +
+			"modules can assert they run as the intended automation account.",
+		Attributes: map[string]schema.Attribute{
+			"user_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Full ID of the user or API token, e.g. terraform@pve or terraform@pve!ci",
+			},
+			"username": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the user without the realm",
+			},
+			"realm": schema.StringAttribute{
+				Computed:    true,
+				Description: "Authentication realm of the user, e.g. pam or pve",
+			},
+			"token_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the API token, null when authenticating as a user",
+			},
+			"privileges": schema.SetAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Privileges granted on any ACL path. Use the proxmox_token_permissions data source for the privileges per path",
+			},
+		},
+	}
+}
+
+func (d *currentUserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	// Without a user ID the API returns the permissions of the caller.
+	tflog.SubsystemDebug(ctx, logAPI, "Reading permissions of the current user")
+	permissions, err := d.client.Permissions(ctx, &proxmox.PermissionsOptions{})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Permissions",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	user, realm, token := splitUserID(d.client.username)
+	privileges, diags := types.SetValueFrom(ctx, types.StringType, aggregatePrivileges(permissions))
+	resp.Diagnostics.Append(diags...)
+
+	state := currentUserDataSourceModel{
+		UserID:     types.StringValue(d.client.username),
+		Username:   types.StringValue(user),
+		Realm:      types.StringValue(realm),
+		TokenName:  proxmoxtf.StringOrNull(token),
+		Privileges: privileges,
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
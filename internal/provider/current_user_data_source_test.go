@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/luthermonson/go-proxmox"
+)
+
+func TestSplitUserID(t *testing.T) {
+	tests := map[string][3]string{
+		"root@pam":              {"root", "pam", ""},
+		"terraform@pve!ci":      {"terraform", "pve", "ci"},
+		"jane@example.com@ldap": {"jane@example.com", "ldap", ""},
+		"root":                  {"root", "", ""},
+	}
+
+	for userID, want := range tests {
+		user, realm, token := splitUserID(userID)
+		if got := [3]string{user, realm, token}; got != want {
+			t.Errorf("%s: got %v, want %v", userID, got, want)
+		}
+	}
+}
+
+func TestAggregatePrivileges(t *testing.T) {
+	permissions := proxmox.Permissions{
+		"/":    {"Sys.Audit": true, "VM.Audit": false},
+		"/vms": {"VM.Audit": true, "VM.Allocate": true},
+		"/sdn": {"Sys.Audit": true},
+	}
+
+	got := aggregatePrivileges(permissions)
+	want := []string{"Sys.Audit", "VM.Allocate", "VM.Audit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
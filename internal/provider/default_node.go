@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// planDefaultNode sets the node of the plan to the default_node of the
+// provider when the configuration leaves it out. A changed default_node
+// replaces the resource, just like a changed node.
+func (c *apiClient) planDefaultNode(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy or before the provider is configured.
+	if c == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var node types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("node"), &node)...)
+	if resp.Diagnostics.HasError() || !node.IsNull() {
+		return
+	}
+
+	if c.defaultNode == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("node"),
+			"Missing Proxmox Node",
+			"The node is not set and the provider has no default_node. Set either of them.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("node"), types.StringValue(c.defaultNode))...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var prior types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("node"), &prior)...)
+	if prior.ValueString() != c.defaultNode {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("node"))
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// apiErrorTranslation maps a recognizable Proxmox API error onto a
+// remediation hint for the practitioner.
+type apiErrorTranslation struct {
+	pattern *regexp.Regexp
+	hint    func(match []string) string
+}
+
+// apiErrorCatalog lists the Proxmox API errors that are translated into
+// actionable diagnostics. The first matching entry wins. HTTP status codes
+// are only matched as the status that starts an error, possibly wrapped, so
+// that IDs and paths in messages, e.g. of VM 401, are not taken for them.
+var apiErrorCatalog = []apiErrorTranslation{
+	{
+		pattern: regexp.MustCompile(`read_only mode: refusing to send`),
+		hint: func(_ []string) string {
+			return "The provider is configured with read_only = true (or PROXMOX_READ_ONLY), which blocks every " +
+				"change to the cluster. Unset it to apply changes."
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`x509: certificate (signed by unknown authority|is not valid|has expired)`),
+		hint: func(_ []string) string {
+			return "The TLS certificate of the Proxmox VE API could not be verified. Install a certificate signed by a trusted CA " +
+				"on the nodes, trust the cluster CA with ca_certificate, pin the certificate with ssl_fingerprint, " +
+				"or set insecure = true (or PROXMOX_INSECURE) to skip the verification."
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)permission check failed \(([^,]+), ([^)]+)\)`),
+		hint: func(m []string) string {
+			return fmt.Sprintf("The configured user or API token is missing the %s privilege on %s. "+
+				"Grant a role containing %s on that path (Datacenter > Permissions), and remember that "+
+				"API tokens with privilege separation need their own ACL entries.", m[2], m[1], m[2])
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)((?:^|: )401 |no ticket|invalid pve ticket|authentication failure|not authorized)`),
+		hint: func(_ []string) string {
+			return "Proxmox VE rejected the credentials or the session ticket has expired. " +
+				"Verify the username, password or API token, and re-run the operation to obtain a new ticket. " +
+				"Users with two-factor authentication also need to set otp (or PROXMOX_OTP) to the current TOTP code."
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)storage '([^']+)' does not support content-type '([^']+)'`),
+		hint: func(m []string) string {
+			return fmt.Sprintf("Enable the %q content type on storage %q (Datacenter > Storage) "+
+				"or choose a storage that already supports it.", m[2], m[1])
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)(can't lock file|got timeout|is locked)`),
+		hint: func(_ []string) string {
+			return "The object is locked by another running operation, such as a backup, clone or migration. " +
+				"Wait for the task to finish or check the task log on the node before retrying."
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)(does not exist|no such)`),
+		hint: func(_ []string) string {
+			return "The object was not found in Proxmox VE. It may have been removed outside of Terraform; " +
+				"refresh the state or remove the resource from the configuration."
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?:^|: )596 `),
+		hint: func(_ []string) string {
+			return "pveproxy could not reach the node serving the request. Check that all cluster nodes are " +
+				"online and consider lowering the request rate against the API."
+		},
+	},
+}
+
+// apiErrorDetail returns the diagnostic detail for an error returned by the
+// Proxmox API, extended with a remediation hint when the error is recognized.
+func apiErrorDetail(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	for _, translation := range apiErrorCatalog {
+		if match := translation.pattern.FindStringSubmatch(err.Error()); match != nil {
+			return fmt.Sprintf("%s\n\n%s", err.Error(), translation.hint(match))
+		}
+	}
+
+	return err.Error()
+}
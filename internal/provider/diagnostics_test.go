@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApiErrorDetail(t *testing.T) {
+	cases := map[string]struct {
+		err         error
+		contains    string
+		notContains string
+	}{
+		"missing privilege": {
+			err:      errors.New("403 Permission check failed (/vms/100, VM.Allocate)"),
+			contains: "missing the VM.Allocate privilege on /vms/100",
+		},
+		"expired ticket": {
+			err:      errors.New("401 permission denied - invalid PVE ticket"),
+			contains: "session ticket has expired",
+		},
+		"vm 401 not found": {
+			err:         errors.New("500 Configuration file 'nodes/pve/qemu-server/401.conf' does not exist"),
+			contains:    "was not found",
+			notContains: "session ticket",
+		},
+		"unreachable node": {
+			err:      errors.New("migrating the guest: 596 Connection timed out"),
+			contains: "could not reach the node",
+		},
+		"vm 596": {
+			err:         errors.New("500 VM 596 is running"),
+			contains:    "500 VM 596 is running",
+			notContains: "could not reach the node",
+		},
+		"storage content type": {
+			err:      errors.New("500 storage 'local' does not support content-type 'images'"),
+			contains: `Enable the "images" content type on storage "local"`,
+		},
+		"self-signed certificate": {
+			err:      errors.New(`Post "https://pve:8006/api2/json/access/ticket": tls: failed to verify certificate: x509: certificate signed by unknown authority`),
+			contains: "set insecure = true",
+		},
+		"unknown error": {
+			err:      errors.New("500 something unexpected"),
+			contains: "500 something unexpected",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			detail := apiErrorDetail(tc.err)
+			if !strings.HasPrefix(detail, tc.err.Error()) {
+				t.Errorf("expected detail to start with the original error, got %q", detail)
+			}
+			if !strings.Contains(detail, tc.contains) {
+				t.Errorf("expected detail to contain %q, got %q", tc.contains, detail)
+			}
+			if tc.notContains != "" && strings.Contains(detail, tc.notContains) {
+				t.Errorf("expected detail not to contain %q, got %q", tc.notContains, detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// diskFormats are the disk image formats disks can be created with.
+var diskFormats = []string{"raw", "qcow2", "vmdk"}
+
+// rawOnlyStorageTypes are the storage types that store disks as block
+// devices or subvolumes and thus only support the raw format.
+var rawOnlyStorageTypes = map[string]bool{
+	"btrfs":       true,
+	"iscsi":       true,
+	"iscsidirect": true,
+	"lvm":         true,
+	"lvmthin":     true,
+	"rbd":         true,
+	"zfs":         true,
+	"zfspool":     true,
+}
+
+// validateDiskFormat returns an error when storages of storageType cannot
+// hold disks in format.
+func validateDiskFormat(storageType, format string) error {
+	if format != "raw" && rawOnlyStorageTypes[storageType] {
+		return fmt.Errorf("%s storage only supports the raw format, not %s", storageType, format)
+	}
+
+	return nil
+}
+
+// validateDiskFormatPlan adds an error when the planned disk_format is not
+// supported by the target_storage or one of the disk_storage entries of the
+// plan.
+func (c *apiClient) validateDiskFormatPlan(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) {
+	// Nothing to validate on destroy or before the provider is configured.
+	if c == nil || plan.Raw.IsNull() {
+		return
+	}
+
+	var format, targetStorage types.String
+	var diskStorage types.Map
+	diags.Append(plan.GetAttribute(ctx, path.Root("disk_format"), &format)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("target_storage"), &targetStorage)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("disk_storage"), &diskStorage)...)
+	if diags.HasError() || format.IsNull() || format.IsUnknown() {
+		return
+	}
+
+	storages := map[string]bool{}
+	if !targetStorage.IsNull() && !targetStorage.IsUnknown() {
+		storages[targetStorage.ValueString()] = true
+	}
+	for _, value := range diskStorage.Elements() {
+		if storage, ok := value.(types.String); ok && !storage.IsNull() && !storage.IsUnknown() {
+			storages[storage.ValueString()] = true
+		}
+	}
+
+	names := make([]string, 0, len(storages))
+	for name := range storages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		storage, err := c.api.Storage(ctx, name)
+		if err != nil {
+			// The storage may not exist yet, the apply reports it otherwise.
+			tflog.SubsystemDebug(ctx, logAPI, "Unable to read storage to validate the disk format", map[string]interface{}{
+				"storage": name,
+				"error":   err.Error(),
+			})
+			continue
+		}
+
+		if err := validateDiskFormat(storage.Type, format.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("disk_format"),
+				"Unsupported Disk Format",
+				fmt.Sprintf("The disk format cannot be used with storage %s: %s.", name, err),
+			)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import "testing"
+
+func TestValidateDiskFormat(t *testing.T) {
+	cases := map[string]struct {
+		storageType string
+		format      string
+		valid       bool
+	}{
+		"raw on lvmthin":   {storageType: "lvmthin", format: "raw", valid: true},
+		"qcow2 on lvmthin": {storageType: "lvmthin", format: "qcow2", valid: false},
+		"qcow2 on dir":     {storageType: "dir", format: "qcow2", valid: true},
+		"vmdk on nfs":      {storageType: "nfs", format: "vmdk", valid: true},
+		"qcow2 on zfspool": {storageType: "zfspool", format: "qcow2", valid: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateDiskFormat(tc.storageType, tc.format)
+			if tc.valid && err != nil {
+				t.Errorf("expected %s to be valid, got %s", tc.format, err)
+			}
+			if !tc.valid && err == nil {
+				t.Errorf("expected %s to be rejected", tc.format)
+			}
+		})
+	}
+}
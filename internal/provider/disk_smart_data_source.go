@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &diskSmartDataSource{}
+	_ datasource.DataSourceWithConfigure = &diskSmartDataSource{}
+)
+
+// smartReallocatedSectorCount is the ID of the ATA SMART attribute counting
+// reallocated sectors.
+const smartReallocatedSectorCount = "5"
+
+func NewDiskSmartDataSource() datasource.DataSource {
+	return &diskSmartDataSource{}
+}
+
+type diskSmartDataSource struct {
+	client *apiClient
+}
+
+type diskSmartDataSourceModel struct {
+	Node               types.String          `tfsdk:"node"`
+	Disk               types.String          `tfsdk:"disk"`
+	Health             types.String          `tfsdk:"health"`
+	Type               types.String          `tfsdk:"type"`
+	ReallocatedSectors types.Int64           `tfsdk:"reallocated_sectors"`
+	Attributes         []smartAttributeModel `tfsdk:"attributes"`
+	Text               types.String          `tfsdk:"text"`
+}
+
+type smartAttributeModel struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Value     types.String `tfsdk:"value"`
+	Worst     types.String `tfsdk:"worst"`
+	Threshold types.String `tfsdk:"threshold"`
+	Raw       types.String `tfsdk:"raw"`
+	Fail      types.String `tfsdk:"fail"`
+}
+
+// diskSmart is the response of the node disks smart endpoint.
+type diskSmart struct {
+	Health     string           `json:"health"`
+	Type       string           `json:"type"`
+	Text       string           `json:"text"`
+	Attributes []smartAttribute `json:"attributes"`
+}
+
+// smartAttribute is an ATA SMART attribute. Depending on the smartctl
+// version values are reported as strings or numbers.
+type smartAttribute struct {
+	ID        interface{} `json:"id"`
+	Name      string      `json:"name"`
+	Value     interface{} `json:"value"`
+	Worst     interface{} `json:"worst"`
+	Threshold interface{} `json:"threshold"`
+	Raw       string      `json:"raw"`
+	Fail      string      `json:"fail"`
+}
+
+func (d *diskSmartDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *diskSmartDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_disk_smart"
+}
+
+func (d *diskSmartDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the SMART health of a disk of a node, e.g. to refuse building storage on failing disks.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node",
+			},
+			"disk": schema.StringAttribute{
+				Required:    true,
+				Description: "Block device of the disk, e.g. /dev/sdb",
+			},
+			"health": schema.StringAttribute{
+				Computed:    true,
+				Description: "Overall health reported by the disk, e.g. PASSED",
+			},
+			"type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Format of the SMART data, ata for disks with attributes or text for e.g. NVMe disks",
+			},
+			"reallocated_sectors": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Raw value of the Reallocated_Sector_Ct attribute, null when the disk does not report it",
+			},
+			"attributes": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed: true,
+						},
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"value": schema.StringAttribute{
+							Computed: true,
+						},
+						"worst": schema.StringAttribute{
+							Computed: true,
+						},
+						"threshold": schema.StringAttribute{
+							Computed: true,
+						},
+						"raw": schema.StringAttribute{
+							Computed: true,
+						},
+						"fail": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"text": schema.StringAttribute{
+				Computed:    true,
+				Description: "SMART output of disks that do not report attributes",
+			},
+		},
+	}
+}
+
+// reallocatedSectors returns the raw reallocated sector count of the SMART
+// attributes, or null when the attribute is missing. Raw values may carry
+// details after the count, e.g. "8 (Average 2)".
+func reallocatedSectors(attributes []smartAttribute) types.Int64 {
+	for _, attribute := range attributes {
+		if strings.TrimSpace(proxmoxtf.ConfigValue(attribute.ID)) != smartReallocatedSectorCount {
+			continue
+		}
+
+		fields := strings.Fields(attribute.Raw)
+		if len(fields) == 0 {
+			break
+		}
+		count, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			break
+		}
+
+		return types.Int64Value(count)
+	}
+
+	return types.Int64Null()
+}
+
+func (d *diskSmartDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state diskSmartDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, state.Node.ValueString())
+	tflog.SubsystemDebug(ctx, logAPI, "Reading disk SMART data", map[string]interface{}{
+		"disk": state.Disk.ValueString(),
+	})
+
+	var smart diskSmart
+	err := d.client.Get(ctx, fmt.Sprintf("/nodes/%s/disks/smart?disk=%s", state.Node.ValueString(), url.QueryEscape(state.Disk.ValueString())), &smart)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Disk SMART Data",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Health = types.StringValue(smart.Health)
+	state.Type = types.StringValue(smart.Type)
+	state.Text = types.StringValue(smart.Text)
+	state.ReallocatedSectors = reallocatedSectors(smart.Attributes)
+
+	state.Attributes = []smartAttributeModel{}
+	for _, attribute := range smart.Attributes {
+		state.Attributes = append(state.Attributes, smartAttributeModel{
+			ID:        types.StringValue(strings.TrimSpace(proxmoxtf.ConfigValue(attribute.ID))),
+			Name:      types.StringValue(attribute.Name),
+			Value:     types.StringValue(proxmoxtf.ConfigValue(attribute.Value)),
+			Worst:     types.StringValue(proxmoxtf.ConfigValue(attribute.Worst)),
+			Threshold: types.StringValue(proxmoxtf.ConfigValue(attribute.Threshold)),
+			Raw:       types.StringValue(attribute.Raw),
+			Fail:      types.StringValue(attribute.Fail),
+		})
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
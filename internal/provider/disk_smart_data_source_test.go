@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestReallocatedSectors(t *testing.T) {
+	cases := map[string]struct {
+		attributes []smartAttribute
+		want       types.Int64
+	}{
+		"numeric id": {
+			attributes: []smartAttribute{
+				{ID: float64(1), Raw: "0"},
+				{ID: float64(5), Raw: "8"},
+			},
+			want: types.Int64Value(8),
+		},
+		"string id with details": {
+			attributes: []smartAttribute{
+				{ID: "  5", Raw: "12 (Average 3)"},
+			},
+			want: types.Int64Value(12),
+		},
+		"missing": {
+			attributes: []smartAttribute{
+				{ID: float64(9), Raw: "1234"},
+			},
+			want: types.Int64Null(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := reallocatedSectors(tc.attributes); !got.Equal(tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &endpointHealthDataSource{}
+	_ datasource.DataSourceWithConfigure = &endpointHealthDataSource{}
+)
+
+// endpointDialTimeout bounds the TLS handshake with the API host.
+const endpointDialTimeout = 10 * time.Second
+
+func NewEndpointHealthDataSource() datasource.DataSource {
+	return &endpointHealthDataSource{}
+}
+
+type endpointHealthDataSource struct {
+	client *apiClient
+}
+
+type endpointHealthDataSourceModel struct {
+	Host           types.String `tfsdk:"host"`
+	Addresses      types.List   `tfsdk:"addresses"`
+	Reachable      types.Bool   `tfsdk:"reachable"`
+	TLSFingerprint types.String `tfsdk:"tls_fingerprint"`
+	Authenticated  types.Bool   `tfsdk:"authenticated"`
+	Identity       types.String `tfsdk:"identity"`
+	Version        types.String `tfsdk:"version"`
+	Error          types.String `tfsdk:"error"`
+}
+
+func (d *endpointHealthDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *endpointHealthDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_endpoint_health"
+}
+
+func (d *endpointHealthDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports whether the configured API endpoint resolves, is reachable and accepts the configured " +
+			"credentials. Failed checks are reported in the attributes instead of failing the read, so that they " +
+			"can be asserted in preconditions.",
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				Computed:    true,
+				Description: "Configured API host",
+			},
+			"addresses": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Addresses the host name resolves to",
+			},
+			"reachable": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether a TLS connection to the host could be established",
+			},
+			"tls_fingerprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 fingerprint of the certificate presented by the host, in the format shown by Proxmox VE",
+			},
+			"authenticated": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the API accepted the configured credentials",
+			},
+			"identity": schema.StringAttribute{
+				Computed:    true,
+				Description: "User the provider is authenticated as, empty when authentication failed",
+			},
+			"version": schema.StringAttribute{
+				Computed:    true,
+				Description: "Proxmox VE version reported by the API",
+			},
+			"error": schema.StringAttribute{
+				Computed:    true,
+				Description: "Error of the first failed check, empty when all checks passed",
+			},
+		},
+	}
+}
+
+// certFingerprint returns the SHA-256 fingerprint of a certificate as
+// colon-separated upper case hex, the format used by Proxmox VE.
+func certFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// endpointAddress returns the host name and the host:port address of an API
+// host URL.
+func endpointAddress(host string) (string, string, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Hostname() == "" {
+		return "", "", fmt.Errorf("host %q has no host name", host)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	return u.Hostname(), net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// dialEndpoint opens a TLS connection to address and returns the fingerprint
+// of the leaf certificate. The certificate is not verified, it is reported.
+func dialEndpoint(ctx context.Context, address string) (string, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: endpointDialTimeout},
+		Config:    &tls.Config{InsecureSkipVerify: true},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", fmt.Errorf("%s presented no certificate", address)
+	}
+
+	return certFingerprint(certs[0]), nil
+}
+
+func (d *endpointHealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	state := endpointHealthDataSourceModel{
+		Host:           types.StringValue(d.client.host),
+		Addresses:      types.ListValueMust(types.StringType, nil),
+		Reachable:      types.BoolValue(false),
+		TLSFingerprint: types.StringValue(""),
+		Authenticated:  types.BoolValue(false),
+		Identity:       types.StringValue(""),
+		Version:        types.StringValue(""),
+		Error:          types.StringValue(""),
+	}
+
+	check := func() error {
+		hostname, address, err := endpointAddress(d.client.host)
+		if err != nil {
+			return err
+		}
+
+		tflog.SubsystemDebug(ctx, logAPI, "Resolving API host", map[string]interface{}{
+			"hostname": hostname,
+		})
+		addresses, err := net.DefaultResolver.LookupHost(ctx, hostname)
+		if err != nil {
+			return err
+		}
+		addressesValue, diags := types.ListValueFrom(ctx, types.StringType, addresses)
+		resp.Diagnostics.Append(diags...)
+		state.Addresses = addressesValue
+
+		tflog.SubsystemDebug(ctx, logAPI, "Connecting to API host", map[string]interface{}{
+			"address": address,
+		})
+		fingerprint, err := dialEndpoint(ctx, address)
+		if err != nil {
+			return err
+		}
+		state.Reachable = types.BoolValue(true)
+		state.TLSFingerprint = types.StringValue(fingerprint)
+
+		tflog.SubsystemDebug(ctx, logAPI, "Authenticating against API host")
+		version, err := d.client.Version(ctx)
+		if err != nil {
+			return err
+		}
+		state.Authenticated = types.BoolValue(true)
+		state.Identity = types.StringValue(d.client.username)
+		state.Version = types.StringValue(version.Version)
+
+		return nil
+	}
+
+	if err := check(); err != nil {
+		tflog.SubsystemWarn(ctx, logAPI, "API endpoint health check failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		state.Error = types.StringValue(err.Error())
+	}
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,35 @@
+package provider
+
+import "testing"
+
+func TestEndpointAddress(t *testing.T) {
+	cases := map[string]struct {
+		host     string
+		hostname string
+		address  string
+		wantErr  bool
+	}{
+		"explicit port": {host: "https://pve.example.com:8006", hostname: "pve.example.com", address: "pve.example.com:8006"},
+		"https default": {host: "https://pve.example.com", hostname: "pve.example.com", address: "pve.example.com:443"},
+		"ipv6":          {host: "https://[fd00::1]:8006", hostname: "fd00::1", address: "[fd00::1]:8006"},
+		"no host name":  {host: "pve.example.com", wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hostname, address, err := endpointAddress(tc.host)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.host)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hostname != tc.hostname || address != tc.address {
+				t.Errorf("got %q, %q, want %q, %q", hostname, address, tc.hostname, tc.address)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &esxiStorageResource{}
+	_ resource.ResourceWithConfigure = &esxiStorageResource{}
+)
+
+// NewEsxiStorageResource is a helper function to simplify the provider implementation.
+func NewEsxiStorageResource() resource.Resource {
+	return &esxiStorageResource{}
+}
+
+// esxiStorageResource connects an ESXi host as import storage.
+type esxiStorageResource struct {
+	client *apiClient
+}
+
+// esxiStorageResourceModel maps the resource schema data.
+type esxiStorageResourceModel struct {
+	Storage              types.String `tfsdk:"storage"`
+	Server               types.String `tfsdk:"server"`
+	Username             types.String `tfsdk:"username"`
+	Password             types.String `tfsdk:"password"`
+	SkipCertVerification types.Bool   `tfsdk:"skip_cert_verification"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *esxiStorageResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *esxiStorageResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_esxi_storage"
+}
+
+// Schema defines the schema for the resource.
+func (r *esxiStorageResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Connects an ESXi host or vCenter as storage with the import content type, " +
+			"so that its VMs can be imported with proxmox_vm_import. Requires Proxmox VE 8.2 or later.",
+		Attributes: map[string]schema.Attribute{
+			"storage": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the storage",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"server": schema.StringAttribute{
+				Required:    true,
+				Description: "Address of the ESXi host or vCenter",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Required:    true,
+				Description: "User to log in to the ESXi host with",
+			},
+			"password": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password of the user",
+			},
+			"skip_cert_verification": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Accept the certificate of the ESXi host without verifying it, e.g. when it is self-signed",
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *esxiStorageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan esxiStorageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	skipCertVerification := proxmoxtf.BoolToInt(plan.SkipCertVerification.ValueBool())
+	params := pveapi.StorageRequest{
+		Storage:              plan.Storage.ValueString(),
+		Type:                 "esxi",
+		Content:              "import",
+		Server:               plan.Server.ValueString(),
+		Username:             plan.Username.ValueString(),
+		Password:             plan.Password.ValueString(),
+		SkipCertVerification: &skipCertVerification,
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Creating ESXi storage", map[string]interface{}{
+		"storage": plan.Storage.ValueString(),
+		"server":  plan.Server.ValueString(),
+	})
+
+	err := r.client.api.CreateStorage(ctx, params)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox ESXi Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *esxiStorageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state esxiStorageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	storage, err := r.client.api.Storage(ctx, state.Storage.ValueString())
+	if err != nil {
+		// The storage was removed outside of Terraform.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox ESXi Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// The password is not returned by the API and kept from the state.
+	state.Server = types.StringValue(storage.Server)
+	state.Username = types.StringValue(storage.Username)
+	state.SkipCertVerification = proxmoxtf.Bool(storage.SkipCertVerification)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *esxiStorageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan esxiStorageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	skipCertVerification := proxmoxtf.BoolToInt(plan.SkipCertVerification.ValueBool())
+	params := pveapi.StorageRequest{
+		Username:             plan.Username.ValueString(),
+		Password:             plan.Password.ValueString(),
+		SkipCertVerification: &skipCertVerification,
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Updating ESXi storage", map[string]interface{}{
+		"storage": plan.Storage.ValueString(),
+	})
+
+	err := r.client.api.UpdateStorage(ctx, plan.Storage.ValueString(), params)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox ESXi Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *esxiStorageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state esxiStorageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Deleting ESXi storage", map[string]interface{}{
+		"storage": state.Storage.ValueString(),
+	})
+
+	err := r.client.api.DeleteStorage(ctx, state.Storage.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox ESXi Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &groupMembershipResource{}
+	_ resource.ResourceWithConfigure = &groupMembershipResource{}
+)
+
+// NewGroupMembershipResource is a helper function to simplify the provider implementation.
+func NewGroupMembershipResource() resource.Resource {
+	return &groupMembershipResource{}
+}
+
+// groupMembershipResource manages the members of a group.
+type groupMembershipResource struct {
+	client *apiClient
+}
+
+// groupMembershipResourceModel maps the resource schema data.
+type groupMembershipResourceModel struct {
+	Group types.String `tfsdk:"group"`
+	Users types.Set    `tfsdk:"users"`
+}
+
+// accessGroup is the response of the access group endpoint.
+type accessGroup struct {
+	Members []string `json:"members"`
+}
+
+// accessUser is the subset of the access user endpoint used for group
+// membership.
+type accessUser struct {
+	Groups []string `json:"groups"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *groupMembershipResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *groupMembershipResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_membership"
+}
+
+// Schema defines the schema for the resource.
+func (r *groupMembershipResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the members of an existing group, independently of the users, which may be created by " +
+			"other systems. The membership is authoritative: users added to the group outside of Terraform are removed.",
+		Attributes: map[string]schema.Attribute{
+			"group": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the group",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"users": schema.SetAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "IDs of the users in the group, e.g. alice@pve",
+			},
+		},
+	}
+}
+
+// membershipChanges returns the users to add to and remove from a group to
+// get from the current to the wanted members.
+func membershipChanges(current, want []string) ([]string, []string) {
+	isCurrent := map[string]bool{}
+	for _, user := range current {
+		isCurrent[user] = true
+	}
+	isWanted := map[string]bool{}
+	for _, user := range want {
+		isWanted[user] = true
+	}
+
+	var add, remove []string
+	for _, user := range want {
+		if !isCurrent[user] {
+			add = append(add, user)
+		}
+	}
+	for _, user := range current {
+		if !isWanted[user] {
+			remove = append(remove, user)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+
+	return add, remove
+}
+
+// userPath returns the API path of a user.
+func userPath(userID string) string {
+	return fmt.Sprintf("/access/users/%s", url.PathEscape(userID))
+}
+
+// groupMembers returns the members of a group.
+func (r *groupMembershipResource) groupMembers(ctx context.Context, group string) ([]string, error) {
+	var g accessGroup
+	if err := r.client.Get(ctx, fmt.Sprintf("/access/groups/%s", url.PathEscape(group)), &g); err != nil {
+		return nil, err
+	}
+
+	return g.Members, nil
+}
+
+// setMembers adds and removes users so that the group has exactly the given
+// members. Group membership is a property of the user, so users are removed
+// by rewriting their list of groups.
+func (r *groupMembershipResource) setMembers(ctx context.Context, group string, users []string) error {
+	current, err := r.groupMembers(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	add, remove := membershipChanges(current, users)
+	for _, user := range add {
+		tflog.SubsystemInfo(ctx, logAPI, "Adding user to group", map[string]interface{}{
+			"user": user,
+		})
+		params := map[string]interface{}{
+			"groups": group,
+			"append": 1,
+		}
+		if err := r.client.Put(ctx, userPath(user), params, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range remove {
+		tflog.SubsystemInfo(ctx, logAPI, "Removing user from group", map[string]interface{}{
+			"user": user,
+		})
+		var u accessUser
+		if err := r.client.Get(ctx, userPath(user), &u); err != nil {
+			return err
+		}
+
+		var groups []string
+		for _, g := range u.Groups {
+			if g != group {
+				groups = append(groups, g)
+			}
+		}
+		params := map[string]interface{}{
+			"groups": strings.Join(groups, ","),
+		}
+		if err := r.client.Put(ctx, userPath(user), params, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply sets the members of the group to the users of the model.
+func (r *groupMembershipResource) apply(ctx context.Context, model groupMembershipResourceModel) error {
+	var users []string
+	if diags := model.Users.ElementsAs(ctx, &users, false); diags.HasError() {
+		return fmt.Errorf("unable to read users from plan")
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "group", model.Group.ValueString())
+	return r.setMembers(ctx, model.Group.ValueString(), users)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *groupMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan groupMembershipResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Set Proxmox Group Members",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *groupMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state groupMembershipResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, err := r.groupMembers(ctx, state.Group.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Group Members",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	users, diags := types.SetValueFrom(ctx, types.StringType, members)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Users = users
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *groupMembershipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan groupMembershipResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Set Proxmox Group Members",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *groupMembershipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state groupMembershipResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "group", state.Group.ValueString())
+	err := r.setMembers(ctx, state.Group.ValueString(), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Remove Proxmox Group Members",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
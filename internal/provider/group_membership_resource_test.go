@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMembershipChanges(t *testing.T) {
+	add, remove := membershipChanges(
+		[]string{"alice@pve", "bob@pve", "carol@pam"},
+		[]string{"dave@pve", "alice@pve", "bob@pve"},
+	)
+
+	if want := []string{"dave@pve"}; !reflect.DeepEqual(add, want) {
+		t.Errorf("add: got %v, want %v", add, want)
+	}
+	if want := []string{"carol@pam"}; !reflect.DeepEqual(remove, want) {
+		t.Errorf("remove: got %v, want %v", remove, want)
+	}
+}
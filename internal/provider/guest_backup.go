@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// guestBackupAttributes returns the schema attributes of resources that
+// optionally back up the guest they manage before destroying it.
+func guestBackupAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"backup_before_destroy": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Back up the guest before destroying it, including when it is replaced. The backup is protected from pruning and the destroy fails if the backup does. Defaults to false",
+		},
+		"backup_storage": schema.StringAttribute{
+			Optional:    true,
+			Description: "Storage of the backup taken before destroying the guest. Defaults to the storage of the backup defaults of the node",
+		},
+	}
+}
+
+// backupGuest backs up a guest to storage, the backup defaults of the node
+// applying when empty, and waits for the backup to finish. Running guests
+// are backed up from a snapshot without stopping them.
+func (c *apiClient) backupGuest(ctx context.Context, node string, vmid int64, storage string) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+	tflog.SubsystemInfo(ctx, logVM, "Backing up guest before destroying it", map[string]interface{}{
+		"storage": storage,
+	})
+
+	upid, err := c.api.BackupGuest(ctx, node, pveapi.BackupRequest{
+		VMID:          vmid,
+		Storage:       storage,
+		Mode:          "snapshot",
+		Compress:      "zstd",
+		NotesTemplate: "{{guestname}} before destroy",
+		Protected:     1,
+	})
+	if err != nil {
+		return err
+	}
+
+	task, err := c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	if err != nil {
+		return withTaskLog(ctx, task, err)
+	}
+
+	return nil
+}
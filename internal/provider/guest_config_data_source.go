@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &guestConfigDataSource{}
+	_ datasource.DataSourceWithConfigure = &guestConfigDataSource{}
+)
+
+func NewGuestConfigDataSource() datasource.DataSource {
+	return &guestConfigDataSource{}
+}
+
+type guestConfigDataSource struct {
+	client *apiClient
+}
+
+type guestConfigDataSourceModel struct {
+	Node      types.String `tfsdk:"node"`
+	VMID      types.Int64  `tfsdk:"vm_id"`
+	GuestType types.String `tfsdk:"guest_type"`
+	Current   types.Bool   `tfsdk:"current"`
+	Config    types.Map    `tfsdk:"config"`
+	Digest    types.String `tfsdk:"digest"`
+}
+
+func (d *guestConfigDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *guestConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest_config"
+}
+
+func (d *guestConfigDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exports the raw config of a guest as a map, e.g. to compare it with the intended configuration " +
+			"or to feed migration tooling.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required: true,
+			},
+			"vm_id": schema.Int64Attribute{
+				Required: true,
+			},
+			"guest_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Type of the guest, either qemu (default) or lxc",
+				Validators: []validator.String{
+					stringvalidator.OneOf("qemu", "lxc"),
+				},
+			},
+			"current": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Return the config the guest currently runs with instead of including pending changes",
+			},
+			"config": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Config options keyed by their API name, formatted as in the guest config file",
+			},
+			"digest": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *guestConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state guestConfigDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	guestType := "qemu"
+	if !state.GuestType.IsNull() {
+		guestType = state.GuestType.ValueString()
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, state.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, state.VMID.ValueInt64())
+	tflog.SubsystemDebug(ctx, logVM, "Reading guest config")
+
+	path := guestPath(state.Node.ValueString(), guestType, state.VMID.ValueInt64()) + "/config"
+	if state.Current.ValueBool() {
+		path += "?current=1"
+	}
+
+	var raw map[string]interface{}
+	err := d.client.Get(ctx, path, &raw)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Guest Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	config := map[string]string{}
+	for key, value := range raw {
+		if key == "digest" {
+			continue
+		}
+		config[key] = proxmoxtf.ConfigValue(value)
+	}
+
+	state.Digest = types.StringValue(proxmoxtf.ConfigValue(raw["digest"]))
+	state.Config, diags = types.MapValueFrom(ctx, types.StringType, config)
+	resp.Diagnostics.Append(diags...)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// guestExtraConfigAttribute returns the extra_config attribute of guests.
+func guestExtraConfigAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+		Description: "Config options that have no dedicated attribute yet, applied verbatim and keyed by their API name, " +
+			"e.g. `args` or `hookscript`. Only the listed keys are managed, removed keys are deleted from the guest. " +
+			"Options with a dedicated attribute must not be set here",
+	}
+}
+
+// extraConfigValues returns the options of an extra_config map, which is
+// empty when the map is null or unknown.
+func extraConfigValues(extraConfig types.Map) map[string]string {
+	values := map[string]string{}
+	for key, value := range extraConfig.Elements() {
+		if value, ok := value.(types.String); ok && !value.IsUnknown() {
+			values[key] = value.ValueString()
+		}
+	}
+
+	return values
+}
+
+// extraConfigParams returns the parameters of the config update that applies
+// want, removing the keys that were managed before but are no longer set.
+func extraConfigParams(want, previous map[string]string) map[string]interface{} {
+	params := map[string]interface{}{}
+	for key, value := range want {
+		params[key] = value
+	}
+
+	var remove []string
+	for key := range previous {
+		if _, ok := want[key]; !ok {
+			remove = append(remove, key)
+		}
+	}
+	if len(remove) > 0 {
+		sort.Strings(remove)
+		params["delete"] = strings.Join(remove, ",")
+	}
+
+	return params
+}
+
+// applyExtraConfig writes the extra config options want to a guest, removing
+// the ones that are in previous only.
+func (c *apiClient) applyExtraConfig(ctx context.Context, node, guestType string, vmid int64, want, previous map[string]string) error {
+	if len(want) == 0 && len(previous) == 0 {
+		return nil
+	}
+
+	tflog.SubsystemInfo(ctx, logVM, "Applying extra guest config")
+	return c.Put(ctx, guestPath(node, guestType, vmid)+"/config", extraConfigParams(want, previous), nil)
+}
+
+// readExtraConfig returns the managed extra config options with their values
+// in the config of the guest. Keys that were removed from the guest are
+// dropped so that they are applied again.
+func readExtraConfig(managed types.Map, config map[string]interface{}) (types.Map, error) {
+	if managed.IsNull() {
+		return managed, nil
+	}
+
+	current := map[string]string{}
+	for key := range managed.Elements() {
+		if value, ok := config[key]; ok {
+			current[key] = proxmoxtf.ConfigValue(value)
+		}
+	}
+
+	extraConfig, diags := types.MapValueFrom(context.Background(), types.StringType, current)
+	if diags.HasError() {
+		return managed, fmt.Errorf("unable to map extra_config")
+	}
+
+	return extraConfig, nil
+}
+
+// validateHostPCIPlan adds an error when a hostpci option of the extra_config
+// of plan passes through a PCI device, e.g. an SR-IOV virtual function, that
+// another VM of the node already uses.
+func (c *apiClient) validateHostPCIPlan(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) {
+	// Nothing to validate on destroy or before the provider is configured.
+	if c == nil || plan.Raw.IsNull() {
+		return
+	}
+
+	var node types.String
+	var vmid types.Int64
+	var extraConfig types.Map
+	diags.Append(plan.GetAttribute(ctx, path.Root("node"), &node)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("vm_id"), &vmid)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("extra_config"), &extraConfig)...)
+	if diags.HasError() || node.IsUnknown() || vmid.IsUnknown() {
+		return
+	}
+
+	planned := map[string]string{}
+	for key, value := range extraConfigValues(extraConfig) {
+		if !hostpciKey.MatchString(key) {
+			continue
+		}
+		for _, address := range hostpciAddresses(value) {
+			planned[address] = key
+		}
+	}
+	if len(planned) == 0 {
+		return
+	}
+
+	ctx = c.logContext(ctx)
+	assigned, err := c.assignedPCIDevices(ctx, node.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Unable to Read Proxmox VM Configs",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	addresses := make([]string, 0, len(planned))
+	for address := range planned {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		owner, ok := assigned[address]
+		if !ok || owner == vmid.ValueInt64() {
+			continue
+		}
+		diags.AddAttributeError(
+			path.Root("extra_config").AtMapKey(planned[address]),
+			"PCI Device Already Assigned",
+			fmt.Sprintf("The PCI device %s is already passed through to VM %d on node %s. "+
+				"Pick a free device, e.g. from the proxmox_node_sriov_vfs data source.", address, owner, node.ValueString()),
+		)
+	}
+}
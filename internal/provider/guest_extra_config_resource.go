@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &guestExtraConfigResource{}
+	_ resource.ResourceWithConfigure  = &guestExtraConfigResource{}
+	_ resource.ResourceWithModifyPlan = &guestExtraConfigResource{}
+)
+
+// NewGuestExtraConfigResource is a helper function to simplify the provider implementation.
+func NewGuestExtraConfigResource() resource.Resource {
+	return &guestExtraConfigResource{}
+}
+
+// guestExtraConfigResource applies raw config options to an existing guest.
+type guestExtraConfigResource struct {
+	client *apiClient
+}
+
+// guestExtraConfigResourceModel maps the resource schema data.
+type guestExtraConfigResourceModel struct {
+	Node        types.String `tfsdk:"node"`
+	VMID        types.Int64  `tfsdk:"vm_id"`
+	GuestType   types.String `tfsdk:"guest_type"`
+	ExtraConfig types.Map    `tfsdk:"extra_config"`
+
+	SnapshotBeforeUpdate types.Bool   `tfsdk:"snapshot_before_update"`
+	LastSnapshot         types.String `tfsdk:"last_snapshot"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *guestExtraConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *guestExtraConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest_extra_config"
+}
+
+// Schema defines the schema for the resource.
+func (r *guestExtraConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Applies Proxmox config options that have no dedicated attribute yet verbatim to an existing guest. " +
+			"Only the listed keys are managed, every other option of the guest is left alone.",
+		DeprecationMessage: "Use the extra_config attribute of proxmox_vm or proxmox_lxc instead. Move the keys there and drop " +
+			"this resource with a removed block that does not destroy it, destroying it deletes the keys from the guest.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the guest runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the guest",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"guest_type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("qemu"),
+				Description: "Type of the guest, either qemu (default) or lxc",
+				Validators: []validator.String{
+					stringvalidator.OneOf("qemu", "lxc"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"extra_config": schema.MapAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "Config options applied verbatim, keyed by their API name, e.g. `args` or `hookscript`",
+			},
+		},
+	}
+
+	for name, attribute := range guestSnapshotAttributes("changing extra_config") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// apply writes the managed options, removing the ones that are in previous only.
+func (r *guestExtraConfigResource) apply(ctx context.Context, plan guestExtraConfigResourceModel, previous map[string]string) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+
+	return r.client.applyExtraConfig(ctx, plan.Node.ValueString(), plan.GuestType.ValueString(), plan.VMID.ValueInt64(),
+		extraConfigValues(plan.ExtraConfig), previous)
+}
+
+// readExtraConfig refreshes the managed options of the model.
+func (r *guestExtraConfigResource) readExtraConfig(ctx context.Context, model *guestExtraConfigResourceModel) error {
+	var config map[string]interface{}
+	path := guestPath(model.Node.ValueString(), model.GuestType.ValueString(), model.VMID.ValueInt64()) + "/config"
+	if err := r.client.Get(ctx, path, &config); err != nil {
+		return err
+	}
+
+	var err error
+	model.ExtraConfig, err = readExtraConfig(model.ExtraConfig, config)
+	return err
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider and
+// PCI devices that are already passed through to another VM, and plans a new
+// last_snapshot for updates that take a snapshot.
+func (r *guestExtraConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+	var guestType types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("guest_type"), &guestType)...)
+	if guestType.ValueString() == "qemu" {
+		r.client.validateHostPCIPlan(ctx, req.Plan, &resp.Diagnostics)
+	}
+	planSnapshotBeforeUpdate(ctx, req, resp, path.Root("extra_config"))
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *guestExtraConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan guestExtraConfigResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.LastSnapshot = types.StringNull()
+	err := r.apply(ctx, plan, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox Guest Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *guestExtraConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state guestExtraConfigResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.readExtraConfig(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Guest Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *guestExtraConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan, state guestExtraConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	previous := extraConfigValues(state.ExtraConfig)
+
+	r.client.snapshotBeforeUpdate(ctx, plan.Node.ValueString(), plan.GuestType.ValueString(), plan.VMID.ValueInt64(),
+		&plan.LastSnapshot, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan, previous)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox Guest Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *guestExtraConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state guestExtraConfigResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	previous := extraConfigValues(state.ExtraConfig)
+	if len(previous) == 0 {
+		return
+	}
+
+	state.ExtraConfig = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	err := r.apply(ctx, state, previous)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Remove Proxmox Guest Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
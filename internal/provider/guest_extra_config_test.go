@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestExtraConfigParams(t *testing.T) {
+	got := extraConfigParams(
+		map[string]string{"args": "-cpu host"},
+		map[string]string{"args": "-cpu kvm64", "hookscript": "local:snippets/hook.sh", "affinity": "0-3"},
+	)
+	want := map[string]interface{}{
+		"args":   "-cpu host",
+		"delete": "affinity,hookscript",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadExtraConfig(t *testing.T) {
+	config := map[string]interface{}{"args": "-cpu host", "memory": float64(512)}
+
+	managed := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"args":       types.StringValue("-cpu kvm64"),
+		"hookscript": types.StringValue("local:snippets/hook.sh"),
+	})
+	got, err := readExtraConfig(managed, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"args": "-cpu host"}; !reflect.DeepEqual(extraConfigValues(got), want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Guests without extra_config leave their other options alone.
+	got, err = readExtraConfig(types.MapNull(types.StringType), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsNull() {
+		t.Errorf("got %v, want null", got)
+	}
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// guestPlacementAttributes returns the schema attributes of guest resources
+// that report where and how the guest currently runs, the task that last
+// placed it, and whether it is pinned to its configured node. The current
+// node and status are observed only, a guest moved to another node is left
+// there unless migrate_back plans the configured node as its current node.
+func guestPlacementAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"current_node": schema.StringAttribute{
+			Computed: true,
+			Description: "Node the guest currently runs on, which differs from node after an HA failover or a migration. " +
+				"A guest found on another node is left there unless migrate_back is set",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"last_task_upid": schema.StringAttribute{
+			Computed:    true,
+			Description: "UPID of the last task that created, cloned or migrated the guest, to correlate applies with the task history of the cluster",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"ha_managed": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "The HA manager places the guest, node is only the node it is created on and the guest is left on whichever node HA moves it to, even with migrate_back. Defaults to false",
+		},
+		"migrate_back": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Migrate a guest found on another node than node back to it on the next apply, e.g. after a manual migration. Guests are left where they run otherwise. Defaults to false",
+		},
+		"status": schema.StringAttribute{
+			Computed:    true,
+			Description: "Current status of the guest, e.g. running or stopped",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+}
+
+// guestLocation is where a guest currently runs and its status.
+type guestLocation struct {
+	node   string
+	status string
+}
+
+// findGuest returns the location of the guest vmid of guestType, either
+// "qemu" or "lxc", among the guests of the cluster.
+func findGuest(resources proxmox.ClusterResources, guestType string, vmid int64) (guestLocation, bool) {
+	for _, res := range resources {
+		if res.Type == guestType && int64(res.VMID) == vmid {
+			return guestLocation{node: res.Node, status: res.Status}, true
+		}
+	}
+
+	return guestLocation{}, false
+}
+
+// locateGuest returns the location of a guest in the cluster, which is not
+// found when the guest was destroyed.
+func (c *apiClient) locateGuest(ctx context.Context, guestType string, vmid int64) (guestLocation, bool, error) {
+	cluster, err := c.Cluster(ctx)
+	if err != nil {
+		return guestLocation{}, false, err
+	}
+
+	resources, err := cluster.Resources(ctx, "vm")
+	if err != nil {
+		return guestLocation{}, false, err
+	}
+
+	location, found := findGuest(resources, guestType, vmid)
+	return location, found, nil
+}
+
+// planGuestNode plans to migrate a guest that runs on another node than its
+// configured node back to it when migrate_back is set and the guest is not
+// ha_managed. Guests that are replaced are created on their configured node
+// anyway.
+func planGuestNode(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var node, prior, current types.String
+	var haManaged, migrateBack types.Bool
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("node"), &node)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("ha_managed"), &haManaged)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("migrate_back"), &migrateBack)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("node"), &prior)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("current_node"), &current)...)
+	if resp.Diagnostics.HasError() || !node.Equal(prior) || current.IsNull() || haManaged.IsUnknown() || haManaged.ValueBool() || !migrateBack.ValueBool() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("current_node"), node)...)
+	if !current.Equal(node) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_task_upid"), types.StringUnknown())...)
+	}
+}
+
+// migrateRequest returns the request to migrate a guest of guestType with
+// status to target, keeping a running guest running.
+func migrateRequest(guestType, status, target string) pveapi.MigrateRequest {
+	req := pveapi.MigrateRequest{Target: target}
+	if status == "running" && guestType == "lxc" {
+		req.Restart = 1
+	} else if status == "running" {
+		req.Online = 1
+	}
+
+	return req
+}
+
+// migrateGuest migrates a guest to target, waits until the cluster reports
+// it there and returns the UPID of the migration task, which is empty when
+// the guest already runs on target. Migrations of HA managed guests are only
+// requested by the migration task and carried out by the HA manager
+// afterwards.
+func (c *apiClient) migrateGuest(ctx context.Context, guestType string, vmid int64, target string) (string, error) {
+	location, found, err := c.locateGuest(ctx, guestType, vmid)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("%s guest %d not found in the cluster", guestType, vmid)
+	}
+	if location.node == target {
+		return "", nil
+	}
+
+	tflog.SubsystemInfo(ctx, logVM, "Migrating guest back to its node", map[string]interface{}{
+		"current_node": location.node,
+		"target":       target,
+	})
+
+	upid, err := c.api.MigrateGuest(ctx, location.node, guestType, vmid, migrateRequest(guestType, location.status, target))
+	if err == nil {
+		_, err = c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(defaultTaskWaitTimeout)
+	for {
+		location, found, err = c.locateGuest(ctx, guestType, vmid)
+		if err != nil {
+			return upid, err
+		}
+		if found && location.node == target {
+			return upid, nil
+		}
+		if time.Now().After(deadline) {
+			return upid, fmt.Errorf("%s guest %d is not on node %s after %s", guestType, vmid, target, defaultTaskWaitTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return upid, ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// currentNode returns the node API requests for a guest go to, the observed
+// current node or, before the guest was observed, its configured node.
+func currentNode(node, current types.String) string {
+	if current.IsNull() || current.IsUnknown() {
+		return node.ValueString()
+	}
+
+	return current.ValueString()
+}
+
+// observeGuest sets the observed current node and status of a guest.
+func (c *apiClient) observeGuest(ctx context.Context, guestType string, vmid int64, node, status *types.String) error {
+	location, found, err := c.locateGuest(ctx, guestType, vmid)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s guest %d not found in the cluster", guestType, vmid)
+	}
+
+	*node = types.StringValue(location.node)
+	*status = types.StringValue(location.status)
+	return nil
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestFindGuest(t *testing.T) {
+	resources := proxmox.ClusterResources{
+		{Type: "node", Node: "pve1", Status: "online"},
+		{Type: "lxc", VMID: 100, Node: "pve1", Status: "running"},
+		{Type: "qemu", VMID: 100, Node: "pve2", Status: "stopped"},
+	}
+
+	tests := []struct {
+		guestType string
+		vmid      int64
+		want      guestLocation
+		found     bool
+	}{
+		{guestType: "qemu", vmid: 100, want: guestLocation{node: "pve2", status: "stopped"}, found: true},
+		{guestType: "lxc", vmid: 100, want: guestLocation{node: "pve1", status: "running"}, found: true},
+		{guestType: "qemu", vmid: 101},
+	}
+
+	for _, tt := range tests {
+		got, found := findGuest(resources, tt.guestType, tt.vmid)
+		if got != tt.want || found != tt.found {
+			t.Errorf("findGuest(%s, %d) = %+v, %t, want %+v, %t", tt.guestType, tt.vmid, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestCurrentNode(t *testing.T) {
+	tests := []struct {
+		current types.String
+		want    string
+	}{
+		{current: types.StringNull(), want: "pve1"},
+		{current: types.StringUnknown(), want: "pve1"},
+		{current: types.StringValue("pve2"), want: "pve2"},
+	}
+
+	for _, tt := range tests {
+		if got := currentNode(types.StringValue("pve1"), tt.current); got != tt.want {
+			t.Errorf("currentNode(%s) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestMigrateRequest(t *testing.T) {
+	tests := []struct {
+		guestType string
+		status    string
+		want      pveapi.MigrateRequest
+	}{
+		{guestType: "qemu", status: "running", want: pveapi.MigrateRequest{Target: "pve1", Online: 1}},
+		{guestType: "lxc", status: "running", want: pveapi.MigrateRequest{Target: "pve1", Restart: 1}},
+		{guestType: "qemu", status: "stopped", want: pveapi.MigrateRequest{Target: "pve1"}},
+	}
+
+	for _, tt := range tests {
+		if got := migrateRequest(tt.guestType, tt.status, "pve1"); got != tt.want {
+			t.Errorf("migrateRequest(%s, %s) = %+v, want %+v", tt.guestType, tt.status, got, tt.want)
+		}
+	}
+}
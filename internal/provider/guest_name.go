@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// guestUniqueNameAttribute returns the schema attribute of guest resources
+// that optionally refuse names used by other guests of the cluster.
+func guestUniqueNameAttribute(name string) schema.Attribute {
+	return schema.BoolAttribute{
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+		Description: fmt.Sprintf("Fail the plan and the apply when another guest of the cluster, VM or container, has the same %s. "+
+			"Guests named by one provider are checked and created one at a time, guests of other workspaces are only detected "+
+			"once they exist, as the API cannot reserve names. Defaults to false", name),
+	}
+}
+
+// guestNameLocks serializes checking and claiming guest names, so that
+// resources of one apply cannot both claim a name. The zero value is ready
+// to use.
+type guestNameLocks struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// acquire waits until no other operation holds name and returns the
+// function that releases it.
+func (l *guestNameLocks) acquire(ctx context.Context, name string) (func(), error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]chan struct{}{}
+	}
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		l.locks[name] = lock
+	}
+	l.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return func() {}, ctx.Err()
+	}
+}
+
+// guestsNamed returns the IDs of the guests of the cluster named name,
+// other than the guest vmid.
+func guestsNamed(resources proxmox.ClusterResources, name string, vmid int64) []int64 {
+	var vmids []int64
+	for _, res := range resources {
+		if (res.Type == "qemu" || res.Type == "lxc") && res.Name == name && int64(res.VMID) != vmid {
+			vmids = append(vmids, int64(res.VMID))
+		}
+	}
+	sort.Slice(vmids, func(i, j int) bool { return vmids[i] < vmids[j] })
+
+	return vmids
+}
+
+// checkUniqueName adds an error to diags when a guest other than vmid, which
+// is 0 for guests without an ID yet, is named name.
+func (c *apiClient) checkUniqueName(ctx context.Context, attribute path.Path, name string, vmid int64, diags *diag.Diagnostics) {
+	cluster, err := c.Cluster(ctx)
+	if err != nil {
+		diags.AddError("Unable to Read Proxmox Cluster", apiErrorDetail(err))
+		return
+	}
+	resources, err := cluster.Resources(ctx, "vm")
+	if err != nil {
+		diags.AddError("Unable to Read Proxmox Cluster Resources", apiErrorDetail(err))
+		return
+	}
+
+	if vmids := guestsNamed(resources, name, vmid); len(vmids) > 0 {
+		diags.AddAttributeError(
+			attribute,
+			"Proxmox Guest Name Not Unique",
+			fmt.Sprintf("The name %q is already used by the guests %v of the cluster. Choose another name or unset unique_name.", name, vmids),
+		)
+	}
+}
+
+// claimUniqueName checks that name is unique when unique is set, holding the
+// name until the returned function is called, so that the guest can be
+// created or renamed before other resources of the apply check the name.
+func (c *apiClient) claimUniqueName(ctx context.Context, unique types.Bool, attribute path.Path, name types.String, vmid int64, diags *diag.Diagnostics) func() {
+	if !unique.ValueBool() || name.IsNull() {
+		return func() {}
+	}
+
+	release, err := c.guestNames.acquire(ctx, name.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Unable to Check Proxmox Guest Name",
+			fmt.Sprintf("Waiting for another resource to claim the name %q: %s", name.ValueString(), err),
+		)
+		return release
+	}
+
+	c.checkUniqueName(ctx, attribute, name.ValueString(), vmid, diags)
+	return release
+}
+
+// planUniqueName rejects plans that create or rename a guest with
+// unique_name set to a name used by another guest. The name of the guest is
+// the attribute nameAttribute.
+func (c *apiClient) planUniqueName(ctx context.Context, nameAttribute string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy or before the provider is configured.
+	if c == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var unique types.Bool
+	var name types.String
+	var vmid types.Int64
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("unique_name"), &unique)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root(nameAttribute), &name)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("vm_id"), &vmid)...)
+	if resp.Diagnostics.HasError() || !unique.ValueBool() || name.IsNull() || name.IsUnknown() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var prior types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(nameAttribute), &prior)...)
+		if resp.Diagnostics.HasError() || prior.Equal(name) {
+			return
+		}
+	}
+
+	c.checkUniqueName(ctx, path.Root(nameAttribute), name.ValueString(), vmid.ValueInt64(), &resp.Diagnostics)
+}
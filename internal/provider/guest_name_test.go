@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luthermonson/go-proxmox"
+)
+
+func TestGuestsNamed(t *testing.T) {
+	resources := proxmox.ClusterResources{
+		{Type: "node", Name: "web"},
+		{Type: "storage", Name: "web"},
+		{Type: "qemu", VMID: 102, Name: "web"},
+		{Type: "lxc", VMID: 101, Name: "web"},
+		{Type: "qemu", VMID: 100, Name: "db"},
+	}
+
+	tests := []struct {
+		name string
+		vmid int64
+		want []int64
+	}{
+		{name: "web", want: []int64{101, 102}},
+		{name: "web", vmid: 102, want: []int64{101}},
+		{name: "db", vmid: 100},
+		{name: "cache"},
+	}
+
+	for _, tt := range tests {
+		if got := guestsNamed(resources, tt.name, tt.vmid); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("guestsNamed(%s, %d) = %v, want %v", tt.name, tt.vmid, got, tt.want)
+		}
+	}
+}
+
+func TestGuestNameLocks(t *testing.T) {
+	var locks guestNameLocks
+
+	release, err := locks.acquire(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := locks.acquire(context.Background(), "db")
+	if err != nil {
+		t.Fatalf("acquiring another name: %s", err)
+	}
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := locks.acquire(ctx, "web"); err == nil {
+		t.Error("acquired a held name")
+	}
+
+	release()
+	if _, err := locks.acquire(context.Background(), "web"); err != nil {
+		t.Errorf("acquiring a released name: %s", err)
+	}
+}
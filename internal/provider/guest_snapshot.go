@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// guestSnapshotAttributes returns the schema attributes of resources that
+// optionally take a snapshot of the guest they manage before changes, e.g.
+// "changing extra_config", are applied in place.
+func guestSnapshotAttributes(changes string) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"snapshot_before_update": schema.BoolAttribute{
+			Optional: true,
+			Computed: true,
+			Default:  booldefault.StaticBool(false),
+			Description: "Take a snapshot of the guest before " + changes + ", to roll back a bad apply. " +
+				"The snapshots are kept until they are removed manually. Defaults to false",
+		},
+		"last_snapshot": schema.StringAttribute{
+			Computed: true,
+			Description: "Name of the snapshot taken before the last update, e.g. to roll back with `qm rollback` " +
+				"or `pct rollback`",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+}
+
+// planSnapshotBeforeUpdate marks last_snapshot as unknown when an in-place
+// update with snapshot_before_update enabled changes one of the attributes
+// of changes.
+func planSnapshotBeforeUpdate(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, changes ...path.Path) {
+	// Snapshots are only taken on updates, replaced guests are destroyed.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || len(resp.RequiresReplace) > 0 {
+		return
+	}
+
+	var enabled types.Bool
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("snapshot_before_update"), &enabled)...)
+	if resp.Diagnostics.HasError() || !enabled.ValueBool() {
+		return
+	}
+
+	for _, p := range changes {
+		var planned, prior, configured attr.Value
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, p, &planned)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &prior)...)
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, p, &configured)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Computed values the provider fills in are unknown on updates
+		// and only count as changes when configured so.
+		if planned.IsUnknown() && !configured.IsUnknown() {
+			continue
+		}
+		if !planned.Equal(prior) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_snapshot"), types.StringUnknown())...)
+			return
+		}
+	}
+}
+
+// preUpdateSnapshotName returns the name of the snapshot taken before an
+// update at now. Snapshot names must start with a letter and are limited to
+// 40 characters, a second resolution timestamp keeps them unique.
+func preUpdateSnapshotName(now time.Time) string {
+	return "preupdate-" + now.UTC().Format("20060102150405")
+}
+
+// snapshotBeforeUpdate takes the snapshot of an update that plans a new
+// lastSnapshot and sets lastSnapshot to its name. It adds an error when the
+// snapshot fails, after which the update must not be applied.
+func (c *apiClient) snapshotBeforeUpdate(ctx context.Context, node, guestType string, vmid int64, lastSnapshot *types.String, diags *diag.Diagnostics) {
+	if !lastSnapshot.IsUnknown() {
+		return
+	}
+
+	name := preUpdateSnapshotName(time.Now())
+	err := c.takeSnapshot(ctx, node, guestType, vmid, name, "Taken by Terraform before updating the guest")
+	if err != nil {
+		diags.AddError(
+			"Unable to Snapshot Proxmox Guest",
+			"The snapshot before the update failed and the update was not applied. "+
+				"Guests with disks on storages without snapshot support need snapshot_before_update = false.\n\n"+
+				apiErrorDetail(err),
+		)
+		return
+	}
+	*lastSnapshot = types.StringValue(name)
+}
+
+// takeSnapshot takes a snapshot of a guest, without the RAM of running VMs,
+// and waits for it to finish.
+func (c *apiClient) takeSnapshot(ctx context.Context, node, guestType string, vmid int64, name, description string) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+	tflog.SubsystemInfo(ctx, logVM, "Taking snapshot", map[string]interface{}{
+		"snapshot": name,
+	})
+
+	var upid string
+	params := map[string]interface{}{
+		"snapname":    name,
+		"description": description,
+	}
+	if err := c.Post(ctx, guestPath(node, guestType, vmid)+"/snapshot", params, &upid); err != nil {
+		return err
+	}
+
+	_, err := c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestPreUpdateSnapshotName(t *testing.T) {
+	name := preUpdateSnapshotName(time.Date(2024, 5, 17, 13, 4, 5, 0, time.FixedZone("CEST", 2*60*60)))
+	if name != "preupdate-20240517110405" {
+		t.Errorf("got %q", name)
+	}
+
+	// Proxmox VE snapshot names start with a letter and have at most 40 characters.
+	if !regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{1,39}$`).MatchString(name) {
+		t.Errorf("%q is not a valid snapshot name", name)
+	}
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// defaultWaitForStatusTimeout is the default number of seconds a started
+// guest has to keep its wait_for_status.
+const defaultWaitForStatusTimeout = 30
+
+// guestStartAttributes returns the schema attributes of resources that
+// optionally start the guest they create.
+func guestStartAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"start": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Start the guest after creating it",
+		},
+		"wait_for_status": schema.StringAttribute{
+			Optional: true,
+			Description: "Status the guest has to keep for wait_for_status_timeout seconds after starting, e.g. to catch " +
+				"broken images that crash right away. The apply fails with the log of the start task otherwise. Only applies when start is true",
+			Validators: []validator.String{
+				stringvalidator.OneOf("running"),
+			},
+		},
+		"wait_for_status_timeout": schema.Int64Attribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     int64default.StaticInt64(defaultWaitForStatusTimeout),
+			Description: fmt.Sprintf("Seconds the guest has to keep its wait_for_status. Defaults to %d", defaultWaitForStatusTimeout),
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+	}
+}
+
+// formatTaskLog returns the lines of a task log in order.
+func formatTaskLog(log proxmox.Log) string {
+	numbers := make([]int, 0, len(log))
+	for n := range log {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	lines := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		lines = append(lines, log[n])
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// withTaskLog appends the log of task to err, so that failures show what the
+// guest reported.
+func withTaskLog(ctx context.Context, task *proxmox.Task, err error) error {
+	if task == nil {
+		return err
+	}
+
+	log, logErr := task.Log(ctx, 0, 50)
+	if logErr != nil || len(log) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w\n\nTask log:\n%s", err, formatTaskLog(log))
+}
+
+// startGuest starts a guest, guestType being either "qemu" or "lxc". When
+// waitForStatus is set, the status of the guest is polled after the start
+// task finished and has to stay at waitForStatus until the timeout expired.
+func (c *apiClient) startGuest(ctx context.Context, node, guestType string, vmid int64, waitForStatus string, timeout time.Duration) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+	tflog.SubsystemInfo(ctx, logVM, "Starting guest")
+
+	var upid string
+	if err := c.Post(ctx, guestPath(node, guestType, vmid)+"/status/start", nil, &upid); err != nil {
+		return err
+	}
+
+	task, err := c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	if err != nil {
+		return withTaskLog(ctx, task, err)
+	}
+
+	if waitForStatus == "" {
+		return nil
+	}
+
+	tflog.SubsystemDebug(ctx, logVM, "Verifying guest status after start", map[string]interface{}{
+		"status":  waitForStatus,
+		"timeout": timeout.String(),
+	})
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var status guestStatus
+		if err := c.Get(ctx, guestPath(node, guestType, vmid)+"/status/current", &status); err != nil {
+			return err
+		}
+		if status.Status != waitForStatus {
+			return withTaskLog(ctx, task, fmt.Errorf("guest %d on node %s is %s instead of %s after starting", vmid, node, status.Status, waitForStatus))
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// stopGuest stops a guest if it is running, guestType being either "qemu"
+// or "lxc". Proxmox VE refuses to destroy running guests.
+func (c *apiClient) stopGuest(ctx context.Context, node, guestType string, vmid int64) error {
+	var status guestStatus
+	if err := c.Get(ctx, guestPath(node, guestType, vmid)+"/status/current", &status); err != nil || status.Status != "running" {
+		return err
+	}
+
+	tflog.SubsystemInfo(ctx, logVM, "Stopping guest")
+
+	var upid string
+	if err := c.Post(ctx, guestPath(node, guestType, vmid)+"/status/stop", nil, &upid); err != nil {
+		return err
+	}
+	_, err := c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
@@ -0,0 +1,15 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/luthermonson/go-proxmox"
+)
+
+func TestFormatTaskLog(t *testing.T) {
+	got := formatTaskLog(proxmox.Log{2: "TASK ERROR: start failed", 0: "starting", 1: "kvm: invalid disk"})
+	want := "starting\nkvm: invalid disk\nTASK ERROR: start failed"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// maxTracedBody is the number of bytes of a body that are logged, so that
+// large responses like task logs do not flood the log.
+const maxTracedBody = 16 * 1024
+
+// sensitiveField matches the names of request and response fields whose
+// values are redacted from traced bodies, e.g. password, ticket and
+// CSRFPreventionToken, as well as the challenge, value and recovery keys of
+// second factors.
+var sensitiveField = regexp.MustCompile(`(?i)password|ticket|csrf|token|secret|key|otp|totp|cookie|recovery|challenge|^value$`)
+
+// clientLevelNull is the level of a go-proxmox client logger that logs
+// nothing, below its lowest level LevelError.
+const clientLevelNull = proxmox.LevelError - 1
+
+// clientLogLevel returns the level of the go-proxmox client logger for the
+// log_level of the provider. The client logs at debug when no level is set.
+func clientLogLevel(level hclog.Level) int {
+	switch level {
+	case hclog.Off:
+		return clientLevelNull
+	case hclog.Error:
+		return proxmox.LevelError
+	case hclog.Warn:
+		return proxmox.LevelWarn
+	case hclog.Info:
+		return proxmox.LevelInfo
+	default:
+		return proxmox.LevelDebug
+	}
+}
+
+// redact replaces the values of sensitive fields in a decoded JSON value.
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveField.MatchString(key) {
+				v[key] = "REDACTED"
+			} else {
+				v[key] = redact(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+	}
+
+	return value
+}
+
+// redactBody returns body with the values of sensitive fields redacted,
+// for JSON and form encoded bodies. Other bodies are left out entirely, as
+// they cannot be redacted reliably.
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		redacted, err := json.Marshal(redact(decoded))
+		if err == nil {
+			return truncateBody(string(redacted))
+		}
+	}
+
+	if form, err := url.ParseQuery(string(body)); err == nil && strings.Contains(string(body), "=") {
+		for key := range form {
+			if sensitiveField.MatchString(key) {
+				form[key] = []string{"REDACTED"}
+			}
+		}
+		return truncateBody(form.Encode())
+	}
+
+	return "(body not logged)"
+}
+
+// truncateBody shortens body to maxTracedBody bytes.
+func truncateBody(body string) string {
+	if len(body) <= maxTracedBody {
+		return body
+	}
+
+	return body[:maxTracedBody] + "... (truncated)"
+}
+
+// tracingTransport logs the bodies of API requests and responses with
+// sensitive fields redacted, so that API issues can be debugged from the
+// provider log. Headers are never logged, as they carry the credentials.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip logs req and its response at debug level of the api subsystem.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.Redacted(),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			fields["body"] = redactBody(data)
+		}
+	}
+	tflog.SubsystemDebug(ctx, logAPI, "Sending API request", fields)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	tflog.SubsystemDebug(ctx, logAPI, "Received API response", map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.Redacted(),
+		"status": resp.StatusCode,
+		"body":   redactBody(data),
+	})
+
+	return resp, nil
+}
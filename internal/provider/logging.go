@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Log subsystems of the provider. Messages are emitted with the
+// "provider.<subsystem>" module, so that e.g. task polling can be filtered
+// from SDN changes when debugging.
+const (
+	logAPI      = "api"
+	logTask     = "task"
+	logVM       = "vm"
+	logSDN      = "sdn"
+	logFirewall = "firewall"
+	logSSH      = "ssh"
+)
+
+// logSubsystems lists all subsystems registered by logContext.
+var logSubsystems = []string{logAPI, logTask, logVM, logSDN, logFirewall, logSSH}
+
+// Common log field keys.
+const (
+	logFieldNode = "node"
+	logFieldVMID = "vmid"
+	logFieldUPID = "upid"
+)
+
+// logContext registers the provider log subsystems on ctx. When the provider
+// was configured with a log_level, the subsystems log at that level,
+// otherwise they inherit the level Terraform runs the provider with.
+func (c *apiClient) logContext(ctx context.Context) context.Context {
+	for _, subsystem := range logSubsystems {
+		var options tflog.Options
+		if c.logLevel != hclog.NoLevel {
+			options = append(options, tflog.WithLevel(c.logLevel))
+		}
+		ctx = tflog.NewSubsystem(ctx, subsystem, options...)
+	}
+
+	return ctx
+}
@@ -0,0 +1,352 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &lxcExecResource{}
+	_ resource.ResourceWithConfigure  = &lxcExecResource{}
+	_ resource.ResourceWithModifyPlan = &lxcExecResource{}
+)
+
+// NewLxcExecResource is a helper function to simplify the provider implementation.
+func NewLxcExecResource() resource.Resource {
+	return &lxcExecResource{}
+}
+
+// lxcExecResource runs a command in a container through its terminal, the
+// API equivalent of pct exec.
+type lxcExecResource struct {
+	client *apiClient
+}
+
+// lxcExecResourceModel maps the resource schema data.
+type lxcExecResourceModel struct {
+	Node     types.String `tfsdk:"node"`
+	VMID     types.Int64  `tfsdk:"vm_id"`
+	Command  types.String `tfsdk:"command"`
+	Timeout  types.String `tfsdk:"timeout"`
+	Triggers types.Map    `tfsdk:"triggers"`
+	Output   types.String `tfsdk:"output"`
+	ExitCode types.Int64  `tfsdk:"exit_code"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *lxcExecResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *lxcExecResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lxc_exec"
+}
+
+// Schema defines the schema for the resource.
+func (r *lxcExecResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a shell command in a running container and captures its output, e.g. to configure containers without SSH. " +
+			"The command is sent through the terminal of the container, which requires the console mode (cmode) of the container " +
+			"to be shell. The command runs again whenever it or the triggers change.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the container runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the container",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"command": schema.StringAttribute{
+				Required:    true,
+				Description: "Shell command run with sh in the container",
+				Validators: []validator.String{
+					// The encoded command has to fit into a single line of the terminal.
+					stringvalidator.LengthBetween(1, 2048),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("5m"),
+				Description: "Maximum time to wait for the command as a Go duration. Defaults to 5m",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that run the command again when changed",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"output": schema.StringAttribute{
+				Computed:    true,
+				Description: "Combined standard output and standard error of the command",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"exit_code": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Exit code of the command",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// terminalEscape matches the escape sequences a terminal adds to the output,
+// e.g. colors and window titles.
+var terminalEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]|\x1b\][^\x07]*\x07`)
+
+// lxcExecLine returns the line typed into the terminal to run command. The
+// command is base64 encoded so that it survives the terminal unchanged, and
+// is framed by markers carrying nonce that are split by quotes, so that the
+// echo of the typed line does not contain them.
+func lxcExecLine(command, nonce string) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(command))
+
+	return fmt.Sprintf(`echo "__TF_""BEGIN_%[1]s"; echo %[2]s | base64 -d | sh 2>&1; echo "__TF_""END_%[1]s:$?"`+"\n", nonce, encoded)
+}
+
+// parseLxcExecOutput extracts the output and exit code of the command run by
+// lxcExecLine from the raw terminal output. It reports false until the end
+// marker has been received.
+func parseLxcExecOutput(raw, nonce string) (string, int64, bool) {
+	text := terminalEscape.ReplaceAllString(raw, "")
+	text = strings.ReplaceAll(text, "\r", "")
+
+	begin := "__TF_BEGIN_" + nonce + "\n"
+	start := strings.Index(text, begin)
+	if start < 0 {
+		return "", 0, false
+	}
+	text = text[start+len(begin):]
+
+	end := strings.Index(text, "__TF_END_"+nonce+":")
+	if end < 0 {
+		return "", 0, false
+	}
+
+	status, _, ok := strings.Cut(text[end+len("__TF_END_"+nonce+":"):], "\n")
+	if !ok {
+		return "", 0, false
+	}
+	exitCode, err := strconv.ParseInt(strings.TrimSpace(status), 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return text[:end], exitCode, true
+}
+
+// terminalHeader returns the header that authenticates a terminal websocket
+// as the client, whose upgrade request does not pass through its transport.
+func (c *apiClient) terminalHeader(ctx context.Context) http.Header {
+	header := http.Header{}
+	if c.apiToken != "" {
+		header.Set("Authorization", "PVEAPIToken="+c.apiToken)
+	} else if c.session != nil {
+		if ticket, _ := c.session.session(ctx); ticket != "" {
+			header.Set("Cookie", (&http.Cookie{Name: "PVEAuthCookie", Value: ticket}).String())
+		}
+	}
+
+	return header
+}
+
+// lxcExec runs command in a container through a terminal session and waits
+// for it to exit or the timeout to expire.
+func (c *apiClient) lxcExec(ctx context.Context, node string, vmid int64, command string, timeout time.Duration) (string, int64, error) {
+	proxy, err := c.api.LXCTermProxy(ctx, node, vmid)
+	if err != nil {
+		return "", 0, err
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 30 * time.Second,
+		TLSClientConfig:  c.tlsConfig,
+	}
+	term, err := pveapi.DialTerminal(ctx, dialer, pveapi.LXCTermWebSocketURL(c.host, node, vmid, proxy), c.terminalHeader(ctx), proxy)
+	if err != nil {
+		return "", 0, err
+	}
+	defer term.Close()
+
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := term.Write([]byte(lxcExecLine(command, nonce))); err != nil {
+		return "", 0, fmt.Errorf("terminal of container %d: %w", vmid, err)
+	}
+
+	type output struct {
+		text     string
+		exitCode int64
+		err      error
+	}
+	done := make(chan output, 1)
+	go func() {
+		var raw strings.Builder
+		for {
+			msg, err := term.Read()
+			if err != nil {
+				done <- output{err: fmt.Errorf("terminal of container %d: %w", vmid, err)}
+				return
+			}
+			raw.Write(msg)
+			if text, exitCode, ok := parseLxcExecOutput(raw.String(), nonce); ok {
+				done <- output{text: text, exitCode: exitCode}
+				return
+			}
+		}
+	}()
+
+	// Closing the terminal on return ends the read loop.
+	select {
+	case out := <-done:
+		return out.text, out.exitCode, out.err
+	case <-time.After(timeout):
+		return "", 0, fmt.Errorf("command did not exit after %s, is the console mode of container %d set to shell?", timeout, vmid)
+	case <-ctx.Done():
+		return "", 0, ctx.Err()
+	}
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider.
+func (r *lxcExecResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *lxcExecResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan lxcExecResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, err := time.ParseDuration(plan.Timeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			err.Error(),
+		)
+		return
+	}
+
+	node, vmid := plan.Node.ValueString(), plan.VMID.ValueInt64()
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+	tflog.SubsystemInfo(ctx, logVM, "Running command in container", map[string]interface{}{
+		"command": plan.Command.ValueString(),
+	})
+
+	output, exitCode, err := r.client.lxcExec(ctx, node, vmid, plan.Command.ValueString(), timeout)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Run Command in Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	if exitCode != 0 {
+		resp.Diagnostics.AddError(
+			"Command Failed in Proxmox Container",
+			fmt.Sprintf("The command exited with code %d:\n\n%s", exitCode, output),
+		)
+		return
+	}
+
+	plan.Output = types.StringValue(output)
+	plan.ExitCode = types.Int64Value(exitCode)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *lxcExecResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The command only runs on create, there is no remote state to refresh.
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *lxcExecResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only the timeout can change in place, it takes effect the next time the
+	// command runs.
+	var plan lxcExecResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *lxcExecResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The effects of the command are left in the container.
+}
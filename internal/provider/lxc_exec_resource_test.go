@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLxcExecLine(t *testing.T) {
+	got := lxcExecLine("hostname", "n1")
+	want := `echo "__TF_""BEGIN_n1"; echo aG9zdG5hbWU= | base64 -d | sh 2>&1; echo "__TF_""END_n1:$?"` + "\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseLxcExecOutput(t *testing.T) {
+	echo := strings.TrimSuffix(lxcExecLine("hostname", "n1"), "\n")
+	raw := "\x1b[?2004h\x1b]0;root@ct: ~\x07root@ct:~# " + echo + "\r\n\x1b[?2004l\r__TF_BEGIN_n1\r\nct\r\n"
+
+	if _, _, ok := parseLxcExecOutput(raw, "n1"); ok {
+		t.Fatal("expected the output to be incomplete without the end marker")
+	}
+
+	output, exitCode, ok := parseLxcExecOutput(raw+"__TF_END_n1:3\r\nroot@ct:~# ", "n1")
+	if !ok {
+		t.Fatal("expected the output to be complete")
+	}
+	if output != "ct\n" || exitCode != 3 {
+		t.Errorf("got %q with exit code %d", output, exitCode)
+	}
+}
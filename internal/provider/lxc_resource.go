@@ -3,17 +3,28 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &lxcResource{}
-	_ resource.ResourceWithConfigure = &lxcResource{}
+	_ resource.Resource               = &lxcResource{}
+	_ resource.ResourceWithConfigure  = &lxcResource{}
+	_ resource.ResourceWithModifyPlan = &lxcResource{}
 )
 
 // NewLxcResource is a helper function to simplify the provider implementation.
@@ -23,14 +34,30 @@ func NewLxcResource() resource.Resource {
 
 // lxcResource is the resource implementation.
 type lxcResource struct {
-	client *proxmox.Client
+	client *apiClient
 }
 
 // lxcResourceModel maps the resource schema data.
 type lxcResourceModel struct {
-	Node       types.String `tfsdk:"node"`
-	OSTemplate types.String `tfsdk:"os_template"`
-	VMID       types.Int64  `tfsdk:"vm_id"`
+	Node                 types.String        `tfsdk:"node"`
+	Placement            *nodePlacementModel `tfsdk:"placement"`
+	OSTemplate           types.String        `tfsdk:"os_template"`
+	Hostname             types.String        `tfsdk:"hostname"`
+	UniqueName           types.Bool          `tfsdk:"unique_name"`
+	VMID                 types.Int64         `tfsdk:"vm_id"`
+	ExtraConfig          types.Map           `tfsdk:"extra_config"`
+	Start                types.Bool          `tfsdk:"start"`
+	WaitForStatus        types.String        `tfsdk:"wait_for_status"`
+	WaitForStatusTimeout types.Int64         `tfsdk:"wait_for_status_timeout"`
+	BackupBeforeDestroy  types.Bool          `tfsdk:"backup_before_destroy"`
+	BackupStorage        types.String        `tfsdk:"backup_storage"`
+	SnapshotBeforeUpdate types.Bool          `tfsdk:"snapshot_before_update"`
+	LastSnapshot         types.String        `tfsdk:"last_snapshot"`
+	CurrentNode          types.String        `tfsdk:"current_node"`
+	HAManaged            types.Bool          `tfsdk:"ha_managed"`
+	MigrateBack          types.Bool          `tfsdk:"migrate_back"`
+	LastTaskUPID         types.String        `tfsdk:"last_task_upid"`
+	Status               types.String        `tfsdk:"status"`
 }
 
 // Configure adds the provider configured client to the resource.
@@ -41,12 +68,12 @@ func (r *lxcResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	client, ok := req.ProviderData.(*proxmox.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *proxmox.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -65,20 +92,78 @@ func (r *lxcResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"node": schema.StringAttribute{
-				Required: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "Node to create the container on. Defaults to the node chosen by placement, or the default_node of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
+			"placement": nodePlacementAttribute(),
 			"os_template": schema.StringAttribute{
-				Required: true,
+				Required:    true,
+				Description: "Template the container is created from, e.g. `local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Hostname of the container. Defaults to CT followed by the ID of the container",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
+			"unique_name":  guestUniqueNameAttribute("hostname"),
+			"extra_config": guestExtraConfigAttribute(),
 			"vm_id": schema.Int64Attribute{
-				Required: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the container, allocated from the vmid_range of the provider when not set",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 		},
 	}
+
+	for name, attribute := range guestStartAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range guestPlacementAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range guestBackupAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range guestSnapshotAttributes("changing extra_config or migrating it back to its node") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// ModifyPlan fills in the default_node of the provider unless the container
+// is placed, rejects guest IDs outside of its vmid_range, hostnames that
+// are not unique_name and templates on storages without vztmpl content, and
+// plans a new last_snapshot for updates that take a snapshot.
+func (r *lxcResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if !configuresPlacement(ctx, req.Config, &resp.Diagnostics) {
+		r.client.planDefaultNode(ctx, req, resp)
+	}
+	r.client.validateVMIDPlan(ctx, resp.Plan, &resp.Diagnostics)
+	r.client.planUniqueName(ctx, "hostname", req, resp)
+	r.client.validateStorageContentPlan(ctx, req, resp, path.MatchRoot("os_template"), "vztmpl")
+	planGuestNode(ctx, req, resp)
+	planSnapshotBeforeUpdate(ctx, req, resp, path.Root("extra_config"), path.Root("current_node"))
 }
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *lxcResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
 	// Retrieve values from plan
 	var plan lxcResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -87,18 +172,115 @@ func (r *lxcResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
+	plan.LastSnapshot = types.StringNull()
 	_, err := r.client.Cluster(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Proxmox Cluster",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	/*
-		TODO: There is currently no API for creating LXC clusters
-	*/
+	if plan.VMID.IsUnknown() {
+		vmid, err := r.client.nextVMID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Allocate Proxmox Guest ID",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		plan.VMID = types.Int64Value(vmid)
+	}
+
+	// Containers are created with the default 512 MiB of memory and a
+	// single core.
+	if plan.Node.IsUnknown() && plan.Placement != nil {
+		node, releasePlacement, err := r.client.placeGuest(ctx, *plan.Placement, 512<<20, 1)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Place Proxmox Container",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		defer releasePlacement()
+		plan.Node = types.StringValue(node)
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Creating container")
+
+	releaseName := r.client.claimUniqueName(ctx, plan.UniqueName, path.Root("hostname"), plan.Hostname, plan.VMID.ValueInt64(), &resp.Diagnostics)
+	defer releaseName()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	if plan.Hostname.IsUnknown() {
+		plan.Hostname = types.StringValue(fmt.Sprintf("CT%d", plan.VMID.ValueInt64()))
+	}
+	upid, err := r.client.api.CreateContainer(ctx, plan.Node.ValueString(), pveapi.ContainerRequest{
+		VMID:       plan.VMID.ValueInt64(),
+		OSTemplate: plan.OSTemplate.ValueString(),
+		Hostname:   plan.Hostname.ValueString(),
+	})
+	if err == nil {
+		_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	plan.LastTaskUPID = types.StringValue(upid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	err = r.client.applyExtraConfig(ctx, plan.Node.ValueString(), "lxc", plan.VMID.ValueInt64(), extraConfigValues(plan.ExtraConfig), nil)
+	if err != nil {
+		// Keep the created guest in the state, so that Terraform
+		// taints and replaces it instead of losing track of it.
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Unable to Configure Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	if plan.Start.ValueBool() {
+		timeout := time.Duration(plan.WaitForStatusTimeout.ValueInt64()) * time.Second
+		err = r.client.startGuest(ctx, plan.Node.ValueString(), "lxc", plan.VMID.ValueInt64(), plan.WaitForStatus.ValueString(), timeout)
+		if err != nil {
+			// Keep the created guest in the state, so that Terraform
+			// taints and replaces it instead of losing track of it.
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.AddError(
+				"Unable to Start Proxmox Container",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	err = r.client.observeGuest(ctx, "lxc", plan.VMID.ValueInt64(), &plan.CurrentNode, &plan.Status)
+	if err != nil {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -110,12 +292,171 @@ func (r *lxcResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 // Read refreshes the Terraform state with the latest data.
 func (r *lxcResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state lxcResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	location, found, err := r.client.locateGuest(ctx, "lxc", state.VMID.ValueInt64())
+	if err == nil && !found {
+		// The container was removed outside of Terraform.
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err == nil {
+		state.CurrentNode = types.StringValue(location.node)
+		state.Status = types.StringValue(location.status)
+		var config map[string]interface{}
+		config, err = r.client.api.ContainerConfig(ctx, location.node, state.VMID.ValueInt64())
+		state.Hostname = proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["hostname"]))
+		if err == nil {
+			state.ExtraConfig, err = readExtraConfig(state.ExtraConfig, config)
+		}
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *lxcResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Besides extra_config, only the start, backup and HA settings can
+	// change in place, they take effect on creation and destruction. A
+	// container found on another node is migrated back to its node with
+	// migrate_back.
+	ctx = r.client.logContext(ctx)
+
+	var plan, state lxcResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.LastTaskUPID.IsUnknown() {
+		plan.LastTaskUPID = state.LastTaskUPID
+	}
+	r.client.snapshotBeforeUpdate(ctx, currentNode(state.Node, state.CurrentNode), "lxc", plan.VMID.ValueInt64(),
+		&plan.LastSnapshot, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	node := currentNode(state.Node, state.CurrentNode)
+	if !plan.CurrentNode.IsUnknown() && !plan.CurrentNode.Equal(state.CurrentNode) {
+		upid, err := r.client.migrateGuest(ctx, "lxc", plan.VMID.ValueInt64(), plan.CurrentNode.ValueString())
+		if upid != "" {
+			plan.LastTaskUPID = types.StringValue(upid)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Migrate Proxmox Container",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		node = plan.CurrentNode.ValueString()
+	}
+
+	err := r.client.applyExtraConfig(ctx, node, "lxc", plan.VMID.ValueInt64(),
+		extraConfigValues(plan.ExtraConfig), extraConfigValues(state.ExtraConfig))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// The observed values are kept from state unless the container was
+	// migrated, they are refreshed on the next read.
+	if plan.Status.IsUnknown() {
+		err := r.client.observeGuest(ctx, "lxc", plan.VMID.ValueInt64(), &plan.CurrentNode, &plan.Status)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Proxmox Container",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *lxcResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state lxcResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node, vmid := currentNode(state.Node, state.CurrentNode), state.VMID.ValueInt64()
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+	tflog.SubsystemInfo(ctx, logVM, "Destroying container")
+
+	release := r.client.acquireNode(ctx, node, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	if state.BackupBeforeDestroy.ValueBool() {
+		err := r.client.backupGuest(ctx, node, vmid, state.BackupStorage.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Back Up Proxmox Container",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	err := r.client.stopGuest(ctx, node, "lxc", vmid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Stop Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	upid, err := r.client.api.DeleteContainer(ctx, node, vmid)
+	if err == nil {
+		_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox Container",
+			apiErrorDetail(err),
+		)
+		return
+	}
 }
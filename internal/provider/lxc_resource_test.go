@@ -0,0 +1,752 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// providerResource returns the resource registered with the provider as
+// typeName.
+func providerResource(t *testing.T, typeName string) resource.Resource {
+	t.Helper()
+
+	ctx := context.Background()
+	for _, newResource := range New("test")().Resources(ctx) {
+		res := newResource()
+		var resp resource.MetadataResponse
+		res.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "proxmox"}, &resp)
+		if resp.TypeName == typeName {
+			return res
+		}
+	}
+
+	t.Fatalf("resource %s is not registered with the provider", typeName)
+	return nil
+}
+
+// testUPID is the UPID of the tasks started through a fakeAPI.
+const testUPID = "UPID:pve:00001234:00005678:6650A000:vzcreate:200:root@pam:"
+
+// fakeAPI answers API requests with the data of responses, keyed by method
+// and path without the /api2/json prefix, and records the requests in order.
+// Tasks finish successfully unless a response is set for their status.
+// The responses of changes replace responses once the request of their key
+// is made, e.g. to report a guest on another node after its migration.
+type fakeAPI struct {
+	mu        sync.Mutex
+	requests  []string
+	responses map[string]string
+	changes   map[string]map[string]string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/api2/json")
+
+	f.mu.Lock()
+	f.requests = append(f.requests, key)
+	data, ok := f.responses[key]
+	for changed, response := range f.changes[key] {
+		f.responses[changed] = response
+	}
+	f.mu.Unlock()
+
+	if node, upid, found := strings.Cut(strings.TrimPrefix(key, "GET /nodes/"), "/tasks/"); !ok && found && strings.HasSuffix(upid, "/status") {
+		data = fmt.Sprintf(`{"node":%q,"upid":%q,"status":"stopped","exitstatus":"OK"}`, node, strings.TrimSuffix(upid, "/status"))
+		ok = true
+	}
+	if !ok {
+		http.Error(w, "unexpected request "+key, http.StatusNotImplemented)
+		return
+	}
+
+	fmt.Fprintf(w, `{"data":%s}`, data)
+}
+
+// index returns the position of the first request key, or -1 when it was
+// not made.
+func (f *fakeAPI) index(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Index(f.requests, key)
+}
+
+// fakeClient returns a client of api with the default node pve.
+func fakeClient(t *testing.T, api *fakeAPI) *apiClient {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client := proxmox.NewClient(server.URL+"/api2/json", proxmox.WithHTTPClient(server.Client()))
+	return &apiClient{Client: client, host: server.URL, api: pveapi.New(client), defaultNode: "pve"}
+}
+
+// configuredResource returns the resource registered as typeName, configured
+// with a client of api.
+func configuredResource(t *testing.T, typeName string, api *fakeAPI) resource.Resource {
+	t.Helper()
+
+	res := providerResource(t, typeName)
+	var resp resource.ConfigureResponse
+	res.(resource.ResourceWithConfigure).Configure(context.Background(), resource.ConfigureRequest{
+		ProviderData: fakeClient(t, api),
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	return res
+}
+
+// resourceValue returns the raw value of a resource with the schema s, with
+// values for some of its attributes and null for the others.
+func resourceValue(t *testing.T, s schema.Schema, values map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	objectType := s.Type().TerraformType(context.Background()).(tftypes.Object)
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+	for name := range values {
+		if _, ok := objectType.AttributeTypes[name]; !ok {
+			t.Fatalf("unknown attribute %s", name)
+		}
+	}
+
+	return tftypes.NewValue(objectType, attributes)
+}
+
+// resourceSchema returns the schema of res.
+func resourceSchema(t *testing.T, res resource.Resource) schema.Schema {
+	t.Helper()
+
+	var resp resource.SchemaResponse
+	res.Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	return resp.Schema
+}
+
+func TestLxcResourceRegistered(t *testing.T) {
+	ctx := context.Background()
+	res := providerResource(t, "proxmox_lxc")
+
+	var resp resource.SchemaResponse
+	res.Schema(ctx, resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if diags := resp.Schema.ValidateImplementation(ctx); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	for _, name := range []string{"node", "os_template", "hostname", "vm_id"} {
+		if _, ok := resp.Schema.Attributes[name]; !ok {
+			t.Errorf("missing attribute %s", name)
+		}
+	}
+}
+
+// lxcPlan returns a plan of the container 200 named web on node pve with the
+// attribute values of overrides.
+func lxcPlan(t *testing.T, res resource.Resource, overrides map[string]tftypes.Value) tfsdk.Plan {
+	t.Helper()
+
+	values := map[string]tftypes.Value{
+		"node":                    tftypes.NewValue(tftypes.String, "pve"),
+		"os_template":             tftypes.NewValue(tftypes.String, "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"),
+		"hostname":                tftypes.NewValue(tftypes.String, "web"),
+		"unique_name":             tftypes.NewValue(tftypes.Bool, false),
+		"vm_id":                   tftypes.NewValue(tftypes.Number, 200),
+		"start":                   tftypes.NewValue(tftypes.Bool, false),
+		"wait_for_status_timeout": tftypes.NewValue(tftypes.Number, 1),
+		"backup_before_destroy":   tftypes.NewValue(tftypes.Bool, false),
+		"ha_managed":              tftypes.NewValue(tftypes.Bool, false),
+		"migrate_back":            tftypes.NewValue(tftypes.Bool, false),
+		"current_node":            tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"status":                  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"last_task_upid":          tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}
+	for name, value := range overrides {
+		values[name] = value
+	}
+
+	s := resourceSchema(t, res)
+	return tfsdk.Plan{Schema: s, Raw: resourceValue(t, s, values)}
+}
+
+// placementValue returns the placement of res with strategy among all
+// nodes.
+func placementValue(t *testing.T, res resource.Resource, strategy string) tftypes.Value {
+	t.Helper()
+
+	objectType := resourceSchema(t, res).Type().TerraformType(context.Background()).(tftypes.Object)
+	return tftypes.NewValue(objectType.AttributeTypes["placement"], map[string]tftypes.Value{
+		"strategy": tftypes.NewValue(tftypes.String, strategy),
+		"nodes":    tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, nil),
+		"tag":      tftypes.NewValue(tftypes.String, nil),
+	})
+}
+
+func TestLxcResourceCreatePlacement(t *testing.T) {
+	nodes := `{"type":"node","node":"pve","status":"online","mem":48000,"maxmem":64000},` +
+		`{"type":"node","node":"pve2","status":"online","mem":16000,"maxmem":64000}`
+	api := &fakeAPI{
+		responses: map[string]string{
+			"GET /cluster/status":    `[]`,
+			"GET /cluster/resources": "[" + nodes + "]",
+			"POST /nodes/pve2/lxc":   fmt.Sprintf("%q", testUPID),
+		},
+		changes: map[string]map[string]string{
+			"POST /nodes/pve2/lxc": {"GET /cluster/resources": "[" + nodes + `,{"type":"lxc","vmid":200,"node":"pve2","status":"stopped"}]`},
+		},
+	}
+	res := configuredResource(t, "proxmox_lxc", api)
+	plan := lxcPlan(t, res, map[string]tftypes.Value{
+		"node":      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"placement": placementValue(t, res, "least-memory"),
+	})
+
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}}
+	res.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	var state lxcResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.Node.ValueString() != "pve2" || state.CurrentNode.ValueString() != "pve2" {
+		t.Errorf("got node %s and current node %s, want the container placed on pve2", state.Node, state.CurrentNode)
+	}
+	if pending := res.(*lxcResource).client.placements.pending["pve2"]; pending.guests != 0 {
+		t.Errorf("the created container is still pending on pve2: %+v", pending)
+	}
+}
+
+func TestLxcResourceCreateWaitForStatus(t *testing.T) {
+	tests := map[string]struct {
+		status    string
+		wantError bool
+	}{
+		"running": {status: "running"},
+		"crashed": {status: "stopped", wantError: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeAPI{responses: map[string]string{
+				"GET /cluster/status":                       `[]`,
+				"GET /cluster/resources":                    fmt.Sprintf(`[{"type":"lxc","vmid":200,"node":"pve","status":%q}]`, tt.status),
+				"POST /nodes/pve/lxc":                       fmt.Sprintf("%q", testUPID),
+				"POST /nodes/pve/lxc/200/status/start":      fmt.Sprintf("%q", testUPID),
+				"GET /nodes/pve/lxc/200/status/current":     fmt.Sprintf(`{"status":%q}`, tt.status),
+				"GET /nodes/pve/tasks/" + testUPID + "/log": `[{"n":1,"t":"startup for container '200' failed"}]`,
+			}}
+			res := configuredResource(t, "proxmox_lxc", api)
+			plan := lxcPlan(t, res, map[string]tftypes.Value{
+				"start":           tftypes.NewValue(tftypes.Bool, true),
+				"wait_for_status": tftypes.NewValue(tftypes.String, "running"),
+			})
+
+			resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}}
+			res.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Fatalf("got error %t, want %t: %v", got, tt.wantError, resp.Diagnostics)
+			}
+			if tt.wantError && !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "startup for container '200' failed") {
+				t.Errorf("the error does not include the task log: %v", resp.Diagnostics)
+			}
+			if created, started := api.index("POST /nodes/pve/lxc"), api.index("POST /nodes/pve/lxc/200/status/start"); created < 0 || started < created {
+				t.Errorf("the container was not created and then started: %v", api.requests)
+			}
+
+			// The container is kept in the state also when it crashed, so
+			// that Terraform replaces it.
+			var state lxcResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.VMID.ValueInt64() != 200 {
+				t.Errorf("got state %+v, want container 200", state)
+			}
+			if !tt.wantError && (state.Status.ValueString() != "running" || state.CurrentNode.ValueString() != "pve") {
+				t.Errorf("got status %s on node %s, want running on pve", state.Status, state.CurrentNode)
+			}
+		})
+	}
+}
+
+func TestLxcResourceCreateUniqueName(t *testing.T) {
+	tests := map[string]struct {
+		resources string
+		wantError bool
+	}{
+		"free":  {resources: `[{"type":"lxc","vmid":200,"node":"pve","status":"stopped","name":"web"}]`},
+		"taken": {resources: `[{"type":"qemu","vmid":101,"node":"pve","status":"running","name":"web"}]`, wantError: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeAPI{responses: map[string]string{
+				"GET /cluster/status":    `[]`,
+				"GET /cluster/resources": tt.resources,
+				"POST /nodes/pve/lxc":    fmt.Sprintf("%q", testUPID),
+			}}
+			res := configuredResource(t, "proxmox_lxc", api)
+			plan := lxcPlan(t, res, map[string]tftypes.Value{
+				"unique_name": tftypes.NewValue(tftypes.Bool, true),
+			})
+
+			resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}}
+			res.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Fatalf("got error %t, want %t: %v", got, tt.wantError, resp.Diagnostics)
+			}
+			if tt.wantError {
+				if api.index("POST /nodes/pve/lxc") >= 0 {
+					t.Error("the container was created with a name that is not unique")
+				}
+				return
+			}
+
+			var state lxcResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.LastTaskUPID.ValueString() != testUPID || state.CurrentNode.ValueString() != "pve" || state.Status.ValueString() != "stopped" {
+				t.Errorf("got last task %s, node %s and status %s", state.LastTaskUPID, state.CurrentNode, state.Status)
+			}
+		})
+	}
+}
+
+func TestLxcResourceRead(t *testing.T) {
+	tests := map[string]struct {
+		resources  string
+		wantNode   string
+		wantStatus string
+	}{
+		"failed over": {
+			resources:  `[{"type":"lxc","vmid":200,"node":"pve2","status":"running"}]`,
+			wantNode:   "pve2",
+			wantStatus: "running",
+		},
+		"destroyed": {resources: `[]`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeAPI{responses: map[string]string{
+				"GET /cluster/status":            `[]`,
+				"GET /cluster/resources":         tt.resources,
+				"GET /nodes/pve2/lxc/200/config": `{"hostname":"web2"}`,
+			}}
+			res := configuredResource(t, "proxmox_lxc", api)
+			plan := lxcPlan(t, res, map[string]tftypes.Value{
+				"ha_managed":     tftypes.NewValue(tftypes.Bool, true),
+				"current_node":   tftypes.NewValue(tftypes.String, "pve"),
+				"status":         tftypes.NewValue(tftypes.String, "running"),
+				"last_task_upid": tftypes.NewValue(tftypes.String, testUPID),
+			})
+			state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+
+			resp := resource.ReadResponse{State: state}
+			res.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+
+			if tt.wantNode == "" {
+				if !resp.State.Raw.IsNull() {
+					t.Error("the destroyed container was kept in the state")
+				}
+				return
+			}
+			var got lxcResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+			if got.CurrentNode.ValueString() != tt.wantNode || got.Status.ValueString() != tt.wantStatus || got.Hostname.ValueString() != "web2" {
+				t.Errorf("got node %s, status %s and hostname %s", got.CurrentNode, got.Status, got.Hostname)
+			}
+			if !got.HAManaged.ValueBool() || got.LastTaskUPID.ValueString() != testUPID {
+				t.Errorf("got ha_managed %s and last task %s, want them kept", got.HAManaged, got.LastTaskUPID)
+			}
+		})
+	}
+}
+
+// lxcDelete destroys the container 200 on node pve with backup_before_destroy
+// set through api.
+func lxcDelete(t *testing.T, api *fakeAPI) resource.DeleteResponse {
+	t.Helper()
+
+	res := configuredResource(t, "proxmox_lxc", api)
+	plan := lxcPlan(t, res, map[string]tftypes.Value{
+		"backup_before_destroy": tftypes.NewValue(tftypes.Bool, true),
+		"backup_storage":        tftypes.NewValue(tftypes.String, "pbs"),
+		"current_node":          tftypes.NewValue(tftypes.String, "pve"),
+		"status":                tftypes.NewValue(tftypes.String, "running"),
+		"last_task_upid":        tftypes.NewValue(tftypes.String, testUPID),
+	})
+	state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+
+	resp := resource.DeleteResponse{State: state}
+	res.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+	return resp
+}
+
+func TestLxcResourceDeleteBackup(t *testing.T) {
+	api := &fakeAPI{responses: map[string]string{
+		"POST /nodes/pve/vzdump":                fmt.Sprintf("%q", testUPID),
+		"GET /nodes/pve/lxc/200/status/current": `{"status":"stopped"}`,
+		"DELETE /nodes/pve/lxc/200":             fmt.Sprintf("%q", testUPID),
+	}}
+	resp := lxcDelete(t, api)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	backup, destroy := api.index("POST /nodes/pve/vzdump"), api.index("DELETE /nodes/pve/lxc/200")
+	if backup < 0 || destroy < backup {
+		t.Errorf("the container was not backed up before it was destroyed: %v", api.requests)
+	}
+	// The destroy waits for the backup task to finish.
+	if wait := api.index("GET /nodes/pve/tasks/" + testUPID + "/status"); wait < backup || wait > destroy {
+		t.Errorf("the backup task was not waited for: %v", api.requests)
+	}
+}
+
+func TestLxcResourceDeleteBackupFailed(t *testing.T) {
+	api := &fakeAPI{responses: map[string]string{
+		"POST /nodes/pve/vzdump":                       fmt.Sprintf("%q", testUPID),
+		"GET /nodes/pve/tasks/" + testUPID + "/status": fmt.Sprintf(`{"node":"pve","upid":%q,"status":"stopped","exitstatus":"job errors"}`, testUPID),
+		"GET /nodes/pve/tasks/" + testUPID + "/log":    `[{"n":1,"t":"ERROR: Backup of VM 200 failed - no space left on device"}]`,
+	}}
+	resp := lxcDelete(t, api)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected the failed backup to fail the destroy")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "no space left on device") {
+		t.Errorf("the error does not include the backup log: %s", detail)
+	}
+	if api.index("DELETE /nodes/pve/lxc/200") >= 0 {
+		t.Errorf("the container was destroyed without a backup: %v", api.requests)
+	}
+}
+
+func TestLxcResourceDeleteRunning(t *testing.T) {
+	api := &fakeAPI{responses: map[string]string{
+		"POST /nodes/pve/vzdump":                fmt.Sprintf("%q", testUPID),
+		"GET /nodes/pve/lxc/200/status/current": `{"status":"running"}`,
+		"POST /nodes/pve/lxc/200/status/stop":   fmt.Sprintf("%q", testUPID),
+		"DELETE /nodes/pve/lxc/200":             fmt.Sprintf("%q", testUPID),
+	}}
+	resp := lxcDelete(t, api)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	backup, stop, destroy := api.index("POST /nodes/pve/vzdump"), api.index("POST /nodes/pve/lxc/200/status/stop"), api.index("DELETE /nodes/pve/lxc/200")
+	if backup < 0 || stop < backup || destroy < stop {
+		t.Errorf("the running container was not backed up and stopped before it was destroyed: %v", api.requests)
+	}
+}
+
+func TestLxcResourceDefaultNode(t *testing.T) {
+	tests := map[string]struct {
+		defaultNode string
+		placement   string
+		wantNode    string
+		wantError   bool
+	}{
+		"default node":    {defaultNode: "pve", wantNode: "pve"},
+		"no default node": {wantError: true},
+		"placement":       {defaultNode: "pve", placement: "spread"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := configuredResource(t, "proxmox_lxc", &fakeAPI{})
+			res.(*lxcResource).client.defaultNode = tt.defaultNode
+
+			plan := lxcPlan(t, res, map[string]tftypes.Value{
+				"node": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			})
+			values := map[string]tftypes.Value{
+				"os_template": tftypes.NewValue(tftypes.String, "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"),
+				"hostname":    tftypes.NewValue(tftypes.String, "web"),
+				"vm_id":       tftypes.NewValue(tftypes.Number, 200),
+			}
+			if tt.placement != "" {
+				values["placement"] = placementValue(t, res, tt.placement)
+			}
+			config := tfsdk.Config{Schema: plan.Schema, Raw: resourceValue(t, resourceSchema(t, res), values)}
+			state := tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}
+
+			resp := resource.ModifyPlanResponse{Plan: plan}
+			res.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Config: config,
+				Plan:   plan,
+				State:  state,
+			}, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Fatalf("got error %t, want %t: %v", got, tt.wantError, resp.Diagnostics)
+			}
+			var node types.String
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("node"), &node)...)
+			// A placed container gets its node when it is created.
+			if tt.placement != "" && !node.IsUnknown() {
+				t.Errorf("got node %s, want it unknown until placed", node)
+			}
+			if !tt.wantError && tt.placement == "" && node.ValueString() != tt.wantNode {
+				t.Errorf("got node %s, want %s", node, tt.wantNode)
+			}
+		})
+	}
+}
+
+func TestLxcResourcePlanTemplateStorage(t *testing.T) {
+	tests := map[string]struct {
+		template  string
+		wantError bool
+	}{
+		"template storage": {template: "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"},
+		"image storage":    {template: "local-lvm:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst", wantError: true},
+		"new storage":      {template: "nfs:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"},
+	}
+
+	api := &fakeAPI{responses: map[string]string{
+		"GET /storage": `[{"storage":"local","type":"dir","content":"iso,vztmpl,backup"},{"storage":"local-lvm","type":"lvmthin","content":"images,rootdir"}]`,
+	}}
+	res := configuredResource(t, "proxmox_lxc", api)
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			plan := lxcPlan(t, res, map[string]tftypes.Value{
+				"os_template": tftypes.NewValue(tftypes.String, tt.template),
+			})
+			config := tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}
+			state := tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}
+
+			resp := resource.ModifyPlanResponse{Plan: plan}
+			res.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Config: config,
+				Plan:   plan,
+				State:  state,
+			}, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Fatalf("got error %t, want %t: %v", got, tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+
+	// The storages are read once for all plans.
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if n := slices.Index(api.requests, "GET /storage"); n < 0 || slices.Index(api.requests[n+1:], "GET /storage") >= 0 {
+		t.Errorf("got requests %v, want the storages read once", api.requests)
+	}
+}
+
+func TestLxcResourceUpdateSnapshot(t *testing.T) {
+	tests := map[string]struct {
+		haManaged    bool
+		wantSnapshot bool
+	}{
+		"migration":  {wantSnapshot: true},
+		"ha managed": {haManaged: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			running := `[{"type":"lxc","vmid":200,"node":"pve2","status":"running"}]`
+			api := &fakeAPI{
+				responses: map[string]string{
+					"GET /cluster/status":               `[]`,
+					"GET /cluster/resources":            running,
+					"POST /nodes/pve2/lxc/200/snapshot": fmt.Sprintf("%q", testUPID),
+					"POST /nodes/pve2/lxc/200/migrate":  fmt.Sprintf("%q", testUPID),
+				},
+				changes: map[string]map[string]string{
+					"POST /nodes/pve2/lxc/200/migrate": {"GET /cluster/resources": `[{"type":"lxc","vmid":200,"node":"pve","status":"running"}]`},
+				},
+			}
+			res := configuredResource(t, "proxmox_lxc", api)
+
+			values := map[string]tftypes.Value{
+				"snapshot_before_update": tftypes.NewValue(tftypes.Bool, true),
+				"ha_managed":             tftypes.NewValue(tftypes.Bool, tt.haManaged),
+				"migrate_back":           tftypes.NewValue(tftypes.Bool, true),
+			}
+			plan := lxcPlan(t, res, values)
+			values["current_node"] = tftypes.NewValue(tftypes.String, "pve2")
+			values["status"] = tftypes.NewValue(tftypes.String, "running")
+			values["last_task_upid"] = tftypes.NewValue(tftypes.String, testUPID)
+			state := tfsdk.State{Schema: plan.Schema, Raw: lxcPlan(t, res, values).Raw}
+			config := tfsdk.Config{Schema: plan.Schema, Raw: resourceValue(t, resourceSchema(t, res), map[string]tftypes.Value{
+				"os_template":            tftypes.NewValue(tftypes.String, "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"),
+				"hostname":               tftypes.NewValue(tftypes.String, "web"),
+				"vm_id":                  tftypes.NewValue(tftypes.Number, 200),
+				"snapshot_before_update": tftypes.NewValue(tftypes.Bool, true),
+				"ha_managed":             tftypes.NewValue(tftypes.Bool, tt.haManaged),
+				"migrate_back":           tftypes.NewValue(tftypes.Bool, true),
+			})}
+
+			planResp := resource.ModifyPlanResponse{Plan: plan}
+			res.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Config: config,
+				Plan:   plan,
+				State:  state,
+			}, &planResp)
+			if planResp.Diagnostics.HasError() {
+				t.Fatal(planResp.Diagnostics)
+			}
+			var lastSnapshot types.String
+			planResp.Diagnostics.Append(planResp.Plan.GetAttribute(context.Background(), path.Root("last_snapshot"), &lastSnapshot)...)
+			if lastSnapshot.IsUnknown() != tt.wantSnapshot {
+				t.Fatalf("got last_snapshot %s, want a new snapshot %t", lastSnapshot, tt.wantSnapshot)
+			}
+			if !tt.wantSnapshot {
+				return
+			}
+
+			resp := resource.UpdateResponse{State: state}
+			res.Update(context.Background(), resource.UpdateRequest{Plan: planResp.Plan, State: state}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+
+			var got lxcResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+			if !strings.HasPrefix(got.LastSnapshot.ValueString(), "preupdate-") || got.CurrentNode.ValueString() != "pve" {
+				t.Errorf("got last_snapshot %s and current node %s", got.LastSnapshot, got.CurrentNode)
+			}
+			snapshot, migrate := api.index("POST /nodes/pve2/lxc/200/snapshot"), api.index("POST /nodes/pve2/lxc/200/migrate")
+			if snapshot < 0 || migrate < snapshot {
+				t.Errorf("got requests %v, want the snapshot taken before the migration", api.requests)
+			}
+		})
+	}
+}
+
+func TestLxcResourcePlanMigrateBack(t *testing.T) {
+	tests := map[string]struct {
+		haManaged, migrateBack bool
+		wantMigration          bool
+	}{
+		"moved by HA":      {},
+		"migrate back":     {migrateBack: true, wantMigration: true},
+		"ha managed guest": {haManaged: true, migrateBack: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := configuredResource(t, "proxmox_lxc", &fakeAPI{})
+			values := map[string]tftypes.Value{
+				"ha_managed":   tftypes.NewValue(tftypes.Bool, tt.haManaged),
+				"migrate_back": tftypes.NewValue(tftypes.Bool, tt.migrateBack),
+				// The observed values are planned from state.
+				"current_node":   tftypes.NewValue(tftypes.String, "pve2"),
+				"status":         tftypes.NewValue(tftypes.String, "running"),
+				"last_task_upid": tftypes.NewValue(tftypes.String, testUPID),
+			}
+			plan := lxcPlan(t, res, values)
+			state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+
+			resp := resource.ModifyPlanResponse{Plan: plan}
+			res.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+				Plan:   plan,
+				State:  state,
+			}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+
+			var currentNode, status types.String
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("current_node"), &currentNode)...)
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("status"), &status)...)
+			if got := currentNode.ValueString() == "pve"; got != tt.wantMigration {
+				t.Errorf("got current_node %s, want a migration back to pve %t", currentNode, tt.wantMigration)
+			}
+			if tt.wantMigration {
+				return
+			}
+
+			// Guests left on their node keep their observed values without
+			// touching the API.
+			if currentNode.ValueString() != "pve2" || status.ValueString() != "running" {
+				t.Errorf("got current_node %s and status %s, want them kept from state", currentNode, status)
+			}
+			updateResp := resource.UpdateResponse{State: state}
+			res.Update(context.Background(), resource.UpdateRequest{Plan: resp.Plan, State: state}, &updateResp)
+			if updateResp.Diagnostics.HasError() {
+				t.Fatal(updateResp.Diagnostics)
+			}
+			if !updateResp.State.Raw.Equal(state.Raw) {
+				t.Errorf("got state %v, want %v", updateResp.State.Raw, state.Raw)
+			}
+		})
+	}
+}
+
+func TestLxcResourceUpdateExtraConfig(t *testing.T) {
+	extraConfig := func(values map[string]string) tftypes.Value {
+		elements := map[string]tftypes.Value{}
+		for key, value := range values {
+			elements[key] = tftypes.NewValue(tftypes.String, value)
+		}
+		return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elements)
+	}
+	observed := map[string]tftypes.Value{
+		"current_node":   tftypes.NewValue(tftypes.String, "pve"),
+		"status":         tftypes.NewValue(tftypes.String, "running"),
+		"last_task_upid": tftypes.NewValue(tftypes.String, testUPID),
+	}
+
+	api := &fakeAPI{responses: map[string]string{
+		"PUT /nodes/pve/lxc/200/config": "null",
+	}}
+	res := configuredResource(t, "proxmox_lxc", api)
+	stateValues := map[string]tftypes.Value{"extra_config": extraConfig(map[string]string{"lock": "", "tty": "2"})}
+	planValues := map[string]tftypes.Value{"extra_config": extraConfig(map[string]string{"tty": "4"})}
+	for name, value := range observed {
+		stateValues[name], planValues[name] = value, value
+	}
+	state := tfsdk.State{Schema: resourceSchema(t, res), Raw: lxcPlan(t, res, stateValues).Raw}
+	plan := lxcPlan(t, res, planValues)
+
+	resp := resource.UpdateResponse{State: state}
+	res.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if api.index("PUT /nodes/pve/lxc/200/config") < 0 {
+		t.Errorf("extra_config was not applied: %v", api.requests)
+	}
+	if !resp.State.Raw.Equal(plan.Raw) {
+		t.Errorf("got state %v, want %v", resp.State.Raw, plan.Raw)
+	}
+}
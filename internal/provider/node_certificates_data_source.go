@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &nodeCertificatesDataSource{}
+	_ datasource.DataSourceWithConfigure = &nodeCertificatesDataSource{}
+)
+
+func NewNodeCertificatesDataSource() datasource.DataSource {
+	return &nodeCertificatesDataSource{}
+}
+
+type nodeCertificatesDataSource struct {
+	client *apiClient
+}
+
+type nodeCertificatesDataSourceModel struct {
+	Node         types.String           `tfsdk:"node"`
+	Certificates []nodeCertificateModel `tfsdk:"certificates"`
+}
+
+type nodeCertificateModel struct {
+	Filename    types.String `tfsdk:"filename"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+	Issuer      types.String `tfsdk:"issuer"`
+	Subject     types.String `tfsdk:"subject"`
+	SAN         types.List   `tfsdk:"san"`
+	NotBefore   types.String `tfsdk:"not_before"`
+	NotAfter    types.String `tfsdk:"not_after"`
+}
+
+// nodeCertificate is an entry of the node certificates info endpoint.
+type nodeCertificate struct {
+	Filename    string   `json:"filename"`
+	Fingerprint string   `json:"fingerprint"`
+	Issuer      string   `json:"issuer"`
+	Subject     string   `json:"subject"`
+	SAN         []string `json:"san"`
+	NotBefore   int64    `json:"notbefore"`
+	NotAfter    int64    `json:"notafter"`
+}
+
+func (d *nodeCertificatesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *nodeCertificatesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_certificates"
+}
+
+func (d *nodeCertificatesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the TLS certificates of a node, e.g. to alert on or rotate certificates before they expire.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node",
+			},
+			"certificates": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"filename": schema.StringAttribute{
+							Computed: true,
+						},
+						"fingerprint": schema.StringAttribute{
+							Computed:    true,
+							Description: "SHA-256 fingerprint of the certificate",
+						},
+						"issuer": schema.StringAttribute{
+							Computed: true,
+						},
+						"subject": schema.StringAttribute{
+							Computed: true,
+						},
+						"san": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "Subject alternative names of the certificate",
+						},
+						"not_before": schema.StringAttribute{
+							Computed:    true,
+							Description: "Start of the validity period in RFC 3339 format",
+						},
+						"not_after": schema.StringAttribute{
+							Computed:    true,
+							Description: "End of the validity period in RFC 3339 format",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *nodeCertificatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state nodeCertificatesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, state.Node.ValueString())
+	tflog.SubsystemDebug(ctx, logAPI, "Reading node certificates")
+
+	var certificates []nodeCertificate
+	err := d.client.Get(ctx, fmt.Sprintf("/nodes/%s/certificates/info", state.Node.ValueString()), &certificates)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Node Certificates",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Certificates = []nodeCertificateModel{}
+	for _, cert := range certificates {
+		san, diags := types.ListValueFrom(ctx, types.StringType, cert.SAN)
+		resp.Diagnostics.Append(diags...)
+
+		state.Certificates = append(state.Certificates, nodeCertificateModel{
+			Filename:    types.StringValue(cert.Filename),
+			Fingerprint: types.StringValue(cert.Fingerprint),
+			Issuer:      types.StringValue(cert.Issuer),
+			Subject:     types.StringValue(cert.Subject),
+			SAN:         san,
+			NotBefore:   proxmoxtf.UnixTime(cert.NotBefore),
+			NotAfter:    proxmoxtf.UnixTime(cert.NotAfter),
+		})
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
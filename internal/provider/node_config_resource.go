@@ -0,0 +1,277 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &nodeConfigResource{}
+	_ resource.ResourceWithConfigure = &nodeConfigResource{}
+)
+
+// NewNodeConfigResource is a helper function to simplify the provider implementation.
+func NewNodeConfigResource() resource.Resource {
+	return &nodeConfigResource{}
+}
+
+// nodeConfigResource manages the settings of a node.
+type nodeConfigResource struct {
+	client *apiClient
+}
+
+// nodeConfigResourceModel maps the resource schema data.
+type nodeConfigResourceModel struct {
+	Node                types.String `tfsdk:"node"`
+	Wakeonlan           types.String `tfsdk:"wakeonlan"`
+	StartallOnbootDelay types.Int64  `tfsdk:"startall_onboot_delay"`
+	Description         types.String `tfsdk:"description"`
+	Digest              types.String `tfsdk:"digest"`
+}
+
+// nodeConfig is the API representation of the node config.
+type nodeConfig struct {
+	Wakeonlan           string `json:"wakeonlan"`
+	StartallOnbootDelay *int64 `json:"startall-onboot-delay"`
+	Description         string `json:"description"`
+	Digest              string `json:"digest"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *nodeConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *nodeConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_config"
+}
+
+// Schema defines the schema for the resource.
+func (r *nodeConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the settings of a node. Destroying the resource resets the managed settings.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"wakeonlan": schema.StringAttribute{
+				Optional:    true,
+				Description: "MAC address used to wake the node with Wake-on-LAN, optionally followed by options such as bind-interface",
+			},
+			"startall_onboot_delay": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Delay in seconds before the guests marked to start on boot are started",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 300),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Description of the node, shown in the node summary",
+			},
+			"digest": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// nodeConfigPath returns the API path of the config of a node.
+func nodeConfigPath(node string) string {
+	return fmt.Sprintf("/nodes/%s/config", node)
+}
+
+// nodeConfigParams maps the plan onto the parameters of the node config
+// update. Unset values are removed through the `delete` parameter.
+func nodeConfigParams(plan nodeConfigResourceModel) map[string]interface{} {
+	params := map[string]interface{}{}
+
+	var remove []string
+	if plan.Wakeonlan.IsNull() {
+		remove = append(remove, "wakeonlan")
+	} else {
+		params["wakeonlan"] = plan.Wakeonlan.ValueString()
+	}
+	if plan.StartallOnbootDelay.IsNull() {
+		remove = append(remove, "startall-onboot-delay")
+	} else {
+		params["startall-onboot-delay"] = plan.StartallOnbootDelay.ValueInt64()
+	}
+	if plan.Description.IsNull() {
+		remove = append(remove, "description")
+	} else {
+		params["description"] = plan.Description.ValueString()
+	}
+	if len(remove) > 0 {
+		params["delete"] = strings.Join(remove, ",")
+	}
+
+	return params
+}
+
+// readNodeConfig refreshes the model from the node config endpoint.
+func (r *nodeConfigResource) readNodeConfig(ctx context.Context, model *nodeConfigResourceModel) error {
+	var config nodeConfig
+	if err := r.client.Get(ctx, nodeConfigPath(model.Node.ValueString()), &config); err != nil {
+		return err
+	}
+
+	model.Wakeonlan = proxmoxtf.StringOrNull(config.Wakeonlan)
+	model.StartallOnbootDelay = types.Int64PointerValue(config.StartallOnbootDelay)
+	model.Description = proxmoxtf.StringOrNull(strings.TrimSuffix(config.Description, "\n"))
+	model.Digest = types.StringValue(config.Digest)
+
+	return nil
+}
+
+// apply writes the planned settings and refreshes the model.
+func (r *nodeConfigResource) apply(ctx context.Context, plan *nodeConfigResourceModel) error {
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, plan.Node.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Updating node config")
+
+	if err := r.client.Put(ctx, nodeConfigPath(plan.Node.ValueString()), nodeConfigParams(*plan), nil); err != nil {
+		return err
+	}
+
+	return r.readNodeConfig(ctx, plan)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *nodeConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan nodeConfigResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Node Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *nodeConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeConfigResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.readNodeConfig(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Node Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *nodeConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan nodeConfigResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Node Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *nodeConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeConfigResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, state.Node.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Resetting node config")
+	reset := nodeConfigResourceModel{Node: state.Node}
+	err := r.client.Put(ctx, nodeConfigPath(state.Node.ValueString()), nodeConfigParams(reset), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Reset Proxmox Node Config",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNodeConfigParams(t *testing.T) {
+	plan := nodeConfigResourceModel{
+		Node:                types.StringValue("pve1"),
+		Wakeonlan:           types.StringValue("aa:bb:cc:dd:ee:ff"),
+		StartallOnbootDelay: types.Int64Null(),
+		Description:         types.StringNull(),
+	}
+
+	got := nodeConfigParams(plan)
+	want := map[string]interface{}{
+		"wakeonlan": "aa:bb:cc:dd:ee:ff",
+		"delete":    "startall-onboot-delay,description",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,374 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &nodeDirectoryStorageResource{}
+	_ resource.ResourceWithConfigure      = &nodeDirectoryStorageResource{}
+	_ resource.ResourceWithValidateConfig = &nodeDirectoryStorageResource{}
+)
+
+// NewNodeDirectoryStorageResource is a helper function to simplify the provider implementation.
+func NewNodeDirectoryStorageResource() resource.Resource {
+	return &nodeDirectoryStorageResource{}
+}
+
+// nodeDirectoryStorageResource formats a disk of a node and mounts it as
+// directory storage.
+type nodeDirectoryStorageResource struct {
+	client *apiClient
+}
+
+// nodeDirectoryStorageResourceModel maps the resource schema data.
+type nodeDirectoryStorageResourceModel struct {
+	Node          types.String `tfsdk:"node"`
+	Name          types.String `tfsdk:"name"`
+	Device        types.String `tfsdk:"device"`
+	Filesystem    types.String `tfsdk:"filesystem"`
+	AddStorage    types.Bool   `tfsdk:"add_storage"`
+	CleanupDisks  types.Bool   `tfsdk:"cleanup_disks"`
+	Preallocation types.String `tfsdk:"preallocation"`
+	Path          types.String `tfsdk:"path"`
+}
+
+// nodeDirectory is an entry of the node disks directory endpoint.
+type nodeDirectory struct {
+	Path   string `json:"path"`
+	Device string `json:"device"`
+	Type   string `json:"type"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *nodeDirectoryStorageResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *nodeDirectoryStorageResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_directory_storage"
+}
+
+// Schema defines the schema for the resource.
+func (r *nodeDirectoryStorageResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Formats an unused disk of a node, mounts it below /mnt/pve and optionally registers it as " +
+			"directory storage. Every change recreates the storage and formats the disk again.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the directory and of the storage",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"device": schema.StringAttribute{
+				Required:    true,
+				Description: "Block device of the unused disk, e.g. /dev/sdb",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"filesystem": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("ext4"),
+				Description: "Filesystem to format the disk with, either ext4 (default) or xfs",
+				Validators: []validator.String{
+					stringvalidator.OneOf("ext4", "xfs"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"add_storage": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Register the directory as storage. Defaults to true",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"cleanup_disks": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Wipe the disk when the resource is destroyed. By default only the mount and the storage are removed",
+			},
+			"preallocation": schema.StringAttribute{
+				Optional: true,
+				Description: "Preallocation mode of raw and qcow2 disks created on the storage, one of off, metadata, falloc or full. " +
+					"Requires add_storage. Defaults to the Proxmox VE default, metadata",
+				Validators: []validator.String{
+					stringvalidator.OneOf("off", "metadata", "falloc", "full"),
+				},
+			},
+			"path": schema.StringAttribute{
+				Computed:    true,
+				Description: "Mount point of the directory",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig rejects a preallocation mode without a storage to set it on.
+func (r *nodeDirectoryStorageResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config nodeDirectoryStorageResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Preallocation.IsNull() && !config.AddStorage.IsNull() && !config.AddStorage.IsUnknown() && !config.AddStorage.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("preallocation"),
+			"Preallocation Without Storage",
+			"The preallocation mode is a setting of the storage, which is only created with add_storage = true.",
+		)
+	}
+}
+
+// setPreallocation sets the preallocation mode of the storage of plan, if
+// any.
+func (r *nodeDirectoryStorageResource) setPreallocation(ctx context.Context, plan nodeDirectoryStorageResourceModel) error {
+	if !plan.AddStorage.ValueBool() || plan.Preallocation.IsNull() {
+		return nil
+	}
+
+	return r.client.api.UpdateStorage(ctx, plan.Name.ValueString(), pveapi.StorageRequest{
+		Preallocation: plan.Preallocation.ValueString(),
+	})
+}
+
+// directoryMountPath returns the mount point Proxmox VE uses for a directory.
+func directoryMountPath(name string) string {
+	return fmt.Sprintf("/mnt/pve/%s", name)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *nodeDirectoryStorageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan nodeDirectoryStorageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := map[string]interface{}{
+		"name":        plan.Name.ValueString(),
+		"device":      plan.Device.ValueString(),
+		"filesystem":  plan.Filesystem.ValueString(),
+		"add_storage": proxmoxtf.BoolToInt(plan.AddStorage.ValueBool()),
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, plan.Node.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Creating directory storage", map[string]interface{}{
+		"device": plan.Device.ValueString(),
+	})
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	var upid string
+	err := r.client.Post(ctx, fmt.Sprintf("/nodes/%s/disks/directory", plan.Node.ValueString()), params, &upid)
+	if err == nil {
+		_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox Directory Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	err = r.setPreallocation(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Set Proxmox Storage Preallocation",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	plan.Path = types.StringValue(directoryMountPath(plan.Name.ValueString()))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *nodeDirectoryStorageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeDirectoryStorageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var directories []nodeDirectory
+	err := r.client.Get(ctx, fmt.Sprintf("/nodes/%s/disks/directory", state.Node.ValueString()), &directories)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Directory Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	found := false
+	for _, directory := range directories {
+		if directory.Path == directoryMountPath(state.Name.ValueString()) {
+			found = true
+			state.Filesystem = types.StringValue(directory.Type)
+		}
+	}
+
+	// The mount was removed outside of Terraform.
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if state.AddStorage.ValueBool() && !state.Preallocation.IsNull() {
+		storage, err := r.client.api.Storage(ctx, state.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Proxmox Storage",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		state.Preallocation = proxmoxtf.StringOrNull(storage.Preallocation)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *nodeDirectoryStorageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	// Only cleanup_disks and preallocation can change in place, the former
+	// takes effect on destroy.
+	var plan nodeDirectoryStorageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.setPreallocation(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Set Proxmox Storage Preallocation",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *nodeDirectoryStorageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeDirectoryStorageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, state.Node.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Deleting directory storage")
+
+	release := r.client.acquireNode(ctx, state.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	path := fmt.Sprintf("/nodes/%s/disks/directory/%s?cleanup-config=1&cleanup-disks=%d",
+		state.Node.ValueString(), state.Name.ValueString(), proxmoxtf.BoolToInt(state.CleanupDisks.ValueBool()))
+	var upid string
+	err := r.client.Delete(ctx, path, &upid)
+	if err == nil {
+		_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox Directory Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &nodeKernelDataSource{}
+	_ datasource.DataSourceWithConfigure = &nodeKernelDataSource{}
+)
+
+func NewNodeKernelDataSource() datasource.DataSource {
+	return &nodeKernelDataSource{}
+}
+
+type nodeKernelDataSource struct {
+	client *apiClient
+}
+
+type nodeKernelDataSourceModel struct {
+	Node             types.String `tfsdk:"node"`
+	KernelRelease    types.String `tfsdk:"kernel_release"`
+	KernelVersion    types.String `tfsdk:"kernel_version"`
+	BootMode         types.String `tfsdk:"boot_mode"`
+	SecureBoot       types.Bool   `tfsdk:"secure_boot"`
+	InstalledKernels types.List   `tfsdk:"installed_kernels"`
+}
+
+// nodeKernelStatus is the kernel and boot part of the node status endpoint.
+type nodeKernelStatus struct {
+	KVersion      string `json:"kversion"`
+	CurrentKernel struct {
+		Release string `json:"release"`
+	} `json:"current-kernel"`
+	BootInfo struct {
+		Mode       string `json:"mode"`
+		SecureBoot int    `json:"secureboot"`
+	} `json:"boot-info"`
+}
+
+// aptPackage is an entry of the node apt versions endpoint.
+type aptPackage struct {
+	Package      string `json:"Package"`
+	Version      string `json:"Version"`
+	CurrentState string `json:"CurrentState"`
+}
+
+func (d *nodeKernelDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *nodeKernelDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_kernel"
+}
+
+func (d *nodeKernelDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the running and installed kernels and the boot mode of a node, " +
+			"e.g. to check that all nodes run the expected kernel before applying HA changes.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node",
+			},
+			"kernel_release": schema.StringAttribute{
+				Computed:    true,
+				Description: "Release of the running kernel, e.g. `6.8.12-4-pve`",
+			},
+			"kernel_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "Full version string of the running kernel",
+			},
+			"boot_mode": schema.StringAttribute{
+				Computed:    true,
+				Description: "Firmware the node booted with, either efi or legacy-bios",
+			},
+			"secure_boot": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the node booted with secure boot enabled",
+			},
+			"installed_kernels": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Releases of the installed kernel packages, sorted",
+			},
+		},
+	}
+}
+
+// kernelRelease returns the release of a kernel package, e.g. `6.8.12-4-pve`
+// for proxmox-kernel-6.8.12-4-pve-signed. Meta packages that only depend on
+// the latest kernel are not considered kernel packages.
+func kernelRelease(pkg string) (string, bool) {
+	var release string
+	switch {
+	case strings.HasPrefix(pkg, "proxmox-kernel-"):
+		release = strings.TrimPrefix(pkg, "proxmox-kernel-")
+	case strings.HasPrefix(pkg, "pve-kernel-"):
+		release = strings.TrimPrefix(pkg, "pve-kernel-")
+	default:
+		return "", false
+	}
+
+	release = strings.TrimSuffix(release, "-signed")
+	return release, strings.HasSuffix(release, "-pve")
+}
+
+// installedKernels returns the sorted releases of the installed kernel packages.
+func installedKernels(packages []aptPackage) []string {
+	kernels := []string{}
+	for _, pkg := range packages {
+		if pkg.CurrentState != "Installed" {
+			continue
+		}
+		if release, ok := kernelRelease(pkg.Package); ok {
+			kernels = append(kernels, release)
+		}
+	}
+	sort.Strings(kernels)
+
+	return kernels
+}
+
+func (d *nodeKernelDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state nodeKernelDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node := state.Node.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node)
+	tflog.SubsystemDebug(ctx, logAPI, "Reading node kernel")
+
+	var status nodeKernelStatus
+	err := d.client.Get(ctx, fmt.Sprintf("/nodes/%s/status", node), &status)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Node Status",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	var packages []aptPackage
+	err = d.client.Get(ctx, fmt.Sprintf("/nodes/%s/apt/versions", node), &packages)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Node Package Versions",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.KernelRelease = types.StringValue(status.CurrentKernel.Release)
+	state.KernelVersion = types.StringValue(status.KVersion)
+	state.BootMode = types.StringValue(status.BootInfo.Mode)
+	state.SecureBoot = proxmoxtf.Bool(status.BootInfo.SecureBoot)
+
+	state.InstalledKernels, diags = types.ListValueFrom(ctx, types.StringType, installedKernels(packages))
+	resp.Diagnostics.Append(diags...)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInstalledKernels(t *testing.T) {
+	packages := []aptPackage{
+		{Package: "proxmox-ve", Version: "8.2.0", CurrentState: "Installed"},
+		{Package: "proxmox-kernel-6.8", Version: "6.8.12-4", CurrentState: "Installed"},
+		{Package: "proxmox-kernel-6.8.12-4-pve-signed", Version: "6.8.12-4", CurrentState: "Installed"},
+		{Package: "proxmox-kernel-6.5.13-6-pve-signed", Version: "6.5.13-6", CurrentState: "Installed"},
+		{Package: "pve-kernel-5.15.158-2-pve", Version: "5.15.158-2", CurrentState: "ConfigFiles"},
+	}
+
+	got := installedKernels(packages)
+	want := []string{"6.5.13-6-pve", "6.8.12-4-pve"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
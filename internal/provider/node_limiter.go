@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// nodeLimiter bounds the number of guest operations, e.g. creating or
+// destroying guests, that run at once per node. Such operations lock guest
+// configs and storages of the node, so that parallel applies collide
+// otherwise.
+type nodeLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// newNodeLimiter returns a nodeLimiter allowing limit operations per node.
+func newNodeLimiter(limit int) *nodeLimiter {
+	return &nodeLimiter{
+		limit: limit,
+		slots: map[string]chan struct{}{},
+	}
+}
+
+// nodeSlots returns the slots of node, creating them on first use.
+func (l *nodeLimiter) nodeSlots(node string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots, ok := l.slots[node]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.slots[node] = slots
+	}
+
+	return slots
+}
+
+// acquire waits for a free slot on node and returns the function that
+// releases it.
+func (l *nodeLimiter) acquire(ctx context.Context, node string) (func(), error) {
+	slots := l.nodeSlots(node)
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	default:
+	}
+
+	tflog.SubsystemDebug(ctx, logTask, "Waiting for a free operation slot on node", map[string]interface{}{
+		logFieldNode: node,
+	})
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return func() {}, ctx.Err()
+	}
+}
+
+// acquireNode waits until an operation may run on node under the
+// node_parallelism of the provider, adding an error to diags when the wait is
+// canceled. The returned function releases the slot and must be called when
+// the operation is done.
+func (c *apiClient) acquireNode(ctx context.Context, node string, diags *diag.Diagnostics) func() {
+	if c.nodeLimiter == nil {
+		return func() {}
+	}
+
+	release, err := c.nodeLimiter.acquire(ctx, node)
+	if err != nil {
+		diags.AddError(
+			"Unable to Start Proxmox Node Operation",
+			fmt.Sprintf("Waiting for a free operation slot on node %s under node_parallelism: %s", node, err),
+		)
+	}
+
+	return release
+}
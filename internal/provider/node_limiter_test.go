@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNodeLimiter(t *testing.T) {
+	limiter := newNodeLimiter(1)
+	ctx := context.Background()
+
+	release, err := limiter.acquire(ctx, "pve1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other nodes have their own slots.
+	releaseOther, err := limiter.acquire(ctx, "pve2")
+	if err != nil {
+		t.Fatalf("pve2 should not wait for pve1: %s", err)
+	}
+	releaseOther()
+
+	// The node is busy until the slot is released.
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(waitCtx, "pve1"); err == nil {
+		t.Fatal("expected the second operation on pve1 to wait")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := limiter.acquire(ctx, "pve1")
+		if err == nil {
+			release()
+		}
+		close(acquired)
+	}()
+	release()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("the released slot was not handed on")
+	}
+}
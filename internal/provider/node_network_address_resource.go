@@ -0,0 +1,371 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &nodeNetworkAddressResource{}
+	_ resource.ResourceWithConfigure = &nodeNetworkAddressResource{}
+)
+
+// NewNodeNetworkAddressResource is a helper function to simplify the provider implementation.
+func NewNodeNetworkAddressResource() resource.Resource {
+	return &nodeNetworkAddressResource{}
+}
+
+// nodeNetworkAddressResource manages the addresses and gateways of an
+// existing network interface of a node.
+type nodeNetworkAddressResource struct {
+	client *apiClient
+}
+
+// nodeNetworkAddressResourceModel maps the resource schema data.
+type nodeNetworkAddressResourceModel struct {
+	Node     types.String `tfsdk:"node"`
+	Iface    types.String `tfsdk:"iface"`
+	CIDR     types.String `tfsdk:"cidr"`
+	Gateway  types.String `tfsdk:"gateway"`
+	CIDR6    types.String `tfsdk:"cidr6"`
+	Gateway6 types.String `tfsdk:"gateway6"`
+	MTU      types.Int64  `tfsdk:"mtu"`
+	Comments types.String `tfsdk:"comments"`
+	Apply    types.Bool   `tfsdk:"apply"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *nodeNetworkAddressResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *nodeNetworkAddressResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_network_address"
+}
+
+// Schema defines the schema for the resource.
+func (r *nodeNetworkAddressResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the addresses, default gateways and MTU of an existing network interface of a node, " +
+			"e.g. of a second bridge on a multi-homed node. Only the set attributes are managed, every other option of " +
+			"the interface is left alone. A node has at most one default gateway per address family. Static routes and " +
+			"post-up commands are not available through the Proxmox VE API.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node of the interface",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"iface": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the interface, e.g. vmbr1",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cidr": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv4 address with prefix length, e.g. 10.0.1.2/24",
+			},
+			"gateway": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv4 default gateway",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("cidr")),
+				},
+			},
+			"cidr6": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv6 address with prefix length",
+			},
+			"gateway6": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv6 default gateway",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("cidr6")),
+				},
+			},
+			"mtu": schema.Int64Attribute{
+				Optional:    true,
+				Description: "MTU of the interface",
+				Validators: []validator.Int64{
+					int64validator.Between(1280, 65520),
+				},
+			},
+			"comments": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comments of the interface",
+			},
+			"apply": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Reload the network config of the node after changes, which requires ifupdown2. Otherwise the changes stay pending until they are applied. Defaults to true",
+			},
+		},
+	}
+}
+
+// networkAddressRequest maps the plan onto the options of the interface.
+// Options that were managed in state but are no longer set are removed.
+func networkAddressRequest(ifaceType string, plan, state nodeNetworkAddressResourceModel) pveapi.NodeNetworkRequest {
+	params := pveapi.NodeNetworkRequest{
+		Type:     ifaceType,
+		CIDR:     plan.CIDR.ValueString(),
+		Gateway:  plan.Gateway.ValueString(),
+		CIDR6:    plan.CIDR6.ValueString(),
+		Gateway6: plan.Gateway6.ValueString(),
+		MTU:      plan.MTU.ValueInt64(),
+		Comments: plan.Comments.ValueString(),
+	}
+
+	var remove []string
+	options := []struct {
+		name         string
+		planned, set bool
+	}{
+		{"cidr", !plan.CIDR.IsNull(), !state.CIDR.IsNull()},
+		{"gateway", !plan.Gateway.IsNull(), !state.Gateway.IsNull()},
+		{"cidr6", !plan.CIDR6.IsNull(), !state.CIDR6.IsNull()},
+		{"gateway6", !plan.Gateway6.IsNull(), !state.Gateway6.IsNull()},
+		{"mtu", !plan.MTU.IsNull(), !state.MTU.IsNull()},
+		{"comments", !plan.Comments.IsNull(), !state.Comments.IsNull()},
+	}
+	for _, option := range options {
+		if option.set && !option.planned {
+			remove = append(remove, option.name)
+		}
+	}
+	params.Delete = strings.Join(remove, ",")
+
+	return params
+}
+
+// write applies the plan to the interface and reloads the network config.
+func (r *nodeNetworkAddressResource) write(ctx context.Context, plan, state nodeNetworkAddressResourceModel) error {
+	node, iface := plan.Node.ValueString(), plan.Iface.ValueString()
+
+	// The API requires the type of the interface on every update.
+	network, err := r.client.api.NodeNetwork(ctx, node, iface)
+	if err != nil {
+		return err
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node)
+	tflog.SubsystemInfo(ctx, logAPI, "Updating network interface", map[string]interface{}{
+		"iface": iface,
+	})
+	if err := r.client.api.UpdateNodeNetwork(ctx, node, iface, networkAddressRequest(network.Type, plan, state)); err != nil {
+		return err
+	}
+
+	if !plan.Apply.ValueBool() {
+		return nil
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Reloading network config")
+	upid, err := r.client.api.ReloadNodeNetwork(ctx, node)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *nodeNetworkAddressResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan nodeNetworkAddressResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	// Nothing is managed yet, so nothing is removed.
+	err := r.write(ctx, plan, nodeNetworkAddressResourceModel{
+		CIDR:     types.StringNull(),
+		Gateway:  types.StringNull(),
+		CIDR6:    types.StringNull(),
+		Gateway6: types.StringNull(),
+		MTU:      types.Int64Null(),
+		Comments: types.StringNull(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Node Network",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *nodeNetworkAddressResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeNetworkAddressResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	network, err := r.client.api.NodeNetwork(ctx, state.Node.ValueString(), state.Iface.ValueString())
+	if err != nil {
+		// The interface was removed outside of Terraform.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Node Network",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// Only the managed options are refreshed.
+	if !state.CIDR.IsNull() {
+		state.CIDR = proxmoxtf.StringOrNull(network.CIDR)
+	}
+	if !state.Gateway.IsNull() {
+		state.Gateway = proxmoxtf.StringOrNull(network.Gateway)
+	}
+	if !state.CIDR6.IsNull() {
+		state.CIDR6 = proxmoxtf.StringOrNull(network.CIDR6)
+	}
+	if !state.Gateway6.IsNull() {
+		state.Gateway6 = proxmoxtf.StringOrNull(network.Gateway6)
+	}
+	if !state.MTU.IsNull() {
+		state.MTU = proxmoxtf.Int64OrNull(int64(network.MTU))
+	}
+	if !state.Comments.IsNull() {
+		state.Comments = proxmoxtf.StringOrNull(strings.TrimSpace(network.Comments))
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *nodeNetworkAddressResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan, state nodeNetworkAddressResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	err := r.write(ctx, plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Node Network",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *nodeNetworkAddressResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeNetworkAddressResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, state.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	// Removing the managed options leaves the interface itself in place.
+	plan := state
+	plan.CIDR, plan.Gateway = types.StringNull(), types.StringNull()
+	plan.CIDR6, plan.Gateway6 = types.StringNull(), types.StringNull()
+	plan.MTU, plan.Comments = types.Int64Null(), types.StringNull()
+
+	err := r.write(ctx, plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Node Network",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
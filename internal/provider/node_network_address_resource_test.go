@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestNetworkAddressRequest(t *testing.T) {
+	unset := nodeNetworkAddressResourceModel{
+		CIDR:     types.StringNull(),
+		Gateway:  types.StringNull(),
+		CIDR6:    types.StringNull(),
+		Gateway6: types.StringNull(),
+		MTU:      types.Int64Null(),
+		Comments: types.StringNull(),
+	}
+
+	managed := unset
+	managed.CIDR = types.StringValue("10.10.0.11/24")
+	managed.Gateway = types.StringValue("10.10.0.1")
+	managed.MTU = types.Int64Value(9000)
+
+	tests := map[string]struct {
+		plan, state nodeNetworkAddressResourceModel
+		want        pveapi.NodeNetworkRequest
+	}{
+		"create": {
+			plan:  managed,
+			state: unset,
+			want: pveapi.NodeNetworkRequest{
+				Type:    "bridge",
+				CIDR:    "10.10.0.11/24",
+				Gateway: "10.10.0.1",
+				MTU:     9000,
+			},
+		},
+		"removed gateway": {
+			plan: func() nodeNetworkAddressResourceModel {
+				m := managed
+				m.Gateway = types.StringNull()
+				return m
+			}(),
+			state: managed,
+			want: pveapi.NodeNetworkRequest{
+				Type:   "bridge",
+				CIDR:   "10.10.0.11/24",
+				MTU:    9000,
+				Delete: "gateway",
+			},
+		},
+		"delete": {
+			plan:  unset,
+			state: managed,
+			want: pveapi.NodeNetworkRequest{
+				Type:   "bridge",
+				Delete: "cidr,gateway,mtu",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := networkAddressRequest("bridge", tc.plan, tc.state)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
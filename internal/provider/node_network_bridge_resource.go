@@ -0,0 +1,447 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &nodeNetworkBridgeResource{}
+	_ resource.ResourceWithConfigure      = &nodeNetworkBridgeResource{}
+	_ resource.ResourceWithValidateConfig = &nodeNetworkBridgeResource{}
+)
+
+// bridgeVids matches the VLAN IDs trunked by a VLAN aware bridge, e.g.
+// `2 10 100-200`.
+var bridgeVids = regexp.MustCompile(`^\d+(-\d+)?( \d+(-\d+)?)*$`)
+
+// NewNodeNetworkBridgeResource is a helper function to simplify the provider implementation.
+func NewNodeNetworkBridgeResource() resource.Resource {
+	return &nodeNetworkBridgeResource{}
+}
+
+// nodeNetworkBridgeResource manages a Linux bridge of a node.
+type nodeNetworkBridgeResource struct {
+	client *apiClient
+}
+
+// nodeNetworkBridgeResourceModel maps the resource schema data.
+type nodeNetworkBridgeResourceModel struct {
+	Node            types.String `tfsdk:"node"`
+	Iface           types.String `tfsdk:"iface"`
+	BridgePorts     types.String `tfsdk:"bridge_ports"`
+	BridgeVlanAware types.Bool   `tfsdk:"bridge_vlan_aware"`
+	BridgeVids      types.String `tfsdk:"bridge_vids"`
+	Autostart       types.Bool   `tfsdk:"autostart"`
+	CIDR            types.String `tfsdk:"cidr"`
+	Gateway         types.String `tfsdk:"gateway"`
+	CIDR6           types.String `tfsdk:"cidr6"`
+	Gateway6        types.String `tfsdk:"gateway6"`
+	MTU             types.Int64  `tfsdk:"mtu"`
+	Comments        types.String `tfsdk:"comments"`
+	Apply           types.Bool   `tfsdk:"apply"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *nodeNetworkBridgeResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *nodeNetworkBridgeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_network_bridge"
+}
+
+// Schema defines the schema for the resource.
+func (r *nodeNetworkBridgeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a Linux bridge of a node, e.g. the VLAN aware bridge of an SDN vlan zone. SDN vnets warn at " +
+			"plan time when the bridge of their zone does not trunk their tag. Do not manage the addresses of the bridge " +
+			"with proxmox_node_network_address as well.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node of the bridge",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"iface": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bridge, e.g. vmbr1",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"bridge_ports": schema.StringAttribute{
+				Optional:    true,
+				Description: "Space separated ports of the bridge, e.g. `eno2` or a bond. A bridge without ports only connects the guests of the node",
+			},
+			"bridge_vlan_aware": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Make the bridge VLAN aware, so that guests and SDN vlan zones can use VLAN tags on it. Defaults to false",
+			},
+			"bridge_vids": schema.StringAttribute{
+				Optional: true,
+				Description: "VLAN IDs trunked by the VLAN aware bridge, e.g. `2 10 100-200`. Requires bridge_vlan_aware. " +
+					"Defaults to 2-4094",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(bridgeVids, "must be space separated VLAN IDs or ranges, e.g. `2 10 100-200`"),
+				},
+			},
+			"autostart": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Bring the bridge up when the node boots. Defaults to true",
+			},
+			"cidr": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv4 address with prefix length, e.g. 10.0.1.2/24",
+			},
+			"gateway": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv4 default gateway",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("cidr")),
+				},
+			},
+			"cidr6": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv6 address with prefix length",
+			},
+			"gateway6": schema.StringAttribute{
+				Optional:    true,
+				Description: "IPv6 default gateway",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("cidr6")),
+				},
+			},
+			"mtu": schema.Int64Attribute{
+				Optional:    true,
+				Description: "MTU of the bridge",
+				Validators: []validator.Int64{
+					int64validator.Between(1280, 65520),
+				},
+			},
+			"comments": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comments of the bridge",
+			},
+			"apply": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Reload the network config of the node after changes, which requires ifupdown2. Otherwise the changes stay pending until they are applied. Defaults to true",
+			},
+		},
+	}
+}
+
+// ValidateConfig rejects bridge_vids on bridges that are not VLAN aware,
+// which ignore them.
+func (r *nodeNetworkBridgeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config nodeNetworkBridgeResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.BridgeVids.IsNull() && !config.BridgeVlanAware.IsUnknown() && !config.BridgeVlanAware.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("bridge_vids"),
+			"Bridge Is Not VLAN Aware",
+			"bridge_vids only applies to VLAN aware bridges, set bridge_vlan_aware = true.",
+		)
+	}
+}
+
+// bridgeRequest maps the plan onto the options of the bridge. Options that
+// were set in state but are no longer set are removed.
+func bridgeRequest(plan, state nodeNetworkBridgeResourceModel) pveapi.NodeNetworkRequest {
+	params := pveapi.NodeNetworkRequest{
+		Type:            "bridge",
+		Autostart:       proxmoxtf.BoolToInt(plan.Autostart.ValueBool()),
+		CIDR:            plan.CIDR.ValueString(),
+		Gateway:         plan.Gateway.ValueString(),
+		CIDR6:           plan.CIDR6.ValueString(),
+		Gateway6:        plan.Gateway6.ValueString(),
+		MTU:             plan.MTU.ValueInt64(),
+		Comments:        plan.Comments.ValueString(),
+		BridgePorts:     plan.BridgePorts.ValueString(),
+		BridgeVlanAware: proxmoxtf.BoolToInt(plan.BridgeVlanAware.ValueBool()),
+		BridgeVids:      plan.BridgeVids.ValueString(),
+	}
+
+	var remove []string
+	options := []struct {
+		name         string
+		planned, set bool
+	}{
+		{"autostart", plan.Autostart.ValueBool(), state.Autostart.ValueBool()},
+		{"cidr", !plan.CIDR.IsNull(), !state.CIDR.IsNull()},
+		{"gateway", !plan.Gateway.IsNull(), !state.Gateway.IsNull()},
+		{"cidr6", !plan.CIDR6.IsNull(), !state.CIDR6.IsNull()},
+		{"gateway6", !plan.Gateway6.IsNull(), !state.Gateway6.IsNull()},
+		{"mtu", !plan.MTU.IsNull(), !state.MTU.IsNull()},
+		{"comments", !plan.Comments.IsNull(), !state.Comments.IsNull()},
+		{"bridge_ports", !plan.BridgePorts.IsNull(), !state.BridgePorts.IsNull()},
+		{"bridge_vlan_aware", plan.BridgeVlanAware.ValueBool(), state.BridgeVlanAware.ValueBool()},
+		{"bridge_vids", !plan.BridgeVids.IsNull(), !state.BridgeVids.IsNull()},
+	}
+	for _, option := range options {
+		if option.set && !option.planned {
+			remove = append(remove, option.name)
+		}
+	}
+	params.Delete = strings.Join(remove, ",")
+
+	return params
+}
+
+// reload applies the pending network changes of node unless apply is
+// false.
+func (r *nodeNetworkBridgeResource) reload(ctx context.Context, node string, apply types.Bool) error {
+	if !apply.ValueBool() {
+		return nil
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Reloading network config")
+	upid, err := r.client.api.ReloadNodeNetwork(ctx, node)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *nodeNetworkBridgeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan nodeNetworkBridgeResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	node, iface := plan.Node.ValueString(), plan.Iface.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node)
+	tflog.SubsystemInfo(ctx, logAPI, "Creating network bridge", map[string]interface{}{
+		"iface": iface,
+	})
+
+	// Nothing is set yet, so nothing is removed.
+	err := r.client.api.CreateNodeNetwork(ctx, node, iface, bridgeRequest(plan, nodeNetworkBridgeResourceModel{
+		CIDR:        types.StringNull(),
+		Gateway:     types.StringNull(),
+		CIDR6:       types.StringNull(),
+		Gateway6:    types.StringNull(),
+		MTU:         types.Int64Null(),
+		Comments:    types.StringNull(),
+		BridgePorts: types.StringNull(),
+		BridgeVids:  types.StringNull(),
+	}))
+	if err == nil {
+		err = r.reload(ctx, node, plan.Apply)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox Node Network Bridge",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *nodeNetworkBridgeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeNetworkBridgeResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	network, err := r.client.api.NodeNetwork(ctx, state.Node.ValueString(), state.Iface.ValueString())
+	if err != nil {
+		// The bridge was removed outside of Terraform.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Node Network Bridge",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.BridgeVlanAware = proxmoxtf.Bool(network.BridgeVlanAware)
+	state.Autostart = proxmoxtf.Bool(network.Autostart)
+
+	// Only the set options are refreshed, the node fills in defaults for
+	// some of the others, e.g. bridge_vids of VLAN aware bridges.
+	if !state.BridgePorts.IsNull() {
+		state.BridgePorts = proxmoxtf.StringOrNull(network.BridgePorts)
+	}
+	if !state.BridgeVids.IsNull() {
+		state.BridgeVids = proxmoxtf.StringOrNull(network.BridgeVids)
+	}
+	if !state.CIDR.IsNull() {
+		state.CIDR = proxmoxtf.StringOrNull(network.CIDR)
+	}
+	if !state.Gateway.IsNull() {
+		state.Gateway = proxmoxtf.StringOrNull(network.Gateway)
+	}
+	if !state.CIDR6.IsNull() {
+		state.CIDR6 = proxmoxtf.StringOrNull(network.CIDR6)
+	}
+	if !state.Gateway6.IsNull() {
+		state.Gateway6 = proxmoxtf.StringOrNull(network.Gateway6)
+	}
+	if !state.MTU.IsNull() {
+		state.MTU = proxmoxtf.Int64OrNull(int64(network.MTU))
+	}
+	if !state.Comments.IsNull() {
+		state.Comments = proxmoxtf.StringOrNull(strings.TrimSpace(network.Comments))
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *nodeNetworkBridgeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan, state nodeNetworkBridgeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	node, iface := plan.Node.ValueString(), plan.Iface.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node)
+	tflog.SubsystemInfo(ctx, logAPI, "Updating network bridge", map[string]interface{}{
+		"iface": iface,
+	})
+
+	err := r.client.api.UpdateNodeNetwork(ctx, node, iface, bridgeRequest(plan, state))
+	if err == nil {
+		err = r.reload(ctx, node, plan.Apply)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox Node Network Bridge",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *nodeNetworkBridgeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state nodeNetworkBridgeResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, state.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	node, iface := state.Node.ValueString(), state.Iface.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node)
+	tflog.SubsystemInfo(ctx, logAPI, "Removing network bridge", map[string]interface{}{
+		"iface": iface,
+	})
+
+	err := r.client.api.DeleteNodeNetwork(ctx, node, iface)
+	if err == nil {
+		err = r.reload(ctx, node, state.Apply)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox Node Network Bridge",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestBridgeRequest(t *testing.T) {
+	unset := nodeNetworkBridgeResourceModel{
+		BridgePorts: types.StringNull(),
+		BridgeVids:  types.StringNull(),
+		Autostart:   types.BoolValue(true),
+		CIDR:        types.StringNull(),
+		Gateway:     types.StringNull(),
+		CIDR6:       types.StringNull(),
+		Gateway6:    types.StringNull(),
+		MTU:         types.Int64Null(),
+		Comments:    types.StringNull(),
+	}
+
+	trunk := unset
+	trunk.BridgePorts = types.StringValue("eno2")
+	trunk.BridgeVlanAware = types.BoolValue(true)
+	trunk.BridgeVids = types.StringValue("10 20 100-199")
+
+	tests := map[string]struct {
+		plan, state nodeNetworkBridgeResourceModel
+		want        pveapi.NodeNetworkRequest
+	}{
+		"create": {
+			plan:  trunk,
+			state: nodeNetworkBridgeResourceModel{},
+			want: pveapi.NodeNetworkRequest{
+				Type:            "bridge",
+				Autostart:       1,
+				BridgePorts:     "eno2",
+				BridgeVlanAware: 1,
+				BridgeVids:      "10 20 100-199",
+			},
+		},
+		"no longer VLAN aware": {
+			plan: func() nodeNetworkBridgeResourceModel {
+				m := trunk
+				m.BridgeVlanAware = types.BoolValue(false)
+				m.BridgeVids = types.StringNull()
+				return m
+			}(),
+			state: trunk,
+			want: pveapi.NodeNetworkRequest{
+				Type:        "bridge",
+				Autostart:   1,
+				BridgePorts: "eno2",
+				Delete:      "bridge_vlan_aware,bridge_vids",
+			},
+		},
+		"no autostart": {
+			plan: func() nodeNetworkBridgeResourceModel {
+				m := unset
+				m.Autostart = types.BoolValue(false)
+				return m
+			}(),
+			state: unset,
+			want: pveapi.NodeNetworkRequest{
+				Type:   "bridge",
+				Delete: "autostart",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := bridgeRequest(tc.plan, tc.state)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
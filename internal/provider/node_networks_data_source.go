@@ -8,7 +8,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/luthermonson/go-proxmox"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
 )
 
 var (
@@ -21,7 +23,7 @@ func NewNodeNetworksDataSource() datasource.DataSource {
 }
 
 type nodeNetworksDataSource struct {
-	client *proxmox.Client
+	client *apiClient
 }
 
 type nodeNetworkModel struct {
@@ -29,6 +31,17 @@ type nodeNetworkModel struct {
 	Method types.String `tfsdk:"method"`
 	Iface  types.String `tfsdk:"iface"`
 	Type   types.String `tfsdk:"type"`
+
+	CIDR     types.String `tfsdk:"cidr"`
+	Gateway  types.String `tfsdk:"gateway"`
+	CIDR6    types.String `tfsdk:"cidr6"`
+	Gateway6 types.String `tfsdk:"gateway6"`
+	MTU      types.Int64  `tfsdk:"mtu"`
+	Comments types.String `tfsdk:"comments"`
+
+	BridgePorts     types.String `tfsdk:"bridge_ports"`
+	BridgeVlanAware types.Bool   `tfsdk:"bridge_vlan_aware"`
+	BridgeVids      types.String `tfsdk:"bridge_vids"`
 }
 
 type nodeNetworksDataSourceModel struct {
@@ -43,11 +56,11 @@ func (d *nodeNetworksDataSource) Configure(_ context.Context, req datasource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*proxmox.Client)
+	client, ok := req.ProviderData.(*apiClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *proxmox.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -82,6 +95,43 @@ func (d *nodeNetworksDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 						"type": schema.StringAttribute{
 							Computed: true,
 						},
+						"cidr": schema.StringAttribute{
+							Computed:    true,
+							Description: "IPv4 address with prefix length",
+						},
+						"gateway": schema.StringAttribute{
+							Computed:    true,
+							Description: "IPv4 default gateway",
+						},
+						"cidr6": schema.StringAttribute{
+							Computed:    true,
+							Description: "IPv6 address with prefix length",
+						},
+						"gateway6": schema.StringAttribute{
+							Computed:    true,
+							Description: "IPv6 default gateway",
+						},
+						"mtu": schema.Int64Attribute{
+							Computed:    true,
+							Description: "MTU of the interface",
+						},
+						"comments": schema.StringAttribute{
+							Computed:    true,
+							Description: "Comments of the interface",
+						},
+						"bridge_ports": schema.StringAttribute{
+							Computed:    true,
+							Description: "Space separated ports of the bridge",
+						},
+						"bridge_vlan_aware": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the bridge is VLAN aware",
+						},
+						"bridge_vids": schema.StringAttribute{
+							Computed: true,
+							Description: "VLAN IDs trunked by a VLAN aware bridge, e.g. `2 10 100-200`. " +
+								"A VLAN aware bridge without the setting trunks 2-4094",
+						},
 					},
 				},
 			},
@@ -100,20 +150,15 @@ func (d *nodeNetworksDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
-	node, err := d.client.Node(ctx, nodeName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Proxmox Node",
-			err.Error(),
-		)
-		return
-	}
+	ctx = d.client.logContext(ctx)
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, nodeName.ValueString())
 
-	networks, err := node.Networks(ctx)
+	tflog.SubsystemDebug(ctx, logAPI, "Reading node networks")
+	networks, err := d.client.api.NodeNetworks(ctx, nodeName.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Proxmox Node Networks",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
@@ -127,6 +172,17 @@ func (d *nodeNetworksDataSource) Read(ctx context.Context, req datasource.ReadRe
 			Method: types.StringValue(network.Method),
 			Iface:  types.StringValue(network.Iface),
 			Type:   types.StringValue(network.Type),
+
+			CIDR:     proxmoxtf.StringOrNull(network.CIDR),
+			Gateway:  proxmoxtf.StringOrNull(network.Gateway),
+			CIDR6:    proxmoxtf.StringOrNull(network.CIDR6),
+			Gateway6: proxmoxtf.StringOrNull(network.Gateway6),
+			MTU:      proxmoxtf.Int64OrNull(int64(network.MTU)),
+			Comments: proxmoxtf.StringOrNull(network.Comments),
+
+			BridgePorts:     proxmoxtf.StringOrNull(network.BridgePorts),
+			BridgeVlanAware: proxmoxtf.Bool(network.BridgeVlanAware),
+			BridgeVids:      proxmoxtf.StringOrNull(network.BridgeVids),
 		}
 
 		state.Networks = append(state.Networks, networkState)
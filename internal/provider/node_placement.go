@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// placementStrategies are the strategies to choose the node of a guest
+// with.
+var placementStrategies = []string{"least-memory", "least-cpu", "spread"}
+
+// nodePlacementModel maps the placement of a guest that is created on the
+// least loaded of a set of nodes.
+type nodePlacementModel struct {
+	Strategy types.String   `tfsdk:"strategy"`
+	Nodes    []types.String `tfsdk:"nodes"`
+	Tag      types.String   `tfsdk:"tag"`
+}
+
+// nodePlacementAttribute returns the schema attribute of guest resources
+// that choose the node of the guest when it is created.
+func nodePlacementAttribute() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Description: "Create the guest on the least loaded online node instead of a fixed node, chosen from the cluster " +
+			"resources when the guest is created and kept in node afterwards. Guests placed by one provider in the same " +
+			"apply count towards the load of their node. Conflicts with node",
+		Validators: []validator.Object{
+			objectvalidator.ConflictsWith(path.MatchRoot("node")),
+		},
+		Attributes: map[string]schema.Attribute{
+			"strategy": schema.StringAttribute{
+				Required: true,
+				Description: "How the node is chosen: least-memory for the lowest share of used memory, least-cpu for the " +
+					"lowest CPU usage, or spread for the fewest guests, only counting guests with tag when set",
+				Validators: []validator.String{
+					stringvalidator.OneOf(placementStrategies...),
+				},
+			},
+			"nodes": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Nodes the guest may be placed on. Defaults to all nodes of the cluster",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"tag": schema.StringAttribute{
+				Optional:    true,
+				Description: "Tag of the guests spread across the nodes, e.g. the tag of the guests of one service",
+			},
+		},
+	}
+}
+
+// placementLoad is the load of the guests placed on a node whose creation
+// is not visible in the cluster resources yet.
+type placementLoad struct {
+	guests int
+	memory uint64
+	cpus   float64
+}
+
+// guestPlacements holds the load of the guests being placed, so that guests
+// placed in parallel do not all choose the same node. The zero value is
+// ready to use.
+type guestPlacements struct {
+	mu      sync.Mutex
+	pending map[string]placementLoad
+}
+
+// chooseNode returns the node of resources that placement puts a guest on,
+// given the load already placed on the nodes by pending.
+func chooseNode(resources proxmox.ClusterResources, placement nodePlacementModel, pending map[string]placementLoad) (string, error) {
+	var candidates []string
+	for _, node := range placement.Nodes {
+		candidates = append(candidates, node.ValueString())
+	}
+
+	nodes := map[string]*proxmox.ClusterResource{}
+	guests := map[string]int{}
+	for _, res := range resources {
+		switch {
+		case res.Type == "node" && res.Status == "online" && (len(candidates) == 0 || slices.Contains(candidates, res.Node)):
+			nodes[res.Node] = res
+		case (res.Type == "qemu" || res.Type == "lxc") && res.Template == 0:
+			if tag := placement.Tag.ValueString(); tag == "" || slices.Contains(strings.Split(res.Tags, ";"), tag) {
+				guests[res.Node]++
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		if len(candidates) > 0 {
+			return "", fmt.Errorf("none of the nodes %s is online", strings.Join(candidates, ", "))
+		}
+		return "", fmt.Errorf("no node of the cluster is online")
+	}
+
+	score := func(node *proxmox.ClusterResource) float64 {
+		load := pending[node.Node]
+		switch placement.Strategy.ValueString() {
+		case "least-memory":
+			if node.MaxMem == 0 {
+				return 1
+			}
+			return float64(node.Mem+load.memory) / float64(node.MaxMem)
+		case "least-cpu":
+			if node.MaxCPU == 0 {
+				return 1
+			}
+			return node.CPU + load.cpus/float64(node.MaxCPU)
+		default:
+			return float64(guests[node.Node] + load.guests)
+		}
+	}
+
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best := names[0]
+	for _, name := range names[1:] {
+		if score(nodes[name]) < score(nodes[best]) {
+			best = name
+		}
+	}
+
+	return best, nil
+}
+
+// placeGuest chooses the node of a guest with placement, which needs memory
+// bytes and cpus, and counts the guest towards the load of the node until
+// the returned function is called once the guest is created.
+func (c *apiClient) placeGuest(ctx context.Context, placement nodePlacementModel, memory uint64, cpus float64) (string, func(), error) {
+	cluster, err := c.Cluster(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	resources, err := cluster.Resources(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c.placements.mu.Lock()
+	defer c.placements.mu.Unlock()
+
+	node, err := chooseNode(resources, placement, c.placements.pending)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.placements.pending == nil {
+		c.placements.pending = map[string]placementLoad{}
+	}
+	load := c.placements.pending[node]
+	load.guests++
+	load.memory += memory
+	load.cpus += cpus
+	c.placements.pending[node] = load
+
+	tflog.SubsystemInfo(ctx, logVM, "Placing guest on node", map[string]interface{}{
+		"strategy":   placement.Strategy.ValueString(),
+		logFieldNode: node,
+	})
+
+	release := func() {
+		c.placements.mu.Lock()
+		defer c.placements.mu.Unlock()
+
+		load := c.placements.pending[node]
+		load.guests--
+		load.memory -= memory
+		load.cpus -= cpus
+		c.placements.pending[node] = load
+	}
+	return node, release, nil
+}
+
+// configuresPlacement reports whether config places the guest with a
+// placement block, leaving its node to be chosen when it is created.
+func configuresPlacement(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) bool {
+	var placement types.Object
+	diags.Append(config.GetAttribute(ctx, path.Root("placement"), &placement)...)
+
+	return !placement.IsNull()
+}
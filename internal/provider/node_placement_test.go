@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/luthermonson/go-proxmox"
+)
+
+func TestChooseNode(t *testing.T) {
+	resources := proxmox.ClusterResources{
+		{Type: "node", Node: "pve1", Status: "online", Mem: 48 << 30, MaxMem: 64 << 30, CPU: 0.10, MaxCPU: 16},
+		{Type: "node", Node: "pve2", Status: "online", Mem: 16 << 30, MaxMem: 64 << 30, CPU: 0.60, MaxCPU: 16},
+		{Type: "node", Node: "pve3", Status: "online", Mem: 40 << 30, MaxMem: 128 << 30, CPU: 0.30, MaxCPU: 32},
+		{Type: "node", Node: "pve4", Status: "offline"},
+		{Type: "qemu", Node: "pve1", VMID: 100, Tags: "web"},
+		{Type: "qemu", Node: "pve2", VMID: 101, Tags: "db;web"},
+		{Type: "qemu", Node: "pve2", VMID: 102},
+		{Type: "lxc", Node: "pve3", VMID: 200},
+		{Type: "lxc", Node: "pve3", VMID: 201},
+		{Type: "qemu", Node: "pve3", VMID: 9000, Template: 1, Tags: "web"},
+		{Type: "storage", Node: "pve1", Storage: "local"},
+	}
+	nodes := func(names ...string) []types.String {
+		var list []types.String
+		for _, name := range names {
+			list = append(list, types.StringValue(name))
+		}
+		return list
+	}
+
+	tests := []struct {
+		name      string
+		placement nodePlacementModel
+		pending   map[string]placementLoad
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "least memory",
+			placement: nodePlacementModel{Strategy: types.StringValue("least-memory")},
+			want:      "pve2",
+		},
+		{
+			name:      "least memory with pending guests",
+			placement: nodePlacementModel{Strategy: types.StringValue("least-memory")},
+			pending:   map[string]placementLoad{"pve2": {guests: 2, memory: 16 << 30}},
+			want:      "pve3",
+		},
+		{
+			name:      "least cpu",
+			placement: nodePlacementModel{Strategy: types.StringValue("least-cpu")},
+			want:      "pve1",
+		},
+		{
+			name:      "least cpu among candidates",
+			placement: nodePlacementModel{Strategy: types.StringValue("least-cpu"), Nodes: nodes("pve2", "pve3")},
+			want:      "pve3",
+		},
+		{
+			name:      "spread",
+			placement: nodePlacementModel{Strategy: types.StringValue("spread")},
+			want:      "pve1",
+		},
+		{
+			name:      "spread by tag",
+			placement: nodePlacementModel{Strategy: types.StringValue("spread"), Tag: types.StringValue("web")},
+			want:      "pve3",
+		},
+		{
+			name:      "spread with pending guests",
+			placement: nodePlacementModel{Strategy: types.StringValue("spread"), Tag: types.StringValue("web")},
+			pending:   map[string]placementLoad{"pve3": {guests: 1}},
+			want:      "pve1",
+		},
+		{
+			name:      "offline candidates",
+			placement: nodePlacementModel{Strategy: types.StringValue("spread"), Nodes: nodes("pve4", "pve5")},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := chooseNode(resources, tt.placement, tt.pending)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
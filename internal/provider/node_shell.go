@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// errNodeShellNotConfigured is returned by nodeExec when the provider has no
+// ssh block.
+var errNodeShellNotConfigured = errors.New("this operation runs commands on the node over SSH, which requires the ssh block of the provider")
+
+// sshModel maps the ssh provider schema data.
+type sshModel struct {
+	User           types.String `tfsdk:"user"`
+	PrivateKey     types.String `tfsdk:"private_key"`
+	Agent          types.Bool   `tfsdk:"agent"`
+	Port           types.Int64  `tfsdk:"port"`
+	KnownHostsFile types.String `tfsdk:"known_hosts_file"`
+}
+
+// nodeShell runs commands on the nodes over SSH, for the operations the API
+// does not cover, e.g. writing snippets.
+type nodeShell struct {
+	config *ssh.ClientConfig
+	port   int64
+}
+
+// newNodeShell returns the node shell configured by model. Host keys are
+// verified against the known hosts file, ~/.ssh/known_hosts by default.
+func newNodeShell(model sshModel) (*nodeShell, error) {
+	var auth []ssh.AuthMethod
+
+	if key := model.PrivateKey.ValueString(); key != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("parsing private_key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if model.Agent.ValueBool() {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, errors.New("agent is enabled but SSH_AUTH_SOCK is not set")
+		}
+		// The connection to the agent lives as long as the provider.
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to the SSH agent: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	if len(auth) == 0 {
+		return nil, errors.New("set private_key or enable agent to authenticate")
+	}
+
+	knownHostsFile := model.KnownHostsFile.ValueString()
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("locating the known hosts file: %w", err)
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading known hosts: %w", err)
+	}
+
+	user := model.User.ValueString()
+	if user == "" {
+		user = "root"
+	}
+	port := model.Port.ValueInt64()
+	if port == 0 {
+		port = 22
+	}
+
+	return &nodeShell{
+		config: &ssh.ClientConfig{
+			User:            user,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+		},
+		port: port,
+	}, nil
+}
+
+// nodeAddressFromStatus returns the address of node in the cluster status,
+// falling back to its name for standalone nodes.
+func nodeAddressFromStatus(status []clusterStatusEntry, node string) string {
+	for _, entry := range status {
+		if entry.Type == "node" && entry.Name == node && entry.IP != "" {
+			return entry.IP
+		}
+	}
+
+	return node
+}
+
+// shellQuote quotes s as a single argument of a POSIX shell command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// nodeExec runs command on node over SSH with stdin as its input, and
+// returns its combined output. Resources use it where the API is
+// insufficient, and fail with errNodeShellNotConfigured without an ssh
+// block.
+func (c *apiClient) nodeExec(ctx context.Context, node, command string, stdin io.Reader) (string, error) {
+	if c.shell == nil {
+		return "", errNodeShellNotConfigured
+	}
+
+	var status []clusterStatusEntry
+	if err := c.Get(ctx, "/cluster/status", &status); err != nil {
+		return "", err
+	}
+	address := net.JoinHostPort(nodeAddressFromStatus(status, node), strconv.FormatInt(c.shell.port, 10))
+
+	ctx = tflog.SubsystemSetField(ctx, logSSH, logFieldNode, node)
+	tflog.SubsystemDebug(ctx, logSSH, "Running command on node", map[string]interface{}{
+		"address": address,
+		"command": command,
+	})
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, c.shell.config)
+	if err != nil {
+		conn.Close()
+		return "", err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	var output bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = &output
+	session.Stderr = &output
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(command) }()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		client.Close()
+		return "", ctx.Err()
+	}
+
+	if err != nil {
+		return output.String(), fmt.Errorf("command on node %s failed: %w\n\n%s", node, err, output.String())
+	}
+
+	return output.String(), nil
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's here"); got != `'it'\''s here'` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestNodeAddressFromStatus(t *testing.T) {
+	status := []clusterStatusEntry{
+		{Type: "cluster", Name: "lab"},
+		{Type: "node", Name: "pve1", IP: "192.0.2.11"},
+		{Type: "node", Name: "pve2"},
+	}
+
+	if got := nodeAddressFromStatus(status, "pve1"); got != "192.0.2.11" {
+		t.Errorf("got %s, want 192.0.2.11", got)
+	}
+	if got := nodeAddressFromStatus(status, "pve2"); got != "pve2" {
+		t.Errorf("got %s, want pve2", got)
+	}
+}
+
+func TestNewNodeShell(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	shell, err := newNodeShell(sshModel{
+		User:           types.StringNull(),
+		PrivateKey:     types.StringValue(string(pem.EncodeToMemory(block))),
+		Agent:          types.BoolNull(),
+		Port:           types.Int64Null(),
+		KnownHostsFile: types.StringValue(knownHosts),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shell.config.User != "root" || shell.port != 22 {
+		t.Errorf("got user %s and port %d, want root and 22", shell.config.User, shell.port)
+	}
+
+	_, err = newNodeShell(sshModel{
+		User:           types.StringNull(),
+		PrivateKey:     types.StringNull(),
+		Agent:          types.BoolNull(),
+		Port:           types.Int64Null(),
+		KnownHostsFile: types.StringValue(knownHosts),
+	})
+	if err == nil {
+		t.Error("expected an error without authentication")
+	}
+}
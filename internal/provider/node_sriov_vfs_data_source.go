@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+var (
+	_ datasource.DataSource              = &nodeSriovVfsDataSource{}
+	_ datasource.DataSourceWithConfigure = &nodeSriovVfsDataSource{}
+)
+
+func NewNodeSriovVfsDataSource() datasource.DataSource {
+	return &nodeSriovVfsDataSource{}
+}
+
+type nodeSriovVfsDataSource struct {
+	client *apiClient
+}
+
+type nodeSriovVfsDataSourceModel struct {
+	Node             types.String         `tfsdk:"node"`
+	Vendor           types.String         `tfsdk:"vendor"`
+	VirtualFunctions []sriovVfsEntryModel `tfsdk:"virtual_functions"`
+}
+
+type sriovVfsEntryModel struct {
+	ID           types.String `tfsdk:"id"`
+	Vendor       types.String `tfsdk:"vendor"`
+	Device       types.String `tfsdk:"device"`
+	DeviceName   types.String `tfsdk:"device_name"`
+	IOMMUGroup   types.Int64  `tfsdk:"iommu_group"`
+	AssignedVMID types.Int64  `tfsdk:"assigned_vm_id"`
+	Available    types.Bool   `tfsdk:"available"`
+}
+
+func (d *nodeSriovVfsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *nodeSriovVfsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_sriov_vfs"
+}
+
+func (d *nodeSriovVfsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the SR-IOV virtual functions of the NICs of a node and the VMs they are passed through to, " +
+			"e.g. to pick a free virtual function for the hostpci option of a VM.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node",
+			},
+			"vendor": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list virtual functions of this PCI vendor ID, e.g. `0x8086`",
+			},
+			"virtual_functions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Virtual functions sorted by their PCI address",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "PCI address of the virtual function, e.g. `0000:01:10.0`",
+						},
+						"vendor": schema.StringAttribute{
+							Computed:    true,
+							Description: "PCI vendor ID",
+						},
+						"device": schema.StringAttribute{
+							Computed:    true,
+							Description: "PCI device ID",
+						},
+						"device_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the device",
+						},
+						"iommu_group": schema.Int64Attribute{
+							Computed:    true,
+							Description: "IOMMU group of the virtual function, -1 without IOMMU",
+						},
+						"assigned_vm_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "ID of the VM on the node with the virtual function in its hostpci options, if any",
+						},
+						"available": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether no VM on the node uses the virtual function",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// isVirtualFunction reports whether a PCI device is an SR-IOV virtual
+// function. The API does not link virtual functions to their physical
+// function, but the PCI ID database names all of them as such, e.g.
+// `Ethernet Virtual Function 700 Series`.
+func isVirtualFunction(device pveapi.PCIDevice) bool {
+	return strings.Contains(strings.ToLower(device.DeviceName), "virtual function")
+}
+
+// hostpciKey matches the option names of PCI passthrough devices.
+var hostpciKey = regexp.MustCompile(`^hostpci\d+$`)
+
+// normalizePCIAddress adds the default domain to a PCI address, so that
+// `01:10.0` and `0000:01:10.0` compare equal.
+func normalizePCIAddress(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if strings.Count(address, ":") == 1 {
+		address = "0000:" + address
+	}
+
+	return address
+}
+
+// hostpciAddresses returns the normalized PCI addresses of a hostpci option,
+// e.g. `0000:01:10.0,pcie=1` or `host=01:10.0;01:10.1`. Devices passed
+// through by their resource mapping have no address and are left out.
+func hostpciAddresses(value string) []string {
+	var host string
+	for i, property := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(property, "=")
+		switch {
+		case !ok && i == 0:
+			host = key
+		case key == "host":
+			host = val
+		}
+	}
+
+	var addresses []string
+	for _, address := range strings.Split(host, ";") {
+		if address != "" {
+			addresses = append(addresses, normalizePCIAddress(address))
+		}
+	}
+
+	return addresses
+}
+
+// assignedPCIDevices returns the VMs of a node by the PCI addresses in their
+// hostpci options.
+func (c *apiClient) assignedPCIDevices(ctx context.Context, node string) (map[string]int64, error) {
+	vms, err := c.api.VMs(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := map[string]int64{}
+	for _, vm := range vms {
+		config, err := c.api.VMConfig(ctx, node, vm.VMID)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range config {
+			if !hostpciKey.MatchString(key) {
+				continue
+			}
+			for _, address := range hostpciAddresses(proxmoxtf.ConfigValue(value)) {
+				assigned[address] = vm.VMID
+			}
+		}
+	}
+
+	return assigned, nil
+}
+
+func (d *nodeSriovVfsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state nodeSriovVfsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node := state.Node.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node)
+	tflog.SubsystemDebug(ctx, logAPI, "Reading node SR-IOV virtual functions")
+
+	devices, err := d.client.api.PCIDevices(ctx, node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Node PCI Devices",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	assigned, err := d.client.assignedPCIDevices(ctx, node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM Configs",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+
+	state.VirtualFunctions = []sriovVfsEntryModel{}
+	for _, device := range devices {
+		if !isVirtualFunction(device) {
+			continue
+		}
+		if !state.Vendor.IsNull() && !strings.EqualFold(device.Vendor, state.Vendor.ValueString()) {
+			continue
+		}
+
+		vmid, inUse := assigned[normalizePCIAddress(device.ID)]
+		state.VirtualFunctions = append(state.VirtualFunctions, sriovVfsEntryModel{
+			ID:           types.StringValue(device.ID),
+			Vendor:       types.StringValue(device.Vendor),
+			Device:       types.StringValue(device.Device),
+			DeviceName:   types.StringValue(device.DeviceName),
+			IOMMUGroup:   types.Int64Value(device.IOMMUGroup),
+			AssignedVMID: proxmoxtf.Int64OrNull(vmid),
+			Available:    types.BoolValue(!inUse),
+		})
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestHostpciAddresses(t *testing.T) {
+	tests := map[string][]string{
+		"0000:01:10.0,pcie=1":           {"0000:01:10.0"},
+		"01:10.1":                       {"0000:01:10.1"},
+		"host=01:10.2;01:10.3,rombar=0": {"0000:01:10.2", "0000:01:10.3"},
+		"mapping=gpu,pcie=1":            nil,
+		"0000:0A:00.0,x-vga=1":          {"0000:0a:00.0"},
+	}
+
+	for value, want := range tests {
+		if got := hostpciAddresses(value); !reflect.DeepEqual(got, want) {
+			t.Errorf("hostpciAddresses(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestIsVirtualFunction(t *testing.T) {
+	if !isVirtualFunction(pveapi.PCIDevice{DeviceName: "Ethernet Virtual Function 700 Series"}) {
+		t.Error("expected a virtual function")
+	}
+	if isVirtualFunction(pveapi.PCIDevice{DeviceName: "Ethernet Controller X710 for 10GbE SFP+"}) {
+		t.Error("expected no virtual function")
+	}
+}
@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &nodeWakeonlanResource{}
+	_ resource.ResourceWithConfigure = &nodeWakeonlanResource{}
+)
+
+// NewNodeWakeonlanResource is a helper function to simplify the provider implementation.
+func NewNodeWakeonlanResource() resource.Resource {
+	return &nodeWakeonlanResource{}
+}
+
+// nodeWakeonlanResource wakes a powered-off node.
+type nodeWakeonlanResource struct {
+	client *apiClient
+}
+
+// nodeWakeonlanResourceModel maps the resource schema data.
+type nodeWakeonlanResourceModel struct {
+	Node     types.String `tfsdk:"node"`
+	Wait     types.Bool   `tfsdk:"wait"`
+	Timeout  types.String `tfsdk:"timeout"`
+	Triggers types.Map    `tfsdk:"triggers"`
+	MAC      types.String `tfsdk:"mac"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *nodeWakeonlanResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *nodeWakeonlanResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_wakeonlan"
+}
+
+// Schema defines the schema for the resource.
+func (r *nodeWakeonlanResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Wakes a powered-off node with Wake-on-LAN when created and whenever the triggers change. " +
+			"The packet is sent by the node serving the API, the woken node needs a wakeonlan MAC address in its config.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node to wake",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Wait until the node reports online. Defaults to true",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("10m"),
+				Description: "Maximum time to wait for the node as a Go duration. Defaults to 10m",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that wake the node again when changed",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"mac": schema.StringAttribute{
+				Computed:    true,
+				Description: "MAC address the packet was sent to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// waitForNodeOnline polls the node list until node reports online or the
+// timeout expired.
+func (c *apiClient) waitForNodeOnline(ctx context.Context, node string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		nodes, err := c.Nodes(ctx)
+		if err != nil {
+			return err
+		}
+		for _, n := range nodes {
+			if n.Node == node && n.Status == "online" {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %s is not online after %s", node, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *nodeWakeonlanResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan nodeWakeonlanResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, err := time.ParseDuration(plan.Timeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			err.Error(),
+		)
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, plan.Node.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Sending Wake-on-LAN packet")
+
+	var mac string
+	err = r.client.Post(ctx, fmt.Sprintf("/nodes/%s/wakeonlan", plan.Node.ValueString()), nil, &mac)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Wake Proxmox Node",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	plan.MAC = types.StringValue(mac)
+
+	if plan.Wait.ValueBool() {
+		tflog.SubsystemInfo(ctx, logAPI, "Waiting for node to come online")
+		err = r.client.waitForNodeOnline(ctx, plan.Node.ValueString(), timeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Proxmox Node Did Not Come Online",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *nodeWakeonlanResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The resource only triggers an action, there is no remote state to refresh.
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *nodeWakeonlanResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only wait and timeout can change in place, they take effect the next
+	// time the node is woken.
+	var plan nodeWakeonlanResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *nodeWakeonlanResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The node is left running.
+}
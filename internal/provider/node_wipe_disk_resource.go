@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &nodeWipeDiskResource{}
+	_ resource.ResourceWithConfigure      = &nodeWipeDiskResource{}
+	_ resource.ResourceWithValidateConfig = &nodeWipeDiskResource{}
+)
+
+// NewNodeWipeDiskResource is a helper function to simplify the provider implementation.
+func NewNodeWipeDiskResource() resource.Resource {
+	return &nodeWipeDiskResource{}
+}
+
+// nodeWipeDiskResource wipes a disk of a node.
+type nodeWipeDiskResource struct {
+	client *apiClient
+}
+
+// nodeWipeDiskResourceModel maps the resource schema data.
+type nodeWipeDiskResourceModel struct {
+	Node     types.String `tfsdk:"node"`
+	Disk     types.String `tfsdk:"disk"`
+	Confirm  types.String `tfsdk:"confirm"`
+	Triggers types.Map    `tfsdk:"triggers"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *nodeWipeDiskResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *nodeWipeDiskResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_wipe_disk"
+}
+
+// Schema defines the schema for the resource.
+func (r *nodeWipeDiskResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Wipes the partition table and filesystem signatures of a disk of a node when created and " +
+			"whenever the triggers change, so that the disk can be reused. All data on the disk is lost.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the node",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk": schema.StringAttribute{
+				Required:    true,
+				Description: "Block device of the disk, e.g. /dev/sdb",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"confirm": schema.StringAttribute{
+				Required:    true,
+				Description: "Confirmation of the wipe, must be WIPE- followed by the disk, e.g. WIPE-/dev/sdb",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that wipe the disk again when changed",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// wipeConfirmation returns the confirmation required to wipe disk.
+func wipeConfirmation(disk string) string {
+	return "WIPE-" + disk
+}
+
+// ValidateConfig rejects configurations whose confirmation does not name the disk.
+func (r *nodeWipeDiskResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config nodeWipeDiskResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Disk.IsUnknown() || config.Confirm.IsUnknown() {
+		return
+	}
+
+	if want := wipeConfirmation(config.Disk.ValueString()); config.Confirm.ValueString() != want {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("confirm"),
+			"Disk Wipe Not Confirmed",
+			fmt.Sprintf("Wiping %s destroys all data on the disk. Set confirm to %q to wipe it.", config.Disk.ValueString(), want),
+		)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *nodeWipeDiskResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan nodeWipeDiskResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The confirmation is checked again, as it may have been unknown
+	// during validation.
+	if plan.Confirm.ValueString() != wipeConfirmation(plan.Disk.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("confirm"),
+			"Disk Wipe Not Confirmed",
+			fmt.Sprintf("Set confirm to %q to wipe %s.", wipeConfirmation(plan.Disk.ValueString()), plan.Disk.ValueString()),
+		)
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, plan.Node.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Wiping disk", map[string]interface{}{
+		"disk": plan.Disk.ValueString(),
+	})
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	var upid string
+	params := map[string]interface{}{
+		"disk": plan.Disk.ValueString(),
+	}
+	err := r.client.Put(ctx, fmt.Sprintf("/nodes/%s/disks/wipedisk", plan.Node.ValueString()), params, &upid)
+	if err == nil {
+		_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Wipe Proxmox Disk",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *nodeWipeDiskResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The resource only triggers an action, there is no remote state to refresh.
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *nodeWipeDiskResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only the confirmation can change in place, the disk is not wiped again.
+	var plan nodeWipeDiskResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *nodeWipeDiskResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// A wipe cannot be undone.
+}
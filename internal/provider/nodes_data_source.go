@@ -7,7 +7,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
 )
 
 var (
@@ -20,24 +23,30 @@ func NewNodesDataSource() datasource.DataSource {
 }
 
 type nodesDataSource struct {
-	client *proxmox.Client
+	client *apiClient
 }
 
 type nodesDataSourceModel struct {
-	Data []nodeModel `tfsdk:"data"`
+	ID   types.String `tfsdk:"id"`
+	Data []nodeModel  `tfsdk:"data"`
 }
 
 // coffeesModel maps coffees schema data.
 type nodeModel struct {
-	ID             types.String `tfsdk:"id"`
-	Node           types.String `tfsdk:"node"`
-	Status         types.String `tfsdk:"status"`
-	MaxCPU         types.Int64  `tfsdk:"maxcpu"`
-	MaxMem         types.Int64  `tfsdk:"maxmem"`
-	MaxDisk        types.Int64  `tfsdk:"maxdisk"`
-	SSLFingerprint types.String `tfsdk:"ssl_fingerprint"`
-	Type           types.String `tfsdk:"type"`
-	Level          types.String `tfsdk:"level"`
+	ID             types.String  `tfsdk:"id"`
+	Node           types.String  `tfsdk:"node"`
+	Status         types.String  `tfsdk:"status"`
+	Online         types.Bool    `tfsdk:"online"`
+	MaxCPU         types.Int64   `tfsdk:"maxcpu"`
+	MaxMem         types.Int64   `tfsdk:"maxmem"`
+	MaxDisk        types.Int64   `tfsdk:"maxdisk"`
+	Uptime         types.Int64   `tfsdk:"uptime"`
+	CPU            types.Float64 `tfsdk:"cpu"`
+	Mem            types.Int64   `tfsdk:"mem"`
+	Disk           types.Int64   `tfsdk:"disk"`
+	SSLFingerprint types.String  `tfsdk:"ssl_fingerprint"`
+	Type           types.String  `tfsdk:"type"`
+	Level          types.String  `tfsdk:"level"`
 }
 
 func (d *nodesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
@@ -47,11 +56,11 @@ func (d *nodesDataSource) Configure(_ context.Context, req datasource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*proxmox.Client)
+	client, ok := req.ProviderData.(*apiClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *proxmox.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -67,6 +76,10 @@ func (d *nodesDataSource) Metadata(_ context.Context, req datasource.MetadataReq
 func (d *nodesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the cluster, or of the node for a standalone node",
+			},
 			"data": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -80,12 +93,32 @@ func (d *nodesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 						"status": schema.StringAttribute{
 							Computed: true,
 						},
+						"online": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the node is online in the cluster",
+						},
 						"maxdisk": schema.Int64Attribute{
 							Computed: true,
 						},
 						"maxcpu": schema.Int64Attribute{
 							Computed: true,
 						},
+						"uptime": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Uptime of the node in seconds",
+						},
+						"cpu": schema.Float64Attribute{
+							Computed:    true,
+							Description: "CPU usage of the node as fraction of maxcpu",
+						},
+						"mem": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Used memory of the node in bytes",
+						},
+						"disk": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Used space of the root filesystem of the node in bytes",
+						},
 						"ssl_fingerprint": schema.StringAttribute{
 							Computed: true,
 						},
@@ -106,35 +139,72 @@ func (d *nodesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 }
 
 func (d *nodesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
 	var state nodesDataSourceModel
 
+	tflog.SubsystemDebug(ctx, logAPI, "Reading cluster nodes")
 	cluster, err := d.client.Cluster(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Proxmox Cluster",
-			err.Error(),
+			apiErrorDetail(err),
 		)
 		return
 	}
 	nodes := cluster.Nodes
 
+	// The cluster status only reports capacities, the current usage is part
+	// of the cluster resources.
+	resources, err := cluster.Resources(ctx, "node")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Resources",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	usage := map[string]*proxmox.ClusterResource{}
+	for _, resource := range resources {
+		usage[resource.Node] = resource
+	}
+
 	// Map response body to model
 	for _, node := range nodes {
 		nodeState := nodeModel{
 			ID:             types.StringValue(node.ID),
 			Node:           types.StringValue(node.Name),
 			Status:         types.StringValue(node.Status),
+			Online:         proxmoxtf.Bool(node.Online),
 			MaxCPU:         types.Int64Value(int64(node.MaxCPU)),
 			MaxMem:         types.Int64Value(int64(node.MaxMem)),
 			MaxDisk:        types.Int64Value(int64(node.MaxDisk)),
 			SSLFingerprint: types.StringValue(node.SSLFingerprint),
 			Type:           types.StringValue(node.Type),
 			Level:          types.StringValue(node.Level),
+			Uptime:         types.Int64Null(),
+			CPU:            types.Float64Null(),
+			Mem:            types.Int64Null(),
+			Disk:           types.Int64Null(),
+		}
+
+		// Offline nodes report no usage.
+		if resource, ok := usage[node.Name]; ok && resource.Status == "online" {
+			nodeState.Uptime = types.Int64Value(int64(resource.Uptime))
+			nodeState.CPU = types.Float64Value(resource.CPU)
+			nodeState.Mem = types.Int64Value(int64(resource.Mem))
+			nodeState.Disk = types.Int64Value(int64(resource.Disk))
 		}
 
 		state.Data = append(state.Data, nodeState)
 	}
 
+	// A standalone node is not part of a named cluster.
+	state.ID = types.StringValue(cluster.Name)
+	if cluster.Name == "" && len(nodes) == 1 {
+		state.ID = types.StringValue(nodes[0].Name)
+	}
+
 	// Set state
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
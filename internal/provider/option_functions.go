@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementations satisfy the expected interfaces.
+var (
+	_ function.Function = &parseOptionsFunction{}
+	_ function.Function = &formatOptionsFunction{}
+)
+
+// NewParseOptionsFunction is a helper function to simplify the provider implementation.
+func NewParseOptionsFunction() function.Function {
+	return &parseOptionsFunction{}
+}
+
+// parseOptionsFunction converts a Proxmox VE option string into a map.
+type parseOptionsFunction struct{}
+
+// Metadata returns the function name.
+func (f *parseOptionsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_options"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *parseOptionsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Convert a Proxmox VE option string into a map",
+		Description: "Converts an option string of a device, e.g. `local-lvm:32,iothread=1` of a disk, into a map of its " +
+			"properties. The leading value without key is stored under default_key, e.g. `file` for disks.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "options",
+				Description: "Option string to convert",
+			},
+			function.StringParameter{
+				Name:        "default_key",
+				Description: "Key of the leading value without key, or an empty string if the option has none",
+			},
+		},
+		Return: function.MapReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+// Run converts the option string.
+func (f *parseOptionsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value, defaultKey string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value, &defaultKey))
+	if resp.Error != nil {
+		return
+	}
+
+	options, err := proxmoxtf.ParseOptions(value, defaultKey)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, options))
+}
+
+// NewFormatOptionsFunction is a helper function to simplify the provider implementation.
+func NewFormatOptionsFunction() function.Function {
+	return &formatOptionsFunction{}
+}
+
+// formatOptionsFunction converts a map into a Proxmox VE option string.
+type formatOptionsFunction struct{}
+
+// Metadata returns the function name.
+func (f *formatOptionsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_options"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *formatOptionsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Convert a map into a Proxmox VE option string",
+		Description: "Converts a map of properties into an option string of a device, e.g. `local-lvm:32,iothread=1` of " +
+			"a disk. The property default_key is written first without key, the others follow sorted by key.",
+		Parameters: []function.Parameter{
+			function.MapParameter{
+				Name:        "options",
+				Description: "Properties to convert",
+				ElementType: types.StringType,
+			},
+			function.StringParameter{
+				Name:        "default_key",
+				Description: "Key of the property written without key, or an empty string if the option has none",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run converts the properties.
+func (f *formatOptionsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var options map[string]string
+	var defaultKey string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &options, &defaultKey))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, proxmoxtf.FormatOptions(options, defaultKey)))
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseOptionsFunction(t *testing.T) {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{
+			types.StringValue("local-lvm:32,iothread=1"),
+			types.StringValue("file"),
+		}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.MapUnknown(types.StringType)),
+	}
+
+	NewParseOptionsFunction().Run(context.Background(), req, resp)
+	if resp.Error != nil {
+		t.Fatal(resp.Error)
+	}
+
+	want := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"file":     types.StringValue("local-lvm:32"),
+		"iothread": types.StringValue("1"),
+	})
+	if got := resp.Result.Value(); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestFormatOptionsFunction(t *testing.T) {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{
+			types.MapValueMust(types.StringType, map[string]attr.Value{
+				"iothread": types.StringValue("1"),
+				"file":     types.StringValue("local-lvm:32"),
+			}),
+			types.StringValue("file"),
+		}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	NewFormatOptionsFunction().Run(context.Background(), req, resp)
+	if resp.Error != nil {
+		t.Fatal(resp.Error)
+	}
+
+	if got := resp.Result.Value(); !got.Equal(types.StringValue("local-lvm:32,iothread=1")) {
+		t.Errorf("got %s", got)
+	}
+}
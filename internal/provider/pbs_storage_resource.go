@@ -0,0 +1,305 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &pbsStorageResource{}
+	_ resource.ResourceWithConfigure = &pbsStorageResource{}
+)
+
+// NewPbsStorageResource is a helper function to simplify the provider implementation.
+func NewPbsStorageResource() resource.Resource {
+	return &pbsStorageResource{}
+}
+
+// pbsStorageResource connects a Proxmox Backup Server datastore as backup
+// storage.
+type pbsStorageResource struct {
+	client *apiClient
+}
+
+// pbsStorageResourceModel maps the resource schema data.
+type pbsStorageResourceModel struct {
+	Storage       types.String `tfsdk:"storage"`
+	Server        types.String `tfsdk:"server"`
+	Datastore     types.String `tfsdk:"datastore"`
+	Username      types.String `tfsdk:"username"`
+	Password      types.String `tfsdk:"password"`
+	Fingerprint   types.String `tfsdk:"fingerprint"`
+	Namespace     types.String `tfsdk:"namespace"`
+	EncryptionKey types.String `tfsdk:"encryption_key"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *pbsStorageResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *pbsStorageResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pbs_storage"
+}
+
+// Schema defines the schema for the resource.
+func (r *pbsStorageResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Connects a Proxmox Backup Server datastore as storage with the backup content type, " +
+			"optionally with client-side encryption of the backups.",
+		Attributes: map[string]schema.Attribute{
+			"storage": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the storage",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"server": schema.StringAttribute{
+				Required:    true,
+				Description: "Address of the Proxmox Backup Server",
+			},
+			"datastore": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the datastore on the Proxmox Backup Server",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Required:    true,
+				Description: "User or API token to log in with, e.g. `backup@pbs` or `backup@pbs!pve`",
+			},
+			"password": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password of the user or secret of the API token",
+			},
+			"fingerprint": schema.StringAttribute{
+				Optional:    true,
+				Description: "SHA-256 fingerprint of the certificate of the server, required when it is not trusted by the nodes",
+			},
+			"namespace": schema.StringAttribute{
+				Optional:    true,
+				Description: "Namespace of the datastore to store the backups in, the root namespace by default",
+			},
+			"encryption_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "Encrypts the backups on the client side with this key, given as the JSON of a " +
+					"`proxmox-backup-client key create` key file, or `autogen` to have the node generate one. " +
+					"The key is stored in /etc/pve/priv/storage/<storage>.enc and must be kept safe " +
+					"outside of the cluster, as encrypted backups cannot be restored without it. " +
+					"Removing the attribute stops encrypting new backups.",
+			},
+		},
+	}
+}
+
+// pbsStorageUpdateRequest maps the plan onto the parameters of the storage
+// update. The encryption key is only sent when it changed from state, as
+// sending `autogen` again would replace the key. Unset values are removed
+// through the `delete` parameter.
+func pbsStorageUpdateRequest(plan, state pbsStorageResourceModel) pveapi.StorageRequest {
+	params := pveapi.StorageRequest{
+		Server:      plan.Server.ValueString(),
+		Username:    plan.Username.ValueString(),
+		Password:    plan.Password.ValueString(),
+		Fingerprint: plan.Fingerprint.ValueString(),
+		Namespace:   plan.Namespace.ValueString(),
+	}
+
+	var remove []string
+	if plan.Fingerprint.IsNull() {
+		remove = append(remove, "fingerprint")
+	}
+	if plan.Namespace.IsNull() {
+		remove = append(remove, "namespace")
+	}
+	if plan.EncryptionKey.IsNull() {
+		if !state.EncryptionKey.IsNull() {
+			remove = append(remove, "encryption-key")
+		}
+	} else if !plan.EncryptionKey.Equal(state.EncryptionKey) {
+		params.EncryptionKey = plan.EncryptionKey.ValueString()
+	}
+	params.Delete = strings.Join(remove, ",")
+
+	return params
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *pbsStorageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan pbsStorageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := pveapi.StorageRequest{
+		Storage:       plan.Storage.ValueString(),
+		Type:          "pbs",
+		Content:       "backup",
+		Server:        plan.Server.ValueString(),
+		Datastore:     plan.Datastore.ValueString(),
+		Username:      plan.Username.ValueString(),
+		Password:      plan.Password.ValueString(),
+		Fingerprint:   plan.Fingerprint.ValueString(),
+		Namespace:     plan.Namespace.ValueString(),
+		EncryptionKey: plan.EncryptionKey.ValueString(),
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Creating PBS storage", map[string]interface{}{
+		"storage":   plan.Storage.ValueString(),
+		"server":    plan.Server.ValueString(),
+		"datastore": plan.Datastore.ValueString(),
+		"encrypted": !plan.EncryptionKey.IsNull(),
+	})
+
+	err := r.client.api.CreateStorage(ctx, params)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox PBS Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *pbsStorageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state pbsStorageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	storage, err := r.client.api.Storage(ctx, state.Storage.ValueString())
+	if err != nil {
+		// The storage was removed outside of Terraform.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox PBS Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// The password and the encryption key are not returned by the API and
+	// kept from the state.
+	state.Server = types.StringValue(storage.Server)
+	state.Datastore = types.StringValue(storage.Datastore)
+	state.Username = types.StringValue(storage.Username)
+	state.Fingerprint = proxmoxtf.StringOrNull(storage.Fingerprint)
+	state.Namespace = proxmoxtf.StringOrNull(storage.Namespace)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *pbsStorageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan, state pbsStorageResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Updating PBS storage", map[string]interface{}{
+		"storage": plan.Storage.ValueString(),
+	})
+
+	err := r.client.api.UpdateStorage(ctx, plan.Storage.ValueString(), pbsStorageUpdateRequest(plan, state))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox PBS Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *pbsStorageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state pbsStorageResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Deleting PBS storage", map[string]interface{}{
+		"storage": state.Storage.ValueString(),
+	})
+
+	err := r.client.api.DeleteStorage(ctx, state.Storage.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox PBS Storage",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
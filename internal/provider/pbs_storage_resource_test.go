@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestPbsStorageUpdateRequest(t *testing.T) {
+	base := pbsStorageResourceModel{
+		Server:        types.StringValue("pbs.example.com"),
+		Username:      types.StringValue("backup@pbs"),
+		Password:      types.StringValue("secret"),
+		Fingerprint:   types.StringNull(),
+		Namespace:     types.StringValue("pve"),
+		EncryptionKey: types.StringValue("autogen"),
+	}
+
+	tests := map[string]struct {
+		plan  func(pbsStorageResourceModel) pbsStorageResourceModel
+		state func(pbsStorageResourceModel) pbsStorageResourceModel
+		want  pveapi.StorageRequest
+	}{
+		"unchanged key": {
+			plan:  func(m pbsStorageResourceModel) pbsStorageResourceModel { return m },
+			state: func(m pbsStorageResourceModel) pbsStorageResourceModel { return m },
+			want: pveapi.StorageRequest{
+				Server:    "pbs.example.com",
+				Username:  "backup@pbs",
+				Password:  "secret",
+				Namespace: "pve",
+				Delete:    "fingerprint",
+			},
+		},
+		"new key": {
+			plan: func(m pbsStorageResourceModel) pbsStorageResourceModel {
+				m.EncryptionKey = types.StringValue(`{"kdf":null}`)
+				return m
+			},
+			state: func(m pbsStorageResourceModel) pbsStorageResourceModel { return m },
+			want: pveapi.StorageRequest{
+				Server:        "pbs.example.com",
+				Username:      "backup@pbs",
+				Password:      "secret",
+				Namespace:     "pve",
+				EncryptionKey: `{"kdf":null}`,
+				Delete:        "fingerprint",
+			},
+		},
+		"removed key": {
+			plan: func(m pbsStorageResourceModel) pbsStorageResourceModel {
+				m.EncryptionKey = types.StringNull()
+				m.Namespace = types.StringNull()
+				return m
+			},
+			state: func(m pbsStorageResourceModel) pbsStorageResourceModel { return m },
+			want: pveapi.StorageRequest{
+				Server:   "pbs.example.com",
+				Username: "backup@pbs",
+				Password: "secret",
+				Delete:   "fingerprint,namespace,encryption-key",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := pbsStorageUpdateRequest(tt.plan(base), tt.state(base))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
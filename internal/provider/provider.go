@@ -4,22 +4,39 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/pveapi"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &proxmoxProvider{}
+	_ provider.Provider              = &proxmoxProvider{}
+	_ provider.ProviderWithFunctions = &proxmoxProvider{}
 )
 
 // proxmoxProviderModel maps provider schema data to a Go type.
@@ -27,6 +44,110 @@ type proxmoxProviderModel struct {
 	Host     types.String `tfsdk:"host"`
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
+	OTP      types.String `tfsdk:"otp"`
+	APIToken types.String `tfsdk:"api_token"`
+	LogLevel types.String `tfsdk:"log_level"`
+	LogHTTP  types.Bool   `tfsdk:"log_http_bodies"`
+	APITrace types.String `tfsdk:"api_trace_file"`
+	ReadOnly types.Bool   `tfsdk:"read_only"`
+	Insecure types.Bool   `tfsdk:"insecure"`
+
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+
+	Endpoints   types.List   `tfsdk:"endpoints"`
+	DefaultNode types.String `tfsdk:"default_node"`
+
+	CACertificate     types.String `tfsdk:"ca_certificate"`
+	CACertificateFile types.String `tfsdk:"ca_certificate_file"`
+	SSLFingerprint    types.String `tfsdk:"ssl_fingerprint"`
+	TLSMinVersion     types.String `tfsdk:"tls_min_version"`
+	TLSCipherSuites   types.List   `tfsdk:"tls_cipher_suites"`
+
+	ClusterName      types.String `tfsdk:"cluster_name"`
+	SkipVersionCheck types.Bool   `tfsdk:"skip_version_check"`
+	VMIDRange        types.Object `tfsdk:"vmid_range"`
+	VMIDStrategy     types.String `tfsdk:"vmid_strategy"`
+	SSH              types.Object `tfsdk:"ssh"`
+
+	MaxRequestsPerSecond  types.Float64 `tfsdk:"max_requests_per_second"`
+	MaxRequestBurst       types.Int64   `tfsdk:"max_request_burst"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	NodeParallelism       types.Int64   `tfsdk:"node_parallelism"`
+
+	RequestTimeout types.Int64 `tfsdk:"request_timeout"`
+	MaxRetries     types.Int64 `tfsdk:"max_retries"`
+	RetryWait      types.Int64 `tfsdk:"retry_wait"`
+}
+
+const (
+	// defaultMaxRetries is the number of retries of failed requests when
+	// max_retries is not set.
+	defaultMaxRetries = 3
+
+	// defaultRetryWait is the number of seconds before the first retry when
+	// retry_wait is not set.
+	defaultRetryWait = 1
+)
+
+// apiClient is handed to data sources and resources as their provider data.
+// It embeds the Proxmox VE client and carries the provider-level settings
+// of the provider instance that configured it.
+type apiClient struct {
+	*proxmox.Client
+
+	// host is the configured API host, e.g. https://proxmox:8006.
+	host string
+
+	// username is the user the client authenticates as.
+	username string
+
+	// apiToken is the API token the client authenticates with, in the form
+	// USER@REALM!TOKENID=SECRET, empty when it logs in with a password.
+	apiToken string
+
+	// session holds the ticket of the client when it logs in with a
+	// password, nil with an API token.
+	session *sessionTransport
+
+	// tlsConfig verifies the API host, also for terminal websockets.
+	tlsConfig *tls.Config
+
+	// api exposes endpoints that go-proxmox does not cover yet.
+	api *pveapi.Client
+
+	// defaultNode is the node of resources that do not set one, empty when
+	// not configured.
+	defaultNode string
+
+	// shell runs commands on the nodes over SSH, nil when the ssh block is
+	// not configured.
+	shell *nodeShell
+
+	// vmidRange restricts the guest IDs resources may use, nil when not
+	// configured.
+	vmidRange *vmidRange
+
+	// vmidStrategy is the strategy to allocate guest IDs with, one of the
+	// vmidStrategy constants.
+	vmidStrategy string
+
+	// nodeLimiter bounds the guest operations per node, nil when
+	// node_parallelism is not configured.
+	nodeLimiter *nodeLimiter
+
+	// logLevel is the level of the provider log subsystems, hclog.NoLevel
+	// when not configured.
+	logLevel hclog.Level
+
+	// guestNames serializes claiming the names of guests with unique_name.
+	guestNames guestNameLocks
+
+	// storages caches the content types of the storages for plan checks.
+	storages storageContents
+
+	// placements holds the load of the guests placed on nodes by their
+	// placement during the apply.
+	placements guestPlacements
 }
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -60,6 +181,22 @@ func (p *proxmoxProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				Description: "URI for Proxmox VE API. May also be provided via PROXMOX_HOST environment variable.",
 				Optional:    true,
 			},
+			"default_node": schema.StringAttribute{
+				Description: "Node used by guest resources that do not set node, e.g. proxmox_lxc. Changing it replaces those resources. " +
+					"May also be provided via PROXMOX_DEFAULT_NODE environment variable.",
+				Optional: true,
+			},
+			"endpoints": schema.ListAttribute{
+				Description: "URIs of the API of further cluster nodes, in the format of host. Requests fail over to them in order " +
+					"when the current node cannot be reached, so that losing a node does not break refresh and apply. " +
+					"The certificates of all nodes must pass verification, which ssl_fingerprint pins to a single certificate. " +
+					"May also be provided as a comma separated list via PROXMOX_ENDPOINTS environment variable.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
 			"username": schema.StringAttribute{
 				Description: "Username for Proxmox VE API. May also be provided via PROXMOX_USERNAME environment variable.",
 				Optional:    true,
@@ -69,6 +206,221 @@ func (p *proxmoxProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"api_token": schema.StringAttribute{
+				Description: "API token of the form USER@REALM!TOKENID=SECRET, used instead of username and password. " +
+					"Tokens need no second factor, proxmox-token-helper creates one after an interactive login. " +
+					"May also be provided via PROXMOX_API_TOKEN environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"credentials_file": schema.StringAttribute{
+				Description: "Path of a file with the host, username and password or api_token, either as a JSON object or as INI style " +
+					"`key = value` lines, so that secrets live outside of Terraform variables and the environment. " +
+					"The provider attributes and environment variables take precedence over the file. " +
+					"May also be provided via PROXMOX_CREDENTIALS_FILE environment variable.",
+				Optional: true,
+			},
+			"otp": schema.StringAttribute{
+				Description: "Current TOTP code for users with two-factor authentication. The provider logs in once with the code " +
+					"and renews the resulting session while it is valid, an expired session cannot be restored without a new code. May also be provided via PROXMOX_OTP environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"insecure": schema.BoolAttribute{
+				Description: "Skip the verification of the TLS certificate of the Proxmox VE API, e.g. for self-signed certificates in homelab setups. " +
+					"Defaults to false. May also be provided via PROXMOX_INSECURE environment variable.",
+				Optional: true,
+			},
+			"ca_certificate": schema.StringAttribute{
+				Description: "PEM encoded CA certificates to verify the TLS certificate of the Proxmox VE API against instead of the system roots, " +
+					"e.g. the cluster CA in /etc/pve/pve-root-ca.pem. May also be provided via PROXMOX_CA_CERTIFICATE environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_certificate_file")),
+				},
+			},
+			"ca_certificate_file": schema.StringAttribute{
+				Description: "Path of a file with PEM encoded CA certificates, as an alternative to ca_certificate. " +
+					"May also be provided via PROXMOX_CA_CERTIFICATE_FILE environment variable.",
+				Optional: true,
+			},
+			"ssl_fingerprint": schema.StringAttribute{
+				Description: "SHA-256 fingerprint of the TLS certificate of the Proxmox VE API host, as shown by the proxmox_nodes data source. " +
+					"The certificate is trusted when it matches the fingerprint, without verifying its chain. " +
+					"May also be provided via PROXMOX_SSL_FINGERPRINT environment variable.",
+				Optional: true,
+			},
+			"tls_min_version": schema.StringAttribute{
+				Description: "Minimum TLS version used to connect to the Proxmox VE API, either 1.2 or 1.3. Defaults to 1.2.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("1.2", "1.3"),
+				},
+			},
+			"tls_cipher_suites": schema.ListAttribute{
+				Description: "Cipher suites offered for TLS 1.2 connections to the Proxmox VE API, by their IANA name, e.g. " +
+					"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384. Only suites without known security issues are accepted. " +
+					"The cipher suites of TLS 1.3 are not configurable. Defaults to the Go defaults.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(tlsCipherSuiteNames()...)),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Refuse every API request that would modify the cluster, so that resource creation, updates and deletion fail. " +
+					"Intended for running plans with production credentials. May also be provided via PROXMOX_READ_ONLY environment variable.",
+				Optional: true,
+			},
+			"cluster_name": schema.StringAttribute{
+				Description: "Name of the cluster the host is expected to belong to. When set, the provider fails to configure " +
+					"if the API reports a different cluster, guarding provider aliases against pointing at the wrong cluster.",
+				Optional: true,
+			},
+			"skip_version_check": schema.BoolAttribute{
+				Description: "Skip reading the version of the Proxmox VE API when the provider is configured, which verifies the host and credentials up front. " +
+					"Set this for restricted API tokens that may not read /version. Defaults to false. " +
+					"May also be provided via PROXMOX_SKIP_VERSION_CHECK environment variable.",
+				Optional: true,
+			},
+			"vmid_range": schema.SingleNestedAttribute{
+				Description: "Range of guest IDs this provider configuration may use. Guest IDs are allocated from the range, " +
+					"and explicitly set vm_id values outside of it fail the plan, so that environments can partition the ID space.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"min": schema.Int64Attribute{
+						Description: "Lowest guest ID of the range",
+						Required:    true,
+						Validators: []validator.Int64{
+							int64validator.Between(100, 999999999),
+						},
+					},
+					"max": schema.Int64Attribute{
+						Description: "Highest guest ID of the range",
+						Required:    true,
+						Validators: []validator.Int64{
+							int64validator.Between(100, 999999999),
+						},
+					},
+				},
+			},
+			"vmid_strategy": schema.StringAttribute{
+				Description: "Strategy to allocate the IDs of guests that do not set vm_id: nextid lets the cluster pick the next free ID, " +
+					"range picks the lowest free ID of the vmid_range and sequential picks the ID after the highest one in use, " +
+					"within the vmid_range if set, so that IDs of destroyed guests and their backups are not reused. " +
+					"Defaults to range with a vmid_range and nextid without. May also be provided via PROXMOX_VMID_STRATEGY environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(vmidStrategyNextID, vmidStrategyRange, vmidStrategySequential),
+				},
+			},
+			"ssh": schema.SingleNestedAttribute{
+				Description: "SSH connection to the nodes for operations the API does not cover, e.g. uploading snippets. " +
+					"The nodes are reached on the address of the cluster status, their host keys are verified against the known hosts file.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"user": schema.StringAttribute{
+						Description: "User to log in as. Defaults to root",
+						Optional:    true,
+					},
+					"private_key": schema.StringAttribute{
+						Description: "PEM encoded private key to authenticate with",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"agent": schema.BoolAttribute{
+						Description: "Authenticate with the keys of the SSH agent of SSH_AUTH_SOCK. Defaults to false",
+						Optional:    true,
+					},
+					"port": schema.Int64Attribute{
+						Description: "Port of the SSH server of the nodes. Defaults to 22",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.Between(1, 65535),
+						},
+					},
+					"known_hosts_file": schema.StringAttribute{
+						Description: "Known hosts file the host keys of the nodes are verified against. Defaults to ~/.ssh/known_hosts",
+						Optional:    true,
+					},
+				},
+			},
+			"max_requests_per_second": schema.Float64Attribute{
+				Description: "Maximum average number of requests per second sent to the Proxmox VE API. Unlimited when not set.",
+				Optional:    true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"max_request_burst": schema.Int64Attribute{
+				Description: "Number of requests that may be sent at once before max_requests_per_second applies. Defaults to 1.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Maximum number of requests sent to the Proxmox VE API at once, shared by all resources and data sources. " +
+					"Unlimited when not set.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"node_parallelism": schema.Int64Attribute{
+				Description: "Maximum number of operations that create, import or destroy guests and run disk tasks at once per node, " +
+					"so that parallel operations do not fail on guest config and storage locks. Set it to 1 to serialize them. " +
+					"Unlimited when not set. May also be provided via PROXMOX_NODE_PARALLELISM environment variable.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"request_timeout": schema.Int64Attribute{
+				Description: "Seconds to wait for the connection to and the response of a single API request. " +
+					"Long running operations are tasks that the provider polls, so this only bounds individual requests. Unlimited when not set.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_retries": schema.Int64Attribute{
+				Description: fmt.Sprintf("Number of times a request is retried after a transient network error or a gateway error (502, 503, 504 or 596) "+
+					"of the Proxmox VE API, and after rate limiting (429). Requests that may create objects are only retried when they could not be sent. Defaults to %d.", defaultMaxRetries),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait": schema.Int64Attribute{
+				Description: fmt.Sprintf("Seconds to wait before the first retry of a request, doubled for every further retry. Defaults to %d.", defaultRetryWait),
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"api_trace_file": schema.StringAttribute{
+				Description: "Path of a file the provider appends each API request and response to as a JSON line, with the method, " +
+					"path, duration, status and task UPID, and the bodies with passwords, tickets, tokens and keys redacted. " +
+					"Intended for support bundles when debugging the interaction with a cluster. " +
+					"May also be provided via PROXMOX_API_TRACE_FILE environment variable.",
+				Optional: true,
+			},
+			"log_http_bodies": schema.BoolAttribute{
+				Description: "Log the bodies of all API requests and responses at debug level of the api log subsystem, " +
+					"with passwords, tickets, tokens and keys redacted. Defaults to false. " +
+					"May also be provided via PROXMOX_LOG_HTTP_BODIES environment variable.",
+				Optional: true,
+			},
+			"log_level": schema.StringAttribute{
+				Description: "Level of the provider log subsystems (api, task, vm, sdn, firewall, ssh) and of the go-proxmox client. One of trace, debug, info, warn, error or off. " +
+					"Defaults to the level Terraform runs the provider with. May also be provided via PROXMOX_LOG_LEVEL environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("trace", "debug", "info", "warn", "error", "off"),
+				},
+			},
 		},
 	}
 }
@@ -95,6 +447,15 @@ func (p *proxmoxProvider) Configure(ctx context.Context, req provider.ConfigureR
 		)
 	}
 
+	if config.Endpoints.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoints"),
+			"Unknown Proxmox VE API Endpoints",
+			"The provider cannot create the Proxmox VE API client as there is an unknown configuration value for the Proxmox VE API endpoints. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the PROXMOX_ENDPOINTS environment variable.",
+		)
+	}
+
 	if config.Username.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("username"),
@@ -113,6 +474,69 @@ func (p *proxmoxProvider) Configure(ctx context.Context, req provider.ConfigureR
 		)
 	}
 
+	if config.VMIDRange.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vmid_range"),
+			"Unknown Proxmox VE Guest ID Range",
+			"The provider cannot validate guest IDs as there is an unknown configuration value for the vmid_range. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.SSH.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ssh"),
+			"Unknown Proxmox VE SSH Connection",
+			"The provider cannot connect to the nodes over SSH as there is an unknown configuration value for the ssh block. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.CredentialsFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("credentials_file"),
+			"Unknown Proxmox VE API Credentials File",
+			"The provider cannot create the Proxmox VE API client as there is an unknown configuration value for the Proxmox VE API credentials file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the PROXMOX_CREDENTIALS_FILE environment variable.",
+		)
+	}
+
+	if config.OTP.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("otp"),
+			"Unknown Proxmox VE API OTP",
+			"The provider cannot create the Proxmox VE API client as there is an unknown configuration value for the Proxmox VE API OTP. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the PROXMOX_OTP environment variable.",
+		)
+	}
+
+	if config.APIToken.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_token"),
+			"Unknown Proxmox VE API Token",
+			"The provider cannot create the Proxmox VE API client as there is an unknown configuration value for the Proxmox VE API token. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the PROXMOX_API_TOKEN environment variable.",
+		)
+	}
+
+	if config.CACertificate.IsUnknown() || config.CACertificateFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_certificate"),
+			"Unknown Proxmox VE API CA Certificate",
+			"The provider cannot create the Proxmox VE API client as there is an unknown configuration value for the Proxmox VE API CA certificate. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the PROXMOX_CA_CERTIFICATE environment variable.",
+		)
+	}
+
+	if config.SSLFingerprint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ssl_fingerprint"),
+			"Unknown Proxmox VE API SSL Fingerprint",
+			"The provider cannot create the Proxmox VE API client as there is an unknown configuration value for the Proxmox VE API SSL fingerprint. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the PROXMOX_SSL_FINGERPRINT environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -123,11 +547,73 @@ func (p *proxmoxProvider) Configure(ctx context.Context, req provider.ConfigureR
 	host := os.Getenv("PROXMOX_HOST")
 	username := os.Getenv("PROXMOX_USERNAME")
 	password := os.Getenv("PROXMOX_PASSWORD")
+	otp := os.Getenv("PROXMOX_OTP")
+	apiToken := os.Getenv("PROXMOX_API_TOKEN")
+	logLevel := os.Getenv("PROXMOX_LOG_LEVEL")
+	defaultNode := os.Getenv("PROXMOX_DEFAULT_NODE")
+	logHTTP, _ := strconv.ParseBool(os.Getenv("PROXMOX_LOG_HTTP_BODIES"))
+	apiTraceFile := os.Getenv("PROXMOX_API_TRACE_FILE")
+	readOnly, _ := strconv.ParseBool(os.Getenv("PROXMOX_READ_ONLY"))
+	insecure, _ := strconv.ParseBool(os.Getenv("PROXMOX_INSECURE"))
+	skipVersionCheck, _ := strconv.ParseBool(os.Getenv("PROXMOX_SKIP_VERSION_CHECK"))
+	nodeParallelism, _ := strconv.ParseInt(os.Getenv("PROXMOX_NODE_PARALLELISM"), 10, 64)
+	vmidStrategy := os.Getenv("PROXMOX_VMID_STRATEGY")
+	caCertificate := os.Getenv("PROXMOX_CA_CERTIFICATE")
+	caCertificateFile := os.Getenv("PROXMOX_CA_CERTIFICATE_FILE")
+	sslFingerprint := os.Getenv("PROXMOX_SSL_FINGERPRINT")
+
+	credentialsFile := os.Getenv("PROXMOX_CREDENTIALS_FILE")
+	if !config.CredentialsFile.IsNull() {
+		credentialsFile = config.CredentialsFile.ValueString()
+	}
+	if credentialsFile != "" {
+		creds, err := readCredentialsFile(credentialsFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credentials_file"),
+				"Unable to Read Proxmox VE API Credentials File",
+				err.Error(),
+			)
+			return
+		}
+
+		if info, err := os.Stat(credentialsFile); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("credentials_file"),
+				"Proxmox VE API Credentials File Readable by Others",
+				fmt.Sprintf("The credentials file %s may be read by other users of this machine (mode %s). "+
+					"Restrict it to its owner, e.g. with chmod 600.", credentialsFile, info.Mode().Perm()),
+			)
+		}
+
+		// The environment takes precedence over the file.
+		if host == "" {
+			host = creds.Host
+		}
+		if username == "" {
+			username = creds.Username
+		}
+		if password == "" {
+			password = creds.Password
+		}
+		if apiToken == "" {
+			apiToken = creds.APIToken
+		}
+	}
 
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
 	}
 
+	var endpoints []string
+	if value := os.Getenv("PROXMOX_ENDPOINTS"); value != "" {
+		endpoints = strings.Split(value, ",")
+	}
+	if !config.Endpoints.IsNull() {
+		endpoints = nil
+		resp.Diagnostics.Append(config.Endpoints.ElementsAs(ctx, &endpoints, false)...)
+	}
+
 	if !config.Username.IsNull() {
 		username = config.Username.ValueString()
 	}
@@ -136,6 +622,62 @@ func (p *proxmoxProvider) Configure(ctx context.Context, req provider.ConfigureR
 		password = config.Password.ValueString()
 	}
 
+	if !config.OTP.IsNull() {
+		otp = config.OTP.ValueString()
+	}
+
+	if !config.APIToken.IsNull() {
+		apiToken = config.APIToken.ValueString()
+	}
+
+	if !config.LogLevel.IsNull() {
+		logLevel = config.LogLevel.ValueString()
+	}
+
+	if !config.LogHTTP.IsNull() {
+		logHTTP = config.LogHTTP.ValueBool()
+	}
+
+	if !config.APITrace.IsNull() {
+		apiTraceFile = config.APITrace.ValueString()
+	}
+
+	if !config.DefaultNode.IsNull() {
+		defaultNode = config.DefaultNode.ValueString()
+	}
+
+	if !config.ReadOnly.IsNull() {
+		readOnly = config.ReadOnly.ValueBool()
+	}
+
+	if !config.Insecure.IsNull() {
+		insecure = config.Insecure.ValueBool()
+	}
+
+	if !config.SkipVersionCheck.IsNull() {
+		skipVersionCheck = config.SkipVersionCheck.ValueBool()
+	}
+
+	if !config.NodeParallelism.IsNull() {
+		nodeParallelism = config.NodeParallelism.ValueInt64()
+	}
+
+	if !config.VMIDStrategy.IsNull() {
+		vmidStrategy = config.VMIDStrategy.ValueString()
+	}
+
+	if !config.CACertificate.IsNull() {
+		caCertificate = config.CACertificate.ValueString()
+	}
+
+	if !config.CACertificateFile.IsNull() {
+		caCertificateFile = config.CACertificateFile.ValueString()
+	}
+
+	if !config.SSLFingerprint.IsNull() {
+		sslFingerprint = config.SSLFingerprint.ValueString()
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -144,31 +686,130 @@ func (p *proxmoxProvider) Configure(ctx context.Context, req provider.ConfigureR
 			path.Root("host"),
 			"Missing Proxmox VE API Host",
 			"The provider cannot create the Proxmox VE API client as there is a missing or empty value for the Proxmox VE API host. "+
-				"Set the host value in the configuration or use the PROXMOX_HOST environment variable. "+
+				"Set the host value in the configuration, use the PROXMOX_HOST environment variable or the credentials_file. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if username == "" {
+	// API tokens identify the user themselves.
+	var tokenSecret string
+	if apiToken != "" {
+		var err error
+		username, tokenSecret, err = parseAPIToken(apiToken)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_token"),
+				"Invalid Proxmox VE API Token",
+				err.Error(),
+			)
+		}
+	}
+
+	if username == "" && apiToken == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("username"),
 			"Missing Proxmox VE API Username",
 			"The provider cannot create the Proxmox VE API client as there is a missing or empty value for the Proxmox VE API username. "+
-				"Set the username value in the configuration or use the PROXMOX_USERNAME environment variable. "+
+				"Set the username value in the configuration, use the PROXMOX_USERNAME environment variable or the credentials_file, or set an api_token. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if password == "" {
+	if password == "" && apiToken == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("password"),
 			"Missing Proxmox VE API Password",
 			"The provider cannot create the Proxmox VE API client as there is a missing or empty value for the Proxmox VE API password. "+
-				"Set the password value in the configuration or use the PROXMOX_PASSWORD environment variable. "+
+				"Set the password value in the configuration, use the PROXMOX_PASSWORD environment variable or the credentials_file, or set an api_token. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	if caCertificate == "" && caCertificateFile != "" {
+		pemBytes, err := os.ReadFile(caCertificateFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_certificate_file"),
+				"Unable to Read Proxmox VE API CA Certificate",
+				err.Error(),
+			)
+		}
+		caCertificate = string(pemBytes)
+	}
+
+	var cipherSuites []string
+	resp.Diagnostics.Append(config.TLSCipherSuites.ElementsAs(ctx, &cipherSuites, false)...)
+
+	tlsConfig, err := newTLSConfig(tlsSettings{
+		insecure:     insecure,
+		caPEM:        caCertificate,
+		fingerprint:  sslFingerprint,
+		minVersion:   config.TLSMinVersion.ValueString(),
+		cipherSuites: cipherSuites,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Proxmox VE API TLS Configuration",
+			fmt.Sprintf("The TLS settings of the provider could not be used: %s", err),
+		)
+	}
+
+	var failoverURLs []*url.URL
+	if len(endpoints) > 0 {
+		for _, endpoint := range append([]string{host}, endpoints...) {
+			endpointURL, err := url.Parse(strings.TrimSpace(endpoint))
+			if err != nil || endpointURL.Scheme == "" || endpointURL.Host == "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("endpoints"),
+					"Invalid Proxmox VE API Endpoint",
+					fmt.Sprintf("The endpoint %q is not a URI like https://pve2.example.com:8006.", endpoint),
+				)
+				continue
+			}
+			failoverURLs = append(failoverURLs, endpointURL)
+		}
+	}
+
+	var guestIDs *vmidRange
+	if !config.VMIDRange.IsNull() {
+		var model vmidRangeModel
+		resp.Diagnostics.Append(config.VMIDRange.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+		guestIDs = &vmidRange{min: model.Min.ValueInt64(), max: model.Max.ValueInt64()}
+
+		if guestIDs.min > guestIDs.max {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("vmid_range"),
+				"Invalid Proxmox VE Guest ID Range",
+				fmt.Sprintf("The min %d of the vmid_range is greater than its max %d.", guestIDs.min, guestIDs.max),
+			)
+		}
+	}
+
+	vmidStrategy, err = resolveVMIDStrategy(vmidStrategy, guestIDs != nil)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vmid_strategy"),
+			"Invalid Proxmox VE Guest ID Strategy",
+			fmt.Sprintf("The vmid_strategy is invalid: %s.", err),
+		)
+	}
+
+	var shell *nodeShell
+	if !config.SSH.IsNull() {
+		var model sshModel
+		resp.Diagnostics.Append(config.SSH.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+
+		var err error
+		shell, err = newNodeShell(model)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssh"),
+				"Invalid Proxmox VE SSH Connection",
+				err.Error(),
+			)
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -177,34 +818,178 @@ func (p *proxmoxProvider) Configure(ctx context.Context, req provider.ConfigureR
 	ctx = tflog.SetField(ctx, "proxmox_username", username)
 	ctx = tflog.SetField(ctx, "proxmox_password", password)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "proxmox_password")
+	if tokenSecret != "" {
+		ctx = tflog.MaskMessageStrings(ctx, tokenSecret)
+	}
 
 	tflog.Debug(ctx, "Creating Proxmox VE client")
 
-	insecureHTTPClient := http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+	requestTimeout := time.Duration(config.RequestTimeout.ValueInt64()) * time.Second
+	var transport http.RoundTripper = &http.Transport{
+		DialContext:           (&net.Dialer{Timeout: requestTimeout}).DialContext,
+		TLSHandshakeTimeout:   requestTimeout,
+		ResponseHeaderTimeout: requestTimeout,
+		TLSClientConfig:       tlsConfig,
+	}
+
+	if logHTTP {
+		transport = &tracingTransport{base: transport}
+	}
+
+	if apiTraceFile != "" {
+		trace, err := openTraceFile(apiTraceFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_trace_file"),
+				"Unable to Open Proxmox VE API Trace File",
+				err.Error(),
+			)
+			return
+		}
+		transport = newFileTraceTransport(transport, trace)
+	}
+
+	if len(failoverURLs) > 1 {
+		transport = &failoverTransport{base: transport, endpoints: failoverURLs}
+	}
+
+	if requestsPerSecond := config.MaxRequestsPerSecond.ValueFloat64(); requestsPerSecond > 0 {
+		transport = newRateLimitedTransport(transport, requestsPerSecond, int(config.MaxRequestBurst.ValueInt64()))
+	}
+
+	if concurrentRequests := config.MaxConcurrentRequests.ValueInt64(); concurrentRequests > 0 {
+		transport = newConcurrencyLimitedTransport(transport, int(concurrentRequests))
+	}
+
+	maxRetries, retryWait := int64(defaultMaxRetries), int64(defaultRetryWait)
+	if !config.MaxRetries.IsNull() {
+		maxRetries = config.MaxRetries.ValueInt64()
+	}
+	if !config.RetryWait.IsNull() {
+		retryWait = config.RetryWait.ValueInt64()
+	}
+	if maxRetries > 0 {
+		transport = &retryTransport{base: transport, maxRetries: int(maxRetries), wait: time.Duration(retryWait) * time.Second}
 	}
 
-	credentials := proxmox.Credentials{
-		Username: username,
-		Password: password,
+	if readOnly {
+		transport = &readOnlyTransport{base: transport}
 	}
-	client := proxmox.NewClient(fmt.Sprintf("%s/api2/json", host),
-		proxmox.WithHTTPClient(&insecureHTTPClient),
-		//proxmox.WithAPIToken(tokenID, secret),
-		proxmox.WithCredentials(&credentials),
+
+	// TOTP codes cannot be reused, so sessions started with otp are only
+	// renewed and not logged in again. API tokens need no session.
+	var session *sessionTransport
+	if apiToken == "" {
+		session = newSessionTransport(transport, host, username, password, otp == "")
+		transport = session
+	}
+
+	httpClient := http.Client{
+		Transport: transport,
+	}
+
+	options := []proxmox.Option{
+		proxmox.WithHTTPClient(&httpClient),
 		proxmox.WithLogger(&proxmox.LeveledLogger{
-			Level: proxmox.LevelDebug,
+			Level: clientLogLevel(hclog.LevelFromString(strings.ToLower(logLevel))),
 		}),
-	)
+	}
+
+	// The client cannot answer the TOTP challenge of the login, so users
+	// with two-factor authentication log in up front.
+	switch {
+	case apiToken != "":
+		options = append(options, proxmox.WithAPIToken(username, tokenSecret))
+	case otp != "":
+		ticket, csrf, err := loginWithOTP(ctx, &httpClient, host, username, password, otp)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("otp"),
+				"Unable to Log In to Proxmox VE",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		options = append(options, proxmox.WithSession(ticket, csrf))
+	default:
+		options = append(options, proxmox.WithCredentials(&proxmox.Credentials{
+			Username: username,
+			Password: password,
+		}))
+	}
+
+	client := proxmox.NewClient(fmt.Sprintf("%s/api2/json", host), options...)
+
+	c := &apiClient{
+		Client:       client,
+		host:         host,
+		username:     username,
+		apiToken:     apiToken,
+		session:      session,
+		tlsConfig:    tlsConfig,
+		api:          pveapi.New(client),
+		vmidRange:    guestIDs,
+		vmidStrategy: vmidStrategy,
+		defaultNode:  defaultNode,
+		shell:        shell,
+		logLevel:     hclog.LevelFromString(strings.ToLower(logLevel)),
+	}
+	if nodeParallelism > 0 {
+		c.nodeLimiter = newNodeLimiter(int(nodeParallelism))
+	}
+
+	if !skipVersionCheck {
+		version, err := c.Version(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Proxmox VE Version",
+				"The provider reads the version of the Proxmox VE API to verify the host and credentials. "+
+					"Restricted API tokens that may not read /version can skip this check with skip_version_check = true.\n\n"+
+					apiErrorDetail(err),
+			)
+			return
+		}
+
+		tflog.Debug(ctx, "Connected to Proxmox VE API", map[string]interface{}{
+			"version": version.Version,
+		})
+	}
+
+	if !config.ClusterName.IsNull() && !config.ClusterName.IsUnknown() {
+		clusterName, err := c.clusterName(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Proxmox VE Cluster Name",
+				apiErrorDetail(err),
+			)
+			return
+		}
+
+		if clusterName != config.ClusterName.ValueString() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cluster_name"),
+				"Unexpected Proxmox VE Cluster",
+				fmt.Sprintf("The host %s belongs to cluster %q, but the provider is configured for cluster %q. "+
+					"Check the host of this provider configuration.", host, clusterName, config.ClusterName.ValueString()),
+			)
+			return
+		}
+	}
 
 	// Make the Proxmox VE client and cluster available during DataSource and
 	// Resource type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.DataSourceData = c
+	resp.ResourceData = c
+}
+
+// Functions defines the functions implemented in the provider.
+func (p *proxmoxProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewParseSizeFunction,
+		NewFormatSizeFunction,
+		NewParseOptionsFunction,
+		NewFormatOptionsFunction,
+	}
 }
 
 // DataSources defines the data sources implemented in the provider.
@@ -212,6 +997,25 @@ func (p *proxmoxProvider) DataSources(_ context.Context) []func() datasource.Dat
 	return []func() datasource.DataSource{
 		NewNodesDataSource,
 		NewNodeNetworksDataSource,
+		NewTaskWaitDataSource,
+		NewVmAgentFileDataSource,
+		NewCloudinitConfigDataSource,
+		NewVmMetricsDataSource,
+		NewEndpointHealthDataSource,
+		NewProviderMetadataDataSource,
+		NewTokenPermissionsDataSource,
+		NewClusterFirewallGroupUsageDataSource,
+		NewNodeCertificatesDataSource,
+		NewClusterCorosyncDataSource,
+		NewDiskSmartDataSource,
+		NewVmAgentInfoDataSource,
+		NewReplicationStatusDataSource,
+		NewGuestConfigDataSource,
+		NewNodeKernelDataSource,
+		NewAptUpdatesDataSource,
+		NewNodeSriovVfsDataSource,
+		NewCurrentUserDataSource,
+		NewClusterFirewallGroupDataSource,
 	}
 }
 
@@ -219,6 +1023,37 @@ func (p *proxmoxProvider) DataSources(_ context.Context) []func() datasource.Dat
 func (p *proxmoxProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewSdnZoneResource,
+		NewSdnVnetResource,
+		NewSdnApplyResource,
 		NewClusterFirewallGroupResource,
+		NewVmAgentFileResource,
+		NewVmCloudinitResource,
+		NewAntiAffinityResource,
+		NewGuestExtraConfigResource,
+		NewVmAgentExecResource,
+		NewUserTfaResource,
+		NewUserResource,
+		NewGroupMembershipResource,
+		NewNodeConfigResource,
+		NewNodeWakeonlanResource,
+		NewNodeDirectoryStorageResource,
+		NewNodeWipeDiskResource,
+		NewSnapshotPolicyResource,
+		NewClusterTagsResource,
+		NewEsxiStorageResource,
+		NewVmImportResource,
+		NewVmApplianceResource,
+		NewLxcExecResource,
+		NewVmMemoryDevicesResource,
+		NewVmNumaResource,
+		NewPbsStorageResource,
+		NewBackupJobResource,
+		NewNodeNetworkAddressResource,
+		NewNodeNetworkBridgeResource,
+		NewClusterJoinResource,
+		NewClusterInitResource,
+		NewVmInstallMediaResource,
+		NewVmResource,
+		NewLxcResource,
 	}
 }
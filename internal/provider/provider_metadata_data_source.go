@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &providerMetadataDataSource{}
+	_ datasource.DataSourceWithConfigure = &providerMetadataDataSource{}
+)
+
+func NewProviderMetadataDataSource() datasource.DataSource {
+	return &providerMetadataDataSource{}
+}
+
+type providerMetadataDataSource struct {
+	client *apiClient
+}
+
+type providerMetadataDataSourceModel struct {
+	Host           types.String `tfsdk:"host"`
+	ClusterName    types.String `tfsdk:"cluster_name"`
+	TLSFingerprint types.String `tfsdk:"tls_fingerprint"`
+}
+
+// clusterStatusEntry is an entry of the cluster status endpoint, describing
+// either the cluster itself or one of its nodes.
+type clusterStatusEntry struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Local   int    `json:"local"`
+	IP      string `json:"ip"`
+	Online  int    `json:"online"`
+	Quorate int    `json:"quorate"`
+}
+
+// clusterNameFromStatus returns the name of the cluster in the cluster
+// status. A standalone node is not part of a cluster and is identified by its
+// own name instead.
+func clusterNameFromStatus(status []clusterStatusEntry) string {
+	for _, entry := range status {
+		if entry.Type == "cluster" {
+			return entry.Name
+		}
+	}
+	for _, entry := range status {
+		if entry.Type == "node" && entry.Local == 1 {
+			return entry.Name
+		}
+	}
+
+	return ""
+}
+
+// clusterName returns the name of the cluster the client is connected to.
+func (c *apiClient) clusterName(ctx context.Context) (string, error) {
+	var status []clusterStatusEntry
+	if err := c.Get(ctx, "/cluster/status", &status); err != nil {
+		return "", err
+	}
+
+	return clusterNameFromStatus(status), nil
+}
+
+func (d *providerMetadataDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *providerMetadataDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provider_metadata"
+}
+
+func (d *providerMetadataDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exports the identity of the cluster the provider configuration points at, so that configurations " +
+			"with several provider aliases can assert that each alias targets the intended cluster.",
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				Computed:    true,
+				Description: "Configured API host",
+			},
+			"cluster_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the cluster, or of the node for a standalone node",
+			},
+			"tls_fingerprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 fingerprint of the certificate presented by the host, in the format shown by Proxmox VE",
+			},
+		},
+	}
+}
+
+func (d *providerMetadataDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logAPI, "Reading cluster name")
+	clusterName, err := d.client.clusterName(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VE Cluster Name",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	_, address, err := endpointAddress(d.client.host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Proxmox VE API Host",
+			err.Error(),
+		)
+		return
+	}
+
+	fingerprint, err := dialEndpoint(ctx, address)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VE TLS Certificate",
+			err.Error(),
+		)
+		return
+	}
+
+	state := providerMetadataDataSourceModel{
+		Host:           types.StringValue(d.client.host),
+		ClusterName:    types.StringValue(clusterName),
+		TLSFingerprint: types.StringValue(fingerprint),
+	}
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
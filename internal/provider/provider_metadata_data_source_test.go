@@ -0,0 +1,32 @@
+package provider
+
+import "testing"
+
+func TestClusterNameFromStatus(t *testing.T) {
+	cases := map[string]struct {
+		status []clusterStatusEntry
+		want   string
+	}{
+		"cluster": {
+			status: []clusterStatusEntry{
+				{Type: "node", Name: "pve1", Local: 1},
+				{Type: "cluster", Name: "prod"},
+			},
+			want: "prod",
+		},
+		"standalone node": {
+			status: []clusterStatusEntry{
+				{Type: "node", Name: "pve1", Local: 1},
+			},
+			want: "pve1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := clusterNameFromStatus(tc.status); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
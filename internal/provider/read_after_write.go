@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// readAfterWriteAttempts is the number of times an object is read back
+	// after a write before giving up.
+	readAfterWriteAttempts = 5
+
+	// readAfterWriteBackoff is the wait before the first retry, doubled on
+	// every further retry.
+	readAfterWriteBackoff = 100 * time.Millisecond
+)
+
+// readAfterWrite reads an object back after it was written. On clusters the
+// node serving the read may not have the change from pmxcfs yet, so the read
+// is retried with a small backoff while it fails or while the returned digest
+// still equals staleDigest, the digest of the configuration before the write.
+// An empty staleDigest only retries failed reads. When the digest does not
+// change within the attempts, the write presumably left the configuration as
+// it was and the last read is returned.
+func readAfterWrite[T any](ctx context.Context, staleDigest string, read func(context.Context) (T, string, error)) (T, error) {
+	backoff := readAfterWriteBackoff
+
+	var (
+		value  T
+		digest string
+		err    error
+	)
+	for attempt := 1; ; attempt++ {
+		value, digest, err = read(ctx)
+		if err == nil && (staleDigest == "" || digest != staleDigest) {
+			return value, nil
+		}
+
+		if attempt == readAfterWriteAttempts {
+			return value, err
+		}
+
+		tflog.SubsystemDebug(ctx, logAPI, "Object not settled after write, retrying read", map[string]interface{}{
+			"attempt": attempt,
+			"stale":   err == nil,
+		})
+
+		select {
+		case <-ctx.Done():
+			return value, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
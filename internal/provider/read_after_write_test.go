@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReadAfterWrite(t *testing.T) {
+	reads := 0
+	read := func(_ context.Context) (int, string, error) {
+		reads++
+		switch reads {
+		case 1:
+			return 0, "", errors.New("zone 'z1' does not exist")
+		case 2:
+			return 1, "old", nil
+		default:
+			return reads, "new", nil
+		}
+	}
+
+	got, err := readAfterWrite(context.Background(), "old", read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("got %d after %d reads, want the third read", got, reads)
+	}
+
+	reads = 1
+	got, err = readAfterWrite(context.Background(), "", read)
+	if err != nil || got != 1 {
+		t.Errorf("got %d, %v, want the first successful read without a stale digest", got, err)
+	}
+}
+
+func TestReadAfterWriteUnchanged(t *testing.T) {
+	reads := 0
+	got, err := readAfterWrite(context.Background(), "same", func(_ context.Context) (string, string, error) {
+		reads++
+		return "zone", "same", nil
+	})
+	if err != nil || got != "zone" {
+		t.Errorf("got %q, %v, want the last read", got, err)
+	}
+	if reads != readAfterWriteAttempts {
+		t.Errorf("got %d reads, want %d", reads, readAfterWriteAttempts)
+	}
+}
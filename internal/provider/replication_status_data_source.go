@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &replicationStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &replicationStatusDataSource{}
+)
+
+func NewReplicationStatusDataSource() datasource.DataSource {
+	return &replicationStatusDataSource{}
+}
+
+type replicationStatusDataSource struct {
+	client *apiClient
+}
+
+type replicationStatusDataSourceModel struct {
+	Node  types.String          `tfsdk:"node"`
+	JobID types.String          `tfsdk:"job_id"`
+	Jobs  []replicationJobModel `tfsdk:"jobs"`
+	Log   types.List            `tfsdk:"log"`
+}
+
+type replicationJobModel struct {
+	ID        types.String  `tfsdk:"id"`
+	Guest     types.Int64   `tfsdk:"guest"`
+	Target    types.String  `tfsdk:"target"`
+	LastSync  types.String  `tfsdk:"last_sync"`
+	LastTry   types.String  `tfsdk:"last_try"`
+	NextSync  types.String  `tfsdk:"next_sync"`
+	Duration  types.Float64 `tfsdk:"duration"`
+	FailCount types.Int64   `tfsdk:"fail_count"`
+	Error     types.String  `tfsdk:"error"`
+}
+
+// replicationJobStatus is an entry of the node replication endpoint.
+type replicationJobStatus struct {
+	ID        string  `json:"id"`
+	Guest     int64   `json:"guest"`
+	Target    string  `json:"target"`
+	LastSync  int64   `json:"last_sync"`
+	LastTry   int64   `json:"last_try"`
+	NextSync  int64   `json:"next_sync"`
+	Duration  float64 `json:"duration"`
+	FailCount int64   `json:"fail_count"`
+	Error     string  `json:"error"`
+}
+
+// taskLogLine is a line of a task or job log.
+type taskLogLine struct {
+	N int    `json:"n"`
+	T string `json:"t"`
+}
+
+func (d *replicationStatusDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *replicationStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_replication_status"
+}
+
+func (d *replicationStatusDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports the status of the storage replication jobs running on a node, " +
+			"e.g. to confirm replication is healthy after the jobs were created.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the replication jobs run on, i.e. the source node of the guests",
+			},
+			"job_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "ID of a single job to report, e.g. 100-0. Its log is read as well",
+			},
+			"jobs": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed: true,
+						},
+						"guest": schema.Int64Attribute{
+							Computed: true,
+						},
+						"target": schema.StringAttribute{
+							Computed: true,
+						},
+						"last_sync": schema.StringAttribute{
+							Computed:    true,
+							Description: "Time of the last successful sync in RFC 3339 format, null if the job never synced",
+						},
+						"last_try": schema.StringAttribute{
+							Computed: true,
+						},
+						"next_sync": schema.StringAttribute{
+							Computed: true,
+						},
+						"duration": schema.Float64Attribute{
+							Computed:    true,
+							Description: "Duration of the last sync in seconds",
+						},
+						"fail_count": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Number of consecutive failed syncs",
+						},
+						"error": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"log": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Log of the last run of the job selected by job_id",
+			},
+		},
+	}
+}
+
+func (d *replicationStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state replicationStatusDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node := state.Node.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logAPI, logFieldNode, node)
+	tflog.SubsystemDebug(ctx, logAPI, "Reading replication status")
+
+	var jobs []replicationJobStatus
+	err := d.client.Get(ctx, fmt.Sprintf("/nodes/%s/replication", node), &jobs)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Replication Status",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Jobs = []replicationJobModel{}
+	for _, job := range jobs {
+		if !state.JobID.IsNull() && job.ID != state.JobID.ValueString() {
+			continue
+		}
+
+		state.Jobs = append(state.Jobs, replicationJobModel{
+			ID:        types.StringValue(job.ID),
+			Guest:     types.Int64Value(job.Guest),
+			Target:    types.StringValue(job.Target),
+			LastSync:  proxmoxtf.UnixTimeOrNull(job.LastSync),
+			LastTry:   proxmoxtf.UnixTimeOrNull(job.LastTry),
+			NextSync:  proxmoxtf.UnixTimeOrNull(job.NextSync),
+			Duration:  types.Float64Value(job.Duration),
+			FailCount: types.Int64Value(job.FailCount),
+			Error:     types.StringValue(job.Error),
+		})
+	}
+
+	var lines []string
+	if !state.JobID.IsNull() {
+		var log []taskLogLine
+		err := d.client.Get(ctx, fmt.Sprintf("/nodes/%s/replication/%s/log", node, state.JobID.ValueString()), &log)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Proxmox Replication Log",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		for _, line := range log {
+			lines = append(lines, line.T)
+		}
+	}
+	state.Log, diags = types.ListValueFrom(ctx, types.StringType, lines)
+	resp.Diagnostics.Append(diags...)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
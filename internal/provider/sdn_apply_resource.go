@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &sdnApplyResource{}
+	_ resource.ResourceWithConfigure  = &sdnApplyResource{}
+	_ resource.ResourceWithModifyPlan = &sdnApplyResource{}
+)
+
+// NewSdnApplyResource is a helper function to simplify the provider implementation.
+func NewSdnApplyResource() resource.Resource {
+	return &sdnApplyResource{}
+}
+
+// sdnApplyResource applies the pending SDN configuration to the nodes.
+type sdnApplyResource struct {
+	client *apiClient
+}
+
+// sdnApplyResourceModel maps the resource schema data.
+type sdnApplyResourceModel struct {
+	Triggers types.Map  `tfsdk:"triggers"`
+	Vnets    types.List `tfsdk:"vnets"`
+	Pending  types.List `tfsdk:"pending"`
+}
+
+// sdnPendingObject is a zone or vnet as listed with its pending changes.
+type sdnPendingObject struct {
+	Zone  string `json:"zone"`
+	Vnet  string `json:"vnet"`
+	State string `json:"state"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *sdnApplyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *sdnApplyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sdn_apply"
+}
+
+// Schema defines the schema for the resource.
+func (r *sdnApplyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Applies the pending SDN configuration to all nodes and waits for the task to finish. " +
+			"The configuration is applied again when a refresh finds zones or vnets with pending changes, e.g. ones " +
+			"changed outside of Terraform or by a failed apply. Changes made by the same run are not pending yet when " +
+			"it is planned, so reference the managed zones and vnets in triggers or in the replace_triggered_by " +
+			"lifecycle argument. Guests attached to managed vnets should reference the vnets attribute, so that the " +
+			"vnet bridges exist on the nodes before the guests are created.",
+		Attributes: map[string]schema.Attribute{
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values, e.g. the IDs of the managed zones and vnets, that apply the configuration again when changed",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"vnets": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Vnets that exist after the configuration was applied",
+			},
+			"pending": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Zones and vnets with changes that are not applied yet, e.g. `zone/lab`. The configuration " +
+					"is applied again when it is not empty",
+			},
+		},
+	}
+}
+
+// pendingSDNChanges returns the zones and vnets with pending changes as
+// zone/<zone> and vnet/<vnet>.
+func (c *apiClient) pendingSDNChanges(ctx context.Context) ([]string, error) {
+	pending := []string{}
+
+	var zones []sdnPendingObject
+	if err := c.Get(ctx, "/cluster/sdn/zones?pending=1", &zones); err != nil {
+		return nil, err
+	}
+	for _, zone := range zones {
+		if zone.State != "" {
+			pending = append(pending, "zone/"+zone.Zone)
+		}
+	}
+
+	var vnets []sdnPendingObject
+	if err := c.Get(ctx, "/cluster/sdn/vnets?pending=1", &vnets); err != nil {
+		return nil, err
+	}
+	for _, vnet := range vnets {
+		if vnet.State != "" {
+			pending = append(pending, "vnet/"+vnet.Vnet)
+		}
+	}
+
+	return pending, nil
+}
+
+// ModifyPlan applies the configuration again when the refreshed state has
+// pending changes.
+func (r *sdnApplyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var pending types.List
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("pending"), &pending)...)
+	if resp.Diagnostics.HasError() || len(pending.Elements()) == 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("pending"), types.ListValueMust(types.StringType, nil))...)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("pending"))
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *sdnApplyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan sdnApplyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logSDN, "Applying SDN configuration")
+	var upid string
+	err := r.client.Put(ctx, "/cluster/sdn", nil, &upid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox SDN Configuration",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox SDN Configuration",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	var vnets []sdnVnet
+	err = r.client.Get(ctx, "/cluster/sdn/vnets", &vnets)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Vnets",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	names := make([]string, 0, len(vnets))
+	for _, vnet := range vnets {
+		names = append(names, vnet.Vnet)
+	}
+	plan.Vnets, diags = types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+
+	pending, err := r.client.pendingSDNChanges(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Pending Proxmox SDN Changes",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	plan.Pending, diags = types.ListValueFrom(ctx, types.StringType, pending)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *sdnApplyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state sdnApplyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The pending changes are the only remote state, which ModifyPlan
+	// turns into a new apply.
+	pending, err := r.client.pendingSDNChanges(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Pending Proxmox SDN Changes",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	state.Pending, diags = types.ListValueFrom(ctx, types.StringType, pending)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *sdnApplyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes require replacement, which applies the configuration again.
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *sdnApplyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The applied configuration is left on the nodes.
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// sdnApplyPending returns the pending attribute value of names.
+func sdnApplyPending(names ...string) tftypes.Value {
+	values := make([]tftypes.Value, 0, len(names))
+	for _, name := range names {
+		values = append(values, tftypes.NewValue(tftypes.String, name))
+	}
+
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values)
+}
+
+func TestSdnApplyResourceCreate(t *testing.T) {
+	api := &fakeAPI{responses: map[string]string{
+		"PUT /cluster/sdn":       fmt.Sprintf("%q", testUPID),
+		"GET /cluster/sdn/zones": `[{"zone":"lab","type":"simple"}]`,
+		"GET /cluster/sdn/vnets": `[{"vnet":"labnet","zone":"lab"}]`,
+	}}
+	res := configuredResource(t, "proxmox_sdn_apply", api)
+	s := resourceSchema(t, res)
+	plan := tfsdk.Plan{Schema: s, Raw: resourceValue(t, s, map[string]tftypes.Value{
+		"vnets":   tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+		"pending": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+	})}
+
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: s}}
+	res.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	var state sdnApplyResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	wantVnets := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("labnet")})
+	if !state.Vnets.Equal(wantVnets) || len(state.Pending.Elements()) != 0 {
+		t.Errorf("got vnets %s and pending %s, want [labnet] and nothing pending", state.Vnets, state.Pending)
+	}
+}
+
+func TestSdnApplyResourceRead(t *testing.T) {
+	api := &fakeAPI{responses: map[string]string{
+		"GET /cluster/sdn/zones": `[{"zone":"lab","type":"simple","state":"changed"},{"zone":"prod","type":"vlan"}]`,
+		"GET /cluster/sdn/vnets": `[{"vnet":"labnet","zone":"lab","state":"new"}]`,
+	}}
+	res := configuredResource(t, "proxmox_sdn_apply", api)
+	s := resourceSchema(t, res)
+	state := tfsdk.State{Schema: s, Raw: resourceValue(t, s, map[string]tftypes.Value{
+		"vnets":   sdnApplyPending(),
+		"pending": sdnApplyPending(),
+	})}
+
+	resp := resource.ReadResponse{State: state}
+	res.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	var pending []string
+	resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("pending"), &pending)...)
+	if want := []string{"zone/lab", "vnet/labnet"}; !slices.Equal(pending, want) {
+		t.Errorf("got pending %v, want %v", pending, want)
+	}
+}
+
+func TestSdnApplyResourceModifyPlan(t *testing.T) {
+	tests := map[string]struct {
+		pending     tftypes.Value
+		wantReplace bool
+	}{
+		"nothing pending": {pending: sdnApplyPending()},
+		"pending zone":    {pending: sdnApplyPending("zone/lab"), wantReplace: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := configuredResource(t, "proxmox_sdn_apply", &fakeAPI{})
+			s := resourceSchema(t, res)
+			value := resourceValue(t, s, map[string]tftypes.Value{
+				"vnets":   sdnApplyPending(),
+				"pending": tt.pending,
+			})
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: s, Raw: value},
+				Plan:  tfsdk.Plan{Schema: s, Raw: value},
+			}
+
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			res.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+
+			replace := slices.ContainsFunc(resp.RequiresReplace, func(p path.Path) bool { return p.Equal(path.Root("pending")) })
+			if replace != tt.wantReplace {
+				t.Errorf("got replace %t, want %t", replace, tt.wantReplace)
+			}
+			var pending types.List
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("pending"), &pending)...)
+			if tt.wantReplace && len(pending.Elements()) != 0 {
+				t.Errorf("got planned pending %s, want nothing pending after the apply", pending)
+			}
+		})
+	}
+}
@@ -0,0 +1,427 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &sdnVnetResource{}
+	_ resource.ResourceWithConfigure  = &sdnVnetResource{}
+	_ resource.ResourceWithModifyPlan = &sdnVnetResource{}
+)
+
+// NewSdnVnetResource is a helper function to simplify the provider implementation.
+func NewSdnVnetResource() resource.Resource {
+	return &sdnVnetResource{}
+}
+
+// sdnVnetResource is the resource implementation.
+type sdnVnetResource struct {
+	client *apiClient
+}
+
+type sdnVnetResourceModel struct {
+	Vnet         types.String `tfsdk:"vnet"`
+	Zone         types.String `tfsdk:"zone"`
+	Alias        types.String `tfsdk:"alias"`
+	Tag          types.Int64  `tfsdk:"tag"`
+	VlanAware    types.Bool   `tfsdk:"vlan_aware"`
+	IsolatePorts types.Bool   `tfsdk:"isolate_ports"`
+	Digest       types.String `tfsdk:"digest"`
+}
+
+// sdnVnet is the API representation of an SDN vnet. The vnet endpoints are
+// not covered by go-proxmox, so they are called directly.
+type sdnVnet struct {
+	Vnet         string `json:"vnet"`
+	Zone         string `json:"zone"`
+	Alias        string `json:"alias,omitempty"`
+	Tag          int64  `json:"tag,omitempty"`
+	VlanAware    int    `json:"vlanaware,omitempty"`
+	IsolatePorts int    `json:"isolate-ports,omitempty"`
+	Digest       string `json:"digest,omitempty"`
+}
+
+func (v *sdnVnetResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	v.client = client
+}
+
+// Metadata returns the resource type name.
+func (v *sdnVnetResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sdn_vnet"
+}
+
+func (v *sdnVnetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"vnet": schema.StringAttribute{
+				Required:    true,
+				Description: "The SDN vnet object identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"zone": schema.StringAttribute{
+				Required:    true,
+				Description: "The SDN zone the vnet belongs to",
+			},
+			"alias": schema.StringAttribute{
+				Optional:    true,
+				Description: "Alias name of the vnet",
+			},
+			"tag": schema.Int64Attribute{
+				Optional:    true,
+				Description: "VLAN or VXLAN id of the vnet",
+			},
+			"vlan_aware": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow VLANs to pass through the vnet",
+			},
+			"isolate_ports": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Isolate the guest ports of the vnet from each other, only allowing traffic to the uplink",
+			},
+			"digest": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// vnetPath returns the API path of an SDN vnet.
+func vnetPath(vnet string) string {
+	return fmt.Sprintf("/cluster/sdn/vnets/%s", vnet)
+}
+
+// vnetParams maps the plan onto the parameters of the vnet create and update
+// calls. Unset optional values are removed through the `delete` parameter.
+func vnetParams(plan sdnVnetResourceModel, create bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"zone":          plan.Zone.ValueString(),
+		"vlanaware":     proxmoxtf.BoolToInt(plan.VlanAware.ValueBool()),
+		"isolate-ports": proxmoxtf.BoolToInt(plan.IsolatePorts.ValueBool()),
+	}
+	if create {
+		params["vnet"] = plan.Vnet.ValueString()
+	}
+
+	var remove []string
+	if plan.Alias.IsNull() {
+		remove = append(remove, "alias")
+	} else {
+		params["alias"] = plan.Alias.ValueString()
+	}
+	if plan.Tag.IsNull() {
+		remove = append(remove, "tag")
+	} else {
+		params["tag"] = plan.Tag.ValueInt64()
+	}
+	if !create && len(remove) > 0 {
+		params["delete"] = strings.Join(remove, ",")
+	}
+
+	return params
+}
+
+// bridgeTrunksVLAN reports whether a VLAN aware bridge with the bridge_vids
+// setting vids passes tag, e.g. `2 10 100-200`. Proxmox VE trunks 2-4094
+// when the setting is empty.
+func bridgeTrunksVLAN(vids string, tag int64) bool {
+	if strings.TrimSpace(vids) == "" {
+		vids = "2-4094"
+	}
+
+	for _, vid := range strings.FieldsFunc(vids, func(r rune) bool { return r == ' ' || r == ',' || r == ';' }) {
+		first, last, isRange := strings.Cut(vid, "-")
+		if !isRange {
+			last = first
+		}
+		from, err := strconv.ParseInt(first, 10, 64)
+		if err != nil {
+			continue
+		}
+		to, err := strconv.ParseInt(last, 10, 64)
+		if err != nil {
+			continue
+		}
+		if tag >= from && tag <= to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ModifyPlan warns when the tag of a vnet in a vlan zone is not trunked by
+// the VLAN aware bridge of the zone on one of its nodes, as guests on the
+// vnet would have no connectivity there.
+func (v *sdnVnetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || v.client == nil {
+		return
+	}
+
+	var plan sdnVnetResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Tag.IsNull() || plan.Tag.IsUnknown() || plan.Zone.IsUnknown() {
+		return
+	}
+
+	ctx = v.client.logContext(ctx)
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "zone", plan.Zone.ValueString())
+
+	// The zone may be created in the same apply, in which case there is
+	// nothing to check yet.
+	zone, err := v.client.api.SDNZone(ctx, plan.Zone.ValueString())
+	if err != nil {
+		tflog.SubsystemDebug(ctx, logSDN, "Skipping bridge VLAN check, zone not readable", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if zone.Type != "vlan" || zone.Bridge == "" {
+		return
+	}
+
+	nodes := zoneNodes(zone.Nodes)
+	if nodes == nil {
+		statuses, err := v.client.Nodes(ctx)
+		if err != nil {
+			tflog.SubsystemDebug(ctx, logSDN, "Skipping bridge VLAN check, nodes not readable", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		for _, status := range statuses {
+			if status.Status == "online" {
+				nodes = append(nodes, status.Node)
+			}
+		}
+	}
+
+	tag := plan.Tag.ValueInt64()
+	for _, node := range nodes {
+		networks, err := v.client.api.NodeNetworks(ctx, node)
+		if err != nil {
+			tflog.SubsystemDebug(ctx, logSDN, "Skipping bridge VLAN check of node", map[string]interface{}{
+				logFieldNode: node,
+				"error":      err.Error(),
+			})
+			continue
+		}
+
+		for _, network := range networks {
+			if network.Iface != zone.Bridge || network.BridgeVlanAware != 1 {
+				continue
+			}
+			if !bridgeTrunksVLAN(network.BridgeVids, tag) {
+				resp.Diagnostics.AddAttributeWarning(
+					path.Root("tag"),
+					"VLAN Not Trunked by Bridge",
+					fmt.Sprintf("The bridge %s of zone %s on node %s is VLAN aware but only trunks VLANs %q, not %d. "+
+						"Guests on this vnet will have no connectivity on that node until the tag is added to bridge_vids.",
+						zone.Bridge, plan.Zone.ValueString(), node, network.BridgeVids, tag),
+				)
+			}
+		}
+	}
+}
+
+// readVnet refreshes the model from the vnet endpoint.
+func (v *sdnVnetResource) readVnet(ctx context.Context, model *sdnVnetResourceModel) error {
+	var vnet sdnVnet
+	if err := v.client.Get(ctx, vnetPath(model.Vnet.ValueString()), &vnet); err != nil {
+		return err
+	}
+
+	model.Vnet = types.StringValue(vnet.Vnet)
+	model.Zone = types.StringValue(vnet.Zone)
+	model.VlanAware = proxmoxtf.Bool(vnet.VlanAware)
+	model.IsolatePorts = proxmoxtf.Bool(vnet.IsolatePorts)
+	model.Digest = types.StringValue(vnet.Digest)
+
+	model.Alias = proxmoxtf.StringOrNull(vnet.Alias)
+	model.Tag = proxmoxtf.Int64OrNull(vnet.Tag)
+
+	return nil
+}
+
+func (v *sdnVnetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = v.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Getting data from plan for proxmox_sdn_vnet")
+	var plan sdnVnetResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "vnet", plan.Vnet.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "zone", plan.Zone.ValueString())
+	tflog.SubsystemInfo(ctx, logSDN, "Creating SDN vnet")
+	err := v.client.Post(ctx, "/cluster/sdn/vnets", vnetParams(plan, true), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox SDN Vnet",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, logSDN, "Mapping response values to resource schema attributes")
+	err = v.readVnet(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Vnet",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	tflog.SubsystemInfo(ctx, logSDN, "Created SDN vnet")
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (v *sdnVnetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = v.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Reading SDN vnet state")
+	var state sdnVnetResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "vnet", state.Vnet.ValueString())
+	tflog.SubsystemDebug(ctx, logSDN, "Refreshing SDN vnet from Proxmox")
+	err := v.readVnet(ctx, &state)
+	if err != nil {
+		// The vnet was removed outside of Terraform.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Vnet",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, logSDN, "Setting state based on response from Proxmox")
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (v *sdnVnetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = v.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Reading SDN vnet config from plan")
+	var plan sdnVnetResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "vnet", plan.Vnet.ValueString())
+	tflog.SubsystemInfo(ctx, logSDN, "Updating SDN vnet")
+	err := v.client.Put(ctx, vnetPath(plan.Vnet.ValueString()), vnetParams(plan, false), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox SDN Vnet",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, logSDN, "Mapping response to resource schema attributes")
+	err = v.readVnet(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Vnet",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, logSDN, "Set state with the updated SDN vnet")
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (v *sdnVnetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = v.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Reading SDN vnet config from state")
+	var state sdnVnetResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "vnet", state.Vnet.ValueString())
+	tflog.SubsystemInfo(ctx, logSDN, "Deleting SDN vnet")
+	err := v.client.Delete(ctx, vnetPath(state.Vnet.ValueString()), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox SDN Vnet",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package provider
+
+import "testing"
+
+func TestBridgeTrunksVLAN(t *testing.T) {
+	tests := []struct {
+		vids string
+		tag  int64
+		want bool
+	}{
+		{"", 100, true},
+		{"", 1, false},
+		{"2 10 100-200", 150, true},
+		{"2 10 100-200", 10, true},
+		{"2 10 100-200", 300, false},
+		{"10,20", 20, true},
+	}
+
+	for _, tc := range tests {
+		if got := bridgeTrunksVLAN(tc.vids, tc.tag); got != tc.want {
+			t.Errorf("bridgeTrunksVLAN(%q, %d) = %t, want %t", tc.vids, tc.tag, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// zoneNodes returns the nodes of the nodes option of an SDN zone, a comma
+// separated list, or nil when the zone spans all nodes.
+func zoneNodes(nodes string) []string {
+	var list []string
+	for _, node := range strings.Split(nodes, ",") {
+		if node = strings.TrimSpace(node); node != "" {
+			list = append(list, node)
+		}
+	}
+
+	return list
+}
+
+// zonePermitsNode reports whether an SDN zone with the nodes option nodes is
+// deployed to node.
+func zonePermitsNode(nodes, node string) bool {
+	list := zoneNodes(nodes)
+	return len(list) == 0 || slices.Contains(list, node)
+}
+
+// validateVnetNodePlan adds an error for each planned bridge matched by
+// bridges that is a vnet of an SDN zone not deployed to the planned node, as
+// the guest would fail to start there. Existing guests are only checked when
+// their node or the bridge changes, so that changing the nodes of a zone does
+// not fail the plans of the guests already running on its vnets.
+func (c *apiClient) validateVnetNodePlan(ctx context.Context, plan tfsdk.Plan, state tfsdk.State, bridges path.Expression, diags *diag.Diagnostics) {
+	// Nothing to validate on destroy or before the provider is configured.
+	if c == nil || plan.Raw.IsNull() {
+		return
+	}
+
+	var node types.String
+	diags.Append(plan.GetAttribute(ctx, path.Root("node"), &node)...)
+	paths, d := plan.PathMatches(ctx, bridges)
+	diags.Append(d...)
+	if diags.HasError() || node.IsNull() || node.IsUnknown() || len(paths) == 0 {
+		return
+	}
+
+	moved := state.Raw.IsNull()
+	if !moved {
+		var prior types.String
+		diags.Append(state.GetAttribute(ctx, path.Root("node"), &prior)...)
+		moved = !prior.Equal(node)
+	}
+
+	planned := map[string]types.String{}
+	for _, p := range paths {
+		// Paths into a null map or object match the map or object itself.
+		var value attr.Value
+		diags.Append(plan.GetAttribute(ctx, p, &value)...)
+		bridge, ok := value.(types.String)
+		if diags.HasError() || !ok || bridge.IsNull() || bridge.IsUnknown() {
+			continue
+		}
+		if !moved {
+			var prior attr.Value
+			diags.Append(state.GetAttribute(ctx, p, &prior)...)
+			if prior != nil && prior.Equal(bridge) {
+				continue
+			}
+		}
+		planned[p.String()] = bridge
+	}
+	if diags.HasError() || len(planned) == 0 {
+		return
+	}
+
+	// The vnets and zones may be created in the same apply, in which case
+	// there is nothing to check yet.
+	vnets, err := c.api.SDNVnets(ctx)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, logSDN, "Skipping vnet node check, vnets not readable", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	vnetZones := make(map[string]string, len(vnets))
+	for _, vnet := range vnets {
+		vnetZones[vnet.Vnet] = vnet.Zone
+	}
+
+	nodesOf := map[string]string{}
+	for _, p := range paths {
+		bridge, ok := planned[p.String()]
+		if !ok {
+			continue
+		}
+		zone, ok := vnetZones[bridge.ValueString()]
+		if !ok {
+			continue
+		}
+
+		nodes, ok := nodesOf[zone]
+		if !ok {
+			z, err := c.api.SDNZone(ctx, zone)
+			if err != nil {
+				tflog.SubsystemDebug(ctx, logSDN, "Skipping vnet node check, zone not readable", map[string]interface{}{
+					"zone":  zone,
+					"error": err.Error(),
+				})
+				continue
+			}
+			nodes = z.Nodes
+			nodesOf[zone] = nodes
+		}
+
+		if !zonePermitsNode(nodes, node.ValueString()) {
+			diags.AddAttributeError(
+				p,
+				"SDN Zone Not Deployed to Node",
+				fmt.Sprintf("The vnet %s belongs to zone %s, which is only deployed to the nodes %s, not to node %s. "+
+					"Place the guest on one of these nodes or add the node to the nodes of the zone.",
+					bridge.ValueString(), zone, nodes, node.ValueString()),
+			)
+		}
+	}
+}
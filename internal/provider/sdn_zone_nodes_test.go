@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestZoneNodes(t *testing.T) {
+	if got := zoneNodes(""); got != nil {
+		t.Errorf("got %v, want nil for a zone spanning all nodes", got)
+	}
+	if got, want := zoneNodes("pve1, pve2,"), []string{"pve1", "pve2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestZonePermitsNode(t *testing.T) {
+	tests := []struct {
+		nodes, node string
+		want        bool
+	}{
+		{nodes: "", node: "pve1", want: true},
+		{nodes: "pve1,pve2", node: "pve2", want: true},
+		{nodes: "pve1,pve2", node: "pve3", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := zonePermitsNode(tt.nodes, tt.node); got != tt.want {
+			t.Errorf("zonePermitsNode(%q, %q) = %v, want %v", tt.nodes, tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestValidateVnetNodePlan(t *testing.T) {
+	responses := map[string]string{
+		"GET /cluster/sdn/vnets":       `[{"vnet":"vnet10","zone":"zone1"}]`,
+		"GET /cluster/sdn/zones/zone1": `{"zone":"zone1","type":"vlan","nodes":"pve2"}`,
+	}
+	s := resourceSchema(t, providerResource(t, "proxmox_vm"))
+	devicesType := s.Type().TerraformType(context.Background()).(tftypes.Object).AttributeTypes["network_devices"].(tftypes.Map)
+	device := func(bridge string) tftypes.Value {
+		attributes := map[string]tftypes.Value{}
+		for name, attributeType := range devicesType.ElementType.(tftypes.Object).AttributeTypes {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+		attributes["bridge"] = tftypes.NewValue(tftypes.String, bridge)
+		return tftypes.NewValue(devicesType.ElementType, attributes)
+	}
+
+	devices := func(bridge string) tftypes.Value {
+		return tftypes.NewValue(devicesType, map[string]tftypes.Value{"net0": device(bridge)})
+	}
+
+	// prior is the node and bridge of an existing guest.
+	type prior struct{ node, bridge string }
+
+	tests := map[string]struct {
+		devices   tftypes.Value
+		prior     *prior
+		wantError bool
+	}{
+		"no network devices": {devices: tftypes.NewValue(devicesType, nil)},
+		"bridge":             {devices: devices("vmbr0")},
+		"vnet on another node": {
+			devices:   devices("vnet10"),
+			wantError: true,
+		},
+		// The zone was deployed to pve when the guest was created and has
+		// since been limited to pve2, which the guest is left alone for.
+		"existing guest after the zone nodes changed": {
+			devices: devices("vnet10"),
+			prior:   &prior{node: "pve", bridge: "vnet10"},
+		},
+		"existing guest moved to another node": {
+			devices:   devices("vnet10"),
+			prior:     &prior{node: "pve2", bridge: "vnet10"},
+			wantError: true,
+		},
+		"existing guest with a new device on the vnet": {
+			devices:   tftypes.NewValue(devicesType, map[string]tftypes.Value{"net0": device("vmbr0"), "net1": device("vnet10")}),
+			prior:     &prior{node: "pve", bridge: "vmbr0"},
+			wantError: true,
+		},
+		"existing guest attached to the vnet": {
+			devices:   devices("vnet10"),
+			prior:     &prior{node: "pve", bridge: "vmbr0"},
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeAPI{responses: responses}
+			plan := tfsdk.Plan{Schema: s, Raw: resourceValue(t, s, map[string]tftypes.Value{
+				"node":            tftypes.NewValue(tftypes.String, "pve"),
+				"network_devices": tt.devices,
+			})}
+			state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+			if tt.prior != nil {
+				state.Raw = resourceValue(t, s, map[string]tftypes.Value{
+					"node":            tftypes.NewValue(tftypes.String, tt.prior.node),
+					"network_devices": devices(tt.prior.bridge),
+				})
+			}
+
+			var diags diag.Diagnostics
+			fakeClient(t, api).validateVnetNodePlan(context.Background(), plan, state, path.MatchRoot("network_devices").AtAnyMapKey().AtName("bridge"), &diags)
+			if got := diags.HasError(); got != tt.wantError {
+				t.Errorf("got error %t, want %t: %v", got, tt.wantError, diags)
+			}
+			if tt.prior != nil && !tt.wantError && len(api.requests) > 0 {
+				t.Errorf("the unchanged guest was checked: %v", api.requests)
+			}
+		})
+	}
+}
@@ -3,40 +3,66 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &sdnZoneResource{}
-	_ resource.ResourceWithConfigure = &sdnZoneResource{}
+	_ resource.Resource                   = &sdnZoneResource{}
+	_ resource.ResourceWithConfigure      = &sdnZoneResource{}
+	_ resource.ResourceWithValidateConfig = &sdnZoneResource{}
 )
 
-// NewClusterFirewallGroupResource is a helper function to simplify the provider implementation.
+// NewSdnZoneResource is a helper function to simplify the provider implementation.
 func NewSdnZoneResource() resource.Resource {
 	return &sdnZoneResource{}
 }
 
-// clusterFirewallGroupResource is the resource implementation.
+// sdnZoneResource is the resource implementation.
 type sdnZoneResource struct {
-	client *proxmox.Client
+	client *apiClient
 }
 
 type sdnZoneResourceModel struct {
-	Zone   types.String `tfsdk:"zone"`
-	Type   types.String `tfsdk:"type"`
-	Dns    types.String `tfsdk:"dns"`
-	Bridge types.String `tfsdk:"bridge"`
-	Digest types.String `tfsdk:"digest"`
+	Zone                    types.String `tfsdk:"zone"`
+	Type                    types.String `tfsdk:"type"`
+	Dns                     types.String `tfsdk:"dns"`
+	Bridge                  types.String `tfsdk:"bridge"`
+	Nodes                   types.Set    `tfsdk:"nodes"`
+	Ipam                    types.String `tfsdk:"ipam"`
+	Mtu                     types.Int64  `tfsdk:"mtu"`
+	AdvertiseSubnets        types.Bool   `tfsdk:"advertise_subnets"`
+	DisableArpNdSuppression types.Bool   `tfsdk:"disable_arp_nd_suppression"`
+	Digest                  types.String `tfsdk:"digest"`
+}
+
+// sdnZoneOptions holds the zone options that pveapi.SDNZone does not
+// cover. They are read and written directly against the zone endpoint.
+type sdnZoneOptions struct {
+	Nodes                   string `json:"nodes"`
+	Ipam                    string `json:"ipam"`
+	Mtu                     int64  `json:"mtu"`
+	AdvertiseSubnets        int    `json:"advertise-subnets"`
+	DisableArpNdSuppression int    `json:"disable-arp-nd-suppression"`
+	Digest                  string `json:"digest"`
 }
 
 func (z *sdnZoneResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -46,12 +72,12 @@ func (z *sdnZoneResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*proxmox.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *proxmox.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -82,12 +108,53 @@ func (z *sdnZoneResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					stringvalidator.OneOf("evpn", "faucet", "qinq", "simple", "vlan", "vxlan"),
 				},
 			},
+			"nodes": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Nodes the zone is deployed to. Guests on the vnets of the zone can only run on these nodes. " +
+					"The zone spans all nodes when not set",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
 			"dns": schema.StringAttribute{
-				Optional: true,
+				Optional:    true,
+				Description: "DNS plugin used by the zone, removed from the zone when not set",
 			},
 			"bridge": schema.StringAttribute{
-				Optional:   true,
-				Validators: []validator.String{}, // TODO: Make `bridge` required if `type` == "vlan"
+				Optional:    true,
+				Description: "Bridge of vlan and qinq zones, required by them. Removed from the zone when not set",
+			},
+			// Attributes that Proxmox defaults server-side are Optional+Computed
+			// and keep their prior state when not configured, so that only
+			// actual changes show up as "known after apply".
+			"ipam": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "IPAM plugin used by the zone, defaults to the Proxmox server setting",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"mtu": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "MTU of the zone, defaults to the Proxmox server setting",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"advertise_subnets": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Announce the full subnet of each vnet instead of the host routes (evpn zones only)",
+			},
+			"disable_arp_nd_suppression": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Disable ARP and ND suppression on the vnets of this zone (evpn zones only)",
 			},
 			"digest": schema.StringAttribute{
 				Computed: true,
@@ -96,9 +163,168 @@ func (z *sdnZoneResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	}
 }
 
+// ValidateConfig rejects vlan and qinq zones without bridge and EVPN-only
+// options on zones of other types.
+func (z *sdnZoneResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config sdnZoneResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.IsUnknown() {
+		return
+	}
+
+	zoneType := config.Type.ValueString()
+	if (zoneType == "vlan" || zoneType == "qinq") && config.Bridge.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("bridge"),
+			"Missing SDN Zone Bridge",
+			fmt.Sprintf("Zones of type %q require the bridge their vnets are attached to.", zoneType),
+		)
+	}
+	if zoneType == "evpn" {
+		return
+	}
+
+	evpnOptions := map[string]types.Bool{
+		"advertise_subnets":          config.AdvertiseSubnets,
+		"disable_arp_nd_suppression": config.DisableArpNdSuppression,
+	}
+	for name, value := range evpnOptions {
+		if value.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid SDN Zone Option",
+				fmt.Sprintf("The %s option is only supported by evpn zones, got zone type %q.", name, config.Type.ValueString()),
+			)
+		}
+	}
+}
+
+// zonePath returns the API path of an SDN zone.
+func zonePath(zone string) string {
+	return fmt.Sprintf("/cluster/sdn/zones/%s", zone)
+}
+
+// setOptions writes the options that are not part of pveapi.SDNZone.
+// Server-defaulted options are only sent when configured, and the EVPN
+// options only for evpn zones since other zone types reject them.
+func (z *sdnZoneResource) setOptions(ctx context.Context, plan sdnZoneResourceModel) error {
+	options := map[string]interface{}{}
+	if !plan.Ipam.IsUnknown() && !plan.Ipam.IsNull() {
+		options["ipam"] = plan.Ipam.ValueString()
+	}
+	if !plan.Mtu.IsUnknown() && !plan.Mtu.IsNull() {
+		options["mtu"] = plan.Mtu.ValueInt64()
+	}
+	if !plan.Nodes.IsUnknown() && !plan.Nodes.IsNull() {
+		var nodes []string
+		if diags := plan.Nodes.ElementsAs(ctx, &nodes, false); diags.HasError() {
+			return fmt.Errorf("converting the nodes of zone %s", plan.Zone.ValueString())
+		}
+		sort.Strings(nodes)
+		options["nodes"] = strings.Join(nodes, ",")
+	}
+	if plan.Type.ValueString() == "evpn" {
+		options["advertise-subnets"] = proxmoxtf.BoolToInt(plan.AdvertiseSubnets.ValueBool())
+		options["disable-arp-nd-suppression"] = proxmoxtf.BoolToInt(plan.DisableArpNdSuppression.ValueBool())
+	}
+
+	if len(options) == 0 {
+		return nil
+	}
+
+	return z.client.Put(ctx, zonePath(plan.Zone.ValueString()), options, nil)
+}
+
+// readOptions maps the options that are not part of pveapi.SDNZone, as
+// well as the latest digest, onto the model.
+func (z *sdnZoneResource) readOptions(ctx context.Context, model *sdnZoneResourceModel) error {
+	var options sdnZoneOptions
+	if err := z.client.Get(ctx, zonePath(model.Zone.ValueString()), &options); err != nil {
+		return err
+	}
+
+	model.Nodes = types.SetNull(types.StringType)
+	if nodes := zoneNodes(options.Nodes); nodes != nil {
+		set, diags := types.SetValueFrom(ctx, types.StringType, nodes)
+		if diags.HasError() {
+			return fmt.Errorf("converting the nodes %q of zone %s", options.Nodes, model.Zone.ValueString())
+		}
+		model.Nodes = set
+	}
+	model.Ipam = proxmoxtf.StringOrNull(options.Ipam)
+	model.Mtu = proxmoxtf.Int64OrNull(options.Mtu)
+	model.AdvertiseSubnets = proxmoxtf.Bool(options.AdvertiseSubnets)
+	model.DisableArpNdSuppression = proxmoxtf.Bool(options.DisableArpNdSuppression)
+	model.Digest = types.StringValue(options.Digest)
+
+	return nil
+}
+
+// readZone reads a zone back after it was written, retrying until its digest
+// differs from staleDigest.
+func (z *sdnZoneResource) readZone(ctx context.Context, name, staleDigest string) (*pveapi.SDNZone, error) {
+	return readAfterWrite(ctx, staleDigest, func(ctx context.Context) (*pveapi.SDNZone, string, error) {
+		zone, err := z.client.api.SDNZone(ctx, name)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return zone, zone.Digest, nil
+	})
+}
+
+// zoneParams returns the dns and bridge options of the plan, which are only
+// sent when set.
+func zoneParams(plan sdnZoneResourceModel) map[string]interface{} {
+	params := map[string]interface{}{}
+	if dns := plan.Dns.ValueString(); dns != "" {
+		params["dns"] = dns
+	}
+	if bridge := plan.Bridge.ValueString(); bridge != "" {
+		params["bridge"] = bridge
+	}
+
+	return params
+}
+
+// zoneDeletes returns the options of prior that are no longer set in plan,
+// which are sent as the delete parameter of the zone update.
+func zoneDeletes(plan, prior sdnZoneResourceModel) []string {
+	options := map[string][2]attr.Value{
+		"bridge": {plan.Bridge, prior.Bridge},
+		"dns":    {plan.Dns, prior.Dns},
+		"nodes":  {plan.Nodes, prior.Nodes},
+	}
+
+	var deletes []string
+	for name, values := range options {
+		if values[0].IsNull() && !values[1].IsNull() {
+			deletes = append(deletes, name)
+		}
+	}
+	sort.Strings(deletes)
+
+	return deletes
+}
+
+// mapZone maps the zone returned by the API onto the model.
+func mapZone(model *sdnZoneResourceModel, zone *pveapi.SDNZone) {
+	model.Zone = types.StringValue(zone.Zone)
+	model.Type = types.StringValue(zone.Type)
+	model.Digest = types.StringValue(zone.Digest)
+	model.Dns = proxmoxtf.StringOrNull(zone.DNS)
+	model.Bridge = proxmoxtf.StringOrNull(zone.Bridge)
+}
+
 func (z *sdnZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = z.client.logContext(ctx)
 
-	tflog.Info(ctx, "Getting data from plan for proxmox_sdn_zone")
+	tflog.SubsystemDebug(ctx, logSDN, "Getting data from plan for proxmox_sdn_zone")
 	var plan sdnZoneResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -106,38 +332,56 @@ func (z *sdnZoneResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	tflog.Info(ctx, "Mapping schema resource attributes to API params")
 	zoneName := plan.Zone.ValueString()
-	config := proxmox.ZoneConfig{
-		Zone:   plan.Zone.ValueString(),
-		Type:   plan.Type.ValueString(),
-		Dns:    plan.Dns.ValueString(),
-		Bridge: plan.Bridge.ValueString(),
-	}
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "zone", zoneName)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Mapping schema resource attributes to API params")
+	config := zoneParams(plan)
+	config["zone"] = zoneName
+	config["type"] = plan.Type.ValueString()
 
-	tflog.Info(ctx, fmt.Sprintf("Creating new zone %s", zoneName))
-	zone, err := z.client.NewZone(ctx, config)
+	tflog.SubsystemInfo(ctx, logSDN, "Creating SDN zone")
+	err := z.client.api.CreateSDNZone(ctx, config)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Read Proxmox Node",
-			err.Error(),
+			"Unable to Create Proxmox SDN Zone",
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Mapping response values to resource schema %s", zone.Zone))
-	plan.Zone = types.StringValue(zone.Zone)
-	plan.Type = types.StringValue(zone.Type)
-	plan.Digest = types.StringValue(zone.Digest)
+	tflog.SubsystemDebug(ctx, logSDN, "Setting additional zone options")
+	err = z.setOptions(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Set Proxmox SDN Zone Options",
+			apiErrorDetail(err),
+		)
+		return
+	}
 
-	if zone.Dns != "" {
-		plan.Dns = types.StringValue(zone.Dns)
+	zone, err := z.readZone(ctx, zoneName, "")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Zone",
+			apiErrorDetail(err),
+		)
+		return
 	}
-	if zone.Bridge != "" {
-		plan.Bridge = types.StringValue(zone.Bridge)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Mapping response values to resource schema attributes")
+	mapZone(&plan, zone)
+
+	err = z.readOptions(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Zone Options",
+			apiErrorDetail(err),
+		)
+		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Successfully created Zone: %s", zone.Zone))
+	tflog.SubsystemInfo(ctx, logSDN, "Created SDN zone")
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -148,8 +392,9 @@ func (z *sdnZoneResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (z *sdnZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = z.client.logContext(ctx)
 
-	tflog.Info(ctx, "Reading SDN Zone state")
+	tflog.SubsystemDebug(ctx, logSDN, "Reading SDN zone state")
 	var state sdnZoneResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -157,32 +402,38 @@ func (z *sdnZoneResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	tflog.Info(ctx, "Refreshing SDN Zone from Proxmox")
 	zoneName := state.Zone.ValueString()
-	ret, err := z.client.Zone(ctx, zoneName)
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "zone", zoneName)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Refreshing SDN zone from Proxmox")
+	ret, err := z.client.api.SDNZone(ctx, zoneName)
 	if err != nil {
+		// The zone was removed outside of Terraform.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
 		resp.Diagnostics.AddError(
-			"Unable to Read Proxmox Node",
-			err.Error(),
+			"Unable to Read Proxmox SDN Zone",
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	tflog.Info(ctx, "Mapping response values to resource schema attributes")
-	state.Zone = types.StringValue(ret.Zone)
-	state.Type = types.StringValue(ret.Type)
-	state.Bridge = types.StringValue(ret.Bridge)
-	if ret.Dns != "" {
-		state.Dns = types.StringValue(ret.Dns)
-	}
-	if ret.Bridge != "" {
-		state.Bridge = types.StringValue(ret.Bridge)
-	}
+	tflog.SubsystemDebug(ctx, logSDN, "Mapping response values to resource schema attributes")
+	mapZone(&state, ret)
 
-	state.Dns = types.StringValue(ret.Dns)
-	state.Bridge = types.StringValue(ret.Bridge)
+	err = z.readOptions(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Zone Options",
+			apiErrorDetail(err),
+		)
+		return
+	}
 
-	tflog.Info(ctx, "Setting state based on response from Proxmox")
+	tflog.SubsystemDebug(ctx, logSDN, "Setting state based on response from Proxmox")
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -191,7 +442,9 @@ func (z *sdnZoneResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (z *sdnZoneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	tflog.Info(ctx, "Reading SDN Zone config from plan")
+	ctx = z.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Reading SDN zone config from plan")
 	var plan sdnZoneResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -199,45 +452,72 @@ func (z *sdnZoneResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	tflog.Info(ctx, "Generating API request from plan")
 	zoneName := plan.Zone.ValueString()
-	config := proxmox.ZoneConfig{
-		Zone:   plan.Zone.ValueString(),
-		Dns:    plan.Dns.ValueString(),
-		Bridge: plan.Bridge.ValueString(),
-	}
-	ret, err := z.client.Zone(ctx, zoneName)
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "zone", zoneName)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Generating API request from plan")
+	config := zoneParams(plan)
+	ret, err := z.client.api.SDNZone(ctx, zoneName)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Read Proxmox Node",
-			err.Error(),
+			"Unable to Update Proxmox SDN Zone",
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	tflog.Info(ctx, "Updating the SDN Zone")
-	ret.Update(ctx, config)
+	// Options are only sent when set, so their removal is planned from the
+	// prior state.
+	var prior sdnZoneResourceModel
+	diags = req.State.Get(ctx, &prior)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if deletes := zoneDeletes(plan, prior); len(deletes) > 0 {
+		config["delete"] = strings.Join(deletes, ",")
+	}
+
+	tflog.SubsystemInfo(ctx, logSDN, "Updating SDN zone")
+	err = z.client.api.UpdateSDNZone(ctx, zoneName, config)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox SDN Zone",
+			apiErrorDetail(err),
+		)
+		return
+	}
 
-	tflog.Info(ctx, "Mapping response to resource schema attributes")
-	ret, err = z.client.Zone(ctx, zoneName)
+	err = z.setOptions(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Read Proxmox Node",
-			err.Error(),
+			"Unable to Set Proxmox SDN Zone Options",
+			apiErrorDetail(err),
 		)
 		return
 	}
-	plan.Zone = types.StringValue(ret.Zone)
-	plan.Type = types.StringValue(ret.Type)
-	plan.Digest = types.StringValue(ret.Digest)
-	if ret.Dns != "" {
-		plan.Dns = types.StringValue(ret.Dns)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Mapping response to resource schema attributes")
+	ret, err = z.readZone(ctx, zoneName, ret.Digest)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox SDN Zone",
+			apiErrorDetail(err),
+		)
+		return
 	}
-	if ret.Bridge != "" {
-		plan.Bridge = types.StringValue(ret.Bridge)
+	mapZone(&plan, ret)
+
+	err = z.readOptions(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox SDN Zone Options",
+			apiErrorDetail(err),
+		)
+		return
 	}
 
-	tflog.Info(ctx, "Set state with the updated SDN Zone")
+	tflog.SubsystemDebug(ctx, logSDN, "Set state with the updated SDN zone")
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -247,7 +527,9 @@ func (z *sdnZoneResource) Update(ctx context.Context, req resource.UpdateRequest
 }
 
 func (z *sdnZoneResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	tflog.Info(ctx, "Reading SDN Zone config from state")
+	ctx = z.client.logContext(ctx)
+
+	tflog.SubsystemDebug(ctx, logSDN, "Reading SDN zone config from state")
 	var state sdnZoneResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -255,17 +537,16 @@ func (z *sdnZoneResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	tflog.Info(ctx, "Deleting SDN Zone")
-	zone, err := z.client.Zone(ctx, state.Zone.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logSDN, "zone", state.Zone.ValueString())
+	tflog.SubsystemInfo(ctx, logSDN, "Deleting SDN zone")
+	err := z.client.api.DeleteSDNZone(ctx, state.Zone.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Read Proxmox Node",
-			err.Error(),
+			"Unable to Delete Proxmox SDN Zone",
+			apiErrorDetail(err),
 		)
 		return
 	}
 
-	zone.Delete(ctx)
-
 	return
 }
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+func TestMapZone(t *testing.T) {
+	cases := map[string]struct {
+		zone       pveapi.SDNZone
+		wantDns    types.String
+		wantBridge types.String
+	}{
+		"unset": {
+			zone:       pveapi.SDNZone{Zone: "simple1", Type: "simple"},
+			wantDns:    types.StringNull(),
+			wantBridge: types.StringNull(),
+		},
+		"set": {
+			zone:       pveapi.SDNZone{Zone: "vlan1", Type: "vlan", DNS: "powerdns", Bridge: "vmbr0"},
+			wantDns:    types.StringValue("powerdns"),
+			wantBridge: types.StringValue("vmbr0"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			model := sdnZoneResourceModel{
+				Dns:    types.StringValue("stale"),
+				Bridge: types.StringValue("stale"),
+			}
+			mapZone(&model, &tc.zone)
+
+			if !model.Dns.Equal(tc.wantDns) {
+				t.Errorf("dns: got %v, want %v", model.Dns, tc.wantDns)
+			}
+			if !model.Bridge.Equal(tc.wantBridge) {
+				t.Errorf("bridge: got %v, want %v", model.Bridge, tc.wantBridge)
+			}
+			if model.Zone.ValueString() != tc.zone.Zone || model.Type.ValueString() != tc.zone.Type {
+				t.Errorf("got zone %v of type %v, want %s of type %s", model.Zone, model.Type, tc.zone.Zone, tc.zone.Type)
+			}
+		})
+	}
+}
+
+func TestZoneDeletes(t *testing.T) {
+	nodes := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("pve1")})
+	set := sdnZoneResourceModel{
+		Dns:    types.StringValue("powerdns"),
+		Bridge: types.StringValue("vmbr0"),
+		Nodes:  nodes,
+	}
+	unset := sdnZoneResourceModel{
+		Dns:    types.StringNull(),
+		Bridge: types.StringNull(),
+		Nodes:  types.SetNull(types.StringType),
+	}
+
+	cases := map[string]struct {
+		plan, prior sdnZoneResourceModel
+		want        []string
+	}{
+		"unchanged": {plan: set, prior: set},
+		"added":     {plan: set, prior: unset},
+		"removed":   {plan: unset, prior: set, want: []string{"bridge", "dns", "nodes"}},
+		"never set": {plan: unset, prior: unset},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := zoneDeletes(tc.plan, tc.prior); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSdnZoneResourceValidateConfig(t *testing.T) {
+	cases := map[string]struct {
+		values    map[string]tftypes.Value
+		wantError bool
+	}{
+		"simple": {values: map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "simple"),
+		}},
+		"vlan with bridge": {values: map[string]tftypes.Value{
+			"type":   tftypes.NewValue(tftypes.String, "vlan"),
+			"bridge": tftypes.NewValue(tftypes.String, "vmbr0"),
+		}},
+		"vlan without bridge": {values: map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "vlan"),
+		}, wantError: true},
+		"qinq without bridge": {values: map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "qinq"),
+		}, wantError: true},
+		"evpn option on simple zone": {values: map[string]tftypes.Value{
+			"type":              tftypes.NewValue(tftypes.String, "simple"),
+			"advertise_subnets": tftypes.NewValue(tftypes.Bool, true),
+		}, wantError: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res := configuredResource(t, "proxmox_sdn_zone", &fakeAPI{})
+			s := resourceSchema(t, res)
+			tc.values["zone"] = tftypes.NewValue(tftypes.String, "zone1")
+
+			var resp resource.ValidateConfigResponse
+			res.(resource.ResourceWithValidateConfig).ValidateConfig(context.Background(), resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: s, Raw: resourceValue(t, s, tc.values)},
+			}, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tc.wantError {
+				t.Errorf("got error %t, want %t: %v", got, tc.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
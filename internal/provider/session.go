@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ticketRenewAfter is the age after which a session ticket is renewed.
+// Proxmox VE tickets expire after two hours and can be renewed with the
+// ticket itself while they are still valid.
+const ticketRenewAfter = time.Hour
+
+// sessionTransport keeps the session of the provider alive during long
+// applies. It picks up the ticket of every login that passes through it,
+// renews the ticket before it expires and sends the current one with every
+// request, as the go-proxmox client would keep sending its first ticket.
+// Requests rejected with 401 are sent again after logging in with the
+// password, unless the user logs in with a TOTP code that cannot be reused.
+// Concurrent requests share a single renewal or login, which runs without
+// holding the lock so that requests with a valid ticket are not blocked.
+type sessionTransport struct {
+	base     http.RoundTripper
+	host     string
+	username string
+	password string
+	relogin  bool
+	now      func() time.Time
+
+	mu     sync.Mutex
+	ticket string
+	csrf   string
+	issued time.Time
+	logins map[string]*sessionLogin
+}
+
+// sessionLogin is a login in flight that concurrent requests wait for
+// instead of logging in themselves.
+type sessionLogin struct {
+	done chan struct{}
+	err  error
+}
+
+// The keys of the logins in flight.
+const (
+	loginRenew   = "renew"
+	loginRelogin = "relogin"
+)
+
+// newSessionTransport returns a sessionTransport logging in to host as
+// username. relogin enables logging in again with password after a 401.
+func newSessionTransport(base http.RoundTripper, host, username, password string, relogin bool) *sessionTransport {
+	return &sessionTransport{
+		base:     base,
+		host:     host,
+		username: username,
+		password: password,
+		relogin:  relogin,
+		now:      time.Now,
+	}
+}
+
+// ticketRequest reports whether req requests a session ticket.
+func ticketRequest(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/access/ticket")
+}
+
+// store remembers the ticket of a successful login. The caller holds t.mu.
+func (t *sessionTransport) store(ticket *ticketResponse) {
+	// Tickets of a pending TOTP challenge are not valid for the API.
+	if ticket.Data.Ticket == "" || ticket.Data.NeedTFA == 1 {
+		return
+	}
+
+	t.ticket = ticket.Data.Ticket
+	t.csrf = ticket.Data.CSRFPreventionToken
+	t.issued = t.now()
+}
+
+// login requests a ticket with password, which is either the password of
+// the user or the current ticket to renew it. Concurrent logins with the same
+// key share the request of the first one, which holds t.mu only to store the
+// ticket.
+func (t *sessionTransport) login(ctx context.Context, key, password string) error {
+	t.mu.Lock()
+	if call, ok := t.logins[key]; ok {
+		t.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	call := &sessionLogin{done: make(chan struct{})}
+	if t.logins == nil {
+		t.logins = map[string]*sessionLogin{}
+	}
+	t.logins[key] = call
+	t.mu.Unlock()
+
+	ticket, err := requestTicket(ctx, &http.Client{Transport: t.base}, t.host, url.Values{
+		"username": {t.username},
+		"password": {password},
+	})
+
+	t.mu.Lock()
+	if err == nil {
+		t.store(ticket)
+	}
+	delete(t.logins, key)
+	t.mu.Unlock()
+
+	call.err = err
+	close(call.done)
+	return err
+}
+
+// current returns the current ticket, CSRF token and the time the ticket was
+// issued at.
+func (t *sessionTransport) current() (ticket, csrf string, issued time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.ticket, t.csrf, t.issued
+}
+
+// session returns the current ticket and CSRF token, renewing the ticket
+// when it is due. Without a ticket, e.g. before the first login, it returns
+// empty values and requests are sent as they are.
+func (t *sessionTransport) session(ctx context.Context) (ticket, csrf string) {
+	ticket, csrf, issued := t.current()
+	if ticket == "" || t.now().Sub(issued) < ticketRenewAfter {
+		return ticket, csrf
+	}
+
+	tflog.SubsystemDebug(ctx, logAPI, "Renewing session ticket", map[string]interface{}{
+		"issued": issued.Format(time.RFC3339),
+	})
+	// A failed renewal leaves the ticket as is, an expired one is handled
+	// by the login after a 401.
+	if err := t.login(ctx, loginRenew, ticket); err != nil {
+		tflog.SubsystemWarn(ctx, logAPI, "Unable to renew session ticket", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	ticket, csrf, _ = t.current()
+	return ticket, csrf
+}
+
+// reauthenticate logs in with the password after the ticket sent was
+// rejected. Concurrent requests rejected with the same ticket log in once.
+func (t *sessionTransport) reauthenticate(ctx context.Context, rejected string) (ticket, csrf string, err error) {
+	if ticket, csrf, _ := t.current(); ticket != rejected {
+		return ticket, csrf, nil
+	}
+
+	tflog.SubsystemInfo(ctx, logAPI, "Session ticket rejected, logging in again")
+	if err := t.login(ctx, loginRelogin, t.password); err != nil {
+		return "", "", err
+	}
+
+	ticket, csrf, _ = t.current()
+	return ticket, csrf, nil
+}
+
+// withSession returns a copy of req authenticated with ticket and csrf.
+func withSession(req *http.Request, ticket, csrf string) (*http.Request, error) {
+	req = req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+
+	req.Header.Set("Cookie", "PVEAuthCookie="+ticket)
+	if csrf != "" {
+		req.Header.Set("CSRFPreventionToken", csrf)
+	}
+
+	return req, nil
+}
+
+// RoundTrip sends req with the current session ticket.
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if ticketRequest(req) {
+		return t.roundTripLogin(req)
+	}
+
+	ticket, csrf := t.session(ctx)
+	if ticket == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	authenticated, err := withSession(req, ticket, csrf)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(authenticated)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !t.relogin {
+		return resp, err
+	}
+
+	// A request body can only be sent again when it can be recreated.
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	ticket, csrf, err = t.reauthenticate(ctx, ticket)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, logAPI, "Unable to log in again", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	authenticated, err = withSession(req, ticket, csrf)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(authenticated)
+}
+
+// roundTripLogin sends a ticket request and remembers the resulting ticket.
+func (t *sessionTransport) roundTripLogin(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	var ticket ticketResponse
+	if err := json.Unmarshal(data, &ticket); err == nil {
+		t.mu.Lock()
+		t.store(&ticket)
+		t.mu.Unlock()
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSessionTransport(t *testing.T) {
+	var mu sync.Mutex
+	valid, issued := "", 0
+	logins := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/api2/json/access/ticket" {
+			password := r.FormValue("password")
+			logins = append(logins, password)
+			if password != "secret" && password != valid {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			issued++
+			valid = fmt.Sprintf("ticket%d", issued)
+			fmt.Fprintf(w, `{"data":{"ticket":%q,"CSRFPreventionToken":"csrf"}}`, valid)
+			return
+		}
+
+		if r.Header.Get("Cookie") != "PVEAuthCookie="+valid || r.Header.Get("CSRFPreventionToken") != "csrf" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"data":null}`)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	transport := newSessionTransport(http.DefaultTransport, server.URL, "root@pam", "secret", true)
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	get := func() int {
+		t.Helper()
+		resp, err := client.Get(server.URL + "/api2/json/version")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The ticket of a login through the transport is picked up.
+	ticket, _, err := loginWithOTP(context.Background(), client, server.URL, "root@pam", "secret", "")
+	if err != nil || ticket != "ticket1" {
+		t.Fatalf("login: %s, %v", ticket, err)
+	}
+	if status := get(); status != http.StatusOK {
+		t.Fatalf("got status %d with the first ticket", status)
+	}
+
+	// Tickets are renewed with the ticket before they expire.
+	now = now.Add(ticketRenewAfter)
+	if status := get(); status != http.StatusOK {
+		t.Fatalf("got status %d after renewal", status)
+	}
+	if got := strings.Join(logins, ","); got != "secret,ticket1" {
+		t.Errorf("got logins %s", got)
+	}
+
+	// A rejected ticket leads to a new login with the password.
+	mu.Lock()
+	valid = "expired"
+	mu.Unlock()
+	if status := get(); status != http.StatusOK {
+		t.Fatalf("got status %d after the ticket was rejected", status)
+	}
+	if got := strings.Join(logins, ","); got != "secret,ticket1,secret" {
+		t.Errorf("got logins %s", got)
+	}
+}
+
+func TestSessionTransportWithoutRelogin(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/access/ticket" {
+			logins++
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	transport := newSessionTransport(http.DefaultTransport, server.URL, "root@pam", "secret", false)
+	transport.ticket, transport.issued = "ticket", time.Now()
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL + "/api2/json/version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized || logins != 0 {
+		t.Errorf("got status %d after %d logins, want 401 without login", resp.StatusCode, logins)
+	}
+}
+
+func TestSessionTransportConcurrentRenewal(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	renewals := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api2/json/access/ticket" {
+			mu.Lock()
+			renewals++
+			first := renewals == 1
+			mu.Unlock()
+			if first {
+				close(started)
+			}
+			<-release
+			fmt.Fprint(w, `{"data":{"ticket":"ticket2","CSRFPreventionToken":"csrf"}}`)
+			return
+		}
+
+		if r.Header.Get("Cookie") != "PVEAuthCookie=ticket2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"data":null}`)
+	}))
+	defer server.Close()
+
+	transport := newSessionTransport(http.DefaultTransport, server.URL, "root@pam", "secret", false)
+	transport.ticket, transport.csrf, transport.issued = "ticket1", "csrf", time.Now().Add(-ticketRenewAfter)
+	client := &http.Client{Transport: transport}
+
+	const requests = 5
+	statuses := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			resp, err := client.Get(server.URL + "/api2/json/version")
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+
+	// The lock is free while the renewal is in flight.
+	<-started
+	if !transport.mu.TryLock() {
+		t.Fatal("the session lock is held during the renewal")
+	}
+	transport.mu.Unlock()
+	close(release)
+
+	for i := 0; i < requests; i++ {
+		if status := <-statuses; status != http.StatusOK {
+			t.Errorf("got status %d, want 200 with the renewed ticket", status)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if renewals != 1 {
+		t.Errorf("got %d renewals, want 1", renewals)
+	}
+}
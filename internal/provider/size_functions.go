@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementations satisfy the expected interfaces.
+var (
+	_ function.Function = &parseSizeFunction{}
+	_ function.Function = &formatSizeFunction{}
+)
+
+// NewParseSizeFunction is a helper function to simplify the provider implementation.
+func NewParseSizeFunction() function.Function {
+	return &parseSizeFunction{}
+}
+
+// parseSizeFunction converts a Proxmox VE size string into bytes.
+type parseSizeFunction struct{}
+
+// Metadata returns the function name.
+func (f *parseSizeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_size"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *parseSizeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Convert a Proxmox VE size into bytes",
+		Description: "Converts a size with an optional K, M, G or T suffix, e.g. `8G` of a disk, into bytes. The units are powers of 1024 and sizes without suffix are bytes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "size",
+				Description: "Size to convert, e.g. 8G",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+// Run converts the size.
+func (f *parseSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &size))
+	if resp.Error != nil {
+		return
+	}
+
+	bytes, err := proxmoxtf.ParseSize(size)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, bytes))
+}
+
+// NewFormatSizeFunction is a helper function to simplify the provider implementation.
+func NewFormatSizeFunction() function.Function {
+	return &formatSizeFunction{}
+}
+
+// formatSizeFunction converts bytes into a Proxmox VE size string.
+type formatSizeFunction struct{}
+
+// Metadata returns the function name.
+func (f *formatSizeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_size"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *formatSizeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Convert bytes into a Proxmox VE size",
+		Description: "Converts bytes into a size with the largest K, M, G or T suffix that represents them exactly, the way Proxmox VE writes sizes, e.g. 8589934592 into `8G`.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "bytes",
+				Description: "Number of bytes to convert",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run converts the bytes.
+func (f *formatSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bytes int64
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &bytes))
+	if resp.Error != nil {
+		return
+	}
+
+	if bytes < 0 {
+		resp.Error = function.NewArgumentFuncError(0, "bytes must not be negative")
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, proxmoxtf.FormatSize(bytes)))
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseSizeFunction(t *testing.T) {
+	tests := map[string]struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		"gigabytes": {size: "8G", want: 8 << 30},
+		"bytes":     {size: "512", want: 512},
+		"invalid":   {size: "eight", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tc.size)}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.Int64Unknown()),
+			}
+
+			NewParseSizeFunction().Run(context.Background(), req, resp)
+			if (resp.Error != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %t", resp.Error, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := resp.Result.Value(); !got.Equal(types.Int64Value(tc.want)) {
+				t.Errorf("got %s, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatSizeFunction(t *testing.T) {
+	tests := map[string]struct {
+		bytes   int64
+		want    string
+		wantErr bool
+	}{
+		"gigabytes": {bytes: 8 << 30, want: "8G"},
+		"megabytes": {bytes: 1536 << 20, want: "1536M"},
+		"negative":  {bytes: -1, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(tc.bytes)}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewFormatSizeFunction().Run(context.Background(), req, resp)
+			if (resp.Error != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %t", resp.Error, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := resp.Result.Value(); !got.Equal(types.StringValue(tc.want)) {
+				t.Errorf("got %s, want %q", got, tc.want)
+			}
+		})
+	}
+}
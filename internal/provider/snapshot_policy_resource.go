@@ -0,0 +1,297 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &snapshotPolicyResource{}
+	_ resource.ResourceWithConfigure = &snapshotPolicyResource{}
+)
+
+// NewSnapshotPolicyResource is a helper function to simplify the provider implementation.
+func NewSnapshotPolicyResource() resource.Resource {
+	return &snapshotPolicyResource{}
+}
+
+// snapshotPolicyResource takes and prunes snapshots of a set of guests.
+type snapshotPolicyResource struct {
+	client *apiClient
+}
+
+// snapshotPolicyResourceModel maps the resource schema data.
+type snapshotPolicyResourceModel struct {
+	VMIDs     types.Set    `tfsdk:"vm_ids"`
+	Prefix    types.String `tfsdk:"prefix"`
+	Retain    types.Int64  `tfsdk:"retain"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	Snapshots types.List   `tfsdk:"snapshots"`
+}
+
+// guestSnapshot is an entry of the guest snapshot endpoint.
+type guestSnapshot struct {
+	Name     string `json:"name"`
+	SnapTime int64  `json:"snaptime"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *snapshotPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *snapshotPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot_policy"
+}
+
+// Schema defines the schema for the resource.
+func (r *snapshotPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Takes a snapshot of each guest whenever the triggers change and removes the oldest snapshots " +
+			"taken by the policy beyond the retention count. Proxmox VE has no snapshot scheduler, combine the " +
+			"triggers with e.g. a time_rotating resource and regular applies to take periodic snapshots.",
+		Attributes: map[string]schema.Attribute{
+			"vm_ids": schema.SetAttribute{
+				ElementType: types.Int64Type,
+				Required:    true,
+				Description: "IDs of the guests to snapshot",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("auto"),
+				Description: "Prefix of the snapshot names, only snapshots with this prefix are pruned. Defaults to auto",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"retain": schema.Int64Attribute{
+				Required:    true,
+				Description: "Number of snapshots with the prefix to keep per guest",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that take a new snapshot when changed",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"snapshots": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Snapshots taken by the last run, as vmid/name",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// snapshotsToPrune returns the names of the oldest snapshots with the prefix
+// beyond the retain count.
+func snapshotsToPrune(snapshots []guestSnapshot, prefix string, retain int) []string {
+	var managed []guestSnapshot
+	for _, snapshot := range snapshots {
+		if strings.HasPrefix(snapshot.Name, prefix+"-") {
+			managed = append(managed, snapshot)
+		}
+	}
+	if len(managed) <= retain {
+		return nil
+	}
+
+	sort.Slice(managed, func(i, j int) bool {
+		return managed[i].SnapTime < managed[j].SnapTime
+	})
+
+	var prune []string
+	for _, snapshot := range managed[:len(managed)-retain] {
+		prune = append(prune, snapshot.Name)
+	}
+
+	return prune
+}
+
+// snapshotGuest takes a snapshot of a guest and prunes old ones.
+func (r *snapshotPolicyResource) snapshotGuest(ctx context.Context, guest *proxmox.ClusterResource, name, prefix string, retain int) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, guest.Node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, guest.VMID)
+	path := guestPath(guest.Node, guest.Type, int64(guest.VMID)) + "/snapshot"
+
+	err := r.client.takeSnapshot(ctx, guest.Node, guest.Type, int64(guest.VMID), name, "Taken by Terraform snapshot policy "+prefix)
+	if err != nil {
+		return err
+	}
+
+	var snapshots []guestSnapshot
+	if err := r.client.Get(ctx, path, &snapshots); err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshotsToPrune(snapshots, prefix, retain) {
+		tflog.SubsystemInfo(ctx, logVM, "Pruning snapshot", map[string]interface{}{
+			"snapshot": snapshot,
+		})
+		var upid string
+		if err := r.client.Delete(ctx, path+"/"+snapshot, &upid); err != nil {
+			return err
+		}
+		if _, err := r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *snapshotPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan snapshotPolicyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var vmids []int64
+	resp.Diagnostics.Append(plan.VMIDs.ElementsAs(ctx, &vmids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cluster, err := r.client.Cluster(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	resources, err := cluster.Resources(ctx, "vm")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Cluster Resources",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// Snapshot names must start with a letter and are limited to 40
+	// characters, a second resolution timestamp keeps them unique.
+	prefix := plan.Prefix.ValueString()
+	name := fmt.Sprintf("%s-%s", prefix, time.Now().UTC().Format("20060102150405"))
+
+	var taken []string
+	for _, vmid := range vmids {
+		var guest *proxmox.ClusterResource
+		for _, res := range resources {
+			if int64(res.VMID) == vmid {
+				guest = res
+			}
+		}
+		if guest == nil {
+			resp.Diagnostics.AddError(
+				"Unable to Snapshot Proxmox Guest",
+				fmt.Sprintf("Guest %d does not exist.", vmid),
+			)
+			return
+		}
+
+		err = r.snapshotGuest(ctx, guest, name, prefix, int(plan.Retain.ValueInt64()))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Snapshot Proxmox Guest",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		taken = append(taken, fmt.Sprintf("%d/%s", vmid, name))
+	}
+
+	plan.Snapshots, diags = types.ListValueFrom(ctx, types.StringType, taken)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *snapshotPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Snapshots are taken as an action, there is no remote state to refresh.
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *snapshotPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only retain can change in place, it takes effect with the next snapshot.
+	var plan snapshotPolicyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *snapshotPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Snapshots are kept, a replacement prunes them according to retain.
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotsToPrune(t *testing.T) {
+	snapshots := []guestSnapshot{
+		{Name: "current"},
+		{Name: "auto-20240103000000", SnapTime: 3},
+		{Name: "auto-20240101000000", SnapTime: 1},
+		{Name: "before-upgrade", SnapTime: 0},
+		{Name: "auto-20240102000000", SnapTime: 2},
+	}
+
+	got := snapshotsToPrune(snapshots, "auto", 1)
+	want := []string{"auto-20240101000000", "auto-20240102000000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := snapshotsToPrune(snapshots, "auto", 3); got != nil {
+		t.Errorf("expected nothing to prune, got %v", got)
+	}
+}
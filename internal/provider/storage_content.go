@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// storageContents caches the content types of the storages of the cluster,
+// read once per provider run for the plan checks of guest resources. The
+// zero value is ready to use.
+type storageContents struct {
+	mu       sync.Mutex
+	contents map[string][]string
+}
+
+// storageContent returns the content types of storage, which is not found
+// when it does not exist yet, e.g. because it is created in the same apply.
+func (c *apiClient) storageContent(ctx context.Context, storage string) ([]string, bool, error) {
+	c.storages.mu.Lock()
+	defer c.storages.mu.Unlock()
+
+	if c.storages.contents == nil {
+		storages, err := c.api.Storages(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		contents := make(map[string][]string, len(storages))
+		for _, s := range storages {
+			contents[s.Storage] = nil
+			if s.Content != "" {
+				contents[s.Storage] = strings.Split(s.Content, ",")
+			}
+		}
+		c.storages.contents = contents
+	}
+
+	content, found := c.storages.contents[storage]
+	return content, found, nil
+}
+
+// validateStorageContentPlan adds an error for each planned value matched by
+// storages, either a storage or a volume as storage:volume, that is created
+// or changed and whose storage does not hold content, as the API would only
+// reject it during the apply.
+func (c *apiClient) validateStorageContentPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, storages path.Expression, content string) {
+	// Nothing to validate on destroy or before the provider is configured.
+	if c == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	paths, diags := resp.Plan.PathMatches(ctx, storages)
+	resp.Diagnostics.Append(diags...)
+	for _, p := range paths {
+		// Paths into a null object match the object itself.
+		var planned, prior attr.Value
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, p, &planned)...)
+		if !req.State.Raw.IsNull() {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &prior)...)
+		}
+		value, ok := planned.(types.String)
+		if resp.Diagnostics.HasError() || !ok || value.IsNull() || value.IsUnknown() || value.Equal(prior) {
+			continue
+		}
+
+		storage, _, _ := strings.Cut(value.ValueString(), ":")
+		if strings.HasPrefix(storage, "/") {
+			continue
+		}
+		contents, found, err := c.storageContent(ctx, storage)
+		if err != nil {
+			tflog.SubsystemDebug(ctx, logVM, "Skipping storage content check, storages not readable", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		if found && !slices.Contains(contents, content) {
+			resp.Diagnostics.AddAttributeError(
+				p,
+				"Storage Does Not Hold Content Type",
+				fmt.Sprintf("The storage %s does not hold %s content, only %q. Choose another storage or add %s to the content "+
+					"of the storage before using it here.", storage, content, strings.Join(contents, ","), content),
+			)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// taskPollInterval is the interval in which running tasks and guest locks
+// are polled.
+const taskPollInterval = 2 * time.Second
+
+// waitForTask polls the task identified by upid until it has finished or the
+// timeout expired. A task that did not finish successfully is returned
+// together with an error that includes its exit status.
+func (c *apiClient) waitForTask(ctx context.Context, upid proxmox.UPID, timeout time.Duration) (*proxmox.Task, error) {
+	ctx = tflog.SubsystemSetField(ctx, logTask, logFieldUPID, string(upid))
+	tflog.SubsystemDebug(ctx, logTask, "Waiting for task to finish")
+
+	task := proxmox.NewTask(upid, c.Client)
+	if err := task.Wait(ctx, taskPollInterval, timeout); err != nil {
+		return task, fmt.Errorf("waiting for task %s: %w", upid, err)
+	}
+
+	tflog.SubsystemDebug(ctx, logTask, "Task finished", map[string]interface{}{
+		"exit_status": task.ExitStatus,
+	})
+
+	if task.IsFailed {
+		return task, fmt.Errorf("task %s failed: %s", upid, task.ExitStatus)
+	}
+
+	return task, nil
+}
+
+// guestPath returns the API path of a guest, guestType being either "qemu"
+// or "lxc".
+func guestPath(node, guestType string, vmid int64) string {
+	return fmt.Sprintf("/nodes/%s/%s/%d", node, guestType, vmid)
+}
+
+// guestStatus is the subset of the current guest status used by the provider.
+type guestStatus struct {
+	Status string `json:"status"`
+	Lock   string `json:"lock"`
+	Name   string `json:"name"`
+}
+
+// waitForGuestUnlock polls the status of a guest until it no longer holds a
+// lock, e.g. from a running backup or migration, or the timeout expired.
+func (c *apiClient) waitForGuestUnlock(ctx context.Context, node, guestType string, vmid int64, timeout time.Duration) error {
+	ctx = tflog.SubsystemSetField(ctx, logTask, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logTask, logFieldVMID, vmid)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var status guestStatus
+		if err := c.Get(ctx, guestPath(node, guestType, vmid)+"/status/current", &status); err != nil {
+			return err
+		}
+		if status.Lock == "" {
+			return nil
+		}
+
+		tflog.SubsystemDebug(ctx, logTask, "Guest is locked, waiting", map[string]interface{}{
+			"lock": status.Lock,
+		})
+		if time.Now().After(deadline) {
+			return fmt.Errorf("guest %d on node %s is still locked (%s) after %s", vmid, node, status.Lock, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// upidNode returns the node a task identified by upid runs on. UPIDs have
+// the format UPID:node:pid:pstart:starttime:type:id:user:.
+func upidNode(upid string) (string, error) {
+	parts := strings.Split(upid, ":")
+	if len(parts) < 8 || parts[0] != "UPID" {
+		return "", fmt.Errorf("invalid UPID %q", upid)
+	}
+
+	return parts[1], nil
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+var (
+	_ datasource.DataSource                     = &taskWaitDataSource{}
+	_ datasource.DataSourceWithConfigure        = &taskWaitDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &taskWaitDataSource{}
+)
+
+// defaultTaskWaitTimeout is used when no timeout is configured.
+const defaultTaskWaitTimeout = 30 * time.Minute
+
+func NewTaskWaitDataSource() datasource.DataSource {
+	return &taskWaitDataSource{}
+}
+
+type taskWaitDataSource struct {
+	client *apiClient
+}
+
+type taskWaitDataSourceModel struct {
+	UPID       types.String `tfsdk:"upid"`
+	Node       types.String `tfsdk:"node"`
+	VMID       types.Int64  `tfsdk:"vm_id"`
+	GuestType  types.String `tfsdk:"guest_type"`
+	Timeout    types.String `tfsdk:"timeout"`
+	ExitStatus types.String `tfsdk:"exit_status"`
+}
+
+func (d *taskWaitDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *taskWaitDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_task_wait"
+}
+
+func (d *taskWaitDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Blocks until a task or a guest lock started outside of Terraform has finished, " +
+			"so that changes can be sequenced after e.g. a backup window.",
+		Attributes: map[string]schema.Attribute{
+			"upid": schema.StringAttribute{
+				Optional:    true,
+				Description: "UPID of the task to wait for",
+			},
+			"node": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Node of the guest whose lock to wait for. Set to the node of the task when waiting for a UPID",
+			},
+			"vm_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "ID of the guest whose lock to wait for",
+			},
+			"guest_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Type of the guest whose lock to wait for, either qemu (default) or lxc",
+				Validators: []validator.String{
+					stringvalidator.OneOf("qemu", "lxc"),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum time to wait as a Go duration, e.g. 1h30m. Defaults to 30m",
+			},
+			"exit_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Exit status of the task, empty when waiting for a guest lock",
+			},
+		},
+	}
+}
+
+func (d *taskWaitDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("upid"),
+			path.MatchRoot("vm_id"),
+		),
+		datasourcevalidator.Conflicting(
+			path.MatchRoot("upid"),
+			path.MatchRoot("guest_type"),
+		),
+	}
+}
+
+func (d *taskWaitDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state taskWaitDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := defaultTaskWaitTimeout
+	if !state.Timeout.IsNull() {
+		var err error
+		timeout, err = time.ParseDuration(state.Timeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("timeout"),
+				"Invalid Timeout",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	if !state.UPID.IsNull() {
+		node, err := upidNode(state.UPID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("upid"),
+				"Invalid UPID",
+				err.Error(),
+			)
+			return
+		}
+
+		task, err := d.client.waitForTask(ctx, proxmox.UPID(state.UPID.ValueString()), timeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Proxmox Task Did Not Finish Successfully",
+				apiErrorDetail(err),
+			)
+			return
+		}
+
+		state.Node = types.StringValue(node)
+		state.ExitStatus = types.StringValue(task.ExitStatus)
+	} else {
+		if state.Node.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("node"),
+				"Missing Node",
+				"The node attribute is required when waiting for a guest lock.",
+			)
+			return
+		}
+
+		guestType := "qemu"
+		if !state.GuestType.IsNull() {
+			guestType = state.GuestType.ValueString()
+		}
+
+		tflog.SubsystemInfo(ctx, logTask, "Waiting for guest lock to clear")
+		err := d.client.waitForGuestUnlock(ctx, state.Node.ValueString(), guestType, state.VMID.ValueInt64(), timeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Proxmox Guest Lock Did Not Clear",
+				apiErrorDetail(err),
+			)
+			return
+		}
+
+		state.ExitStatus = types.StringValue("")
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ticketResponse is the response of the access ticket endpoint.
+type ticketResponse struct {
+	Data struct {
+		Ticket              string `json:"ticket"`
+		CSRFPreventionToken string `json:"CSRFPreventionToken"`
+		NeedTFA             int    `json:"NeedTFA"`
+	} `json:"data"`
+}
+
+// requestTicket posts the form to the access ticket endpoint of host.
+func requestTicket(ctx context.Context, client *http.Client, host string, form url.Values) (*ticketResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host+"/api2/json/access/ticket", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%d %s: authentication failure", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var ticket ticketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ticket); err != nil {
+		return nil, err
+	}
+
+	return &ticket, nil
+}
+
+// loginWithOTP obtains a session ticket for a user with two-factor
+// authentication, answering the TOTP challenge of the login with otp. Users
+// without a second factor get their ticket right away.
+func loginWithOTP(ctx context.Context, client *http.Client, host, username, password, otp string) (ticket, csrf string, err error) {
+	resp, err := requestTicket(ctx, client, host, url.Values{
+		"username": {username},
+		"password": {password},
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	if resp.Data.NeedTFA == 1 {
+		resp, err = requestTicket(ctx, client, host, url.Values{
+			"username":      {username},
+			"tfa-challenge": {resp.Data.Ticket},
+			"password":      {"totp:" + otp},
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("answering the TOTP challenge: %w", err)
+		}
+	}
+
+	return resp.Data.Ticket, resp.Data.CSRFPreventionToken, nil
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoginWithOTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case r.PostForm.Get("tfa-challenge") == "PVE:!tfa!challenge" && r.PostForm.Get("password") == "totp:123456":
+			fmt.Fprint(w, `{"data":{"ticket":"PVE:root@pam:full","CSRFPreventionToken":"csrf"}}`)
+		case r.PostForm.Get("password") == "secret" && r.PostForm.Get("username") == "root@pam":
+			fmt.Fprint(w, `{"data":{"ticket":"PVE:!tfa!challenge","NeedTFA":1}}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	ticket, csrf, err := loginWithOTP(context.Background(), server.Client(), server.URL, "root@pam", "secret", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket != "PVE:root@pam:full" || csrf != "csrf" {
+		t.Errorf("got ticket %q and token %q", ticket, csrf)
+	}
+
+	_, _, err = loginWithOTP(context.Background(), server.Client(), server.URL, "root@pam", "secret", "000000")
+	if err == nil {
+		t.Error("expected a wrong code to fail")
+	}
+}
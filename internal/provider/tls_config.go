@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// normalizeFingerprint returns a SHA-256 fingerprint as colon-separated upper
+// case hex, accepting lower case and missing colons.
+func normalizeFingerprint(fingerprint string) (string, error) {
+	hex := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+	if len(hex) != 64 || strings.Trim(hex, "0123456789ABCDEF") != "" {
+		return "", fmt.Errorf("%q is not a SHA-256 fingerprint", fingerprint)
+	}
+
+	parts := make([]string, 0, 32)
+	for i := 0; i < len(hex); i += 2 {
+		parts = append(parts, hex[i:i+2])
+	}
+
+	return strings.Join(parts, ":"), nil
+}
+
+// tlsVersions maps the values of tls_min_version onto TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsCipherSuiteNames returns the names of the cipher suites that
+// tls_cipher_suites accepts, the TLS 1.0 to 1.2 suites without known
+// security issues.
+func tlsCipherSuiteNames() []string {
+	var names []string
+	for _, suite := range tls.CipherSuites() {
+		names = append(names, suite.Name)
+	}
+
+	return names
+}
+
+// tlsSettings holds the TLS related provider configuration.
+type tlsSettings struct {
+	insecure     bool
+	caPEM        string
+	fingerprint  string
+	minVersion   string
+	cipherSuites []string
+}
+
+// newTLSConfig returns the TLS configuration of the API client. Certificates
+// are verified against the CA certificates when set, or the system roots
+// otherwise. A fingerprint pins the certificate of the host instead of
+// verifying its chain, which trusts self-signed certificates without skipping
+// the verification altogether.
+func newTLSConfig(settings tlsSettings) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: settings.insecure,
+	}
+
+	if settings.minVersion != "" {
+		version, ok := tlsVersions[settings.minVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q", settings.minVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if len(settings.cipherSuites) > 0 {
+		ids := map[string]uint16{}
+		for _, suite := range tls.CipherSuites() {
+			ids[suite.Name] = suite.ID
+		}
+
+		for _, name := range settings.cipherSuites {
+			id, ok := ids[name]
+			if !ok {
+				return nil, fmt.Errorf("unsupported cipher suite %q", name)
+			}
+			config.CipherSuites = append(config.CipherSuites, id)
+		}
+	}
+
+	if settings.caPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(settings.caPEM)) {
+			return nil, errors.New("no PEM encoded certificate found in the CA certificate")
+		}
+		config.RootCAs = pool
+	}
+
+	if settings.fingerprint != "" {
+		pinned, err := normalizeFingerprint(settings.fingerprint)
+		if err != nil {
+			return nil, err
+		}
+
+		// The chain is not verified, so the pinned fingerprint is checked
+		// on every handshake instead.
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("no certificate presented by the host")
+			}
+
+			cert, err := x509.ParseCertificate(rawCerts[0])
+			if err != nil {
+				return err
+			}
+
+			if got := certFingerprint(cert); got != pinned {
+				return fmt.Errorf("certificate fingerprint %s does not match the pinned ssl_fingerprint %s", got, pinned)
+			}
+
+			return nil
+		}
+	}
+
+	return config, nil
+}
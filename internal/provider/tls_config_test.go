@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeFingerprint(t *testing.T) {
+	hex := strings.Repeat("ab", 32)
+	got, err := normalizeFingerprint(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.TrimSuffix(strings.Repeat("AB:", 32), ":"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := normalizeFingerprint("AB:CD"); err == nil {
+		t.Error("expected a short fingerprint to fail")
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	cert := server.Certificate()
+	get := func(caPEM, fingerprint string) error {
+		config, err := newTLSConfig(tlsSettings{caPEM: caPEM, fingerprint: fingerprint})
+		if err != nil {
+			return err
+		}
+		client := http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get("", ""); err == nil {
+		t.Error("expected the self-signed certificate to be rejected")
+	}
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	if err := get(caPEM, ""); err != nil {
+		t.Errorf("CA certificate: %v", err)
+	}
+
+	if err := get("", certFingerprint(cert)); err != nil {
+		t.Errorf("pinned fingerprint: %v", err)
+	}
+
+	if err := get("", strings.Repeat("00", 32)); err == nil {
+		t.Error("expected a different fingerprint to be rejected")
+	}
+
+	if _, err := newTLSConfig(tlsSettings{caPEM: "not a certificate"}); err == nil {
+		t.Error("expected an invalid CA certificate to fail")
+	}
+}
+
+func TestNewTLSConfigVersions(t *testing.T) {
+	config, err := newTLSConfig(tlsSettings{
+		minVersion:   "1.2",
+		cipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.MinVersion != tls.VersionTLS12 || len(config.CipherSuites) != 1 || config.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 {
+		t.Errorf("got min version %x and cipher suites %v", config.MinVersion, config.CipherSuites)
+	}
+
+	if _, err := newTLSConfig(tlsSettings{cipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}); err == nil {
+		t.Error("expected an insecure cipher suite to fail")
+	}
+}
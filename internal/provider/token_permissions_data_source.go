@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+var (
+	_ datasource.DataSource              = &tokenPermissionsDataSource{}
+	_ datasource.DataSourceWithConfigure = &tokenPermissionsDataSource{}
+)
+
+func NewTokenPermissionsDataSource() datasource.DataSource {
+	return &tokenPermissionsDataSource{}
+}
+
+type tokenPermissionsDataSource struct {
+	client *apiClient
+}
+
+type tokenPermissionsDataSourceModel struct {
+	TokenID     types.String           `tfsdk:"token_id"`
+	Required    types.Map              `tfsdk:"required_privileges"`
+	Permissions []tokenPermissionModel `tfsdk:"permissions"`
+	Missing     types.List             `tfsdk:"missing"`
+	Satisfied   types.Bool             `tfsdk:"satisfied"`
+}
+
+type tokenPermissionModel struct {
+	Path       types.String `tfsdk:"path"`
+	Privileges types.Set    `tfsdk:"privileges"`
+}
+
+func (d *tokenPermissionsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *tokenPermissionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_token_permissions"
+}
+
+func (d *tokenPermissionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the effective privileges of a user or API token per ACL path and checks them against a list " +
+			"of required privileges, e.g. to verify the credential Terraform itself runs with.",
+		Attributes: map[string]schema.Attribute{
+			"token_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "User or API token to audit, e.g. terraform@pve!provider. Defaults to the user the provider authenticates as",
+			},
+			"required_privileges": schema.MapAttribute{
+				ElementType: types.ListType{ElemType: types.StringType},
+				Optional:    true,
+				Description: "Privileges that are required, keyed by ACL path, e.g. { \"/vms\" = [\"VM.Allocate\"] }",
+			},
+			"permissions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Effective privileges per ACL path",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							Computed: true,
+						},
+						"privileges": schema.SetAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"missing": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Required privileges that are not granted, as path:privilege",
+			},
+			"satisfied": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether all required privileges are granted",
+			},
+		},
+	}
+}
+
+// grantedPrivileges returns the privileges that are granted in a permission
+// entry, sorted by name.
+func grantedPrivileges(permission proxmox.Permission) []string {
+	var privileges []string
+	for privilege, granted := range permission {
+		if granted {
+			privileges = append(privileges, privilege)
+		}
+	}
+	sort.Strings(privileges)
+
+	return privileges
+}
+
+// missingPrivileges returns the required privileges that are not granted by
+// the effective permissions of their path, as path:privilege.
+func missingPrivileges(required map[string][]string, effective map[string]proxmox.Permission) []string {
+	var missing []string
+	for path, privileges := range required {
+		for _, privilege := range privileges {
+			if !effective[path][privilege] {
+				missing = append(missing, fmt.Sprintf("%s:%s", path, privilege))
+			}
+		}
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+func (d *tokenPermissionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state tokenPermissionsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tokenID := d.client.username
+	if !state.TokenID.IsNull() {
+		tokenID = state.TokenID.ValueString()
+	}
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "userid", tokenID)
+
+	tflog.SubsystemDebug(ctx, logAPI, "Reading effective permissions")
+	permissions, err := d.client.Permissions(ctx, &proxmox.PermissionsOptions{UserID: tokenID})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Permissions",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	paths := make([]string, 0, len(permissions))
+	for path := range permissions {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	state.Permissions = []tokenPermissionModel{}
+	for _, path := range paths {
+		privileges, diags := types.SetValueFrom(ctx, types.StringType, grantedPrivileges(permissions[path]))
+		resp.Diagnostics.Append(diags...)
+		state.Permissions = append(state.Permissions, tokenPermissionModel{
+			Path:       types.StringValue(path),
+			Privileges: privileges,
+		})
+	}
+
+	required := map[string][]string{}
+	if !state.Required.IsNull() {
+		resp.Diagnostics.Append(state.Required.ElementsAs(ctx, &required, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Privileges are inherited by child paths, so the effective permissions
+	// of a required path are queried for it specifically.
+	effective := map[string]proxmox.Permission{}
+	for path := range required {
+		pathPermissions, err := d.client.Permissions(ctx, &proxmox.PermissionsOptions{UserID: tokenID, Path: path})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Proxmox Permissions",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		effective[path] = pathPermissions[path]
+	}
+
+	missing := missingPrivileges(required, effective)
+	missingValue, diags := types.ListValueFrom(ctx, types.StringType, missing)
+	resp.Diagnostics.Append(diags...)
+	state.Missing = missingValue
+	state.Satisfied = types.BoolValue(len(missing) == 0)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/luthermonson/go-proxmox"
+)
+
+func TestMissingPrivileges(t *testing.T) {
+	required := map[string][]string{
+		"/vms":    {"VM.Allocate", "VM.Config.Disk"},
+		"/sdn":    {"SDN.Allocate"},
+		"/access": {"User.Modify"},
+	}
+	effective := map[string]proxmox.Permission{
+		"/vms": {"VM.Allocate": true, "VM.Config.Disk": false},
+		"/sdn": {"SDN.Allocate": true},
+	}
+
+	got := missingPrivileges(required, effective)
+	want := []string{"/access:User.Modify", "/vms:VM.Config.Disk"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
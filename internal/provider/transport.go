@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitedTransport delays outgoing requests so that no more than the
+// configured number of requests per second is sent to the Proxmox VE API.
+// pveproxy answers bursts above its own limits with 596 errors, which large
+// refreshes on small clusters easily trigger.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+// newRateLimitedTransport wraps base with a token bucket limiter allowing
+// requestsPerSecond requests on average and up to burst requests at once.
+func newRateLimitedTransport(base http.RoundTripper, requestsPerSecond float64, burst int) *rateLimitedTransport {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimitedTransport{
+		base:    base,
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+	}
+}
+
+// RoundTrip waits for the limiter before passing the request on.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// concurrencyLimitedTransport bounds the number of requests in flight, so
+// that applies with many guests do not overload pvedaemon. A request holds
+// its slot until its response body is closed.
+type concurrencyLimitedTransport struct {
+	base  http.RoundTripper
+	slots chan struct{}
+}
+
+// newConcurrencyLimitedTransport wraps base allowing up to limit requests at
+// once.
+func newConcurrencyLimitedTransport(base http.RoundTripper, limit int) *concurrencyLimitedTransport {
+	return &concurrencyLimitedTransport{
+		base:  base,
+		slots: make(chan struct{}, limit),
+	}
+}
+
+// RoundTrip waits for a free slot before passing the request on.
+func (t *concurrencyLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		<-t.slots
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() { <-t.slots }}
+	return resp, nil
+}
+
+// releasingBody calls release once when the body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close closes the body and releases its slot.
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// readOnlyTransport refuses every request that could modify the cluster, so
+// that plans can safely run with production credentials. Logging in is the
+// only non-GET request that is let through.
+type readOnlyTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip rejects all requests other than GET and the ticket request.
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && !strings.HasSuffix(req.URL.Path, "/access/ticket") {
+		return nil, fmt.Errorf("read_only mode: refusing to send %s %s", req.Method, req.URL.Path)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// retryTransport retries requests that failed with a transient network error,
+// were rate limited or failed with a gateway error of pveproxy, waiting wait before the first retry and
+// twice as long before every further one. pveproxy reports regular API errors
+// as 500, so those are passed on as is. Requests that may create objects are
+// only retried when they could not be sent at all.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	wait       time.Duration
+}
+
+// retryableStatus reports whether status is a gateway error of pveproxy,
+// including 596 for a failed connection to another node of the cluster.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout, 596:
+		return true
+	}
+
+	return false
+}
+
+// idempotentMethod reports whether a request with method may be sent twice.
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// shouldRetry reports whether req is retried after it returned resp or err.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if err == nil {
+		// Rate limited requests were not processed and may be sent again.
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+		return idempotentMethod(req.Method) && retryableStatus(resp.StatusCode)
+	}
+
+	if dialError(err) {
+		return true
+	}
+
+	if !idempotentMethod(req.Method) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// dialError reports whether err means that no connection could be opened, so
+// that the request was not sent.
+func dialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// RoundTrip sends req and retries it up to maxRetries times.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	wait := t.wait
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt == t.maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		// A request body can only be sent again when it can be recreated.
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// failoverTransport sends requests to one of several API endpoints of the
+// cluster. When the current endpoint cannot be reached, the request is sent
+// to the next one, which then serves all further requests. Tickets are valid
+// on every node, so the session carries over.
+type failoverTransport struct {
+	base      http.RoundTripper
+	endpoints []*url.URL
+
+	mu      sync.Mutex
+	current int
+}
+
+// active returns the index of the endpoint requests are sent to.
+func (t *failoverTransport) active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+// activate makes the endpoint at index serve further requests.
+func (t *failoverTransport) activate(index int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = index
+}
+
+// RoundTrip sends req to the active endpoint and fails over to the others
+// in order when it cannot be reached.
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.active()
+
+	var lastErr error
+	for i := range t.endpoints {
+		index := (start + i) % len(t.endpoints)
+
+		attempt := req.Clone(req.Context())
+		attempt.URL.Scheme = t.endpoints[index].Scheme
+		attempt.URL.Host = t.endpoints[index].Host
+		attempt.Host = ""
+
+		if i > 0 && req.Body != nil {
+			// A request body can only be sent again when it can be recreated.
+			if req.GetBody == nil {
+				return nil, lastErr
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attempt)
+		if err == nil {
+			if index != start {
+				t.activate(index)
+			}
+			return resp, nil
+		}
+		if !dialError(err) || req.Context().Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("no API endpoint reachable: %w", lastErr)
+}
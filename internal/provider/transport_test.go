@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadOnlyTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &readOnlyTransport{base: http.DefaultTransport}}
+
+	cases := map[string]struct {
+		method  string
+		path    string
+		allowed bool
+	}{
+		"get":    {method: http.MethodGet, path: "/api2/json/nodes", allowed: true},
+		"ticket": {method: http.MethodPost, path: "/api2/json/access/ticket", allowed: true},
+		"post":   {method: http.MethodPost, path: "/api2/json/cluster/sdn/vnets", allowed: false},
+		"delete": {method: http.MethodDelete, path: "/api2/json/cluster/sdn/vnets/a", allowed: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, server.URL+tc.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := client.Do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if tc.allowed && err != nil {
+				t.Errorf("expected request to be allowed, got %s", err)
+			}
+			if !tc.allowed && err == nil {
+				t.Errorf("expected request to be refused")
+			}
+		})
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.Method+" "+r.URL.Path]++
+		switch r.URL.Path {
+		case "/flaky":
+			if attempts[r.Method+" "+r.URL.Path] < 3 {
+				w.WriteHeader(596)
+				return
+			}
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, maxRetries: 3, wait: time.Millisecond}}
+
+	cases := map[string]struct {
+		method   string
+		path     string
+		status   int
+		attempts int
+	}{
+		"flaky get":  {method: http.MethodGet, path: "/flaky", status: http.StatusOK, attempts: 3},
+		"flaky post": {method: http.MethodPost, path: "/flaky", status: 596, attempts: 1},
+		"api error":  {method: http.MethodGet, path: "/error", status: http.StatusInternalServerError, attempts: 1},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, server.URL+tc.path, strings.NewReader("vmid=100"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.status || attempts[tc.method+" "+tc.path] != tc.attempts {
+				t.Errorf("got %d after %d attempts, want %d after %d", resp.StatusCode, attempts[tc.method+" "+tc.path], tc.status, tc.attempts)
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimitedTransport(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newConcurrencyLimitedTransport(http.DefaultTransport, 2)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("got %d requests in flight, want at most 2", maxInFlight)
+	}
+}
+
+func TestFailoverTransport(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	downURL, _ := url.Parse(down.URL)
+	down.Close()
+
+	var hits int
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+	upURL, _ := url.Parse(up.URL)
+
+	transport := &failoverTransport{base: http.DefaultTransport, endpoints: []*url.URL{downURL, upURL}}
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Post(down.URL+"/api2/json/nodes", "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("request %d: %s", i, err)
+		}
+		resp.Body.Close()
+	}
+	if hits != 2 || transport.active() != 1 {
+		t.Errorf("got %d hits on endpoint %d, want 2 on endpoint 1", hits, transport.active())
+	}
+
+	transport = &failoverTransport{base: http.DefaultTransport, endpoints: []*url.URL{downURL}}
+	if _, err := (&http.Client{Transport: transport}).Get(down.URL); err == nil {
+		t.Error("expected an error without reachable endpoint")
+	}
+}
@@ -0,0 +1,338 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &userResource{}
+	_ resource.ResourceWithConfigure      = &userResource{}
+	_ resource.ResourceWithValidateConfig = &userResource{}
+)
+
+// The length limits Proxmox VE enforces on the passwords of pve realm users.
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 64
+)
+
+// NewUserResource is a helper function to simplify the provider implementation.
+func NewUserResource() resource.Resource {
+	return &userResource{}
+}
+
+// userResource manages a Proxmox VE user.
+type userResource struct {
+	client *apiClient
+}
+
+// userResourceModel maps the resource schema data.
+type userResourceModel struct {
+	UserID    types.String `tfsdk:"user_id"`
+	Password  types.String `tfsdk:"password"`
+	Comment   types.String `tfsdk:"comment"`
+	Email     types.String `tfsdk:"email"`
+	FirstName types.String `tfsdk:"first_name"`
+	LastName  types.String `tfsdk:"last_name"`
+	Enable    types.Bool   `tfsdk:"enable"`
+	Expire    types.Int64  `tfsdk:"expire"`
+}
+
+// accessUserConfig is the response of the access user endpoint.
+type accessUserConfig struct {
+	Comment   string `json:"comment"`
+	Email     string `json:"email"`
+	FirstName string `json:"firstname"`
+	LastName  string `json:"lastname"`
+	Enable    int    `json:"enable"`
+	Expire    int64  `json:"expire"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *userResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+// Schema defines the schema for the resource.
+func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a user of Proxmox VE. Passwords are only stored for users of the pve realm and are checked " +
+			"against the password policy of Proxmox VE at plan time. Proxmox VE cannot force a password change on the " +
+			"next login, use expire to limit accounts with initial passwords instead. Manage the groups of the user " +
+			"with proxmox_group_membership.",
+		Attributes: map[string]schema.Attribute{
+			"user_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the user as name@realm, e.g. alice@pve",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: fmt.Sprintf("Password of a pve realm user, %d to %d characters long. Changing it sets a new "+
+					"password, a password changed outside of Terraform is not detected", minPasswordLength, maxPasswordLength),
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment of the user",
+			},
+			"email": schema.StringAttribute{
+				Optional:    true,
+				Description: "Email address of the user",
+			},
+			"first_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "First name of the user",
+			},
+			"last_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Last name of the user",
+			},
+			"enable": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Allow the user to log in. Defaults to true",
+			},
+			"expire": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+				Description: "Unix time the account expires at, 0 for never. Defaults to 0",
+			},
+		},
+	}
+}
+
+// passwordPolicyViolation returns why Proxmox VE would reject password for
+// userID, or an empty string when it is accepted.
+func passwordPolicyViolation(userID, password string) string {
+	if _, realm, _ := strings.Cut(userID, "@"); realm != "pve" {
+		return fmt.Sprintf("Proxmox VE only stores passwords of pve realm users, not of users of the %s realm. "+
+			"Set the password in the realm itself, e.g. on the nodes for the pam realm.", realm)
+	}
+	if length := len([]rune(password)); length < minPasswordLength || length > maxPasswordLength {
+		return fmt.Sprintf("The password has %d characters, Proxmox VE requires %d to %d.", length, minPasswordLength, maxPasswordLength)
+	}
+
+	return ""
+}
+
+// ValidateConfig rejects passwords Proxmox VE would refuse when the user is
+// created or the password is changed.
+func (r *userResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config userResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.UserID.IsUnknown() || config.Password.IsNull() || config.Password.IsUnknown() {
+		return
+	}
+	if violation := passwordPolicyViolation(config.UserID.ValueString(), config.Password.ValueString()); violation != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Password Rejected by Proxmox VE",
+			violation,
+		)
+	}
+}
+
+// userParams returns the parameters of the user of model. Unset strings are
+// sent empty, which clears them.
+func userParams(model userResourceModel) map[string]interface{} {
+	return map[string]interface{}{
+		"comment":   model.Comment.ValueString(),
+		"email":     model.Email.ValueString(),
+		"firstname": model.FirstName.ValueString(),
+		"lastname":  model.LastName.ValueString(),
+		"enable":    proxmoxtf.BoolToInt(model.Enable.ValueBool()),
+		"expire":    model.Expire.ValueInt64(),
+	}
+}
+
+// setPassword sets the password of a user.
+func (r *userResource) setPassword(ctx context.Context, userID, password string) error {
+	tflog.SubsystemInfo(ctx, logAPI, "Setting user password")
+	params := map[string]interface{}{
+		"userid":   userID,
+		"password": password,
+	}
+	return r.client.Put(ctx, "/access/password", params, nil)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan userResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "user", plan.UserID.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Creating user")
+
+	params := userParams(plan)
+	params["userid"] = plan.UserID.ValueString()
+	if !plan.Password.IsNull() {
+		params["password"] = plan.Password.ValueString()
+	}
+	err := r.client.Post(ctx, "/access/users", params, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox User",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state userResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var user accessUserConfig
+	err := r.client.Get(ctx, userPath(state.UserID.ValueString()), &user)
+	if err != nil {
+		// The user was removed outside of Terraform.
+		if strings.Contains(strings.ToLower(err.Error()), "no such user") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox User",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// The password cannot be read back and is kept from state.
+	state.Comment = proxmoxtf.StringOrNull(user.Comment)
+	state.Email = proxmoxtf.StringOrNull(user.Email)
+	state.FirstName = proxmoxtf.StringOrNull(user.FirstName)
+	state.LastName = proxmoxtf.StringOrNull(user.LastName)
+	state.Enable = proxmoxtf.Bool(user.Enable)
+	state.Expire = types.Int64Value(user.Expire)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan, state userResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userID := plan.UserID.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "user", userID)
+	tflog.SubsystemInfo(ctx, logAPI, "Updating user")
+
+	err := r.client.Put(ctx, userPath(userID), userParams(plan), nil)
+	// A removed password stays set, as users cannot be left without one.
+	if err == nil && !plan.Password.IsNull() && !plan.Password.Equal(state.Password) {
+		err = r.setPassword(ctx, userID, plan.Password.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox User",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state userResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "user", state.UserID.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Deleting user")
+
+	err := r.client.Delete(ctx, userPath(state.UserID.ValueString()), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox User",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
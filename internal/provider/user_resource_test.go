@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyViolation(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		password string
+		contains string
+	}{
+		{name: "accepted", userID: "alice@pve", password: "correct horse"},
+		{name: "minimum length", userID: "alice@pve", password: "12345678"},
+		{name: "multibyte characters", userID: "alice@pve", password: "pässwörd"},
+		{name: "too short", userID: "alice@pve", password: "1234567", contains: "has 7 characters"},
+		{name: "too long", userID: "alice@pve", password: strings.Repeat("a", 65), contains: "has 65 characters"},
+		{name: "pam realm", userID: "root@pam", password: "correct horse", contains: "pam realm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := passwordPolicyViolation(tt.userID, tt.password)
+			if tt.contains == "" {
+				if got != "" {
+					t.Errorf("got %q, want none", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.contains) {
+				t.Errorf("got %q, want it to contain %q", got, tt.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &userTfaResource{}
+	_ resource.ResourceWithConfigure = &userTfaResource{}
+)
+
+// totpPeriod is the time step of the TOTP codes accepted by Proxmox VE.
+const totpPeriod = 30 * time.Second
+
+// NewUserTfaResource is a helper function to simplify the provider implementation.
+func NewUserTfaResource() resource.Resource {
+	return &userTfaResource{}
+}
+
+// userTfaResource manages a second factor of a Proxmox VE user.
+type userTfaResource struct {
+	client *apiClient
+}
+
+// userTfaResourceModel maps the resource schema data.
+type userTfaResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	UserID       types.String `tfsdk:"user_id"`
+	Type         types.String `tfsdk:"type"`
+	Description  types.String `tfsdk:"description"`
+	Issuer       types.String `tfsdk:"issuer"`
+	Enable       types.Bool   `tfsdk:"enable"`
+	OtpauthURL   types.String `tfsdk:"otpauth_url"`
+	RecoveryKeys types.List   `tfsdk:"recovery_keys"`
+}
+
+// tfaEntry is an entry of the access tfa endpoint.
+type tfaEntry struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Enable      *int   `json:"enable"`
+}
+
+// tfaCreated is the response of the access tfa create endpoint.
+type tfaCreated struct {
+	ID       string   `json:"id"`
+	Recovery []string `json:"recovery"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *userTfaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *userTfaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_tfa"
+}
+
+// Schema defines the schema for the resource.
+func (r *userTfaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Provisions a TOTP second factor or a set of recovery keys for a user. " +
+			"The TOTP secret is generated by the provider and exported as otpauth URL to hand to the user.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the second factor",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_id": schema.StringAttribute{
+				Required:    true,
+				Description: "User the second factor belongs to, e.g. alice@pve",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Type of the second factor, either totp or recovery",
+				Validators: []validator.String{
+					stringvalidator.OneOf("totp", "recovery"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Description of the second factor",
+			},
+			"issuer": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("Proxmox VE"),
+				Description: "Issuer shown by authenticator apps for a TOTP factor. Defaults to Proxmox VE",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"enable": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether the second factor can be used to log in",
+			},
+			"otpauth_url": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "otpauth:// URL of a TOTP factor, to be imported into an authenticator app",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"recovery_keys": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Recovery keys of a recovery factor. They are only returned once, on creation",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// tfaPath returns the API path of the second factors of a user.
+func tfaPath(userID string) string {
+	return fmt.Sprintf("/access/tfa/%s", url.PathEscape(userID))
+}
+
+// newTotpSecret returns a random TOTP secret in unpadded base32.
+func newTotpSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+// totpCode returns the 6 digit RFC 6238 code of a base32 secret at time t.
+func totpCode(secret string, t time.Time) (string, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(totpPeriod/time.Second)))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", code%1000000), nil
+}
+
+// otpauthURL returns the key URI of a TOTP secret understood by authenticator apps.
+func otpauthURL(issuer, userID, secret string) string {
+	label := url.PathEscape(issuer + ":" + userID)
+	query := url.Values{
+		"secret": {secret},
+		"issuer": {issuer},
+	}
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *userTfaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan userTfaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := map[string]interface{}{
+		"type": plan.Type.ValueString(),
+	}
+	if !plan.Description.IsNull() {
+		params["description"] = plan.Description.ValueString()
+	}
+
+	plan.OtpauthURL = types.StringValue("")
+	if plan.Type.ValueString() == "totp" {
+		secret, err := newTotpSecret()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Generate TOTP Secret",
+				err.Error(),
+			)
+			return
+		}
+
+		// Proxmox VE only accepts a TOTP factor together with a valid code
+		// of its secret.
+		code, err := totpCode(secret, time.Now())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Generate TOTP Code",
+				err.Error(),
+			)
+			return
+		}
+
+		plan.OtpauthURL = types.StringValue(otpauthURL(plan.Issuer.ValueString(), plan.UserID.ValueString(), secret))
+		params["totp"] = plan.OtpauthURL.ValueString()
+		params["value"] = code
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "userid", plan.UserID.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Creating second factor", map[string]interface{}{
+		"type": plan.Type.ValueString(),
+	})
+
+	var created tfaCreated
+	err := r.client.Post(ctx, tfaPath(plan.UserID.ValueString()), params, &created)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox User Second Factor",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(created.ID)
+	recoveryKeys, diags := types.ListValueFrom(ctx, types.StringType, created.Recovery)
+	resp.Diagnostics.Append(diags...)
+	plan.RecoveryKeys = recoveryKeys
+
+	// New factors are enabled, only disabling requires an update.
+	if !plan.Enable.ValueBool() {
+		err = r.client.Put(ctx, tfaPath(plan.UserID.ValueString())+"/"+created.ID, map[string]interface{}{
+			"enable": 0,
+		}, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Update Proxmox User Second Factor",
+				apiErrorDetail(err),
+			)
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *userTfaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state userTfaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var entry tfaEntry
+	err := r.client.Get(ctx, tfaPath(state.UserID.ValueString())+"/"+state.ID.ValueString(), &entry)
+	if err != nil {
+		// The factor was removed outside of Terraform, create it again.
+		if strings.Contains(strings.ToLower(err.Error()), "no such") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox User Second Factor",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Description = proxmoxtf.StringOrNull(entry.Description)
+	state.Enable = types.BoolValue(entry.Enable == nil || *entry.Enable == 1)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *userTfaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan userTfaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := map[string]interface{}{
+		"description": plan.Description.ValueString(),
+		"enable":      proxmoxtf.BoolToInt(plan.Enable.ValueBool()),
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "userid", plan.UserID.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Updating second factor")
+	err := r.client.Put(ctx, tfaPath(plan.UserID.ValueString())+"/"+plan.ID.ValueString(), params, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox User Second Factor",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *userTfaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state userTfaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logAPI, "userid", state.UserID.ValueString())
+	tflog.SubsystemInfo(ctx, logAPI, "Deleting second factor")
+	err := r.client.Delete(ctx, tfaPath(state.UserID.ValueString())+"/"+state.ID.ValueString(), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox User Second Factor",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+func TestTotpCode(t *testing.T) {
+	// Test vectors of RFC 6238 for SHA-1, truncated to 6 digits.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, want := range cases {
+		got, err := totpCode(secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("time %d: got %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestOtpauthURL(t *testing.T) {
+	got := otpauthURL("Proxmox VE", "alice@pve", "JBSWY3DPEHPK3PXP")
+	want := "otpauth://totp/Proxmox%20VE:alice@pve?issuer=Proxmox+VE&secret=JBSWY3DPEHPK3PXP"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
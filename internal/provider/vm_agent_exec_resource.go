@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &vmAgentExecResource{}
+	_ resource.ResourceWithConfigure  = &vmAgentExecResource{}
+	_ resource.ResourceWithModifyPlan = &vmAgentExecResource{}
+)
+
+// NewVmAgentExecResource is a helper function to simplify the provider implementation.
+func NewVmAgentExecResource() resource.Resource {
+	return &vmAgentExecResource{}
+}
+
+// vmAgentExecResource runs provisioning commands in a VM through the QEMU
+// guest agent.
+type vmAgentExecResource struct {
+	client *apiClient
+}
+
+// vmAgentExecResourceModel maps the resource schema data.
+type vmAgentExecResourceModel struct {
+	Node      types.String `tfsdk:"node"`
+	VMID      types.Int64  `tfsdk:"vm_id"`
+	Commands  types.List   `tfsdk:"commands"`
+	Retries   types.Int64  `tfsdk:"retries"`
+	Timeout   types.String `tfsdk:"timeout"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	ExitCodes types.List   `tfsdk:"exit_codes"`
+}
+
+// agentExec is the response of the agent exec endpoint.
+type agentExec struct {
+	PID int64 `json:"pid"`
+}
+
+// agentExecStatus is the response of the agent exec-status endpoint.
+type agentExecStatus struct {
+	Exited   int    `json:"exited"`
+	ExitCode int64  `json:"exitcode"`
+	OutData  string `json:"out-data"`
+	ErrData  string `json:"err-data"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmAgentExecResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmAgentExecResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_agent_exec"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmAgentExecResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Waits for the QEMU guest agent of a VM and runs provisioning commands through it, " +
+			"a lightweight alternative to remote-exec for agent-enabled images. " +
+			"The commands run again whenever they or the triggers change.",
+		DeprecationMessage: "Use the provisioning attribute of proxmox_vm instead, which runs the commands once the VM is " +
+			"created and started and again when they or their triggers change.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the VM runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the VM",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"commands": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "Shell commands run in order with /bin/sh -c",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"retries": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(3),
+				Description: "Number of times a failing command is retried. Defaults to 3",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("5m"),
+				Description: "Maximum time to wait for the agent and for each command as a Go duration. Defaults to 5m",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that run the commands again when changed",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"exit_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Computed:    true,
+				Description: "Exit code of each command",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// waitForAgent pings the guest agent of a VM until it responds or the timeout
+// expired.
+func (c *apiClient) waitForAgent(ctx context.Context, node string, vmid int64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := c.Post(ctx, agentPath(node, vmid, "ping"), nil, nil)
+		if err == nil {
+			return nil
+		}
+
+		tflog.SubsystemDebug(ctx, logVM, "Guest agent is not responding, waiting", map[string]interface{}{
+			"error": err.Error(),
+		})
+		if time.Now().After(deadline) {
+			return fmt.Errorf("guest agent of VM %d on node %s did not respond after %s: %w", vmid, node, timeout, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// agentExec runs command in a VM through the guest agent and waits for it to
+// exit or the timeout to expire.
+func (c *apiClient) agentExec(ctx context.Context, node string, vmid int64, command string, timeout time.Duration) (*agentExecStatus, error) {
+	var exec agentExec
+	params := map[string]interface{}{
+		"command": []string{"/bin/sh", "-c", command},
+	}
+	if err := c.Post(ctx, agentPath(node, vmid, "exec"), params, &exec); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var status agentExecStatus
+		err := c.Get(ctx, fmt.Sprintf("%s?pid=%d", agentPath(node, vmid, "exec-status"), exec.PID), &status)
+		if err != nil {
+			return nil, err
+		}
+		if status.Exited == 1 {
+			return &status, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("command %q did not exit after %s", command, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// runAgentCommands waits for the agent of a VM and runs commands in order,
+// retrying failing ones, and returns their exit codes.
+func (c *apiClient) runAgentCommands(ctx context.Context, node string, vmid int64, commands types.List, retries int64, timeout string) (types.List, error) {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return types.ListNull(types.Int64Type), fmt.Errorf("invalid timeout: %w", err)
+	}
+
+	var values []string
+	if diags := commands.ElementsAs(ctx, &values, false); diags.HasError() {
+		return types.ListNull(types.Int64Type), fmt.Errorf("unable to read commands from plan")
+	}
+
+	tflog.SubsystemInfo(ctx, logVM, "Waiting for guest agent")
+	if err := c.waitForAgent(ctx, node, vmid, duration); err != nil {
+		return types.ListNull(types.Int64Type), err
+	}
+
+	exitCodes := make([]int64, 0, len(values))
+	for _, command := range values {
+		var status *agentExecStatus
+		for attempt := int64(0); attempt <= retries; attempt++ {
+			tflog.SubsystemInfo(ctx, logVM, "Running command through guest agent", map[string]interface{}{
+				"command": command,
+				"attempt": attempt + 1,
+			})
+			status, err = c.agentExec(ctx, node, vmid, command, duration)
+			if err == nil && status.ExitCode == 0 {
+				break
+			}
+		}
+		if err != nil {
+			return types.ListNull(types.Int64Type), err
+		}
+		if status.ExitCode != 0 {
+			return types.ListNull(types.Int64Type), fmt.Errorf("command %q exited with code %d: %s", command, status.ExitCode, status.ErrData)
+		}
+
+		exitCodes = append(exitCodes, status.ExitCode)
+	}
+
+	exitCodesValue, diags := types.ListValueFrom(ctx, types.Int64Type, exitCodes)
+	if diags.HasError() {
+		return types.ListNull(types.Int64Type), fmt.Errorf("unable to map exit codes")
+	}
+
+	return exitCodesValue, nil
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider.
+func (r *vmAgentExecResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmAgentExecResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmAgentExecResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := time.ParseDuration(plan.Timeout.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			err.Error(),
+		)
+		return
+	}
+
+	exitCodes, err := r.client.runAgentCommands(ctx, plan.Node.ValueString(), plan.VMID.ValueInt64(), plan.Commands,
+		plan.Retries.ValueInt64(), plan.Timeout.ValueString())
+	plan.ExitCodes = exitCodes
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Run Commands Through Proxmox Guest Agent",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmAgentExecResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The commands only run on create, there is no remote state to refresh.
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmAgentExecResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only retries and timeout can change in place, they take effect the next
+	// time the commands run.
+	var plan vmAgentExecResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmAgentExecResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The effects of the commands are left in the guest.
+}
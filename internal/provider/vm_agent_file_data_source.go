@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+var (
+	_ datasource.DataSource              = &vmAgentFileDataSource{}
+	_ datasource.DataSourceWithConfigure = &vmAgentFileDataSource{}
+)
+
+func NewVmAgentFileDataSource() datasource.DataSource {
+	return &vmAgentFileDataSource{}
+}
+
+type vmAgentFileDataSource struct {
+	client *apiClient
+}
+
+type vmAgentFileDataSourceModel struct {
+	Node      types.String `tfsdk:"node"`
+	VMID      types.Int64  `tfsdk:"vm_id"`
+	Path      types.String `tfsdk:"path"`
+	Content   types.String `tfsdk:"content"`
+	Truncated types.Bool   `tfsdk:"truncated"`
+}
+
+func (d *vmAgentFileDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *vmAgentFileDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_agent_file"
+}
+
+func (d *vmAgentFileDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads a file from a VM through the QEMU guest agent.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required: true,
+			},
+			"vm_id": schema.Int64Attribute{
+				Required: true,
+			},
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "Absolute path of the file in the guest",
+			},
+			"content": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+			"truncated": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the agent truncated the content because the file is too large",
+			},
+		},
+	}
+}
+
+func (d *vmAgentFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state vmAgentFileDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, state.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, state.VMID.ValueInt64())
+	tflog.SubsystemDebug(ctx, logVM, "Reading file through guest agent", map[string]interface{}{
+		"path": state.Path.ValueString(),
+	})
+
+	file, err := d.client.readAgentFile(ctx, state.Node.ValueString(), state.VMID.ValueInt64(), state.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read File Through Proxmox Guest Agent",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Content = types.StringValue(file.Content)
+	state.Truncated = proxmoxtf.Bool(file.Truncated)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
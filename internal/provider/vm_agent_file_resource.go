@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &vmAgentFileResource{}
+	_ resource.ResourceWithConfigure  = &vmAgentFileResource{}
+	_ resource.ResourceWithModifyPlan = &vmAgentFileResource{}
+)
+
+// NewVmAgentFileResource is a helper function to simplify the provider implementation.
+func NewVmAgentFileResource() resource.Resource {
+	return &vmAgentFileResource{}
+}
+
+// vmAgentFileResource is the resource implementation.
+type vmAgentFileResource struct {
+	client *apiClient
+}
+
+// vmAgentFileResourceModel maps the resource schema data.
+type vmAgentFileResourceModel struct {
+	Node    types.String `tfsdk:"node"`
+	VMID    types.Int64  `tfsdk:"vm_id"`
+	Path    types.String `tfsdk:"path"`
+	Content types.String `tfsdk:"content"`
+}
+
+// agentFile is the response of the agent file-read endpoint.
+type agentFile struct {
+	Content   string `json:"content"`
+	Truncated int    `json:"truncated"`
+}
+
+// agentPath returns the API path of an agent command of a VM.
+func agentPath(node string, vmid int64, command string) string {
+	return fmt.Sprintf("%s/agent/%s", guestPath(node, "qemu", vmid), command)
+}
+
+// readAgentFile reads a file from a VM through the QEMU guest agent.
+func (c *apiClient) readAgentFile(ctx context.Context, node string, vmid int64, path string) (*agentFile, error) {
+	var file agentFile
+	err := c.Get(ctx, agentPath(node, vmid, "file-read")+"?file="+url.QueryEscape(path), &file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmAgentFileResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmAgentFileResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_agent_file"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmAgentFileResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Writes a file into a VM through the QEMU guest agent. " +
+			"Destroying the resource only removes it from the state, the file is left in the guest.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the VM runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the VM",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "Absolute path of the file in the guest",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "Content of the file",
+			},
+		},
+	}
+}
+
+// write writes the planned content into the guest.
+func (r *vmAgentFileResource) write(ctx context.Context, plan vmAgentFileResourceModel) error {
+	params := map[string]interface{}{
+		"file":    plan.Path.ValueString(),
+		"content": plan.Content.ValueString(),
+	}
+
+	return r.client.Post(ctx, agentPath(plan.Node.ValueString(), plan.VMID.ValueInt64(), "file-write"), params, nil)
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider.
+func (r *vmAgentFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmAgentFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmAgentFileResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Writing file through guest agent", map[string]interface{}{
+		"path": plan.Path.ValueString(),
+	})
+
+	err := r.write(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Write File Through Proxmox Guest Agent",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmAgentFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmAgentFileResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, state.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, state.VMID.ValueInt64())
+	tflog.SubsystemDebug(ctx, logVM, "Reading file through guest agent", map[string]interface{}{
+		"path": state.Path.ValueString(),
+	})
+
+	file, err := r.client.readAgentFile(ctx, state.Node.ValueString(), state.VMID.ValueInt64(), state.Path.ValueString())
+	if err != nil {
+		// The file was removed inside the guest, write it again.
+		if strings.Contains(strings.ToLower(err.Error()), "no such file") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read File Through Proxmox Guest Agent",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// Large files are truncated by the agent, only track changes of files
+	// that were read completely.
+	if file.Truncated == 0 {
+		state.Content = types.StringValue(file.Content)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmAgentFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmAgentFileResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Rewriting file through guest agent", map[string]interface{}{
+		"path": plan.Path.ValueString(),
+	})
+
+	err := r.write(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Write File Through Proxmox Guest Agent",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmAgentFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The guest agent has no API to remove files, the file is left in place.
+}
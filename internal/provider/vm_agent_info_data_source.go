@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &vmAgentInfoDataSource{}
+	_ datasource.DataSourceWithConfigure = &vmAgentInfoDataSource{}
+)
+
+func NewVmAgentInfoDataSource() datasource.DataSource {
+	return &vmAgentInfoDataSource{}
+}
+
+type vmAgentInfoDataSource struct {
+	client *apiClient
+}
+
+type vmAgentInfoDataSourceModel struct {
+	Node           types.String `tfsdk:"node"`
+	VMID           types.Int64  `tfsdk:"vm_id"`
+	Hostname       types.String `tfsdk:"hostname"`
+	OSID           types.String `tfsdk:"os_id"`
+	OSName         types.String `tfsdk:"os_name"`
+	OSPrettyName   types.String `tfsdk:"os_pretty_name"`
+	OSVersion      types.String `tfsdk:"os_version"`
+	KernelRelease  types.String `tfsdk:"kernel_release"`
+	Machine        types.String `tfsdk:"machine"`
+	Timezone       types.String `tfsdk:"timezone"`
+	TimezoneOffset types.Int64  `tfsdk:"timezone_offset"`
+}
+
+// agentHostName is the response of the agent get-host-name command.
+type agentHostName struct {
+	Result struct {
+		HostName string `json:"host-name"`
+	} `json:"result"`
+}
+
+// agentOSInfo is the response of the agent get-osinfo command.
+type agentOSInfo struct {
+	Result struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		PrettyName    string `json:"pretty-name"`
+		VersionID     string `json:"version-id"`
+		KernelRelease string `json:"kernel-release"`
+		Machine       string `json:"machine"`
+	} `json:"result"`
+}
+
+// agentTimezone is the response of the agent get-timezone command.
+type agentTimezone struct {
+	Result struct {
+		Zone   string `json:"zone"`
+		Offset int64  `json:"offset"`
+	} `json:"result"`
+}
+
+func (d *vmAgentInfoDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *vmAgentInfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_agent_info"
+}
+
+func (d *vmAgentInfoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the hostname, operating system and timezone of a VM through the QEMU guest agent, " +
+			"e.g. to feed inventory or naming systems after cloning.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required: true,
+			},
+			"vm_id": schema.Int64Attribute{
+				Required: true,
+			},
+			"hostname": schema.StringAttribute{
+				Computed: true,
+			},
+			"os_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Operating system ID, e.g. debian or mswindows",
+			},
+			"os_name": schema.StringAttribute{
+				Computed: true,
+			},
+			"os_pretty_name": schema.StringAttribute{
+				Computed: true,
+			},
+			"os_version": schema.StringAttribute{
+				Computed: true,
+			},
+			"kernel_release": schema.StringAttribute{
+				Computed: true,
+			},
+			"machine": schema.StringAttribute{
+				Computed:    true,
+				Description: "Machine architecture, e.g. x86_64",
+			},
+			"timezone": schema.StringAttribute{
+				Computed: true,
+			},
+			"timezone_offset": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Offset of the timezone to UTC in seconds",
+			},
+		},
+	}
+}
+
+func (d *vmAgentInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state vmAgentInfoDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node, vmid := state.Node.ValueString(), state.VMID.ValueInt64()
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+	tflog.SubsystemDebug(ctx, logVM, "Reading guest info through guest agent")
+
+	var hostName agentHostName
+	var osInfo agentOSInfo
+	var timezone agentTimezone
+	for command, v := range map[string]interface{}{
+		"get-host-name": &hostName,
+		"get-osinfo":    &osInfo,
+		"get-timezone":  &timezone,
+	} {
+		if err := d.client.Get(ctx, agentPath(node, vmid, command), v); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Guest Info Through Proxmox Guest Agent",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	state.Hostname = types.StringValue(hostName.Result.HostName)
+	state.OSID = types.StringValue(osInfo.Result.ID)
+	state.OSName = types.StringValue(osInfo.Result.Name)
+	state.OSPrettyName = types.StringValue(osInfo.Result.PrettyName)
+	state.OSVersion = types.StringValue(osInfo.Result.VersionID)
+	state.KernelRelease = types.StringValue(osInfo.Result.KernelRelease)
+	state.Machine = types.StringValue(osInfo.Result.Machine)
+	state.Timezone = types.StringValue(timezone.Result.Zone)
+	state.TimezoneOffset = types.Int64Value(timezone.Result.Offset)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
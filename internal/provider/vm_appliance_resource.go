@@ -0,0 +1,446 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &vmApplianceResource{}
+	_ resource.ResourceWithConfigure  = &vmApplianceResource{}
+	_ resource.ResourceWithModifyPlan = &vmApplianceResource{}
+)
+
+// NewVmApplianceResource is a helper function to simplify the provider implementation.
+func NewVmApplianceResource() resource.Resource {
+	return &vmApplianceResource{}
+}
+
+// vmApplianceResource deploys a VM from an OVA or OVF appliance.
+type vmApplianceResource struct {
+	client *apiClient
+}
+
+// vmApplianceResourceModel maps the resource schema data.
+type vmApplianceResourceModel struct {
+	Node                 types.String `tfsdk:"node"`
+	Storage              types.String `tfsdk:"storage"`
+	FileName             types.String `tfsdk:"file_name"`
+	URL                  types.String `tfsdk:"url"`
+	TargetStorage        types.String `tfsdk:"target_storage"`
+	DiskStorage          types.Map    `tfsdk:"disk_storage"`
+	DiskFormat           types.String `tfsdk:"disk_format"`
+	Bridge               types.String `tfsdk:"bridge"`
+	Name                 types.String `tfsdk:"name"`
+	Cores                types.Int64  `tfsdk:"cores"`
+	Memory               types.Int64  `tfsdk:"memory"`
+	VMID                 types.Int64  `tfsdk:"vm_id"`
+	Disks                types.Map    `tfsdk:"disks"`
+	Start                types.Bool   `tfsdk:"start"`
+	WaitForStatus        types.String `tfsdk:"wait_for_status"`
+	WaitForStatusTimeout types.Int64  `tfsdk:"wait_for_status_timeout"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmApplianceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmApplianceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_appliance"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmApplianceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Deploys a VM from an OVA or OVF appliance, e.g. a firewall or NAS VM. The appliance is optionally downloaded " +
+			"to a storage with the import content type, Proxmox VE extracts it and imports its disks. Every change deploys the " +
+			"appliance again, destroying the resource destroys the VM. Requires Proxmox VE 8.3 or later.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Node to deploy the VM to. Defaults to the default_node of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"storage": schema.StringAttribute{
+				Required:    true,
+				Description: "File based storage with the import content type holding the appliance",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"file_name": schema.StringAttribute{
+				Required:    true,
+				Description: "File name of the appliance in the import directory of the storage, e.g. `opnsense.ova`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL to download the appliance from to file_name before deploying it. The file is left on the storage",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"target_storage": schema.StringAttribute{
+				Required:    true,
+				Description: "Storage the disks are imported to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_storage": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Storage of individual disks, keyed by disk, e.g. `scsi1`, overriding target_storage",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_format": schema.StringAttribute{
+				Optional:    true,
+				Description: "Format of the imported disks, one of raw, qcow2 or vmdk. Formats other than raw require file based storage. Defaults to the default format of the storage",
+				Validators: []validator.String{
+					stringvalidator.OneOf(diskFormats...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"bridge": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("vmbr0"),
+				Description: "Bridge the network interfaces are connected to. Defaults to vmbr0",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the VM, defaults to the name in the appliance",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cores": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of CPU cores, defaults to the number in the appliance",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"memory": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Memory in MiB, defaults to the memory in the appliance",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(16),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the VM, allocated from the vmid_range of the provider when not set",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"disks": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Source volumes of the imported disks, keyed by disk of the VM",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+
+	for name, attribute := range guestStartAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// applianceVolume returns the volume ID of an appliance file on storage.
+func applianceVolume(storage, fileName string) string {
+	return fmt.Sprintf("%s:import/%s", storage, fileName)
+}
+
+// applianceParams applies the cores and memory overrides of plan to the
+// parameters of the VM creation.
+func applianceParams(params map[string]interface{}, plan vmApplianceResourceModel) map[string]interface{} {
+	if !plan.Name.IsNull() {
+		params["name"] = plan.Name.ValueString()
+	}
+	if !plan.Cores.IsNull() {
+		params["cores"] = plan.Cores.ValueInt64()
+	}
+	if !plan.Memory.IsNull() {
+		params["memory"] = plan.Memory.ValueInt64()
+	}
+
+	return params
+}
+
+// ModifyPlan fills in the default_node of the provider and rejects guest IDs
+// outside of its vmid_range, disk formats the target storages do not support
+// and bridges that are vnets of SDN zones not deployed to the node.
+func (r *vmApplianceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planDefaultNode(ctx, req, resp)
+	r.client.validateVMIDPlan(ctx, resp.Plan, &resp.Diagnostics)
+	r.client.validateDiskFormatPlan(ctx, resp.Plan, &resp.Diagnostics)
+	r.client.validateVnetNodePlan(ctx, resp.Plan, req.State, path.MatchRoot("bridge"), &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmApplianceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmApplianceResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diskStorage := map[string]string{}
+	diags = plan.DiskStorage.ElementsAs(ctx, &diskStorage, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node := plan.Node.ValueString()
+	storage := plan.Storage.ValueString()
+	volume := applianceVolume(storage, plan.FileName.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+
+	release := r.client.acquireNode(ctx, node, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	if !plan.URL.IsNull() {
+		tflog.SubsystemInfo(ctx, logVM, "Downloading appliance", map[string]interface{}{
+			"url":    plan.URL.ValueString(),
+			"volume": volume,
+		})
+
+		var upid string
+		err := r.client.Post(ctx, fmt.Sprintf("/nodes/%s/storage/%s/download-url", node, storage), map[string]interface{}{
+			"content":  "import",
+			"filename": plan.FileName.ValueString(),
+			"url":      plan.URL.ValueString(),
+		}, &upid)
+		if err == nil {
+			_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Download Proxmox Appliance",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	metadata, err := r.client.importMetadata(ctx, node, volume)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Import Metadata",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	for _, warning := range importWarnings(metadata) {
+		resp.Diagnostics.AddWarning(
+			"Proxmox Import Warning",
+			fmt.Sprintf("Deploying %s: %s", volume, warning),
+		)
+	}
+
+	if plan.VMID.IsUnknown() {
+		vmid, err := r.client.nextVMID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Allocate Proxmox Guest ID",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		plan.VMID = types.Int64Value(vmid)
+	}
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+
+	params := importParams(metadata, plan.VMID.ValueInt64(), plan.TargetStorage.ValueString(), diskStorage, plan.DiskFormat.ValueString(), plan.Bridge.ValueString())
+	params = applianceParams(params, plan)
+
+	tflog.SubsystemInfo(ctx, logVM, "Deploying appliance", map[string]interface{}{
+		"volume": volume,
+	})
+
+	var upid string
+	err = r.client.Post(ctx, fmt.Sprintf("/nodes/%s/qemu", node), params, &upid)
+	if err == nil {
+		_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Deploy Proxmox Appliance",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	disks := map[string]string{}
+	for key, disk := range metadata.Disks {
+		disks[key] = disk.VolID
+	}
+	plan.Disks, diags = types.MapValueFrom(ctx, types.StringType, disks)
+	resp.Diagnostics.Append(diags...)
+
+	if plan.Start.ValueBool() {
+		timeout := time.Duration(plan.WaitForStatusTimeout.ValueInt64()) * time.Second
+		err = r.client.startGuest(ctx, node, "qemu", plan.VMID.ValueInt64(), plan.WaitForStatus.ValueString(), timeout)
+		if err != nil {
+			// Keep the created guest in the state, so that Terraform
+			// taints and replaces it instead of losing track of it.
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.AddError(
+				"Unable to Start Proxmox VM",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmApplianceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmApplianceResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config map[string]interface{}
+	err := r.client.Get(ctx, guestPath(state.Node.ValueString(), "qemu", state.VMID.ValueInt64())+"/config", &config)
+	if err != nil {
+		// The VM was removed outside of Terraform, deploy it again.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmApplianceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires a new deployment or only applies on creation,
+	// there is nothing to update in place.
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmApplianceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmApplianceResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node, vmid := state.Node.ValueString(), state.VMID.ValueInt64()
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+
+	release := r.client.acquireNode(ctx, node, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	err := r.client.stopGuest(ctx, node, "qemu", vmid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Stop Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	err = r.client.destroyVM(ctx, node, vmid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestApplianceParams(t *testing.T) {
+	if got := applianceVolume("local", "opnsense.ova"); got != "local:import/opnsense.ova" {
+		t.Errorf("got %q", got)
+	}
+
+	got := applianceParams(map[string]interface{}{"name": "OPNsense", "cores": float64(1), "memory": float64(1024)}, vmApplianceResourceModel{
+		Name:   types.StringNull(),
+		Cores:  types.Int64Value(4),
+		Memory: types.Int64Null(),
+	})
+	want := map[string]interface{}{"name": "OPNsense", "cores": int64(4), "memory": float64(1024)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
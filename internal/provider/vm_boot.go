@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// vmBootOrderAttribute returns the schema attribute of the boot order of a
+// VM.
+func vmBootOrderAttribute() schema.Attribute {
+	return schema.ListAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+		Computed:    true,
+		Description: "Devices the VM boots from in order, drives and network devices by config key, e.g. `[\"net0\", \"scsi0\"]` " +
+			"to PXE boot before booting from the disk. A VM booting from a network device first needs no disks. Defaults " +
+			"to the disks and network devices of the VM, or the boot order of a cloned source",
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+			listvalidator.UniqueValues(),
+			listvalidator.ValueStringsAre(
+				stringvalidator.Any(
+					stringvalidator.RegexMatches(diskInterface, "must be a drive interface"),
+					stringvalidator.RegexMatches(networkDeviceKey, "must be a network device from net0-31"),
+				),
+			),
+		},
+	}
+}
+
+// formatBootOrder returns the boot option of a boot order, e.g.
+// `order=net0;scsi0`.
+func formatBootOrder(order types.List) string {
+	devices := make([]string, 0, len(order.Elements()))
+	for _, element := range order.Elements() {
+		if device, ok := element.(types.String); ok {
+			devices = append(devices, device.ValueString())
+		}
+	}
+
+	return "order=" + strings.Join(devices, ";")
+}
+
+// parseBootOrder returns the boot order of the boot option of a VM config,
+// which is null for the legacy boot option, e.g. `cdn`, or without one.
+func parseBootOrder(value string) (types.List, error) {
+	order := bootOrder(value)
+	if order == nil {
+		return types.ListNull(types.StringType), nil
+	}
+
+	list, diags := types.ListValueFrom(context.Background(), types.StringType, order)
+	if diags.HasError() {
+		return types.ListNull(types.StringType), fmt.Errorf("setting boot_order: %v", diags)
+	}
+
+	return list, nil
+}
+
+// bootsFromNetwork reports whether the first device of a known boot order is
+// a network device.
+func bootsFromNetwork(order types.List) bool {
+	if order.IsNull() || order.IsUnknown() || len(order.Elements()) == 0 {
+		return false
+	}
+
+	first, ok := order.Elements()[0].(types.String)
+	return ok && networkDeviceKey.MatchString(first.ValueString())
+}
+
+// validateVMBoot rejects network devices in the boot order that the VM does
+// not have, and VMs without disks that do not boot from the network.
+func validateVMBoot(config vmResourceModel, diags *diag.Diagnostics) {
+	if !config.BootOrder.IsNull() && !config.BootOrder.IsUnknown() {
+		for i, element := range config.BootOrder.Elements() {
+			device, ok := element.(types.String)
+			if !ok || device.IsUnknown() || !networkDeviceKey.MatchString(device.ValueString()) {
+				continue
+			}
+			if _, ok := config.NetworkDevices[device.ValueString()]; !ok {
+				diags.AddAttributeError(
+					path.Root("boot_order").AtListIndex(i),
+					"Unknown VM Boot Device",
+					fmt.Sprintf("The VM boots from %s, which is not one of its network_devices.", device.ValueString()),
+				)
+			}
+		}
+	}
+
+	if config.Disks == nil && !config.BootOrder.IsUnknown() && !bootsFromNetwork(config.BootOrder) {
+		diags.AddAttributeError(
+			path.Root("disks"),
+			"Missing VM Disks",
+			"A VM without disks boots from the network, start its boot_order with a network device, e.g. [\"net0\"].",
+		)
+	}
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// bootOrderList returns the boot_order of devices.
+func bootOrderList(devices ...string) types.List {
+	elements := make([]attr.Value, len(devices))
+	for i, device := range devices {
+		elements[i] = types.StringValue(device)
+	}
+
+	return types.ListValueMust(types.StringType, elements)
+}
+
+func TestFormatBootOrder(t *testing.T) {
+	order := bootOrderList("net0", "scsi0", "ide2")
+	value := formatBootOrder(order)
+	if value != "order=net0;scsi0;ide2" {
+		t.Errorf("got %q", value)
+	}
+
+	got, err := parseBootOrder(value)
+	if err != nil || !got.Equal(order) {
+		t.Errorf("got %v, %v, want %v", got, err, order)
+	}
+
+	for _, legacy := range []string{"", "cdn"} {
+		if got, err := parseBootOrder(legacy); err != nil || !got.IsNull() {
+			t.Errorf("%q: got %v, %v, want null", legacy, got, err)
+		}
+	}
+}
+
+func TestValidateVMBoot(t *testing.T) {
+	disks := map[string]vmDiskModel{"scsi0": {}}
+	networks := map[string]vmNetworkModel{"net0": {}}
+
+	tests := []struct {
+		name    string
+		config  vmResourceModel
+		wantErr bool
+	}{
+		{
+			name:   "default boot order",
+			config: vmResourceModel{Disks: disks, BootOrder: types.ListNull(types.StringType)},
+		},
+		{
+			name:   "pxe before disk",
+			config: vmResourceModel{Disks: disks, NetworkDevices: networks, BootOrder: bootOrderList("net0", "scsi0")},
+		},
+		{
+			name:   "pxe without disks",
+			config: vmResourceModel{NetworkDevices: networks, BootOrder: bootOrderList("net0")},
+		},
+		{
+			name:    "no disks without pxe",
+			config:  vmResourceModel{NetworkDevices: networks, BootOrder: types.ListNull(types.StringType)},
+			wantErr: true,
+		},
+		{
+			name:    "no disks booting from a cd-rom",
+			config:  vmResourceModel{NetworkDevices: networks, BootOrder: bootOrderList("ide2", "net0")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown network device",
+			config:  vmResourceModel{NetworkDevices: networks, BootOrder: bootOrderList("net1")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		var diags diag.Diagnostics
+		validateVMBoot(tt.config, &diags)
+		if diags.HasError() != tt.wantErr {
+			t.Errorf("%s: got %v", tt.name, diags)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// cloudInitOptions are the VM config options set by the cloud_init
+// attribute, besides the numbered ipconfig options.
+var cloudInitOptions = []string{"citype", "ciuser", "cipassword", "sshkeys", "nameserver", "searchdomain", "cicustom"}
+
+// cloudInitSnippetTypes are the cloud-init configurations a VM can take from
+// snippets instead of generating them.
+var cloudInitSnippetTypes = []string{"user", "network", "meta", "vendor"}
+
+// cloudInitTypes are the formats of the cloud-init drive. Windows guests
+// read configdrive2 drives with cloudbase-init.
+var cloudInitTypes = []string{"nocloud", "configdrive2", "opennebula"}
+
+// vmCloudInitModel maps the cloud-init configuration of a VM.
+type vmCloudInitModel struct {
+	Interface       types.String            `tfsdk:"interface"`
+	Type            types.String            `tfsdk:"type"`
+	Storage         types.String            `tfsdk:"storage"`
+	User            types.String            `tfsdk:"user"`
+	Password        types.String            `tfsdk:"password"`
+	SSHKeys         []types.String          `tfsdk:"ssh_keys"`
+	Nameserver      types.String            `tfsdk:"nameserver"`
+	SearchDomain    types.String            `tfsdk:"search_domain"`
+	IPConfigs       []vmIPConfigModel       `tfsdk:"ip_config"`
+	Custom          map[string]types.String `tfsdk:"custom"`
+	CustomData      map[string]types.String `tfsdk:"custom_data"`
+	SnippetsStorage types.String            `tfsdk:"snippets_storage"`
+}
+
+// vmIPConfigModel maps the IP configuration of a network device of a VM.
+type vmIPConfigModel struct {
+	IPv4     types.String `tfsdk:"ipv4"`
+	Gateway  types.String `tfsdk:"gateway"`
+	IPv6     types.String `tfsdk:"ipv6"`
+	Gateway6 types.String `tfsdk:"gateway6"`
+}
+
+// vmCloudInitAttribute returns the schema attribute of the cloud-init
+// configuration of a VM.
+func vmCloudInitAttribute() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Description: "Cloud-init configuration of the VM, passed to the guest on a cloud-init drive. The drive and the " +
+			"options are removed when not set. Changes take effect the next time the VM boots",
+		Attributes: map[string]schema.Attribute{
+			"interface": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("ide2"),
+				Description: "Drive interface of the cloud-init drive. Defaults to ide2",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(diskInterface, "must be a drive interface from scsi0-30, virtio0-15, sata0-5 or ide0-3"),
+				},
+			},
+			"type": schema.StringAttribute{
+				Optional: true,
+				Description: "Format of the cloud-init drive, nocloud, configdrive2 or opennebula. Windows guests running " +
+					"cloudbase-init need configdrive2. Defaults to configdrive2 for a Windows os_type and nocloud otherwise",
+				Validators: []validator.String{
+					stringvalidator.OneOf(cloudInitTypes...),
+				},
+			},
+			"storage": schema.StringAttribute{
+				Required:    true,
+				Description: "Storage the cloud-init drive is allocated on",
+			},
+			"user": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the user to create instead of the default user of the image",
+			},
+			"password": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of the user. The API only returns it masked, so changes made outside of Terraform are not detected",
+			},
+			"ssh_keys": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Public SSH keys authorized for the user",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"nameserver": schema.StringAttribute{
+				Optional:    true,
+				Description: "DNS servers, separated by spaces. Defaults to the DNS settings of the node",
+			},
+			"search_domain": schema.StringAttribute{
+				Optional:    true,
+				Description: "DNS search domains, separated by spaces. Defaults to the DNS settings of the node",
+			},
+			"custom": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Snippets replacing the generated user, network, meta or vendor configuration, keyed by the configuration " +
+					"they replace, e.g. user = \"local:snippets/user.yaml\"",
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.OneOf(cloudInitSnippetTypes...)),
+				},
+			},
+			"custom_data": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Inline user, network, meta or vendor configurations, keyed like custom. They are written as snippets " +
+					"to the snippets_storage over the SSH connection of the provider, as the API cannot upload snippets, and removed " +
+					"with the VM. Changes made to the snippets outside of Terraform are not detected",
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.OneOf(cloudInitSnippetTypes...)),
+					mapvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("snippets_storage")),
+				},
+			},
+			"snippets_storage": schema.StringAttribute{
+				Optional:    true,
+				Description: "Storage with the snippets content type and a directory on the node, e.g. local, that custom_data is written to",
+			},
+			"ip_config": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "IP configuration of the network devices, the nth entry configuring the device netN",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ipv4": schema.StringAttribute{
+							Optional:    true,
+							Description: "IPv4 address in CIDR notation, or dhcp",
+						},
+						"gateway": schema.StringAttribute{
+							Optional:    true,
+							Description: "IPv4 gateway of a static address",
+						},
+						"ipv6": schema.StringAttribute{
+							Optional:    true,
+							Description: "IPv6 address in CIDR notation, dhcp or auto for SLAAC",
+						},
+						"gateway6": schema.StringAttribute{
+							Optional:    true,
+							Description: "IPv6 gateway of a static address",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// formatIPConfig returns the ipconfig option of an IP configuration, e.g.
+// `ip=10.0.0.10/24,gw=10.0.0.1,ip6=auto`.
+func formatIPConfig(config vmIPConfigModel) string {
+	var options []string
+	values := []struct {
+		key   string
+		value types.String
+	}{
+		{key: "ip", value: config.IPv4},
+		{key: "gw", value: config.Gateway},
+		{key: "ip6", value: config.IPv6},
+		{key: "gw6", value: config.Gateway6},
+	}
+	for _, option := range values {
+		if !option.value.IsNull() {
+			options = append(options, option.key+"="+option.value.ValueString())
+		}
+	}
+
+	return strings.Join(options, ",")
+}
+
+// parseIPConfig parses an ipconfig option of a VM config.
+func parseIPConfig(value string) (vmIPConfigModel, error) {
+	options, err := proxmoxtf.ParseOptions(value, "")
+	if err != nil {
+		return vmIPConfigModel{}, err
+	}
+
+	return vmIPConfigModel{
+		IPv4:     proxmoxtf.StringOrNull(options["ip"]),
+		Gateway:  proxmoxtf.StringOrNull(options["gw"]),
+		IPv6:     proxmoxtf.StringOrNull(options["ip6"]),
+		Gateway6: proxmoxtf.StringOrNull(options["gw6"]),
+	}, nil
+}
+
+// encodeSSHKeys returns the sshkeys option of keys. The API expects the keys
+// newline separated and URL encoded, with spaces encoded as %20.
+func encodeSSHKeys(keys []types.String) string {
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key.ValueString())
+	}
+
+	return strings.ReplaceAll(url.QueryEscape(strings.Join(lines, "\n")), "+", "%20")
+}
+
+// decodeSSHKeys parses the sshkeys option of a VM config.
+func decodeSSHKeys(value string) ([]types.String, error) {
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sshkeys %q: %w", value, err)
+	}
+
+	var keys []types.String
+	for _, line := range strings.Split(decoded, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			keys = append(keys, types.StringValue(line))
+		}
+	}
+
+	return keys, nil
+}
+
+// isCloudInitDrive reports whether the drive option value is a cloud-init
+// drive, e.g. `local-lvm:vm-100-cloudinit,media=cdrom`.
+func isCloudInitDrive(value string) bool {
+	volume, _, _ := strings.Cut(value, ",")
+	return strings.HasSuffix(volume, "-cloudinit") || strings.HasSuffix(volume, ":cloudinit")
+}
+
+// snippetFile returns the name of the snippet file of the inline cloud-init
+// configuration kind of the VM vmid.
+func snippetFile(vmid int64, kind string) string {
+	return fmt.Sprintf("vm-%d-cloud-init-%s.yaml", vmid, kind)
+}
+
+// snippetVolume returns the volume ID of the snippet file of the inline
+// cloud-init configuration kind of the VM vmid on storage.
+func snippetVolume(storage string, vmid int64, kind string) string {
+	return storage + ":snippets/" + snippetFile(vmid, kind)
+}
+
+// formatCICustom returns the cicustom option of ci for the VM vmid, e.g.
+// `network=local:snippets/vm-100-cloud-init-network.yaml,user=local:snippets/user.yaml`,
+// or "" when the VM takes no configuration from snippets.
+func formatCICustom(ci vmCloudInitModel, vmid int64) string {
+	options := map[string]string{}
+	for kind, volume := range ci.Custom {
+		options[kind] = volume.ValueString()
+	}
+	for kind := range ci.CustomData {
+		options[kind] = snippetVolume(ci.SnippetsStorage.ValueString(), vmid, kind)
+	}
+
+	return proxmoxtf.FormatOptions(options, "")
+}
+
+// staleSnippets returns the inline cloud-init configurations of state whose
+// snippet files plan, which is nil once the VM is destroyed, no longer
+// writes.
+func staleSnippets(state, plan *vmCloudInitModel) []string {
+	if state == nil {
+		return nil
+	}
+
+	var stale []string
+	for kind := range state.CustomData {
+		if plan == nil || !plan.SnippetsStorage.Equal(state.SnippetsStorage) {
+			stale = append(stale, kind)
+		} else if _, ok := plan.CustomData[kind]; !ok {
+			stale = append(stale, kind)
+		}
+	}
+	sort.Strings(stale)
+
+	return stale
+}
+
+// snippetsDir returns the directory of the snippets of storage on its
+// nodes.
+func (c *apiClient) snippetsDir(ctx context.Context, storage string) (string, error) {
+	config, err := c.api.Storage(ctx, storage)
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(config.Content, "snippets") {
+		return "", fmt.Errorf("storage %s does not hold snippets, add snippets to its content", storage)
+	}
+	if config.Path == "" {
+		return "", fmt.Errorf("storage %s has no directory to write snippets to", storage)
+	}
+
+	return config.Path + "/snippets", nil
+}
+
+// uploadSnippets writes the inline cloud-init configurations of ci to the
+// snippets storage on node. The API cannot upload snippets, so they are
+// written over SSH.
+func (c *apiClient) uploadSnippets(ctx context.Context, node string, vmid int64, ci *vmCloudInitModel) error {
+	if ci == nil || len(ci.CustomData) == 0 {
+		return nil
+	}
+
+	dir, err := c.snippetsDir(ctx, ci.SnippetsStorage.ValueString())
+	if err != nil {
+		return err
+	}
+	for kind, content := range ci.CustomData {
+		tflog.SubsystemInfo(ctx, logVM, "Writing cloud-init snippet", map[string]interface{}{
+			"volume": snippetVolume(ci.SnippetsStorage.ValueString(), vmid, kind),
+		})
+
+		file := dir + "/" + snippetFile(vmid, kind)
+		command := "mkdir -p " + shellQuote(dir) + " && cat > " + shellQuote(file)
+		if _, err := c.nodeExec(ctx, node, command, strings.NewReader(content.ValueString())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeSnippets removes the snippet files of the inline cloud-init
+// configurations kinds of the VM vmid from storage on node.
+func (c *apiClient) removeSnippets(ctx context.Context, node string, vmid int64, storage string, kinds []string) error {
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	dir, err := c.snippetsDir(ctx, storage)
+	if err != nil {
+		return err
+	}
+	command := "rm -f"
+	for _, kind := range kinds {
+		command += " " + shellQuote(dir+"/"+snippetFile(vmid, kind))
+	}
+
+	_, err = c.nodeExec(ctx, node, command, nil)
+	return err
+}
+
+// cloudInitParams returns the options that apply the cloud-init
+// configuration ci, which is nil without one, to the VM config of vmid, and
+// the options of config it removes. A drive is allocated unless config
+// already has a cloud-init drive on the planned interface and storage.
+func cloudInitParams(ci *vmCloudInitModel, vmid int64, config map[string]interface{}) (map[string]interface{}, []string) {
+	params := map[string]interface{}{}
+	if ci != nil {
+		drive := ci.Interface.ValueString()
+		current := proxmoxtf.ConfigValue(config[drive])
+		if !isCloudInitDrive(current) || !strings.HasPrefix(current, ci.Storage.ValueString()+":") {
+			params[drive] = ci.Storage.ValueString() + ":cloudinit"
+		}
+
+		options := map[string]types.String{
+			"citype":       ci.Type,
+			"ciuser":       ci.User,
+			"cipassword":   ci.Password,
+			"nameserver":   ci.Nameserver,
+			"searchdomain": ci.SearchDomain,
+		}
+		for key, value := range options {
+			if !value.IsNull() {
+				params[key] = value.ValueString()
+			}
+		}
+		if len(ci.SSHKeys) > 0 {
+			params["sshkeys"] = encodeSSHKeys(ci.SSHKeys)
+		}
+		for i, ipConfig := range ci.IPConfigs {
+			params["ipconfig"+strconv.Itoa(i)] = formatIPConfig(ipConfig)
+		}
+		if custom := formatCICustom(*ci, vmid); custom != "" {
+			params["cicustom"] = custom
+		}
+	}
+
+	var remove []string
+	for key, value := range config {
+		if _, ok := params[key]; ok {
+			continue
+		}
+		switch {
+		case diskInterface.MatchString(key):
+			if isCloudInitDrive(proxmoxtf.ConfigValue(value)) && (ci == nil || key != ci.Interface.ValueString()) {
+				remove = append(remove, key)
+			}
+		case strings.HasPrefix(key, "ipconfig"):
+			remove = append(remove, key)
+		default:
+			for _, option := range cloudInitOptions {
+				if key == option {
+					remove = append(remove, key)
+				}
+			}
+		}
+	}
+	sort.Strings(remove)
+
+	return params, remove
+}
+
+// parseCloudInit returns the cloud-init configuration of the config of the VM
+// vmid, or nil when the VM has no cloud-init drive on the interface of
+// current. The password and the inline configurations are kept from
+// current, as the API only returns the password masked and cannot read
+// snippets.
+func parseCloudInit(config map[string]interface{}, vmid int64, current vmCloudInitModel) (*vmCloudInitModel, error) {
+	drive := proxmoxtf.ConfigValue(config[current.Interface.ValueString()])
+	if !isCloudInitDrive(drive) {
+		return nil, nil
+	}
+
+	storage, _, _ := strings.Cut(drive, ":")
+	ci := &vmCloudInitModel{
+		Interface:    current.Interface,
+		Storage:      types.StringValue(storage),
+		Type:         proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["citype"])),
+		User:         proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["ciuser"])),
+		Password:     current.Password,
+		Nameserver:   proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["nameserver"])),
+		SearchDomain: proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["searchdomain"])),
+	}
+	if _, ok := config["cipassword"]; !ok {
+		ci.Password = types.StringNull()
+	}
+
+	var err error
+	if ci.SSHKeys, err = decodeSSHKeys(proxmoxtf.ConfigValue(config["sshkeys"])); err != nil {
+		return nil, err
+	}
+	for i := 0; ; i++ {
+		value, ok := config["ipconfig"+strconv.Itoa(i)]
+		if !ok {
+			break
+		}
+		ipConfig, err := parseIPConfig(proxmoxtf.ConfigValue(value))
+		if err != nil {
+			return nil, fmt.Errorf("ipconfig%d: %w", i, err)
+		}
+		ci.IPConfigs = append(ci.IPConfigs, ipConfig)
+	}
+
+	ci.SnippetsStorage = current.SnippetsStorage
+	snippets, err := proxmoxtf.ParseOptions(proxmoxtf.ConfigValue(config["cicustom"]), "")
+	if err != nil {
+		return nil, fmt.Errorf("cicustom: %w", err)
+	}
+	for kind, volume := range snippets {
+		if _, ok := current.CustomData[kind]; ok && volume == snippetVolume(current.SnippetsStorage.ValueString(), vmid, kind) {
+			if ci.CustomData == nil {
+				ci.CustomData = map[string]types.String{}
+			}
+			ci.CustomData[kind] = current.CustomData[kind]
+			continue
+		}
+		if ci.Custom == nil {
+			ci.Custom = map[string]types.String{}
+		}
+		ci.Custom[kind] = types.StringValue(volume)
+	}
+
+	return ci, nil
+}
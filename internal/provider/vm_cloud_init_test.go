@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSSHKeys(t *testing.T) {
+	keys := []types.String{
+		types.StringValue("ssh-ed25519 AAAAC3Nza+C1lZDI1NTE5/AAAA alice@example.com"),
+		types.StringValue("ssh-rsa AAAAB3NzaC1yc2E bob"),
+	}
+
+	encoded := encodeSSHKeys(keys)
+	want := "ssh-ed25519%20AAAAC3Nza%2BC1lZDI1NTE5%2FAAAA%20alice%40example.com%0Assh-rsa%20AAAAB3NzaC1yc2E%20bob"
+	if encoded != want {
+		t.Errorf("got %q, want %q", encoded, want)
+	}
+
+	decoded, err := decodeSSHKeys(encoded + "%0A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, keys) {
+		t.Errorf("got %v, want %v", decoded, keys)
+	}
+}
+
+func TestCloudInitParams(t *testing.T) {
+	ci := &vmCloudInitModel{
+		Interface:    types.StringValue("ide2"),
+		Type:         types.StringValue("configdrive2"),
+		Storage:      types.StringValue("local-lvm"),
+		User:         types.StringValue("debian"),
+		Password:     types.StringNull(),
+		SSHKeys:      []types.String{types.StringValue("ssh-ed25519 AAAA")},
+		Nameserver:   types.StringNull(),
+		SearchDomain: types.StringValue("example.com"),
+		IPConfigs: []vmIPConfigModel{
+			{IPv4: types.StringValue("10.0.0.10/24"), Gateway: types.StringValue("10.0.0.1"), IPv6: types.StringValue("auto"), Gateway6: types.StringNull()},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		ci         *vmCloudInitModel
+		config     map[string]interface{}
+		wantParams map[string]interface{}
+		wantRemove []string
+	}{
+		{
+			name: "new drive",
+			ci:   ci,
+			wantParams: map[string]interface{}{
+				"ide2":         "local-lvm:cloudinit",
+				"citype":       "configdrive2",
+				"ciuser":       "debian",
+				"sshkeys":      "ssh-ed25519%20AAAA",
+				"searchdomain": "example.com",
+				"ipconfig0":    "ip=10.0.0.10/24,gw=10.0.0.1,ip6=auto",
+			},
+		},
+		{
+			name: "existing drive",
+			ci:   ci,
+			config: map[string]interface{}{
+				"scsi0":      "local-lvm:vm-100-disk-0,size=32G",
+				"ide2":       "local-lvm:vm-100-cloudinit,media=cdrom",
+				"cipassword": "**********",
+				"ipconfig1":  "ip=dhcp",
+			},
+			wantParams: map[string]interface{}{
+				"citype":       "configdrive2",
+				"ciuser":       "debian",
+				"sshkeys":      "ssh-ed25519%20AAAA",
+				"searchdomain": "example.com",
+				"ipconfig0":    "ip=10.0.0.10/24,gw=10.0.0.1,ip6=auto",
+			},
+			wantRemove: []string{"cipassword", "ipconfig1"},
+		},
+		{
+			name: "removed",
+			config: map[string]interface{}{
+				"scsi0":     "local-lvm:vm-100-disk-0,size=32G",
+				"ide2":      "local-lvm:vm-100-cloudinit,media=cdrom",
+				"citype":    "configdrive2",
+				"ciuser":    "debian",
+				"ipconfig0": "ip=dhcp",
+			},
+			wantParams: map[string]interface{}{},
+			wantRemove: []string{"citype", "ciuser", "ide2", "ipconfig0"},
+		},
+	}
+
+	for _, tt := range tests {
+		params, remove := cloudInitParams(tt.ci, 100, tt.config)
+		if !reflect.DeepEqual(params, tt.wantParams) || !reflect.DeepEqual(remove, tt.wantRemove) {
+			t.Errorf("%s: got %v %v, want %v %v", tt.name, params, remove, tt.wantParams, tt.wantRemove)
+		}
+	}
+}
+
+func TestParseCloudInit(t *testing.T) {
+	current := vmCloudInitModel{
+		Interface:       types.StringValue("ide2"),
+		Password:        types.StringValue("secret"),
+		CustomData:      map[string]types.String{"network": types.StringValue("version: 2")},
+		SnippetsStorage: types.StringValue("local"),
+	}
+	config := map[string]interface{}{
+		"ide2":       "local-lvm:vm-100-cloudinit,media=cdrom",
+		"citype":     "configdrive2",
+		"ciuser":     "debian",
+		"cipassword": "**********",
+		"ipconfig0":  "ip=dhcp",
+		"cicustom":   "network=local:snippets/vm-100-cloud-init-network.yaml,user=local:snippets/user.yaml",
+	}
+
+	got, err := parseCloudInit(config, 100, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &vmCloudInitModel{
+		Interface:    types.StringValue("ide2"),
+		Type:         types.StringValue("configdrive2"),
+		Storage:      types.StringValue("local-lvm"),
+		User:         types.StringValue("debian"),
+		Password:     types.StringValue("secret"),
+		Nameserver:   types.StringNull(),
+		SearchDomain: types.StringNull(),
+		IPConfigs: []vmIPConfigModel{
+			{IPv4: types.StringValue("dhcp"), Gateway: types.StringNull(), IPv6: types.StringNull(), Gateway6: types.StringNull()},
+		},
+		Custom:          map[string]types.String{"user": types.StringValue("local:snippets/user.yaml")},
+		CustomData:      map[string]types.String{"network": types.StringValue("version: 2")},
+		SnippetsStorage: types.StringValue("local"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	delete(config, "ide2")
+	if got, err := parseCloudInit(config, 100, current); got != nil || err != nil {
+		t.Errorf("got %+v, %v without a cloud-init drive", got, err)
+	}
+}
+
+func TestFormatCICustom(t *testing.T) {
+	ci := vmCloudInitModel{
+		Custom:          map[string]types.String{"user": types.StringValue("local:snippets/user.yaml")},
+		CustomData:      map[string]types.String{"network": types.StringValue("version: 2")},
+		SnippetsStorage: types.StringValue("local"),
+	}
+
+	want := "network=local:snippets/vm-100-cloud-init-network.yaml,user=local:snippets/user.yaml"
+	if got := formatCICustom(ci, 100); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStaleSnippets(t *testing.T) {
+	state := &vmCloudInitModel{
+		CustomData: map[string]types.String{
+			"network": types.StringValue("version: 2"),
+			"user":    types.StringValue("#cloud-config"),
+		},
+		SnippetsStorage: types.StringValue("local"),
+	}
+
+	tests := []struct {
+		name string
+		plan *vmCloudInitModel
+		want []string
+	}{
+		{name: "destroyed", want: []string{"network", "user"}},
+		{name: "unchanged", plan: state},
+		{
+			name: "removed",
+			plan: &vmCloudInitModel{
+				CustomData:      map[string]types.String{"user": types.StringValue("#cloud-config")},
+				SnippetsStorage: types.StringValue("local"),
+			},
+			want: []string{"network"},
+		},
+		{
+			name: "moved",
+			plan: &vmCloudInitModel{
+				CustomData:      state.CustomData,
+				SnippetsStorage: types.StringValue("shared"),
+			},
+			want: []string{"network", "user"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := staleSnippets(state, tt.plan); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
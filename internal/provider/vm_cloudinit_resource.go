@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &vmCloudinitResource{}
+	_ resource.ResourceWithConfigure  = &vmCloudinitResource{}
+	_ resource.ResourceWithModifyPlan = &vmCloudinitResource{}
+)
+
+// NewVmCloudinitResource is a helper function to simplify the provider implementation.
+func NewVmCloudinitResource() resource.Resource {
+	return &vmCloudinitResource{}
+}
+
+// vmCloudinitResource is the resource implementation.
+type vmCloudinitResource struct {
+	client *apiClient
+}
+
+// vmCloudinitResourceModel maps the resource schema data.
+type vmCloudinitResourceModel struct {
+	Node     types.String `tfsdk:"node"`
+	VMID     types.Int64  `tfsdk:"vm_id"`
+	Triggers types.Map    `tfsdk:"triggers"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmCloudinitResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmCloudinitResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_cloudinit"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmCloudinitResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Regenerates the cloud-init drive of a VM when it is created and whenever the triggers change, " +
+			"so that changed snippets reach the VM without recreating it.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the VM runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the VM",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values, e.g. hashes of the referenced snippets, that regenerate the drive when changed",
+			},
+		},
+	}
+}
+
+// regenerate rebuilds the cloud-init drive of the VM from its current config.
+func (r *vmCloudinitResource) regenerate(ctx context.Context, model vmCloudinitResourceModel) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, model.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, model.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Regenerating cloud-init drive")
+
+	path := guestPath(model.Node.ValueString(), "qemu", model.VMID.ValueInt64()) + "/cloudinit"
+	return r.client.Put(ctx, path, nil, nil)
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider.
+func (r *vmCloudinitResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmCloudinitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmCloudinitResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.regenerate(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Regenerate Proxmox Cloud-Init Drive",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmCloudinitResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The resource only triggers an action, there is no remote state to refresh.
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmCloudinitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmCloudinitResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.regenerate(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Regenerate Proxmox Cloud-Init Drive",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmCloudinitResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The cloud-init drive belongs to the VM and is left in place.
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// vmCPUTypes are the CPU models QEMU emulates for a VM, besides the custom
+// models of the cluster.
+var vmCPUTypes = []string{
+	"486", "athlon", "Broadwell", "Broadwell-IBRS", "Broadwell-noTSX", "Broadwell-noTSX-IBRS",
+	"Cascadelake-Server", "Cascadelake-Server-noTSX", "Cascadelake-Server-v2", "Cascadelake-Server-v4",
+	"Cascadelake-Server-v5", "Conroe", "Cooperlake", "Cooperlake-v2", "core2duo", "coreduo", "EPYC",
+	"EPYC-Genoa", "EPYC-IBPB", "EPYC-Milan", "EPYC-Milan-v2", "EPYC-Rome", "EPYC-Rome-v2", "EPYC-Rome-v3",
+	"EPYC-Rome-v4", "EPYC-v3", "EPYC-v4", "GraniteRapids", "Haswell", "Haswell-IBRS", "Haswell-noTSX",
+	"Haswell-noTSX-IBRS", "host", "Icelake-Server", "Icelake-Server-noTSX", "Icelake-Server-v3",
+	"Icelake-Server-v4", "Icelake-Server-v5", "Icelake-Server-v6", "IvyBridge", "IvyBridge-IBRS",
+	"KnightsMill", "kvm32", "kvm64", "max", "Nehalem", "Nehalem-IBRS", "Opteron_G1", "Opteron_G2",
+	"Opteron_G3", "Opteron_G4", "Opteron_G5", "Penryn", "pentium", "pentium2", "pentium3", "phenom",
+	"qemu32", "qemu64", "SandyBridge", "SandyBridge-IBRS", "SapphireRapids", "SapphireRapids-v2",
+	"Skylake-Client", "Skylake-Client-IBRS", "Skylake-Client-noTSX-IBRS", "Skylake-Client-v4",
+	"Skylake-Server", "Skylake-Server-IBRS", "Skylake-Server-noTSX-IBRS", "Skylake-Server-v4",
+	"Skylake-Server-v5", "Westmere", "Westmere-IBRS", "x86-64-v2", "x86-64-v2-AES", "x86-64-v3", "x86-64-v4",
+}
+
+// customCPUType matches the custom CPU models of the cluster, defined in
+// /etc/pve/virtual-guest/cpu-models.conf.
+var customCPUType = regexp.MustCompile(`^custom-[A-Za-z0-9_.-]+$`)
+
+// cpuFlag matches a CPU flag turned on or off, e.g. `+aes` or `-pcid`.
+var cpuFlag = regexp.MustCompile(`^[+-][a-z0-9_.-]+$`)
+
+// vmCPUModel maps the CPU configuration of a VM.
+type vmCPUModel struct {
+	Cores   types.Int64    `tfsdk:"cores"`
+	Sockets types.Int64    `tfsdk:"sockets"`
+	Type    types.String   `tfsdk:"type"`
+	Flags   []types.String `tfsdk:"flags"`
+	Hidden  types.Bool     `tfsdk:"hidden"`
+	VCPUs   types.Int64    `tfsdk:"vcpus"`
+}
+
+// vmCPUAttributeTypes are the attribute types of the cpu attribute.
+var vmCPUAttributeTypes = map[string]attr.Type{
+	"cores":   types.Int64Type,
+	"sockets": types.Int64Type,
+	"type":    types.StringType,
+	"flags":   types.SetType{ElemType: types.StringType},
+	"hidden":  types.BoolType,
+	"vcpus":   types.Int64Type,
+}
+
+// vmCPUAttribute returns the schema attribute of the CPU configuration of a
+// VM.
+func vmCPUAttribute() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Computed: true,
+		Default: objectdefault.StaticValue(types.ObjectValueMust(vmCPUAttributeTypes, map[string]attr.Value{
+			"cores":   types.Int64Value(1),
+			"sockets": types.Int64Value(1),
+			"type":    types.StringNull(),
+			"flags":   types.SetNull(types.StringType),
+			"hidden":  types.BoolValue(false),
+			"vcpus":   types.Int64Null(),
+		})),
+		Description: "CPU topology and model of the VM. Defaults to a single core of the default model of the cluster. " +
+			"Changes to a running VM take effect the next time it starts, unless CPU hotplug is enabled",
+		Attributes: map[string]schema.Attribute{
+			"cores": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1),
+				Description: "Number of CPU cores per socket. Defaults to 1",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1024),
+				},
+			},
+			"sockets": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1),
+				Description: "Number of CPU sockets. Defaults to 1",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 16),
+				},
+			},
+			"type": schema.StringAttribute{
+				Optional: true,
+				Description: "Emulated CPU model, e.g. `host`, `x86-64-v2-AES` or a custom model of the cluster as " +
+					"`custom-<name>`. Defaults to the default model of the cluster",
+				Validators: []validator.String{
+					stringvalidator.Any(
+						stringvalidator.OneOf(vmCPUTypes...),
+						stringvalidator.RegexMatches(customCPUType, "must be a custom CPU model as custom-<name>"),
+					),
+				},
+			},
+			"flags": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "CPU flags turned on with + or off with -, e.g. `+aes` or `-pcid`",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(cpuFlag, "must be a CPU flag prefixed with + or -"),
+					),
+				},
+			},
+			"hidden": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "Hide the KVM hypervisor from the guest, for guest drivers and software that refuse to run " +
+					"virtualized. Defaults to false",
+			},
+			"vcpus": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of vCPUs plugged at start, at most cores times sockets. Defaults to all of them",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+// validateVMCPU adds an error when more vCPUs are plugged than the topology
+// of the CPU has.
+func validateVMCPU(cpu *vmCPUModel, diags *diag.Diagnostics) {
+	if cpu == nil || cpu.VCPUs.IsNull() || cpu.VCPUs.IsUnknown() || cpu.Cores.IsUnknown() || cpu.Sockets.IsUnknown() {
+		return
+	}
+
+	cores, sockets := int64(1), int64(1)
+	if !cpu.Cores.IsNull() {
+		cores = cpu.Cores.ValueInt64()
+	}
+	if !cpu.Sockets.IsNull() {
+		sockets = cpu.Sockets.ValueInt64()
+	}
+	if cpu.VCPUs.ValueInt64() > cores*sockets {
+		diags.AddAttributeError(
+			path.Root("cpu").AtName("vcpus"),
+			"Invalid VM vCPUs",
+			fmt.Sprintf("The VM has %d vCPUs, which exceeds its %d cores on %d sockets.", cpu.VCPUs.ValueInt64(), cores, sockets),
+		)
+	}
+}
+
+// validateVMKVM adds an error when the host CPU model is used without KVM,
+// as QEMU can only emulate the other models.
+func validateVMKVM(kvm types.Bool, cpu *vmCPUModel, diags *diag.Diagnostics) {
+	if kvm.IsNull() || kvm.IsUnknown() || kvm.ValueBool() || cpu == nil || cpu.Type.ValueString() != "host" {
+		return
+	}
+
+	diags.AddAttributeError(
+		path.Root("kvm"),
+		"Invalid VM KVM Setting",
+		"The host CPU model passes the CPU of the node through with KVM. Disabling kvm requires an emulated CPU model, e.g. qemu64 or max.",
+	)
+}
+
+// vmCPUParams returns the config options that apply cpu to a VM with the
+// current config, and the options to remove. The options of the cpu option
+// not managed by the resource, e.g. `hv-vendor-id`, are kept, and it is only
+// set when it changes.
+func vmCPUParams(cpu *vmCPUModel, config map[string]interface{}) (map[string]interface{}, []string) {
+	params := map[string]interface{}{
+		"cores":   cpu.Cores.ValueInt64(),
+		"sockets": cpu.Sockets.ValueInt64(),
+	}
+	var remove []string
+
+	current := proxmoxtf.ConfigValue(config["cpu"])
+	options, err := proxmoxtf.ParseOptions(current, "cputype")
+	if err != nil {
+		options = map[string]string{}
+	}
+	existing := make(map[string]string, len(options))
+	for key, value := range options {
+		existing[key] = value
+	}
+
+	delete(options, "cputype")
+	if !cpu.Type.IsNull() {
+		options["cputype"] = cpu.Type.ValueString()
+	}
+	delete(options, "flags")
+	if len(cpu.Flags) > 0 {
+		flags := make([]string, len(cpu.Flags))
+		for i, flag := range cpu.Flags {
+			flags[i] = flag.ValueString()
+		}
+		sort.Strings(flags)
+		options["flags"] = strings.Join(flags, ";")
+	}
+	delete(options, "hidden")
+	if cpu.Hidden.ValueBool() {
+		options["hidden"] = "1"
+	}
+	switch {
+	case len(options) == 0 && current != "":
+		remove = append(remove, "cpu")
+	case len(options) > 0 && !reflect.DeepEqual(options, existing):
+		params["cpu"] = proxmoxtf.FormatOptions(options, "cputype")
+	}
+
+	if !cpu.VCPUs.IsNull() {
+		params["vcpus"] = cpu.VCPUs.ValueInt64()
+	} else if _, ok := config["vcpus"]; ok {
+		remove = append(remove, "vcpus")
+	}
+
+	return params, remove
+}
+
+// parseVMCPU returns the CPU configuration of a VM config.
+func parseVMCPU(config map[string]interface{}) (*vmCPUModel, error) {
+	cpu := &vmCPUModel{
+		Type:  types.StringNull(),
+		VCPUs: types.Int64Null(),
+	}
+
+	var err error
+	if cpu.Cores, err = configInt64(config, "cores", 1); err != nil {
+		return nil, err
+	}
+	if cpu.Sockets, err = configInt64(config, "sockets", 1); err != nil {
+		return nil, err
+	}
+	if _, ok := config["vcpus"]; ok {
+		if cpu.VCPUs, err = configInt64(config, "vcpus", 0); err != nil {
+			return nil, err
+		}
+	}
+
+	options, err := proxmoxtf.ParseOptions(proxmoxtf.ConfigValue(config["cpu"]), "cputype")
+	if err != nil {
+		return nil, fmt.Errorf("cpu: %w", err)
+	}
+	cpu.Type = proxmoxtf.StringOrNull(options["cputype"])
+	cpu.Hidden = types.BoolValue(options["hidden"] == "1")
+	if flags := options["flags"]; flags != "" {
+		for _, flag := range strings.Split(flags, ";") {
+			cpu.Flags = append(cpu.Flags, types.StringValue(flag))
+		}
+	}
+
+	return cpu, nil
+}
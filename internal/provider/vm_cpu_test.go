@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseVMCPU(t *testing.T) {
+	cpu, err := parseVMCPU(map[string]interface{}{
+		"cores":   "4",
+		"sockets": float64(2),
+		"vcpus":   "6",
+		"cpu":     "custom-avx512,flags=+aes;-pcid,hidden=1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &vmCPUModel{
+		Cores:   types.Int64Value(4),
+		Sockets: types.Int64Value(2),
+		Type:    types.StringValue("custom-avx512"),
+		Flags:   []types.String{types.StringValue("+aes"), types.StringValue("-pcid")},
+		Hidden:  types.BoolValue(true),
+		VCPUs:   types.Int64Value(6),
+	}
+	if !reflect.DeepEqual(cpu, want) {
+		t.Errorf("got %+v, want %+v", cpu, want)
+	}
+
+	cpu, err = parseVMCPU(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &vmCPUModel{
+		Cores:   types.Int64Value(1),
+		Sockets: types.Int64Value(1),
+		Type:    types.StringNull(),
+		Hidden:  types.BoolValue(false),
+		VCPUs:   types.Int64Null(),
+	}
+	if !reflect.DeepEqual(cpu, want) {
+		t.Errorf("got %+v, want the defaults %+v", cpu, want)
+	}
+}
+
+func TestVMCPUParams(t *testing.T) {
+	cpu := &vmCPUModel{
+		Cores:   types.Int64Value(2),
+		Sockets: types.Int64Value(2),
+		Type:    types.StringValue("host"),
+		VCPUs:   types.Int64Null(),
+	}
+	flagged := *cpu
+	flagged.Flags = []types.String{types.StringValue("-pcid"), types.StringValue("+aes")}
+	flagged.VCPUs = types.Int64Value(3)
+	untyped := *cpu
+	untyped.Type = types.StringNull()
+	hidden := *cpu
+	hidden.Hidden = types.BoolValue(true)
+
+	tests := []struct {
+		name       string
+		cpu        *vmCPUModel
+		config     map[string]interface{}
+		wantParams map[string]interface{}
+		wantRemove []string
+	}{
+		{
+			name:       "unchanged",
+			cpu:        cpu,
+			config:     map[string]interface{}{"cpu": "host"},
+			wantParams: map[string]interface{}{"cores": int64(2), "sockets": int64(2)},
+		},
+		{
+			name:   "changed keeping unmanaged options",
+			cpu:    &flagged,
+			config: map[string]interface{}{"cpu": "kvm64,hv-vendor-id=proxmox"},
+			wantParams: map[string]interface{}{
+				"cores":   int64(2),
+				"sockets": int64(2),
+				"cpu":     "host,flags=+aes;-pcid,hv-vendor-id=proxmox",
+				"vcpus":   int64(3),
+			},
+		},
+		{
+			name:   "hidden",
+			cpu:    &hidden,
+			config: map[string]interface{}{"cpu": "host"},
+			wantParams: map[string]interface{}{
+				"cores":   int64(2),
+				"sockets": int64(2),
+				"cpu":     "host,hidden=1",
+			},
+		},
+		{
+			name:       "unhidden",
+			cpu:        cpu,
+			config:     map[string]interface{}{"cpu": "host,hidden=1"},
+			wantParams: map[string]interface{}{"cores": int64(2), "sockets": int64(2), "cpu": "host"},
+		},
+		{
+			name:       "removed",
+			cpu:        &untyped,
+			config:     map[string]interface{}{"cpu": "host", "vcpus": "2"},
+			wantParams: map[string]interface{}{"cores": int64(2), "sockets": int64(2)},
+			wantRemove: []string{"cpu", "vcpus"},
+		},
+	}
+
+	for _, tt := range tests {
+		params, remove := vmCPUParams(tt.cpu, tt.config)
+		if !reflect.DeepEqual(params, tt.wantParams) || !reflect.DeepEqual(remove, tt.wantRemove) {
+			t.Errorf("%s: got %v %v, want %v %v", tt.name, params, remove, tt.wantParams, tt.wantRemove)
+		}
+	}
+}
+
+func TestValidateVMCPU(t *testing.T) {
+	tests := []struct {
+		name    string
+		cpu     *vmCPUModel
+		wantErr bool
+	}{
+		{name: "unset", cpu: nil},
+		{name: "within topology", cpu: &vmCPUModel{Cores: types.Int64Value(2), Sockets: types.Int64Value(2), VCPUs: types.Int64Value(4)}},
+		{name: "default topology", cpu: &vmCPUModel{Cores: types.Int64Null(), Sockets: types.Int64Null(), VCPUs: types.Int64Value(2)}, wantErr: true},
+		{name: "exceeding topology", cpu: &vmCPUModel{Cores: types.Int64Value(4), Sockets: types.Int64Value(1), VCPUs: types.Int64Value(5)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var diags diag.Diagnostics
+		validateVMCPU(tt.cpu, &diags)
+		if diags.HasError() != tt.wantErr {
+			t.Errorf("%s: got %v", tt.name, diags)
+		}
+	}
+}
+
+func TestValidateVMKVM(t *testing.T) {
+	tests := []struct {
+		name    string
+		kvm     types.Bool
+		cpu     *vmCPUModel
+		wantErr bool
+	}{
+		{name: "host with kvm", kvm: types.BoolValue(true), cpu: &vmCPUModel{Type: types.StringValue("host")}},
+		{name: "emulated without kvm", kvm: types.BoolValue(false), cpu: &vmCPUModel{Type: types.StringValue("max")}},
+		{name: "default model without kvm", kvm: types.BoolValue(false), cpu: &vmCPUModel{Type: types.StringNull()}},
+		{name: "host without kvm", kvm: types.BoolValue(false), cpu: &vmCPUModel{Type: types.StringValue("host")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var diags diag.Diagnostics
+		validateVMKVM(tt.kvm, tt.cpu, &diags)
+		if diags.HasError() != tt.wantErr {
+			t.Errorf("%s: got %v", tt.name, diags)
+		}
+	}
+}
@@ -0,0 +1,581 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// diskInterface matches the drive interfaces a disk can be attached to.
+var diskInterface = regexp.MustCompile(`^(scsi([0-9]|[12][0-9]|30)|virtio([0-9]|1[0-5])|sata[0-5]|ide[0-3])$`)
+
+// vmDiskFormats are the image formats of VM disks, the file extensions of
+// the volumes of file based storages.
+var vmDiskFormats = []string{"raw", "qcow2", "vmdk"}
+
+// vmDiskModel maps a disk of the resource, keyed by its drive interface.
+type vmDiskModel struct {
+	Storage    types.String `tfsdk:"storage"`
+	Size       types.Int64  `tfsdk:"size"`
+	Format     types.String `tfsdk:"format"`
+	Cache      types.String `tfsdk:"cache"`
+	Discard    types.Bool   `tfsdk:"discard"`
+	SSD        types.Bool   `tfsdk:"ssd"`
+	IOThread   types.Bool   `tfsdk:"iothread"`
+	Backup     types.Bool   `tfsdk:"backup"`
+	Serial     types.String `tfsdk:"serial"`
+	ImportFrom types.String `tfsdk:"import_from"`
+}
+
+// vmDisksAttribute returns the schema attribute of the disks of a VM.
+func vmDisksAttribute() schema.Attribute {
+	return schema.MapNestedAttribute{
+		Optional: true,
+		Description: "Disks of the VM by drive interface, e.g. `{ scsi0 = { storage = \"local-lvm\", size = 32 } }`. Disks are " +
+			"added, moved to another storage or format, grown and removed in place, removed disks are destroyed. " +
+			"Disks of a cloned source that are not listed are removed. CD-ROM drives, cloud-init drives and passed through " +
+			"devices are not managed here. Required unless the VM boots from the network first",
+		Validators: []validator.Map{
+			mapvalidator.SizeAtLeast(1),
+			mapvalidator.KeysAre(
+				stringvalidator.RegexMatches(diskInterface, "must be a drive interface from scsi0-30, virtio0-15, sata0-5 or ide0-3"),
+			),
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"storage": schema.StringAttribute{
+					Optional: true,
+					Computed: true,
+					Description: "Storage the disk is allocated on, changing it moves the disk. Required unless the disk " +
+						"is cloned, cloned disks stay on the storage of their source or on the target_storage of the clone",
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
+					},
+				},
+				"size": schema.Int64Attribute{
+					Optional:    true,
+					Computed:    true,
+					Description: "Size of the disk in GiB. Required unless the disk is cloned or imported, which default to their size. Growing a disk resizes it in place, disks cannot shrink",
+					Validators: []validator.Int64{
+						int64validator.AtLeast(1),
+					},
+					PlanModifiers: []planmodifier.Int64{
+						int64planmodifier.UseStateForUnknown(),
+					},
+				},
+				"format": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Description: "Image format of the disk, one of raw, qcow2 or vmdk, changing it converts the disk. Defaults to the format of the storage",
+					Validators: []validator.String{
+						stringvalidator.OneOf(vmDiskFormats...),
+					},
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
+					},
+				},
+				"cache": schema.StringAttribute{
+					Optional:    true,
+					Description: "Cache mode of the disk, one of none, writethrough, writeback, unsafe or directsync. Defaults to the QEMU default",
+					Validators: []validator.String{
+						stringvalidator.OneOf("none", "writethrough", "writeback", "unsafe", "directsync"),
+					},
+				},
+				"discard": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Pass discard requests of the guest on to the storage to free unused blocks. Defaults to false",
+				},
+				"ssd": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Present the disk as a solid-state drive, not available on virtio disks. Defaults to false",
+				},
+				"iothread": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Run the I/O of the disk in its own thread, only available on scsi and virtio disks. Defaults to false",
+				},
+				"backup": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(true),
+					Description: "Include the disk in backups of the VM. Defaults to true",
+				},
+				"serial": schema.StringAttribute{
+					Optional:    true,
+					Description: "Serial number the guest sees on the disk",
+					Validators: []validator.String{
+						stringvalidator.LengthBetween(1, 20),
+					},
+				},
+				"import_from": schema.StringAttribute{
+					Optional: true,
+					Description: "Volume of a qcow2, raw or vmdk image the disk is imported from when it is created, e.g. " +
+						"`nfs:import/debian-12-generic-amd64.qcow2` of a cloud image pre-staged on a shared storage. The disk " +
+						"has the size of the image unless size is larger, changing it replaces the VM",
+					Validators: []validator.String{
+						stringvalidator.LengthAtLeast(1),
+					},
+				},
+			},
+		},
+	}
+}
+
+// vmDisks returns the options of the disks of a VM config by drive
+// interface, leaving out CD-ROM drives, cloud-init drives and passed through
+// devices.
+func vmDisks(config map[string]interface{}) map[string]string {
+	disks := make(map[string]string)
+	for key, value := range config {
+		if !diskInterface.MatchString(key) {
+			continue
+		}
+		options, err := proxmoxtf.ParseOptions(proxmoxtf.ConfigValue(value), "file")
+		if err != nil || options["media"] == "cdrom" || !strings.Contains(options["file"], ":") {
+			continue
+		}
+		disks[key] = proxmoxtf.ConfigValue(value)
+	}
+
+	return disks
+}
+
+// parseVMDisk returns the disk and the volume of a disk option of a VM
+// config, e.g. `local-lvm:vm-100-disk-0,iothread=1,size=32G`.
+func parseVMDisk(value string) (vmDiskModel, string, error) {
+	options, err := proxmoxtf.ParseOptions(value, "file")
+	if err != nil {
+		return vmDiskModel{}, "", err
+	}
+
+	volume := options["file"]
+	storage, name, ok := strings.Cut(volume, ":")
+	if !ok {
+		return vmDiskModel{}, "", fmt.Errorf("disk %q is not on a storage", value)
+	}
+	bytes, err := proxmoxtf.ParseSize(options["size"])
+	if err != nil {
+		return vmDiskModel{}, "", err
+	}
+
+	// Volumes of file based storages carry their format as extension, the
+	// others hold raw images.
+	format := options["format"]
+	if format == "" {
+		format = "raw"
+		for _, f := range vmDiskFormats {
+			if strings.HasSuffix(name, "."+f) {
+				format = f
+			}
+		}
+	}
+
+	return vmDiskModel{
+		Storage:  types.StringValue(storage),
+		Size:     types.Int64Value(bytes >> 30),
+		Format:   types.StringValue(format),
+		Cache:    proxmoxtf.StringOrNull(options["cache"]),
+		Discard:  types.BoolValue(options["discard"] == "on"),
+		SSD:      types.BoolValue(options["ssd"] == "1"),
+		IOThread: types.BoolValue(options["iothread"] == "1"),
+		Backup:   types.BoolValue(options["backup"] != "0"),
+		Serial:   proxmoxtf.StringOrNull(options["serial"]),
+	}, volume, nil
+}
+
+// vmDiskOptions returns the drive options of disk, besides its volume and
+// format.
+func vmDiskOptions(disk vmDiskModel) map[string]string {
+	options := make(map[string]string)
+	if !disk.Cache.IsNull() {
+		options["cache"] = disk.Cache.ValueString()
+	}
+	if disk.Discard.ValueBool() {
+		options["discard"] = "on"
+	}
+	if disk.SSD.ValueBool() {
+		options["ssd"] = "1"
+	}
+	if disk.IOThread.ValueBool() {
+		options["iothread"] = "1"
+	}
+	if !disk.Backup.ValueBool() {
+		options["backup"] = "0"
+	}
+	if !disk.Serial.IsNull() {
+		options["serial"] = disk.Serial.ValueString()
+	}
+
+	return options
+}
+
+// newVMDisk returns the option that allocates disk, e.g.
+// `local-lvm:32,discard=on`. Imported disks take the size of their image and
+// are grown to their size afterwards.
+func newVMDisk(disk vmDiskModel) string {
+	options := vmDiskOptions(disk)
+	options["file"] = fmt.Sprintf("%s:%d", disk.Storage.ValueString(), disk.Size.ValueInt64())
+	if !disk.ImportFrom.IsNull() {
+		options["file"] = disk.Storage.ValueString() + ":0"
+		options["import-from"] = disk.ImportFrom.ValueString()
+	}
+	if !disk.Format.IsNull() && !disk.Format.IsUnknown() {
+		options["format"] = disk.Format.ValueString()
+	}
+
+	return proxmoxtf.FormatOptions(options, "file")
+}
+
+// vmDiskParams returns the config options that add the planned disks missing
+// from the current config and change the drive options of the others, and
+// the disks of the config to remove. Only options that change are set, so
+// that a running VM does not get pending changes for unchanged disks.
+func vmDiskParams(disks map[string]vmDiskModel, config map[string]interface{}) (map[string]interface{}, []string, error) {
+	current := vmDisks(config)
+	params := make(map[string]interface{})
+
+	for key, disk := range disks {
+		value, ok := current[key]
+		if !ok {
+			sized := !disk.Size.IsNull() && !disk.Size.IsUnknown() || !disk.ImportFrom.IsNull()
+			if disk.Storage.IsNull() || disk.Storage.IsUnknown() || !sized {
+				return nil, nil, fmt.Errorf("disk %s does not exist yet, set its storage and its size or import_from to add it", key)
+			}
+			params[key] = newVMDisk(disk)
+			continue
+		}
+
+		existing, volume, err := parseVMDisk(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if options := vmDiskOptions(disk); !reflect.DeepEqual(options, vmDiskOptions(existing)) {
+			options["file"] = volume
+			params[key] = proxmoxtf.FormatOptions(options, "file")
+		}
+	}
+
+	var remove []string
+	for key := range current {
+		if _, ok := disks[key]; !ok {
+			remove = append(remove, key)
+		}
+	}
+	sort.Strings(remove)
+
+	return params, remove, nil
+}
+
+// validateVMDisks adds errors for drive options the interface of a disk does
+// not support.
+func validateVMDisks(disks map[string]vmDiskModel, diags *diag.Diagnostics) {
+	for key, disk := range disks {
+		if strings.HasPrefix(key, "virtio") && disk.SSD.ValueBool() {
+			diags.AddAttributeError(
+				path.Root("disks").AtMapKey(key).AtName("ssd"),
+				"Unsupported VM Disk Option",
+				"Virtio disks cannot emulate solid-state drives, use a scsi disk instead.",
+			)
+		}
+		if (strings.HasPrefix(key, "sata") || strings.HasPrefix(key, "ide")) && disk.IOThread.ValueBool() {
+			diags.AddAttributeError(
+				path.Root("disks").AtMapKey(key).AtName("iothread"),
+				"Unsupported VM Disk Option",
+				"Only scsi and virtio disks support I/O threads.",
+			)
+		}
+	}
+}
+
+// shrunkDisks returns the drive interfaces of the disks planned smaller than
+// they are in the state, sorted.
+func shrunkDisks(plan, state map[string]vmDiskModel) []string {
+	var shrunk []string
+	for key, disk := range plan {
+		prior, ok := state[key]
+		if ok && !disk.Size.IsUnknown() && !disk.Size.IsNull() && disk.Size.ValueInt64() < prior.Size.ValueInt64() {
+			shrunk = append(shrunk, key)
+		}
+	}
+	sort.Strings(shrunk)
+
+	return shrunk
+}
+
+// vmStorages are the storages and volumes of a VM with the content type
+// their storage must hold.
+var vmStorages = []struct {
+	expression path.Expression
+	content    string
+}{
+	{expression: path.MatchRoot("disks").AtAnyMapKey().AtName("storage"), content: "images"},
+	{expression: path.MatchRoot("disks").AtAnyMapKey().AtName("import_from"), content: "import"},
+	{expression: path.MatchRoot("cloud_init").AtName("storage"), content: "images"},
+	{expression: path.MatchRoot("clone").AtName("target_storage"), content: "images"},
+	{expression: path.MatchRoot("vmstatestorage"), content: "images"},
+}
+
+// planDisks rejects plans that shrink a disk, which Proxmox cannot do,
+// replaces the VM when a disk is to be imported from another image, and
+// leaves the format of disks moved to another storage without a configured
+// format to the target storage. Growing disks are resized in place.
+func planDisks(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned, configured types.Map
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("disks"), &planned)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("disks"), &configured)...)
+	if resp.Diagnostics.HasError() || planned.IsUnknown() || configured.IsUnknown() {
+		return
+	}
+
+	var plan, config, state map[string]vmDiskModel
+	resp.Diagnostics.Append(planned.ElementsAs(ctx, &plan, false)...)
+	resp.Diagnostics.Append(configured.ElementsAs(ctx, &config, false)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("disks"), &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, key := range shrunkDisks(plan, state) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("disks").AtMapKey(key).AtName("size"),
+			"VM Disk Cannot Shrink",
+			fmt.Sprintf("The %s disk has %d GiB and cannot shrink to %d GiB. Keep its size, or replace the VM, e.g. with terraform apply -replace, to recreate it smaller.",
+				key, state[key].Size.ValueInt64(), plan[key].Size.ValueInt64()),
+		)
+	}
+	for key, disk := range plan {
+		prior, ok := state[key]
+		if !ok {
+			continue
+		}
+		if !disk.ImportFrom.IsNull() && !disk.ImportFrom.Equal(prior.ImportFrom) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("disks").AtMapKey(key).AtName("import_from"))
+		}
+		if !disk.Storage.IsUnknown() && !disk.Storage.Equal(prior.Storage) && config[key].Format.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("disks").AtMapKey(key).AtName("format"), types.StringUnknown())...)
+		}
+	}
+}
+
+// updateDisks moves and grows the disks that the VM had, in its config before
+// the update, on another storage or format or with a smaller size than
+// planned, and grows the disks the update imported. The disks removed by the
+// update are only detached by the config update and destroyed here.
+func (c *apiClient) updateDisks(ctx context.Context, node string, vmid int64, disks map[string]vmDiskModel, config map[string]interface{}) error {
+	current := vmDisks(config)
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var removed []string
+	for _, key := range keys {
+		existing, volume, err := parseVMDisk(current[key])
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		disk, ok := disks[key]
+		if !ok {
+			removed = append(removed, volume)
+			continue
+		}
+
+		storage, format := disk.Storage.ValueString(), disk.Format.ValueString()
+		if disk.Storage.IsUnknown() {
+			storage = existing.Storage.ValueString()
+		}
+		if disk.Format.IsUnknown() || disk.Format.IsNull() {
+			format = ""
+		}
+		if storage != existing.Storage.ValueString() || format != "" && format != existing.Format.ValueString() {
+			if err := c.moveDisk(ctx, node, vmid, key, storage, format); err != nil {
+				return err
+			}
+		}
+		if !disk.Size.IsUnknown() && disk.Size.ValueInt64() > existing.Size.ValueInt64() {
+			if err := c.resizeDisk(ctx, node, vmid, key, disk.Size.ValueInt64()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := c.growImportedDisks(ctx, node, vmid, disks, current); err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		return nil
+	}
+	return c.destroyUnusedDisks(ctx, node, vmid, removed)
+}
+
+// importedDisks returns the planned disks imported from an image that the
+// current disks of the VM do not have yet, sorted.
+func importedDisks(disks map[string]vmDiskModel, current map[string]string) []string {
+	var imported []string
+	for key, disk := range disks {
+		if _, ok := current[key]; !ok && !disk.ImportFrom.IsNull() {
+			imported = append(imported, key)
+		}
+	}
+	sort.Strings(imported)
+
+	return imported
+}
+
+// growImportedDisks grows the disks imported since the VM had the current
+// disks to their planned size. Images larger than the planned size are an
+// error, as disks cannot shrink.
+func (c *apiClient) growImportedDisks(ctx context.Context, node string, vmid int64, disks map[string]vmDiskModel, current map[string]string) error {
+	imported := importedDisks(disks, current)
+	if len(imported) == 0 {
+		return nil
+	}
+
+	config, err := c.api.VMConfig(ctx, node, vmid)
+	if err != nil {
+		return err
+	}
+	for _, key := range imported {
+		disk := disks[key]
+		if disk.Size.IsNull() || disk.Size.IsUnknown() {
+			continue
+		}
+		actual, _, err := parseVMDisk(proxmoxtf.ConfigValue(config[key]))
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		switch size := disk.Size.ValueInt64(); {
+		case size < actual.Size.ValueInt64():
+			return fmt.Errorf("the image %s imported as the %s disk has %d GiB, more than the planned size of %d GiB",
+				disk.ImportFrom.ValueString(), key, actual.Size.ValueInt64(), size)
+		case size > actual.Size.ValueInt64():
+			if err := c.resizeDisk(ctx, node, vmid, key, size); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateVMConfig applies params to the config of a VM. Config updates that
+// import a disk image run as a task, as the import may outlast an API
+// request.
+func (c *apiClient) updateVMConfig(ctx context.Context, node string, vmid int64, params map[string]interface{}) error {
+	configPath := guestPath(node, "qemu", vmid) + "/config"
+	for _, value := range params {
+		if !strings.Contains(fmt.Sprint(value), "import-from=") {
+			continue
+		}
+
+		var upid string
+		if err := c.Post(ctx, configPath, params, &upid); err != nil || upid == "" {
+			return err
+		}
+		_, err := c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+		return err
+	}
+
+	return c.Put(ctx, configPath, params, nil)
+}
+
+// moveDisk moves a disk of a VM to storage, converting it to format unless
+// format is empty.
+func (c *apiClient) moveDisk(ctx context.Context, node string, vmid int64, disk, storage, format string) error {
+	tflog.SubsystemInfo(ctx, logVM, "Moving VM disk", map[string]interface{}{
+		"disk":    disk,
+		"storage": storage,
+		"format":  format,
+	})
+
+	upid, err := c.api.MoveVMDisk(ctx, node, vmid, pveapi.MoveVMDiskRequest{Disk: disk, Storage: storage, Format: format, Delete: 1})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
+
+// resizeDisk grows a disk of a VM to size GiB.
+func (c *apiClient) resizeDisk(ctx context.Context, node string, vmid int64, disk string, size int64) error {
+	tflog.SubsystemInfo(ctx, logVM, "Resizing VM disk", map[string]interface{}{
+		"disk": disk,
+		"size": size,
+	})
+
+	upid, err := c.api.ResizeVMDisk(ctx, node, vmid, disk, proxmoxtf.FormatSize(size<<30))
+	if err != nil || upid == "" {
+		return err
+	}
+
+	_, err = c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
+
+// destroyUnusedDisks destroys the volumes that a VM keeps as unused disks
+// after they were detached. Disks a running VM cannot unplug stay attached
+// until it restarts and are left as unused disks then.
+func (c *apiClient) destroyUnusedDisks(ctx context.Context, node string, vmid int64, volumes []string) error {
+	config, err := c.api.VMConfig(ctx, node, vmid)
+	if err != nil {
+		return err
+	}
+
+	var unused []string
+	for key, value := range config {
+		if !strings.HasPrefix(key, "unused") {
+			continue
+		}
+		for _, volume := range volumes {
+			if proxmoxtf.ConfigValue(value) == volume {
+				unused = append(unused, key)
+			}
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+	sort.Strings(unused)
+
+	tflog.SubsystemInfo(ctx, logVM, "Destroying detached VM disks", map[string]interface{}{
+		"disks": unused,
+	})
+
+	configPath := guestPath(node, "qemu", vmid) + "/config"
+	return c.Put(ctx, configPath, map[string]interface{}{"delete": strings.Join(unused, ",")}, nil)
+}
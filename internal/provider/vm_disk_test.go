@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseVMDisk(t *testing.T) {
+	disk, volume, err := parseVMDisk("local:100/vm-100-disk-1.qcow2,cache=writeback,discard=on,iothread=1,serial=data,size=32G")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := vmDiskModel{
+		Storage:  types.StringValue("local"),
+		Size:     types.Int64Value(32),
+		Format:   types.StringValue("qcow2"),
+		Cache:    types.StringValue("writeback"),
+		Discard:  types.BoolValue(true),
+		SSD:      types.BoolValue(false),
+		IOThread: types.BoolValue(true),
+		Backup:   types.BoolValue(true),
+		Serial:   types.StringValue("data"),
+	}
+	if volume != "local:100/vm-100-disk-1.qcow2" || !reflect.DeepEqual(disk, want) {
+		t.Errorf("got %q, %+v, want %+v", volume, disk, want)
+	}
+
+	disk, _, err = parseVMDisk("local-lvm:vm-100-disk-0,backup=0,size=8G")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disk.Format.ValueString() != "raw" || disk.Backup.ValueBool() {
+		t.Errorf("got format %s, backup %s", disk.Format, disk.Backup)
+	}
+
+	if _, _, err := parseVMDisk("/dev/disk/by-id/ata-disk,size=500G"); err == nil {
+		t.Error("expected an error for a passed through disk")
+	}
+}
+
+func TestVMDisks(t *testing.T) {
+	config := map[string]interface{}{
+		"scsi0":   "local-lvm:vm-100-disk-0,size=32G",
+		"scsi1":   "/dev/disk/by-id/ata-disk,size=500G",
+		"ide2":    "local:iso/debian.iso,media=cdrom",
+		"ide0":    "local-lvm:vm-100-cloudinit,media=cdrom",
+		"unused0": "local-lvm:vm-100-disk-1",
+		"net0":    "virtio=BC:24:11:00:00:01,bridge=vmbr0",
+	}
+
+	want := map[string]string{"scsi0": "local-lvm:vm-100-disk-0,size=32G"}
+	if got := vmDisks(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestVMDiskParams(t *testing.T) {
+	disk := vmDiskModel{
+		Storage:  types.StringValue("local-lvm"),
+		Size:     types.Int64Value(32),
+		Format:   types.StringValue("raw"),
+		Cache:    types.StringNull(),
+		Discard:  types.BoolValue(false),
+		SSD:      types.BoolValue(false),
+		IOThread: types.BoolValue(true),
+		Backup:   types.BoolValue(true),
+		Serial:   types.StringNull(),
+	}
+	config := map[string]interface{}{
+		"scsi0": "local-lvm:vm-100-disk-0,iothread=1,size=32G",
+		"scsi1": "local-lvm:vm-100-disk-1,size=8G",
+		"scsi2": "local-lvm:vm-100-disk-2,size=8G",
+	}
+
+	discard := disk
+	discard.Discard = types.BoolValue(true)
+	tests := []struct {
+		name       string
+		disks      map[string]vmDiskModel
+		wantParams map[string]interface{}
+		wantRemove []string
+		wantErr    bool
+	}{
+		{
+			name:       "unchanged and removed",
+			disks:      map[string]vmDiskModel{"scsi0": disk},
+			wantParams: map[string]interface{}{},
+			wantRemove: []string{"scsi1", "scsi2"},
+		},
+		{
+			name:  "changed and added",
+			disks: map[string]vmDiskModel{"scsi0": disk, "scsi1": discard, "scsi2": disk, "virtio0": disk},
+			wantParams: map[string]interface{}{
+				"scsi1":   "local-lvm:vm-100-disk-1,discard=on,iothread=1",
+				"scsi2":   "local-lvm:vm-100-disk-2,iothread=1",
+				"virtio0": "local-lvm:32,format=raw,iothread=1",
+			},
+		},
+		{
+			name: "imported",
+			disks: map[string]vmDiskModel{
+				"scsi0": disk, "scsi1": disk, "scsi2": disk,
+				"virtio0": {Storage: types.StringValue("local-lvm"), Size: types.Int64Unknown(), Format: types.StringUnknown(), Backup: types.BoolValue(true), ImportFrom: types.StringValue("nfs:import/debian-12.qcow2")},
+			},
+			wantParams: map[string]interface{}{
+				"scsi1":   "local-lvm:vm-100-disk-1,iothread=1",
+				"scsi2":   "local-lvm:vm-100-disk-2,iothread=1",
+				"virtio0": "local-lvm:0,import-from=nfs:import/debian-12.qcow2",
+			},
+		},
+		{
+			name:    "added without storage",
+			disks:   map[string]vmDiskModel{"scsi3": {Storage: types.StringUnknown(), Size: types.Int64Value(8)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		params, remove, err := vmDiskParams(tt.disks, config)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v", tt.name, err)
+			continue
+		}
+		if !tt.wantErr && (!reflect.DeepEqual(params, tt.wantParams) || !reflect.DeepEqual(remove, tt.wantRemove)) {
+			t.Errorf("%s: got %v %v, want %v %v", tt.name, params, remove, tt.wantParams, tt.wantRemove)
+		}
+	}
+}
+
+func TestShrunkDisks(t *testing.T) {
+	state := map[string]vmDiskModel{
+		"scsi0": {Size: types.Int64Value(32)},
+		"scsi1": {Size: types.Int64Value(100)},
+		"scsi2": {Size: types.Int64Value(8)},
+	}
+	plan := map[string]vmDiskModel{
+		"scsi0": {Size: types.Int64Value(40)},
+		"scsi1": {Size: types.Int64Value(50)},
+		"scsi2": {Size: types.Int64Unknown()},
+		"scsi3": {Size: types.Int64Value(1)},
+	}
+
+	if got, want := shrunkDisks(plan, state), []string{"scsi1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestImportedDisks(t *testing.T) {
+	disks := map[string]vmDiskModel{
+		"scsi0":   {ImportFrom: types.StringValue("nfs:import/debian-12.qcow2")},
+		"scsi1":   {ImportFrom: types.StringNull()},
+		"virtio0": {ImportFrom: types.StringValue("nfs:import/data.raw")},
+	}
+	current := map[string]string{"scsi0": "local-lvm:vm-100-disk-0,size=2G"}
+
+	if got, want := importedDisks(disks, current), []string{"virtio0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := importedDisks(disks, nil), []string{"scsi0", "virtio0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v for a new VM", got, want)
+	}
+}
@@ -0,0 +1,460 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &vmImportResource{}
+	_ resource.ResourceWithConfigure  = &vmImportResource{}
+	_ resource.ResourceWithModifyPlan = &vmImportResource{}
+)
+
+// NewVmImportResource is a helper function to simplify the provider implementation.
+func NewVmImportResource() resource.Resource {
+	return &vmImportResource{}
+}
+
+// vmImportResource imports a VM from import storage, e.g. an ESXi host.
+type vmImportResource struct {
+	client *apiClient
+}
+
+// vmImportResourceModel maps the resource schema data.
+type vmImportResourceModel struct {
+	Node                 types.String `tfsdk:"node"`
+	Source               types.String `tfsdk:"source"`
+	TargetStorage        types.String `tfsdk:"target_storage"`
+	DiskStorage          types.Map    `tfsdk:"disk_storage"`
+	DiskFormat           types.String `tfsdk:"disk_format"`
+	Bridge               types.String `tfsdk:"bridge"`
+	Name                 types.String `tfsdk:"name"`
+	VMID                 types.Int64  `tfsdk:"vm_id"`
+	Disks                types.Map    `tfsdk:"disks"`
+	Start                types.Bool   `tfsdk:"start"`
+	WaitForStatus        types.String `tfsdk:"wait_for_status"`
+	WaitForStatusTimeout types.Int64  `tfsdk:"wait_for_status_timeout"`
+	KeepOnDestroy        types.Bool   `tfsdk:"keep_on_destroy"`
+}
+
+// importMetadata is the response of the storage import-metadata endpoint.
+type importMetadata struct {
+	CreateArgs map[string]interface{} `json:"create-args"`
+	Disks      map[string]struct {
+		VolID string `json:"volid"`
+	} `json:"disks"`
+	Net map[string]struct {
+		Model   string `json:"model"`
+		MacAddr string `json:"macaddr"`
+	} `json:"net"`
+	Warnings []struct {
+		Type string `json:"type"`
+		Key  string `json:"key"`
+	} `json:"warnings"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmImportResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmImportResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_import"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmImportResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Imports a VM from storage with the import content type, e.g. an ESXi host connected with proxmox_esxi_storage. " +
+			"Every change imports the VM again, destroying the resource stops and destroys the imported VM unless keep_on_destroy is set.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Node to import the VM to. Defaults to the default_node of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source": schema.StringAttribute{
+				Required:    true,
+				Description: "Volume ID of the guest to import, e.g. `esxi1:ha-datacenter/datastore1/web/web.vmx`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"target_storage": schema.StringAttribute{
+				Required:    true,
+				Description: "Storage the disks are imported to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_storage": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Storage of individual disks, keyed by disk, e.g. `scsi1`, overriding target_storage",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_format": schema.StringAttribute{
+				Optional:    true,
+				Description: "Format of the imported disks, one of raw, qcow2 or vmdk. Formats other than raw require file based storage. Defaults to the default format of the storage",
+				Validators: []validator.String{
+					stringvalidator.OneOf(diskFormats...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"bridge": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("vmbr0"),
+				Description: "Bridge the network interfaces are connected to. Defaults to vmbr0",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the imported VM, defaults to the name of the source",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the imported VM, allocated from the vmid_range of the provider when not set",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"disks": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Source volumes of the imported disks, keyed by disk of the imported VM",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"keep_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Leave the imported VM in place when the resource is destroyed, e.g. to hand it over to proxmox_vm. Defaults to false",
+			},
+		},
+	}
+
+	for name, attribute := range guestStartAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// importParams maps the import metadata onto the parameters of the VM
+// creation. Every disk is imported to its entry of diskStorage, or to
+// targetStorage, in format unless it is empty, and every network interface
+// is connected to bridge.
+func importParams(metadata importMetadata, vmid int64, targetStorage string, diskStorage map[string]string, format, bridge string) map[string]interface{} {
+	params := map[string]interface{}{}
+	for key, value := range metadata.CreateArgs {
+		params[key] = value
+	}
+	params["vmid"] = vmid
+
+	for key, disk := range metadata.Disks {
+		storage := targetStorage
+		if override, ok := diskStorage[key]; ok {
+			storage = override
+		}
+		params[key] = fmt.Sprintf("%s:0,import-from=%s", storage, disk.VolID)
+		if format != "" {
+			params[key] = fmt.Sprintf("%s,format=%s", params[key], format)
+		}
+	}
+
+	for key, net := range metadata.Net {
+		value := fmt.Sprintf("%s,bridge=%s", net.Model, bridge)
+		if net.MacAddr != "" {
+			value = fmt.Sprintf("%s=%s,bridge=%s", net.Model, net.MacAddr, bridge)
+		}
+		params[key] = value
+	}
+
+	return params
+}
+
+// importWarnings formats the warnings of the import metadata, sorted.
+func importWarnings(metadata importMetadata) []string {
+	var warnings []string
+	for _, warning := range metadata.Warnings {
+		if warning.Key != "" {
+			warnings = append(warnings, fmt.Sprintf("%s (%s)", warning.Type, warning.Key))
+		} else {
+			warnings = append(warnings, warning.Type)
+		}
+	}
+	sort.Strings(warnings)
+
+	return warnings
+}
+
+// importMetadata reads the import metadata of volume, a volume ID of the form
+// <storage>:<volume> on storage with the import content type.
+func (c *apiClient) importMetadata(ctx context.Context, node, volume string) (importMetadata, error) {
+	var metadata importMetadata
+
+	storage, _, _ := strings.Cut(volume, ":")
+	path := fmt.Sprintf("/nodes/%s/storage/%s/import-metadata?volume=%s", node, storage, url.QueryEscape(volume))
+	err := c.Get(ctx, path, &metadata)
+
+	return metadata, err
+}
+
+// ModifyPlan fills in the default_node of the provider and rejects guest IDs
+// outside of its vmid_range, disk formats the target storages do not support
+// and bridges that are vnets of SDN zones not deployed to the node.
+func (r *vmImportResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planDefaultNode(ctx, req, resp)
+	r.client.validateVMIDPlan(ctx, resp.Plan, &resp.Diagnostics)
+	r.client.validateDiskFormatPlan(ctx, resp.Plan, &resp.Diagnostics)
+	r.client.validateVnetNodePlan(ctx, resp.Plan, req.State, path.MatchRoot("bridge"), &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmImportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmImportResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diskStorage := map[string]string{}
+	diags = plan.DiskStorage.ElementsAs(ctx, &diskStorage, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source := plan.Source.ValueString()
+	if !strings.Contains(source, ":") {
+		resp.Diagnostics.AddError(
+			"Invalid Proxmox Import Source",
+			fmt.Sprintf("The source %q is not a volume ID of the form <storage>:<volume>.", source),
+		)
+		return
+	}
+
+	node := plan.Node.ValueString()
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+
+	release := r.client.acquireNode(ctx, node, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	metadata, err := r.client.importMetadata(ctx, node, source)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox Import Metadata",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	for _, warning := range importWarnings(metadata) {
+		resp.Diagnostics.AddWarning(
+			"Proxmox Import Warning",
+			fmt.Sprintf("Importing %s: %s", source, warning),
+		)
+	}
+
+	if plan.VMID.IsUnknown() {
+		vmid, err := r.client.nextVMID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Allocate Proxmox Guest ID",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		plan.VMID = types.Int64Value(vmid)
+	}
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+
+	params := importParams(metadata, plan.VMID.ValueInt64(), plan.TargetStorage.ValueString(), diskStorage, plan.DiskFormat.ValueString(), plan.Bridge.ValueString())
+	if !plan.Name.IsNull() {
+		params["name"] = plan.Name.ValueString()
+	}
+
+	tflog.SubsystemInfo(ctx, logVM, "Importing VM", map[string]interface{}{
+		"source": source,
+	})
+
+	var upid string
+	err = r.client.Post(ctx, fmt.Sprintf("/nodes/%s/qemu", node), params, &upid)
+	if err == nil {
+		_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Import Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	disks := map[string]string{}
+	for key, disk := range metadata.Disks {
+		disks[key] = disk.VolID
+	}
+	plan.Disks, diags = types.MapValueFrom(ctx, types.StringType, disks)
+	resp.Diagnostics.Append(diags...)
+
+	if plan.Start.ValueBool() {
+		timeout := time.Duration(plan.WaitForStatusTimeout.ValueInt64()) * time.Second
+		err = r.client.startGuest(ctx, plan.Node.ValueString(), "qemu", plan.VMID.ValueInt64(), plan.WaitForStatus.ValueString(), timeout)
+		if err != nil {
+			// Keep the created guest in the state, so that Terraform
+			// taints and replaces it instead of losing track of it.
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.AddError(
+				"Unable to Start Proxmox VM",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmImportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmImportResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config map[string]interface{}
+	err := r.client.Get(ctx, guestPath(state.Node.ValueString(), "qemu", state.VMID.ValueInt64())+"/config", &config)
+	if err != nil {
+		// The imported VM was removed, import it again.
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmImportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires a new import or only applies on creation,
+	// there is nothing to update in place.
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmImportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmImportResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.KeepOnDestroy.ValueBool() {
+		return
+	}
+
+	node, vmid := state.Node.ValueString(), state.VMID.ValueInt64()
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+
+	release := r.client.acquireNode(ctx, node, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	err := r.client.stopGuest(ctx, node, "qemu", vmid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Stop Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	err = r.client.destroyVM(ctx, node, vmid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
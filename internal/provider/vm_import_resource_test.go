@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestImportParams(t *testing.T) {
+	var metadata importMetadata
+	err := json.Unmarshal([]byte(`{
+		"create-args": {"name": "web", "memory": 4096, "ostype": "l26"},
+		"disks": {
+			"scsi0": {"volid": "esxi1:ha-datacenter/datastore1/web/web.vmdk"},
+			"scsi1": {"volid": "esxi1:ha-datacenter/datastore1/web/web_1.vmdk"}
+		},
+		"net": {"net0": {"model": "vmxnet3", "macaddr": "00:50:56:aa:bb:cc"}},
+		"warnings": [{"type": "nvme-unsupported", "key": "nvme0:0"}, {"type": "guest-is-running"}]
+	}`), &metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := importParams(metadata, 1000, "local-lvm", map[string]string{"scsi1": "ceph"}, "", "vmbr1")
+	want := map[string]interface{}{
+		"name":   "web",
+		"memory": float64(4096),
+		"ostype": "l26",
+		"vmid":   int64(1000),
+		"scsi0":  "local-lvm:0,import-from=esxi1:ha-datacenter/datastore1/web/web.vmdk",
+		"scsi1":  "ceph:0,import-from=esxi1:ha-datacenter/datastore1/web/web_1.vmdk",
+		"net0":   "vmxnet3=00:50:56:aa:bb:cc,bridge=vmbr1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got = importParams(metadata, 1000, "local", nil, "qcow2", "vmbr0")
+	if want := "local:0,import-from=esxi1:ha-datacenter/datastore1/web/web.vmdk,format=qcow2"; got["scsi0"] != want {
+		t.Errorf("got %v, want %v", got["scsi0"], want)
+	}
+
+	warnings := importWarnings(metadata)
+	wantWarnings := []string{"guest-is-running", "nvme-unsupported (nvme0:0)"}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("got %v, want %v", warnings, wantWarnings)
+	}
+}
+
+func TestVmImportResourceDelete(t *testing.T) {
+	tests := map[string]struct {
+		keepOnDestroy bool
+		wantRequests  []string
+	}{
+		"destroy": {wantRequests: []string{
+			"GET /nodes/pve/qemu/1000/status/current",
+			"POST /nodes/pve/qemu/1000/status/stop",
+			"DELETE /nodes/pve/qemu/1000",
+		}},
+		"keep on destroy": {keepOnDestroy: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeAPI{responses: map[string]string{
+				"GET /nodes/pve/qemu/1000/status/current": `{"status":"running"}`,
+				"POST /nodes/pve/qemu/1000/status/stop":   fmt.Sprintf("%q", testUPID),
+				"DELETE /nodes/pve/qemu/1000":             fmt.Sprintf("%q", testUPID),
+			}}
+			res := configuredResource(t, "proxmox_vm_import", api)
+			s := resourceSchema(t, res)
+			state := tfsdk.State{Schema: s, Raw: resourceValue(t, s, map[string]tftypes.Value{
+				"node":            tftypes.NewValue(tftypes.String, "pve"),
+				"source":          tftypes.NewValue(tftypes.String, "esxi1:ha-datacenter/datastore1/web/web.vmx"),
+				"target_storage":  tftypes.NewValue(tftypes.String, "local-lvm"),
+				"vm_id":           tftypes.NewValue(tftypes.Number, 1000),
+				"keep_on_destroy": tftypes.NewValue(tftypes.Bool, tt.keepOnDestroy),
+			})}
+
+			resp := resource.DeleteResponse{State: state}
+			res.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+
+			for _, request := range tt.wantRequests {
+				if api.index(request) < 0 {
+					t.Errorf("missing request %s: %v", request, api.requests)
+				}
+			}
+			if tt.keepOnDestroy && len(api.requests) > 0 {
+				t.Errorf("the kept VM was changed: %v", api.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &vmInstallMediaResource{}
+	_ resource.ResourceWithConfigure  = &vmInstallMediaResource{}
+	_ resource.ResourceWithModifyPlan = &vmInstallMediaResource{}
+)
+
+// cdromInterface matches the drive interfaces a CD-ROM can be attached to.
+var cdromInterface = regexp.MustCompile(`^(ide[0-3]|sata[0-5]|scsi([0-9]|[12][0-9]|30))$`)
+
+// NewVmInstallMediaResource is a helper function to simplify the provider implementation.
+func NewVmInstallMediaResource() resource.Resource {
+	return &vmInstallMediaResource{}
+}
+
+// vmInstallMediaResource manages the CD-ROM drives, boot order and extra
+// QEMU arguments of an existing VM for unattended installs.
+type vmInstallMediaResource struct {
+	client *apiClient
+}
+
+// vmInstallMediaResourceModel maps the resource schema data.
+type vmInstallMediaResourceModel struct {
+	Node      types.String `tfsdk:"node"`
+	VMID      types.Int64  `tfsdk:"vm_id"`
+	CDROMs    types.Map    `tfsdk:"cdroms"`
+	BootOrder types.List   `tfsdk:"boot_order"`
+	Args      types.String `tfsdk:"args"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmInstallMediaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmInstallMediaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_install_media"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmInstallMediaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the install media of an existing VM for unattended installs: CD-ROM drives with the " +
+			"installer and an answer file ISO, e.g. a Debian preseed, kickstart or Proxmox VE answer file, the boot " +
+			"order and extra QEMU arguments, e.g. kernel parameters. Destroying the resource removes the CD-ROM drives " +
+			"and the managed options. The changes take effect the next time the VM starts.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the VM runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the VM",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"cdroms": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "ISO images by drive interface, e.g. `{ ide2 = \"local:iso/debian.iso\", ide3 = \"local:iso/preseed.iso\" }`. " +
+					"Use none for an empty drive",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(
+						stringvalidator.RegexMatches(cdromInterface, "must be a drive interface from ide0-3, sata0-5 or scsi0-30"),
+					),
+				},
+			},
+			"boot_order": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Devices to boot from, in order, e.g. `[\"scsi0\", \"ide2\"]`",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"args": schema.StringAttribute{
+				Optional: true,
+				Description: "Extra arguments passed to QEMU, e.g. `-fw_cfg name=opt/answer,file=/root/answer.toml`. " +
+					"Only root@pam may set them",
+			},
+		},
+	}
+}
+
+// cdromValue returns the drive option of a CD-ROM with iso.
+func cdromValue(iso string) string {
+	return iso + ",media=cdrom"
+}
+
+// installMediaParams returns the parameters of the config update that applies
+// plan. Drives and options that state manages but plan no longer sets are
+// removed.
+func installMediaParams(ctx context.Context, plan, state vmInstallMediaResourceModel) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	var remove []string
+
+	planned := map[string]string{}
+	if diags := plan.CDROMs.ElementsAs(ctx, &planned, false); diags.HasError() {
+		return nil, fmt.Errorf("reading cdroms: %v", diags)
+	}
+	managed := map[string]string{}
+	if diags := state.CDROMs.ElementsAs(ctx, &managed, false); diags.HasError() {
+		return nil, fmt.Errorf("reading cdroms: %v", diags)
+	}
+	for drive, iso := range planned {
+		params[drive] = cdromValue(iso)
+	}
+	for drive := range managed {
+		if _, ok := planned[drive]; !ok {
+			remove = append(remove, drive)
+		}
+	}
+
+	if !plan.BootOrder.IsNull() {
+		var order []string
+		if diags := plan.BootOrder.ElementsAs(ctx, &order, false); diags.HasError() {
+			return nil, fmt.Errorf("reading boot_order: %v", diags)
+		}
+		params["boot"] = "order=" + strings.Join(order, ";")
+	} else if !state.BootOrder.IsNull() {
+		remove = append(remove, "boot")
+	}
+
+	if !plan.Args.IsNull() {
+		params["args"] = plan.Args.ValueString()
+	} else if !state.Args.IsNull() {
+		remove = append(remove, "args")
+	}
+
+	if len(remove) > 0 {
+		sort.Strings(remove)
+		params["delete"] = strings.Join(remove, ",")
+	}
+
+	return params, nil
+}
+
+// bootOrder returns the devices of the order property of a boot option.
+func bootOrder(value string) []string {
+	options, err := proxmoxtf.ParseOptions(value, "legacy")
+	if err != nil || options["order"] == "" {
+		return nil
+	}
+
+	return strings.Split(options["order"], ";")
+}
+
+// apply writes plan to the VM, removing what only state manages.
+func (r *vmInstallMediaResource) apply(ctx context.Context, plan, state vmInstallMediaResourceModel) error {
+	params, err := installMediaParams(ctx, plan, state)
+	if err != nil {
+		return err
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Applying VM install media")
+
+	path := guestPath(plan.Node.ValueString(), "qemu", plan.VMID.ValueInt64()) + "/config"
+	return r.client.Put(ctx, path, params, nil)
+}
+
+// readInstallMedia refreshes the managed settings of the model from the
+// config of the VM.
+func (r *vmInstallMediaResource) readInstallMedia(ctx context.Context, model *vmInstallMediaResourceModel) error {
+	config, err := r.client.api.VMConfig(ctx, model.Node.ValueString(), model.VMID.ValueInt64())
+	if err != nil {
+		return err
+	}
+
+	if !model.CDROMs.IsNull() {
+		managed := map[string]string{}
+		if diags := model.CDROMs.ElementsAs(ctx, &managed, false); diags.HasError() {
+			return fmt.Errorf("reading cdroms: %v", diags)
+		}
+
+		cdroms := map[string]string{}
+		for drive := range managed {
+			options, err := proxmoxtf.ParseOptions(proxmoxtf.ConfigValue(config[drive]), "file")
+			if err != nil {
+				return fmt.Errorf("%s: %w", drive, err)
+			}
+			// Drives replaced by a disk are no longer managed.
+			if options["file"] != "" && options["media"] == "cdrom" {
+				cdroms[drive] = options["file"]
+			}
+		}
+
+		value, diags := types.MapValueFrom(ctx, types.StringType, cdroms)
+		if diags.HasError() {
+			return fmt.Errorf("setting cdroms: %v", diags)
+		}
+		model.CDROMs = value
+	}
+
+	if !model.BootOrder.IsNull() {
+		model.BootOrder = types.ListNull(types.StringType)
+		if order := bootOrder(proxmoxtf.ConfigValue(config["boot"])); order != nil {
+			value, diags := types.ListValueFrom(ctx, types.StringType, order)
+			if diags.HasError() {
+				return fmt.Errorf("setting boot_order: %v", diags)
+			}
+			model.BootOrder = value
+		}
+	}
+
+	if !model.Args.IsNull() {
+		model.Args = proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["args"]))
+	}
+
+	return nil
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider.
+func (r *vmInstallMediaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmInstallMediaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmInstallMediaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Nothing is managed yet, so nothing is removed.
+	state := vmInstallMediaResourceModel{
+		CDROMs:    types.MapNull(types.StringType),
+		BootOrder: types.ListNull(types.StringType),
+		Args:      types.StringNull(),
+	}
+	err := r.apply(ctx, plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox VM Install Media",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmInstallMediaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmInstallMediaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.readInstallMedia(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM Install Media",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmInstallMediaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan, state vmInstallMediaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox VM Install Media",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmInstallMediaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmInstallMediaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan := state
+	plan.CDROMs = types.MapNull(types.StringType)
+	plan.BootOrder = types.ListNull(types.StringType)
+	plan.Args = types.StringNull()
+
+	err := r.apply(ctx, plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Remove Proxmox VM Install Media",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
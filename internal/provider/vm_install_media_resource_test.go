@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestInstallMediaParams(t *testing.T) {
+	installing := vmInstallMediaResourceModel{
+		CDROMs: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"ide2": types.StringValue("local:iso/debian.iso"),
+			"ide3": types.StringValue("local:iso/preseed.iso"),
+		}),
+		BootOrder: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("scsi0"),
+			types.StringValue("ide2"),
+		}),
+		Args: types.StringValue("-append auto=true"),
+	}
+	installed := vmInstallMediaResourceModel{
+		CDROMs: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"ide2": types.StringValue("none"),
+		}),
+		BootOrder: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("scsi0"),
+		}),
+		Args: types.StringNull(),
+	}
+
+	tests := map[string]struct {
+		plan, state vmInstallMediaResourceModel
+		want        map[string]interface{}
+	}{
+		"install": {
+			plan: installing,
+			state: vmInstallMediaResourceModel{
+				CDROMs:    types.MapNull(types.StringType),
+				BootOrder: types.ListNull(types.StringType),
+				Args:      types.StringNull(),
+			},
+			want: map[string]interface{}{
+				"ide2": "local:iso/debian.iso,media=cdrom",
+				"ide3": "local:iso/preseed.iso,media=cdrom",
+				"boot": "order=scsi0;ide2",
+				"args": "-append auto=true",
+			},
+		},
+		"installed": {
+			plan:  installed,
+			state: installing,
+			want: map[string]interface{}{
+				"ide2":   "none,media=cdrom",
+				"boot":   "order=scsi0",
+				"delete": "args,ide3",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := installMediaParams(context.Background(), tc.plan, tc.state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBootOrder(t *testing.T) {
+	cases := map[string][]string{
+		"order=scsi0;ide2;net0": {"scsi0", "ide2", "net0"},
+		"cdn":                   nil,
+		"":                      nil,
+	}
+
+	for value, want := range cases {
+		if got := bootOrder(value); !reflect.DeepEqual(got, want) {
+			t.Errorf("bootOrder(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// vmMemoryDevicesModel maps the shared memory and balloon devices of a VM.
+type vmMemoryDevicesModel struct {
+	IvshmemSize    types.Int64  `tfsdk:"ivshmem_size"`
+	IvshmemName    types.String `tfsdk:"ivshmem_name"`
+	Balloon        types.Bool   `tfsdk:"balloon"`
+	BalloonMinimum types.Int64  `tfsdk:"balloon_minimum"`
+}
+
+// vmMemoryDeviceAttributes returns the schema attributes of the inter-VM
+// shared memory (ivshmem) and memory balloon devices of a VM.
+func vmMemoryDeviceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"ivshmem_size": schema.Int64Attribute{
+			Optional: true,
+			Description: "Size of the inter-VM shared memory segment in MiB, e.g. for Looking Glass on GPU passthrough " +
+				"VMs. No segment is attached when not set",
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+		"ivshmem_name": schema.StringAttribute{
+			Optional: true,
+			Description: "Name of the shared memory segment, which appears as /dev/shm/pve-shm-<name> on the node. " +
+				"Defaults to the ID of the VM",
+			Validators: []validator.String{
+				stringvalidator.AlsoRequires(path.MatchRoot("ivshmem_size")),
+			},
+		},
+		"balloon": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(true),
+			Description: "Whether the VM has a memory balloon device. Defaults to true",
+		},
+		"balloon_minimum": schema.Int64Attribute{
+			Optional:    true,
+			Description: "Amount of memory in MiB the balloon may shrink the VM to. Defaults to the memory of the VM",
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+	}
+}
+
+// validateVMMemoryDevices adds an error when a balloon minimum is set without
+// a balloon device or above the memory of the VM, which is null when it is
+// not known.
+func validateVMMemoryDevices(devices vmMemoryDevicesModel, memory types.Int64, diags *diag.Diagnostics) {
+	if devices.BalloonMinimum.IsNull() || devices.BalloonMinimum.IsUnknown() {
+		return
+	}
+
+	if !devices.Balloon.IsNull() && !devices.Balloon.IsUnknown() && !devices.Balloon.ValueBool() {
+		diags.AddAttributeError(
+			path.Root("balloon_minimum"),
+			"Balloon Minimum Without Balloon",
+			"The balloon minimum is a setting of the balloon device, which is removed with balloon = false.",
+		)
+	}
+	if !memory.IsNull() && !memory.IsUnknown() && devices.BalloonMinimum.ValueInt64() > memory.ValueInt64() {
+		diags.AddAttributeError(
+			path.Root("balloon_minimum"),
+			"Balloon Minimum Above Memory",
+			fmt.Sprintf("The balloon minimum of %d MiB is above the %d MiB of memory of the VM, the balloon can only shrink it.",
+				devices.BalloonMinimum.ValueInt64(), memory.ValueInt64()),
+		)
+	}
+}
+
+// vmMemoryDeviceParams returns the config options that apply devices to a VM
+// with the current config, and the options to remove so that the VM falls
+// back to its defaults.
+func vmMemoryDeviceParams(devices vmMemoryDevicesModel, config map[string]interface{}) (map[string]interface{}, []string) {
+	params := map[string]interface{}{}
+	var remove []string
+
+	if devices.IvshmemSize.IsNull() {
+		if _, ok := config["ivshmem"]; ok {
+			remove = append(remove, "ivshmem")
+		}
+	} else {
+		ivshmem := "size=" + strconv.FormatInt(devices.IvshmemSize.ValueInt64(), 10)
+		if devices.IvshmemName.ValueString() != "" {
+			ivshmem += ",name=" + devices.IvshmemName.ValueString()
+		}
+		params["ivshmem"] = ivshmem
+	}
+
+	_, ballooned := config["balloon"]
+	switch {
+	case !devices.Balloon.IsNull() && !devices.Balloon.ValueBool():
+		// A balloon target of 0 disables the device.
+		params["balloon"] = 0
+	case !devices.BalloonMinimum.IsNull():
+		params["balloon"] = devices.BalloonMinimum.ValueInt64()
+	case ballooned:
+		remove = append(remove, "balloon")
+	}
+
+	return params, remove
+}
+
+// parseIvshmem returns the size and name of the ivshmem option of a VM
+// config, e.g. `size=32,name=looking-glass`.
+func parseIvshmem(value string) (int64, string, error) {
+	var size int64
+	var name string
+	for _, property := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(property, "=")
+		switch key {
+		case "size":
+			parsed, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return 0, "", fmt.Errorf("invalid ivshmem size %q: %w", val, err)
+			}
+			size = parsed
+		case "name":
+			name = val
+		}
+	}
+
+	return size, name, nil
+}
+
+// parseVMMemoryDevices returns the memory devices of a VM config.
+func parseVMMemoryDevices(config map[string]interface{}) (vmMemoryDevicesModel, error) {
+	devices := vmMemoryDevicesModel{
+		IvshmemSize:    types.Int64Null(),
+		IvshmemName:    types.StringNull(),
+		Balloon:        types.BoolValue(true),
+		BalloonMinimum: types.Int64Null(),
+	}
+
+	if value, ok := config["ivshmem"]; ok {
+		size, name, err := parseIvshmem(proxmoxtf.ConfigValue(value))
+		if err != nil {
+			return devices, err
+		}
+		devices.IvshmemSize = types.Int64Value(size)
+		// The name is only tracked when it was set, as the API leaves it
+		// out when it defaults to the ID of the VM.
+		if name != "" {
+			devices.IvshmemName = types.StringValue(name)
+		}
+	}
+
+	if value, ok := config["balloon"]; ok {
+		minimum, err := strconv.ParseInt(proxmoxtf.ConfigValue(value), 10, 64)
+		if err != nil {
+			return devices, fmt.Errorf("invalid balloon %q: %w", proxmoxtf.ConfigValue(value), err)
+		}
+		if minimum == 0 {
+			devices.Balloon = types.BoolValue(false)
+		} else {
+			devices.BalloonMinimum = types.Int64Value(minimum)
+		}
+	}
+
+	return devices, nil
+}
@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &vmMemoryDevicesResource{}
+	_ resource.ResourceWithConfigure      = &vmMemoryDevicesResource{}
+	_ resource.ResourceWithModifyPlan     = &vmMemoryDevicesResource{}
+	_ resource.ResourceWithValidateConfig = &vmMemoryDevicesResource{}
+)
+
+// NewVmMemoryDevicesResource is a helper function to simplify the provider implementation.
+func NewVmMemoryDevicesResource() resource.Resource {
+	return &vmMemoryDevicesResource{}
+}
+
+// vmMemoryDevicesResource manages the shared memory and balloon devices of
+// an existing VM.
+type vmMemoryDevicesResource struct {
+	client *apiClient
+}
+
+// vmMemoryDevicesResourceModel maps the resource schema data.
+type vmMemoryDevicesResourceModel struct {
+	Node types.String `tfsdk:"node"`
+	VMID types.Int64  `tfsdk:"vm_id"`
+	vmMemoryDevicesModel
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmMemoryDevicesResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmMemoryDevicesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_memory_devices"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmMemoryDevicesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the inter-VM shared memory (ivshmem) and memory balloon devices of an existing VM, " +
+			"e.g. the shared memory segment Looking Glass needs on GPU passthrough VMs. " +
+			"Destroying the resource removes the shared memory and restores the default balloon device. " +
+			"The changes take effect the next time the VM starts.",
+		DeprecationMessage: "Use the ivshmem_size, ivshmem_name, balloon and balloon_minimum attributes of proxmox_vm instead, " +
+			"which manage the memory devices together with the VM.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the VM runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the VM",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+	for name, attribute := range vmMemoryDeviceAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// ValidateConfig rejects a balloon target without a balloon device.
+func (r *vmMemoryDevicesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config vmMemoryDevicesResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateVMMemoryDevices(config.vmMemoryDevicesModel, types.Int64Null(), &resp.Diagnostics)
+}
+
+// apply writes the memory devices of plan to the VM.
+func (r *vmMemoryDevicesResource) apply(ctx context.Context, plan vmMemoryDevicesResourceModel) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Applying VM memory devices")
+
+	path := guestPath(plan.Node.ValueString(), "qemu", plan.VMID.ValueInt64()) + "/config"
+	var config map[string]interface{}
+	if err := r.client.Get(ctx, path, &config); err != nil {
+		return err
+	}
+
+	params, remove := vmMemoryDeviceParams(plan.vmMemoryDevicesModel, config)
+	if len(remove) > 0 {
+		sort.Strings(remove)
+		params["delete"] = strings.Join(remove, ",")
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	return r.client.Put(ctx, path, params, nil)
+}
+
+// readMemoryDevices refreshes the memory devices of the model from the
+// config of the VM.
+func (r *vmMemoryDevicesResource) readMemoryDevices(ctx context.Context, model *vmMemoryDevicesResourceModel) error {
+	var config map[string]interface{}
+	path := guestPath(model.Node.ValueString(), "qemu", model.VMID.ValueInt64()) + "/config"
+	if err := r.client.Get(ctx, path, &config); err != nil {
+		return err
+	}
+
+	devices, err := parseVMMemoryDevices(config)
+	if err != nil {
+		return err
+	}
+	model.vmMemoryDevicesModel = devices
+
+	return nil
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider.
+func (r *vmMemoryDevicesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmMemoryDevicesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmMemoryDevicesResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox VM Memory Devices",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmMemoryDevicesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmMemoryDevicesResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.readMemoryDevices(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM Memory Devices",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmMemoryDevicesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmMemoryDevicesResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox VM Memory Devices",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmMemoryDevicesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmMemoryDevicesResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path := guestPath(state.Node.ValueString(), "qemu", state.VMID.ValueInt64()) + "/config"
+	err := r.client.Put(ctx, path, map[string]interface{}{"delete": "balloon,ivshmem"}, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Remove Proxmox VM Memory Devices",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
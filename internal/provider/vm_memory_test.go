@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestVMMemoryDeviceParams(t *testing.T) {
+	defaults := vmMemoryDevicesModel{
+		IvshmemSize:    types.Int64Null(),
+		IvshmemName:    types.StringNull(),
+		Balloon:        types.BoolValue(true),
+		BalloonMinimum: types.Int64Null(),
+	}
+
+	tests := map[string]struct {
+		devices    vmMemoryDevicesModel
+		config     map[string]interface{}
+		wantParams map[string]interface{}
+		wantRemove []string
+	}{
+		"defaults": {
+			devices:    defaults,
+			wantParams: map[string]interface{}{},
+		},
+		"removed devices": {
+			devices:    defaults,
+			config:     map[string]interface{}{"ivshmem": "size=32", "balloon": "1024"},
+			wantParams: map[string]interface{}{},
+			wantRemove: []string{"ivshmem", "balloon"},
+		},
+		"looking glass": {
+			devices: vmMemoryDevicesModel{
+				IvshmemSize:    types.Int64Value(64),
+				IvshmemName:    types.StringValue("looking-glass"),
+				Balloon:        types.BoolValue(false),
+				BalloonMinimum: types.Int64Null(),
+			},
+			config:     map[string]interface{}{"balloon": "1024"},
+			wantParams: map[string]interface{}{"ivshmem": "size=64,name=looking-glass", "balloon": 0},
+		},
+		"balloon minimum": {
+			devices: vmMemoryDevicesModel{
+				IvshmemSize:    types.Int64Value(32),
+				IvshmemName:    types.StringNull(),
+				Balloon:        types.BoolValue(true),
+				BalloonMinimum: types.Int64Value(2048),
+			},
+			wantParams: map[string]interface{}{"ivshmem": "size=32", "balloon": int64(2048)},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			params, remove := vmMemoryDeviceParams(tc.devices, tc.config)
+			if !reflect.DeepEqual(params, tc.wantParams) {
+				t.Errorf("got params %v, want %v", params, tc.wantParams)
+			}
+			if !reflect.DeepEqual(remove, tc.wantRemove) {
+				t.Errorf("got remove %v, want %v", remove, tc.wantRemove)
+			}
+		})
+	}
+}
+
+func TestValidateVMMemoryDevices(t *testing.T) {
+	tests := map[string]struct {
+		devices vmMemoryDevicesModel
+		memory  types.Int64
+		wantErr bool
+	}{
+		"balloon minimum": {
+			devices: vmMemoryDevicesModel{Balloon: types.BoolValue(true), BalloonMinimum: types.Int64Value(1024)},
+			memory:  types.Int64Value(4096),
+		},
+		"without balloon": {
+			devices: vmMemoryDevicesModel{Balloon: types.BoolValue(false), BalloonMinimum: types.Int64Value(1024)},
+			memory:  types.Int64Value(4096),
+			wantErr: true,
+		},
+		"above memory": {
+			devices: vmMemoryDevicesModel{Balloon: types.BoolValue(true), BalloonMinimum: types.Int64Value(8192)},
+			memory:  types.Int64Value(4096),
+			wantErr: true,
+		},
+		"unknown memory": {
+			devices: vmMemoryDevicesModel{Balloon: types.BoolValue(true), BalloonMinimum: types.Int64Value(8192)},
+			memory:  types.Int64Unknown(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateVMMemoryDevices(tc.devices, tc.memory, &diags)
+			if diags.HasError() != tc.wantErr {
+				t.Errorf("got errors %v, want errors %t", diags, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseVMMemoryDevices(t *testing.T) {
+	devices, err := parseVMMemoryDevices(map[string]interface{}{"ivshmem": "size=64", "balloon": "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if devices.IvshmemSize.ValueInt64() != 64 || !devices.IvshmemName.IsNull() {
+		t.Errorf("got ivshmem %s %s, want 64 without a name", devices.IvshmemSize, devices.IvshmemName)
+	}
+	if devices.Balloon.ValueBool() || !devices.BalloonMinimum.IsNull() {
+		t.Errorf("got balloon %s minimum %s, want a disabled balloon", devices.Balloon, devices.BalloonMinimum)
+	}
+
+	devices, err = parseVMMemoryDevices(map[string]interface{}{"balloon": "2048"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !devices.Balloon.ValueBool() || devices.BalloonMinimum.ValueInt64() != 2048 {
+		t.Errorf("got balloon %s minimum %s, want a balloon down to 2048", devices.Balloon, devices.BalloonMinimum)
+	}
+}
+
+func TestParseIvshmem(t *testing.T) {
+	size, name, err := parseIvshmem("size=64,name=looking-glass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 64 || name != "looking-glass" {
+		t.Errorf("got %d %q, want 64 %q", size, name, "looking-glass")
+	}
+
+	if _, _, err := parseIvshmem("size=big"); err == nil {
+		t.Error("expected an error for an invalid size")
+	}
+}
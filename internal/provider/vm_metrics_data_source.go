@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &vmMetricsDataSource{}
+	_ datasource.DataSourceWithConfigure = &vmMetricsDataSource{}
+)
+
+func NewVmMetricsDataSource() datasource.DataSource {
+	return &vmMetricsDataSource{}
+}
+
+type vmMetricsDataSource struct {
+	client *apiClient
+}
+
+type vmMetricsDataSourceModel struct {
+	Node          types.String    `tfsdk:"node"`
+	VMID          types.Int64     `tfsdk:"vm_id"`
+	Timeframe     types.String    `tfsdk:"timeframe"`
+	Consolidation types.String    `tfsdk:"consolidation"`
+	Data          []vmMetricModel `tfsdk:"data"`
+}
+
+type vmMetricModel struct {
+	Time      types.Int64   `tfsdk:"time"`
+	CPU       types.Float64 `tfsdk:"cpu"`
+	MaxCPU    types.Float64 `tfsdk:"maxcpu"`
+	Mem       types.Float64 `tfsdk:"mem"`
+	MaxMem    types.Float64 `tfsdk:"maxmem"`
+	DiskRead  types.Float64 `tfsdk:"diskread"`
+	DiskWrite types.Float64 `tfsdk:"diskwrite"`
+	NetIn     types.Float64 `tfsdk:"netin"`
+	NetOut    types.Float64 `tfsdk:"netout"`
+}
+
+// rrdData is a single data point of the rrddata endpoint. Values are missing
+// for the time the guest was not running.
+type rrdData struct {
+	Time      int64    `json:"time"`
+	CPU       *float64 `json:"cpu"`
+	MaxCPU    *float64 `json:"maxcpu"`
+	Mem       *float64 `json:"mem"`
+	MaxMem    *float64 `json:"maxmem"`
+	DiskRead  *float64 `json:"diskread"`
+	DiskWrite *float64 `json:"diskwrite"`
+	NetIn     *float64 `json:"netin"`
+	NetOut    *float64 `json:"netout"`
+}
+
+// float64PointerValue converts an optional API value into a framework value.
+func float64PointerValue(v *float64) types.Float64 {
+	if v == nil {
+		return types.Float64Null()
+	}
+
+	return types.Float64Value(*v)
+}
+
+func (d *vmMetricsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *vmMetricsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_metrics"
+}
+
+func (d *vmMetricsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	metric := func(description string) schema.Float64Attribute {
+		return schema.Float64Attribute{
+			Computed:    true,
+			Description: description,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Utilization statistics of a VM over a timeframe, as recorded in the RRD database of the node.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required: true,
+			},
+			"vm_id": schema.Int64Attribute{
+				Required: true,
+			},
+			"timeframe": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("hour", "day", "week", "month", "year"),
+				},
+			},
+			"consolidation": schema.StringAttribute{
+				Optional:    true,
+				Description: "Consolidation function of the data points, AVERAGE (default) or MAX",
+				Validators: []validator.String{
+					stringvalidator.OneOf("AVERAGE", "MAX"),
+				},
+			},
+			"data": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"time": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Unix timestamp of the data point",
+						},
+						"cpu":       metric("CPU usage as fraction of maxcpu"),
+						"maxcpu":    metric("Number of CPUs"),
+						"mem":       metric("Used memory in bytes"),
+						"maxmem":    metric("Available memory in bytes"),
+						"diskread":  metric("Disk read rate in bytes per second"),
+						"diskwrite": metric("Disk write rate in bytes per second"),
+						"netin":     metric("Incoming network rate in bytes per second"),
+						"netout":    metric("Outgoing network rate in bytes per second"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *vmMetricsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.logContext(ctx)
+
+	var state vmMetricsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	consolidation := "AVERAGE"
+	if !state.Consolidation.IsNull() {
+		consolidation = state.Consolidation.ValueString()
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, state.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, state.VMID.ValueInt64())
+	tflog.SubsystemDebug(ctx, logVM, "Reading VM RRD data")
+
+	var points []rrdData
+	path := fmt.Sprintf("%s/rrddata?timeframe=%s&cf=%s",
+		guestPath(state.Node.ValueString(), "qemu", state.VMID.ValueInt64()), state.Timeframe.ValueString(), consolidation)
+	err := d.client.Get(ctx, path, &points)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM Metrics",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	for _, point := range points {
+		state.Data = append(state.Data, vmMetricModel{
+			Time:      types.Int64Value(point.Time),
+			CPU:       float64PointerValue(point.CPU),
+			MaxCPU:    float64PointerValue(point.MaxCPU),
+			Mem:       float64PointerValue(point.Mem),
+			MaxMem:    float64PointerValue(point.MaxMem),
+			DiskRead:  float64PointerValue(point.DiskRead),
+			DiskWrite: float64PointerValue(point.DiskWrite),
+			NetIn:     float64PointerValue(point.NetIn),
+			NetOut:    float64PointerValue(point.NetOut),
+		})
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// networkDeviceKey matches the config keys of the network devices of a VM.
+var networkDeviceKey = regexp.MustCompile(`^net([0-9]|[12][0-9]|3[01])$`)
+
+// vmNetworkModels are the NIC models of a VM, which key the MAC address in
+// a net option.
+var vmNetworkModels = []string{"virtio", "e1000", "e1000e", "rtl8139", "vmxnet3"}
+
+// vmNetworkModel maps a network device of the resource, keyed by its config
+// key.
+type vmNetworkModel struct {
+	Model    types.String  `tfsdk:"model"`
+	Bridge   types.String  `tfsdk:"bridge"`
+	VLAN     types.Int64   `tfsdk:"vlan"`
+	Trunks   []types.Int64 `tfsdk:"trunks"`
+	Firewall types.Bool    `tfsdk:"firewall"`
+	MTU      types.Int64   `tfsdk:"mtu"`
+	Rate     types.Float64 `tfsdk:"rate"`
+	Queues   types.Int64   `tfsdk:"queues"`
+	MAC      types.String  `tfsdk:"mac"`
+}
+
+// vmNetworkDevicesAttribute returns the schema attribute of the network
+// devices of a VM.
+func vmNetworkDevicesAttribute() schema.Attribute {
+	return schema.MapNestedAttribute{
+		Optional: true,
+		Description: "Network devices of the VM by config key from net0 to net31, e.g. `{ net0 = { bridge = \"vmbr0\" } }`. " +
+			"They replace the network devices of a cloned source, which are removed when not listed",
+		Validators: []validator.Map{
+			mapvalidator.SizeAtLeast(1),
+			mapvalidator.KeysAre(
+				stringvalidator.RegexMatches(networkDeviceKey, "must be a network device from net0-31"),
+			),
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"model": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     stringdefault.StaticString("virtio"),
+					Description: "NIC model, one of virtio, e1000, e1000e, rtl8139 or vmxnet3. Defaults to virtio",
+					Validators: []validator.String{
+						stringvalidator.OneOf(vmNetworkModels...),
+					},
+				},
+				"bridge": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     stringdefault.StaticString("vmbr0"),
+					Description: "Bridge the NIC is attached to. Defaults to vmbr0",
+				},
+				"vlan": schema.Int64Attribute{
+					Optional:    true,
+					Description: "VLAN tag of the NIC",
+					Validators: []validator.Int64{
+						int64validator.Between(1, 4094),
+					},
+				},
+				"trunks": schema.ListAttribute{
+					ElementType: types.Int64Type,
+					Optional:    true,
+					Description: "VLANs passed to the guest tagged, on VLAN aware bridges",
+					Validators: []validator.List{
+						listvalidator.SizeAtLeast(1),
+						listvalidator.ValueInt64sAre(int64validator.Between(1, 4094)),
+					},
+				},
+				"firewall": schema.BoolAttribute{
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+					Description: "Filter the traffic of the NIC with the firewall. Defaults to false",
+				},
+				"mtu": schema.Int64Attribute{
+					Optional:    true,
+					Description: "MTU of virtio NICs, 1 for the MTU of the bridge. Defaults to 1500",
+					Validators: []validator.Int64{
+						int64validator.Between(1, 65520),
+					},
+				},
+				"rate": schema.Float64Attribute{
+					Optional:    true,
+					Description: "Rate limit of the NIC in MB/s. Unlimited when not set",
+					Validators: []validator.Float64{
+						float64validator.AtLeast(0),
+					},
+				},
+				"queues": schema.Int64Attribute{
+					Optional:    true,
+					Description: "Number of packet queues of the NIC for multiqueue virtio networking",
+					Validators: []validator.Int64{
+						int64validator.Between(0, 64),
+					},
+				},
+				"mac": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Description: "MAC address of the NIC, generated when not set",
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
+					},
+				},
+			},
+		},
+	}
+}
+
+// formatVMNetwork returns the net option of a network device, e.g.
+// `virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=1,tag=10`.
+func formatVMNetwork(network vmNetworkModel) string {
+	options := map[string]string{
+		"model":    network.Model.ValueString(),
+		"bridge":   network.Bridge.ValueString(),
+		"firewall": strconv.Itoa(proxmoxtf.BoolToInt(network.Firewall.ValueBool())),
+	}
+	if mac := network.MAC.ValueString(); mac != "" {
+		options["model"] += "=" + mac
+	}
+	if !network.VLAN.IsNull() {
+		options["tag"] = strconv.FormatInt(network.VLAN.ValueInt64(), 10)
+	}
+	if len(network.Trunks) > 0 {
+		trunks := make([]string, len(network.Trunks))
+		for i, vlan := range network.Trunks {
+			trunks[i] = strconv.FormatInt(vlan.ValueInt64(), 10)
+		}
+		options["trunks"] = strings.Join(trunks, ";")
+	}
+	if !network.MTU.IsNull() {
+		options["mtu"] = strconv.FormatInt(network.MTU.ValueInt64(), 10)
+	}
+	if !network.Rate.IsNull() {
+		options["rate"] = strconv.FormatFloat(network.Rate.ValueFloat64(), 'f', -1, 64)
+	}
+	if !network.Queues.IsNull() {
+		options["queues"] = strconv.FormatInt(network.Queues.ValueInt64(), 10)
+	}
+
+	return proxmoxtf.FormatOptions(options, "model")
+}
+
+// parseVMNetwork parses a net option of a VM config.
+func parseVMNetwork(value string) (vmNetworkModel, error) {
+	network := vmNetworkModel{
+		Model:  types.StringNull(),
+		VLAN:   types.Int64Null(),
+		MTU:    types.Int64Null(),
+		Rate:   types.Float64Null(),
+		Queues: types.Int64Null(),
+		MAC:    types.StringNull(),
+	}
+
+	options, err := proxmoxtf.ParseOptions(value, "model")
+	if err != nil {
+		return network, err
+	}
+	for _, model := range vmNetworkModels {
+		if mac, ok := options[model]; ok {
+			network.Model = types.StringValue(model)
+			network.MAC = types.StringValue(mac)
+		} else if options["model"] == model {
+			network.Model = types.StringValue(model)
+		}
+	}
+	if network.Model.IsNull() {
+		return network, fmt.Errorf("unsupported network device %q", value)
+	}
+	network.Bridge = proxmoxtf.StringOrNull(options["bridge"])
+	network.Firewall = types.BoolValue(options["firewall"] == "1")
+
+	integers := map[string]*types.Int64{"tag": &network.VLAN, "mtu": &network.MTU, "queues": &network.Queues}
+	for key, field := range integers {
+		if option, ok := options[key]; ok {
+			number, err := strconv.ParseInt(option, 10, 64)
+			if err != nil {
+				return network, fmt.Errorf("invalid %s %q: %w", key, option, err)
+			}
+			*field = types.Int64Value(number)
+		}
+	}
+	if option, ok := options["rate"]; ok {
+		rate, err := strconv.ParseFloat(option, 64)
+		if err != nil {
+			return network, fmt.Errorf("invalid rate %q: %w", option, err)
+		}
+		network.Rate = types.Float64Value(rate)
+	}
+	if option := options["trunks"]; option != "" {
+		for _, trunk := range strings.Split(option, ";") {
+			vlan, err := strconv.ParseInt(trunk, 10, 64)
+			if err != nil {
+				return network, fmt.Errorf("invalid trunk %q: %w", trunk, err)
+			}
+			network.Trunks = append(network.Trunks, types.Int64Value(vlan))
+		}
+	}
+
+	return network, nil
+}
+
+// vmNetworkDevices returns the network devices of a VM config by config key,
+// or nil when it has none.
+func vmNetworkDevices(config map[string]interface{}) (map[string]vmNetworkModel, error) {
+	var devices map[string]vmNetworkModel
+	for key, value := range config {
+		if !networkDeviceKey.MatchString(key) {
+			continue
+		}
+		network, err := parseVMNetwork(proxmoxtf.ConfigValue(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if devices == nil {
+			devices = make(map[string]vmNetworkModel)
+		}
+		devices[key] = network
+	}
+
+	return devices, nil
+}
+
+// vmNetworkParams returns the config options that add the planned network
+// devices and change the others, and the devices of the current config to
+// remove. Devices keep their current MAC address unless one is planned, and
+// unchanged devices are not set, so that a running VM does not get pending
+// changes for them.
+func vmNetworkParams(devices map[string]vmNetworkModel, config map[string]interface{}) (map[string]interface{}, []string) {
+	params := make(map[string]interface{})
+	for key, network := range devices {
+		current := proxmoxtf.ConfigValue(config[key])
+		if network.MAC.IsUnknown() || network.MAC.IsNull() {
+			network.MAC = types.StringNull()
+			if existing, err := parseVMNetwork(current); err == nil && existing.Model.Equal(network.Model) {
+				network.MAC = existing.MAC
+			}
+		}
+
+		value := formatVMNetwork(network)
+		planned, _ := proxmoxtf.ParseOptions(value, "model")
+		if existing, err := proxmoxtf.ParseOptions(current, "model"); err != nil || !reflect.DeepEqual(planned, existing) {
+			params[key] = value
+		}
+	}
+
+	var remove []string
+	for key := range config {
+		if _, ok := devices[key]; networkDeviceKey.MatchString(key) && !ok {
+			remove = append(remove, key)
+		}
+	}
+	sort.Strings(remove)
+
+	return params, remove
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseVMNetwork(t *testing.T) {
+	network, err := parseVMNetwork("virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=1,mtu=9000,queues=4,rate=12.5,tag=10,trunks=20;30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := vmNetworkModel{
+		Model:    types.StringValue("virtio"),
+		Bridge:   types.StringValue("vmbr0"),
+		VLAN:     types.Int64Value(10),
+		Trunks:   []types.Int64{types.Int64Value(20), types.Int64Value(30)},
+		Firewall: types.BoolValue(true),
+		MTU:      types.Int64Value(9000),
+		Rate:     types.Float64Value(12.5),
+		Queues:   types.Int64Value(4),
+		MAC:      types.StringValue("BC:24:11:00:00:01"),
+	}
+	if !reflect.DeepEqual(network, want) {
+		t.Errorf("got %+v, want %+v", network, want)
+	}
+	if got := formatVMNetwork(network); got != "virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=1,mtu=9000,queues=4,rate=12.5,tag=10,trunks=20;30" {
+		t.Errorf("got %q", got)
+	}
+
+	network, err = parseVMNetwork("e1000,bridge=vmbr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Model.ValueString() != "e1000" || !network.MAC.IsNull() || network.Firewall.ValueBool() {
+		t.Errorf("got %+v", network)
+	}
+
+	if _, err := parseVMNetwork("ne2k_pci=BC:24:11:00:00:01,bridge=vmbr0"); err == nil {
+		t.Error("expected an error for an unsupported model")
+	}
+	if _, err := parseVMNetwork("virtio=BC:24:11:00:00:01,bridge=vmbr0,trunks=20;x"); err == nil {
+		t.Error("expected an error for an invalid trunk")
+	}
+}
+
+func TestVMNetworkDevices(t *testing.T) {
+	devices, err := vmNetworkDevices(map[string]interface{}{"scsi0": "local-lvm:vm-100-disk-0,size=32G"})
+	if err != nil || devices != nil {
+		t.Errorf("got %v, %v, want no devices", devices, err)
+	}
+
+	devices, err = vmNetworkDevices(map[string]interface{}{
+		"net0":  "virtio=BC:24:11:00:00:01,bridge=vmbr0",
+		"net12": "vmxnet3=BC:24:11:00:00:02,bridge=vmbr1,tag=20",
+		"scsi0": "local-lvm:vm-100-disk-0,size=32G",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 2 || devices["net12"].MAC.ValueString() != "BC:24:11:00:00:02" {
+		t.Errorf("got %+v", devices)
+	}
+}
+
+func TestVMNetworkParams(t *testing.T) {
+	network := vmNetworkModel{
+		Model:    types.StringValue("virtio"),
+		Bridge:   types.StringValue("vmbr0"),
+		VLAN:     types.Int64Null(),
+		Firewall: types.BoolValue(false),
+		MTU:      types.Int64Null(),
+		Rate:     types.Float64Null(),
+		Queues:   types.Int64Null(),
+		MAC:      types.StringUnknown(),
+	}
+	config := map[string]interface{}{
+		"net0": "virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=0",
+		"net1": "virtio=BC:24:11:00:00:02,bridge=vmbr0,firewall=0",
+		"net2": "e1000=BC:24:11:00:00:03,bridge=vmbr0,firewall=0",
+	}
+
+	tagged := network
+	tagged.VLAN = types.Int64Value(10)
+	fixed := network
+	fixed.MAC = types.StringValue("BC:24:11:00:00:10")
+	tests := []struct {
+		name       string
+		devices    map[string]vmNetworkModel
+		wantParams map[string]interface{}
+		wantRemove []string
+	}{
+		{
+			name:       "unchanged and removed",
+			devices:    map[string]vmNetworkModel{"net0": network},
+			wantParams: map[string]interface{}{},
+			wantRemove: []string{"net1", "net2"},
+		},
+		{
+			name:    "changed and added",
+			devices: map[string]vmNetworkModel{"net0": tagged, "net1": fixed, "net2": network, "net3": network},
+			wantParams: map[string]interface{}{
+				"net0": "virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=0,tag=10",
+				"net1": "virtio=BC:24:11:00:00:10,bridge=vmbr0,firewall=0",
+				"net2": "virtio,bridge=vmbr0,firewall=0",
+				"net3": "virtio,bridge=vmbr0,firewall=0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		params, remove := vmNetworkParams(tt.devices, config)
+		if !reflect.DeepEqual(params, tt.wantParams) || !reflect.DeepEqual(remove, tt.wantRemove) {
+			t.Errorf("%s: got %v %v, want %v %v", tt.name, params, remove, tt.wantParams, tt.wantRemove)
+		}
+	}
+}
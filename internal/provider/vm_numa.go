@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// maxNumaNodes is the number of numaN options a VM config supports.
+const maxNumaNodes = 8
+
+// vmNumaModel maps the hugepages and the NUMA topology of a VM.
+type vmNumaModel struct {
+	Hugepages     types.String      `tfsdk:"hugepages"`
+	KeepHugepages types.Bool        `tfsdk:"keep_hugepages"`
+	NumaNodes     []vmNumaNodeModel `tfsdk:"numa_nodes"`
+}
+
+// vmNumaNodeModel maps a guest NUMA node of a VM.
+type vmNumaNodeModel struct {
+	CPUs      types.String `tfsdk:"cpus"`
+	Memory    types.Int64  `tfsdk:"memory"`
+	HostNodes types.String `tfsdk:"host_nodes"`
+	Policy    types.String `tfsdk:"policy"`
+}
+
+// vmNumaAttributes returns the schema attributes of the hugepages and the
+// NUMA topology of a VM.
+func vmNumaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"hugepages": schema.StringAttribute{
+			Optional:    true,
+			Description: "Size of the hugepages backing the memory of the VM in MiB, either 2, 1024 or any",
+			Validators: []validator.String{
+				stringvalidator.OneOf("2", "1024", "any"),
+			},
+		},
+		"keep_hugepages": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Keep the hugepages allocated after the VM shut down, so that they are available for the next start",
+		},
+		"numa_nodes": schema.ListNestedAttribute{
+			Optional: true,
+			Description: "NUMA nodes of the VM in order, binding their CPUs and memory to NUMA nodes of the host for " +
+				"latency sensitive workloads. NUMA is enabled on the VM when set",
+			Validators: []validator.List{
+				listvalidator.SizeBetween(1, maxNumaNodes),
+			},
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"cpus": schema.StringAttribute{
+						Required:    true,
+						Description: "CPUs of the VM on the NUMA node, e.g. `0-3` or `0-1;4-5`",
+					},
+					"memory": schema.Int64Attribute{
+						Required:    true,
+						Description: "Memory of the VM on the NUMA node in MiB",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"host_nodes": schema.StringAttribute{
+						Optional:    true,
+						Description: "NUMA nodes of the host the memory is allocated on, e.g. `0` or `0-1`",
+					},
+					"policy": schema.StringAttribute{
+						Optional:    true,
+						Description: "Allocation policy on the host nodes, either preferred, bind or interleave",
+						Validators: []validator.String{
+							stringvalidator.OneOf("preferred", "bind", "interleave"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateVMNuma adds an error when the memory of the NUMA nodes does not
+// add up to the memory of the VM, which is null when it is not known.
+func validateVMNuma(numa vmNumaModel, memory types.Int64, diags *diag.Diagnostics) {
+	if len(numa.NumaNodes) == 0 || memory.IsNull() || memory.IsUnknown() {
+		return
+	}
+
+	var total int64
+	for _, node := range numa.NumaNodes {
+		if node.Memory.IsUnknown() {
+			return
+		}
+		total += node.Memory.ValueInt64()
+	}
+	if total != memory.ValueInt64() {
+		diags.AddAttributeError(
+			path.Root("numa_nodes"),
+			"NUMA Node Memory Mismatch",
+			fmt.Sprintf("The NUMA nodes have %d MiB of memory in total, but the VM has %d MiB. "+
+				"Proxmox VE refuses to start VMs whose NUMA nodes do not add up to their memory.", total, memory.ValueInt64()),
+		)
+	}
+}
+
+// formatNumaNode returns the numaN option of a guest NUMA node, e.g.
+// `cpus=0-3,hostnodes=0,memory=4096,policy=bind`.
+func formatNumaNode(node vmNumaNodeModel) string {
+	value := "cpus=" + node.CPUs.ValueString()
+	if !node.HostNodes.IsNull() {
+		value += ",hostnodes=" + node.HostNodes.ValueString()
+	}
+	value += ",memory=" + strconv.FormatInt(node.Memory.ValueInt64(), 10)
+	if !node.Policy.IsNull() {
+		value += ",policy=" + node.Policy.ValueString()
+	}
+
+	return value
+}
+
+// parseNumaNode parses a numaN option of a VM config.
+func parseNumaNode(value string) (vmNumaNodeModel, error) {
+	node := vmNumaNodeModel{
+		CPUs:      types.StringNull(),
+		Memory:    types.Int64Null(),
+		HostNodes: types.StringNull(),
+		Policy:    types.StringNull(),
+	}
+
+	for _, property := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(property, "=")
+		switch key {
+		case "cpus":
+			node.CPUs = types.StringValue(val)
+		case "memory":
+			memory, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return node, fmt.Errorf("invalid NUMA node memory %q: %w", val, err)
+			}
+			node.Memory = types.Int64Value(memory)
+		case "hostnodes":
+			node.HostNodes = types.StringValue(val)
+		case "policy":
+			node.Policy = types.StringValue(val)
+		}
+	}
+
+	return node, nil
+}
+
+// vmNumaParams returns the config options that apply numa to a VM with the
+// current config, and the options to remove, which are the unset ones and
+// the NUMA nodes beyond the planned ones.
+func vmNumaParams(numa vmNumaModel, config map[string]interface{}) (map[string]interface{}, []string) {
+	params := map[string]interface{}{}
+	var remove []string
+
+	if numa.Hugepages.IsNull() {
+		if _, ok := config["hugepages"]; ok {
+			remove = append(remove, "hugepages")
+		}
+	} else {
+		params["hugepages"] = numa.Hugepages.ValueString()
+	}
+
+	if numa.KeepHugepages.ValueBool() {
+		params["keephugepages"] = 1
+	} else if _, ok := config["keephugepages"]; ok {
+		remove = append(remove, "keephugepages")
+	}
+
+	if len(numa.NumaNodes) > 0 {
+		params["numa"] = 1
+	} else if _, ok := config["numa"]; ok {
+		remove = append(remove, "numa")
+	}
+
+	for i := 0; i < maxNumaNodes; i++ {
+		key := fmt.Sprintf("numa%d", i)
+		if i < len(numa.NumaNodes) {
+			params[key] = formatNumaNode(numa.NumaNodes[i])
+		} else if _, ok := config[key]; ok {
+			remove = append(remove, key)
+		}
+	}
+
+	return params, remove
+}
+
+// parseVMNuma returns the hugepages and the NUMA topology of a VM config.
+func parseVMNuma(config map[string]interface{}) (vmNumaModel, error) {
+	numa := vmNumaModel{
+		Hugepages:     types.StringNull(),
+		KeepHugepages: types.BoolValue(proxmoxtf.ConfigValue(config["keephugepages"]) == "1"),
+	}
+	if value, ok := config["hugepages"]; ok {
+		numa.Hugepages = types.StringValue(proxmoxtf.ConfigValue(value))
+	}
+
+	if proxmoxtf.ConfigValue(config["numa"]) != "1" {
+		return numa, nil
+	}
+	for i := 0; i < maxNumaNodes; i++ {
+		value, ok := config[fmt.Sprintf("numa%d", i)]
+		if !ok {
+			break
+		}
+		node, err := parseNumaNode(proxmoxtf.ConfigValue(value))
+		if err != nil {
+			return numa, err
+		}
+		numa.NumaNodes = append(numa.NumaNodes, node)
+	}
+
+	return numa, nil
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &vmNumaResource{}
+	_ resource.ResourceWithConfigure  = &vmNumaResource{}
+	_ resource.ResourceWithModifyPlan = &vmNumaResource{}
+)
+
+// NewVmNumaResource is a helper function to simplify the provider implementation.
+func NewVmNumaResource() resource.Resource {
+	return &vmNumaResource{}
+}
+
+// vmNumaResource manages the NUMA topology and hugepages of an existing VM.
+type vmNumaResource struct {
+	client *apiClient
+}
+
+// vmNumaResourceModel maps the resource schema data.
+type vmNumaResourceModel struct {
+	Node types.String `tfsdk:"node"`
+	VMID types.Int64  `tfsdk:"vm_id"`
+	vmNumaModel
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmNumaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmNumaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_numa"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmNumaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the hugepages and the NUMA topology of an existing VM, binding the CPUs and memory of its " +
+			"NUMA nodes to NUMA nodes of the host for latency sensitive workloads. " +
+			"Destroying the resource removes these settings. The changes take effect the next time the VM starts.",
+		DeprecationMessage: "Use the hugepages, keep_hugepages and numa_nodes attributes of proxmox_vm instead, " +
+			"which manage the NUMA topology together with the VM and check it against its memory.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Required:    true,
+				Description: "Node the VM runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the VM",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+	for name, attribute := range vmNumaAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// apply writes the NUMA settings of plan to the VM.
+func (r *vmNumaResource) apply(ctx context.Context, plan vmNumaResourceModel) error {
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Applying VM NUMA settings")
+
+	path := guestPath(plan.Node.ValueString(), "qemu", plan.VMID.ValueInt64()) + "/config"
+	config, err := r.client.api.VMConfig(ctx, plan.Node.ValueString(), plan.VMID.ValueInt64())
+	if err != nil {
+		return err
+	}
+
+	params, remove := vmNumaParams(plan.vmNumaModel, config)
+	if len(remove) > 0 {
+		params["delete"] = strings.Join(remove, ",")
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	return r.client.Put(ctx, path, params, nil)
+}
+
+// readNuma refreshes the NUMA settings of the model from the config of the VM.
+func (r *vmNumaResource) readNuma(ctx context.Context, model *vmNumaResourceModel) error {
+	config, err := r.client.api.VMConfig(ctx, model.Node.ValueString(), model.VMID.ValueInt64())
+	if err != nil {
+		return err
+	}
+
+	numa, err := parseVMNuma(config)
+	if err != nil {
+		return err
+	}
+	model.vmNumaModel = numa
+
+	return nil
+}
+
+// ModifyPlan rejects guest IDs outside of the vmid_range of the provider.
+func (r *vmNumaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.validateVMIDPlan(ctx, req.Plan, &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmNumaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmNumaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox VM NUMA Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmNumaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmNumaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.readNuma(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM NUMA Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmNumaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmNumaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Apply Proxmox VM NUMA Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmNumaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmNumaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remove := []string{"hugepages", "keephugepages", "numa"}
+	for i := 0; i < maxNumaNodes; i++ {
+		remove = append(remove, fmt.Sprintf("numa%d", i))
+	}
+
+	path := guestPath(state.Node.ValueString(), "qemu", state.VMID.ValueInt64()) + "/config"
+	err := r.client.Put(ctx, path, map[string]interface{}{"delete": strings.Join(remove, ",")}, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Remove Proxmox VM NUMA Settings",
+			apiErrorDetail(err),
+		)
+		return
+	}
+}
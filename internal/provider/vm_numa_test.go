@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestVMNumaParams(t *testing.T) {
+	numa := vmNumaModel{
+		Hugepages:     types.StringValue("1024"),
+		KeepHugepages: types.BoolValue(true),
+		NumaNodes: []vmNumaNodeModel{
+			{CPUs: types.StringValue("0-3"), Memory: types.Int64Value(4096), HostNodes: types.StringValue("0"), Policy: types.StringValue("bind")},
+			{CPUs: types.StringValue("4-7"), Memory: types.Int64Value(4096), HostNodes: types.StringNull(), Policy: types.StringNull()},
+		},
+	}
+	config := map[string]interface{}{
+		"numa":  "1",
+		"numa0": "cpus=0-1,memory=2048",
+		"numa1": "cpus=2-3,memory=2048",
+		"numa2": "cpus=4-5,memory=2048",
+	}
+
+	wantParams := map[string]interface{}{
+		"numa":          1,
+		"keephugepages": 1,
+		"hugepages":     "1024",
+		"numa0":         "cpus=0-3,hostnodes=0,memory=4096,policy=bind",
+		"numa1":         "cpus=4-7,memory=4096",
+	}
+	params, remove := vmNumaParams(numa, config)
+	if !reflect.DeepEqual(params, wantParams) || !reflect.DeepEqual(remove, []string{"numa2"}) {
+		t.Errorf("got %v, %v, want %v, [numa2]", params, remove, wantParams)
+	}
+
+	// Removing the topology clears the options the VM has.
+	removed := vmNumaModel{Hugepages: types.StringNull(), KeepHugepages: types.BoolValue(false)}
+	config["hugepages"] = "1024"
+	config["keephugepages"] = "1"
+	params, remove = vmNumaParams(removed, config)
+	wantRemove := []string{"hugepages", "keephugepages", "numa", "numa0", "numa1", "numa2"}
+	if len(params) != 0 || !reflect.DeepEqual(remove, wantRemove) {
+		t.Errorf("got %v, %v, want no params, %v", params, remove, wantRemove)
+	}
+
+	// VMs without a topology get no options at all.
+	params, remove = vmNumaParams(removed, nil)
+	if len(params) != 0 || len(remove) != 0 {
+		t.Errorf("got %v, %v, want nothing", params, remove)
+	}
+}
+
+func TestValidateVMNuma(t *testing.T) {
+	numa := vmNumaModel{
+		NumaNodes: []vmNumaNodeModel{
+			{CPUs: types.StringValue("0-3"), Memory: types.Int64Value(4096)},
+			{CPUs: types.StringValue("4-7"), Memory: types.Int64Value(4096)},
+		},
+	}
+
+	tests := map[string]struct {
+		memory  types.Int64
+		wantErr bool
+	}{
+		"matching memory": {memory: types.Int64Value(8192)},
+		"missing memory":  {memory: types.Int64Value(16384), wantErr: true},
+		"unknown memory":  {memory: types.Int64Unknown()},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateVMNuma(numa, tc.memory, &diags)
+			if diags.HasError() != tc.wantErr {
+				t.Errorf("got errors %v, want errors %t", diags, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseVMNuma(t *testing.T) {
+	numa, err := parseVMNuma(map[string]interface{}{
+		"hugepages": "2",
+		"numa":      "1",
+		"numa0":     "cpus=0-3,memory=4096",
+		"numa1":     "cpus=4-7,memory=4096",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numa.Hugepages.ValueString() != "2" || numa.KeepHugepages.ValueBool() || len(numa.NumaNodes) != 2 {
+		t.Errorf("got %+v", numa)
+	}
+
+	// NUMA nodes of a VM with NUMA disabled are ignored.
+	numa, err = parseVMNuma(map[string]interface{}{"numa0": "cpus=0-3,memory=4096"})
+	if err != nil || numa.NumaNodes != nil {
+		t.Errorf("got %+v, %v, want no NUMA nodes", numa, err)
+	}
+}
+
+func TestParseNumaNode(t *testing.T) {
+	node, err := parseNumaNode("cpus=0-3,hostnodes=0,memory=4096,policy=bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatNumaNode(node) != "cpus=0-3,hostnodes=0,memory=4096,policy=bind" {
+		t.Errorf("got %+v", node)
+	}
+
+	if _, err := parseNumaNode("cpus=0,memory=lots"); err == nil {
+		t.Error("expected an error for an invalid memory")
+	}
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+)
+
+// vmOSTypes are the guest operating systems of a VM, which the API picks OS
+// specific defaults for.
+var vmOSTypes = []string{"other", "wxp", "w2k", "w2k3", "w2k8", "wvista", "win7", "win8", "win10", "win11", "l24", "l26", "solaris"}
+
+// isWindows reports whether osType is one of the Windows guest operating
+// systems, which all start with a w.
+func isWindows(osType string) bool {
+	return strings.HasPrefix(osType, "w")
+}
+
+// vmOSAttributes returns the schema attributes of the guest operating system
+// of a VM and the OS specific settings it defaults.
+func vmOSAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"os_type": schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+			Description: "Guest operating system, e.g. l26 for Linux or win11, which the API picks OS specific defaults for, " +
+				"such as the real time clock in local time and configdrive2 cloud-init drives for Windows. Defaults to the " +
+				"os type of a cloned source",
+			Validators: []validator.String{
+				stringvalidator.OneOf(vmOSTypes...),
+			},
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"local_time": schema.BoolAttribute{
+			Optional: true,
+			Computed: true,
+			Description: "Keep the real time clock of the VM in local time instead of UTC, as Windows expects unless " +
+				"RealTimeIsUniversal is set in its registry. Defaults to true for a Windows os_type and false otherwise",
+		},
+	}
+}
+
+// planLocalTime plans the default local_time of the planned os_type when it
+// is not configured.
+func planLocalTime(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var osType types.String
+	var localTime types.Bool
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("os_type"), &osType)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("local_time"), &localTime)...)
+	if resp.Diagnostics.HasError() || !localTime.IsUnknown() || osType.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("local_time"), isWindows(osType.ValueString()))...)
+}
+
+// vmOSParams returns the config options that apply the os type and clock of
+// plan to a VM with the current config, and the options to remove. They are
+// only set when they change, and localtime is removed when it matches the
+// default of the os type.
+func vmOSParams(plan vmResourceModel, config map[string]interface{}) (map[string]interface{}, []string) {
+	params := map[string]interface{}{}
+	var remove []string
+
+	osType := proxmoxtf.ConfigValue(config["ostype"])
+	if !plan.OSType.IsUnknown() && !plan.OSType.IsNull() {
+		if plan.OSType.ValueString() != osType {
+			params["ostype"] = plan.OSType.ValueString()
+		}
+		osType = plan.OSType.ValueString()
+	}
+
+	if plan.LocalTime.IsUnknown() || plan.LocalTime.IsNull() {
+		return params, remove
+	}
+	current, ok := config["localtime"]
+	switch localTime := plan.LocalTime.ValueBool(); {
+	case localTime == isWindows(osType) && ok:
+		remove = append(remove, "localtime")
+	case localTime != isWindows(osType) && proxmoxtf.ConfigValue(current) != strconv.Itoa(proxmoxtf.BoolToInt(localTime)):
+		params["localtime"] = proxmoxtf.BoolToInt(localTime)
+	}
+
+	return params, remove
+}
+
+// parseVMOS sets the os type and clock of a VM config on model.
+func parseVMOS(config map[string]interface{}, model *vmResourceModel) {
+	osType := proxmoxtf.ConfigValue(config["ostype"])
+	model.OSType = proxmoxtf.StringOrNull(osType)
+	model.LocalTime = types.BoolValue(isWindows(osType))
+	if localTime, ok := config["localtime"]; ok {
+		model.LocalTime = types.BoolValue(proxmoxtf.ConfigValue(localTime) == "1")
+	}
+}
+
+// validateVMWindows rejects cloud-init drives cloudbase-init cannot read on
+// Windows VMs, and warns about Windows VMs with their clock in UTC.
+func validateVMWindows(config vmResourceModel, diags *diag.Diagnostics) {
+	if config.OSType.IsNull() || config.OSType.IsUnknown() || !isWindows(config.OSType.ValueString()) {
+		return
+	}
+
+	if ci := config.CloudInit; ci != nil && !ci.Type.IsNull() && !ci.Type.IsUnknown() && ci.Type.ValueString() != "configdrive2" {
+		diags.AddAttributeError(
+			path.Root("cloud_init").AtName("type"),
+			"Invalid Windows Cloud-Init Type",
+			"cloudbase-init only reads configdrive2 cloud-init drives on Windows, set type to configdrive2 or leave it unset.",
+		)
+	}
+	if !config.LocalTime.IsNull() && !config.LocalTime.IsUnknown() && !config.LocalTime.ValueBool() {
+		diags.AddAttributeWarning(
+			path.Root("local_time"),
+			"Windows Clock in UTC",
+			"Windows reads the real time clock as local time, so its clock is off by the offset of its time zone unless "+
+				"RealTimeIsUniversal is set in its registry.",
+		)
+	}
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestVMOSParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		osType     types.String
+		localTime  types.Bool
+		config     map[string]interface{}
+		wantParams map[string]interface{}
+		wantRemove []string
+	}{
+		{
+			name:       "windows default clock",
+			osType:     types.StringValue("win11"),
+			localTime:  types.BoolValue(true),
+			wantParams: map[string]interface{}{"ostype": "win11"},
+		},
+		{
+			name:       "windows in utc",
+			osType:     types.StringValue("win11"),
+			localTime:  types.BoolValue(false),
+			wantParams: map[string]interface{}{"ostype": "win11", "localtime": 0},
+		},
+		{
+			name:       "unchanged",
+			osType:     types.StringValue("l26"),
+			localTime:  types.BoolValue(true),
+			config:     map[string]interface{}{"ostype": "l26", "localtime": 1},
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:       "clock back to the default",
+			osType:     types.StringValue("l26"),
+			localTime:  types.BoolValue(false),
+			config:     map[string]interface{}{"ostype": "l26", "localtime": 1},
+			wantParams: map[string]interface{}{},
+			wantRemove: []string{"localtime"},
+		},
+		{
+			name:       "os type of a cloned template",
+			osType:     types.StringUnknown(),
+			localTime:  types.BoolValue(false),
+			config:     map[string]interface{}{"ostype": "win10"},
+			wantParams: map[string]interface{}{"localtime": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		params, remove := vmOSParams(vmResourceModel{OSType: tt.osType, LocalTime: tt.localTime}, tt.config)
+		if !reflect.DeepEqual(params, tt.wantParams) || !reflect.DeepEqual(remove, tt.wantRemove) {
+			t.Errorf("%s: got %v %v, want %v %v", tt.name, params, remove, tt.wantParams, tt.wantRemove)
+		}
+	}
+}
+
+func TestParseVMOS(t *testing.T) {
+	tests := []struct {
+		config        map[string]interface{}
+		wantOSType    types.String
+		wantLocalTime types.Bool
+	}{
+		{config: map[string]interface{}{}, wantOSType: types.StringNull(), wantLocalTime: types.BoolValue(false)},
+		{config: map[string]interface{}{"ostype": "win11"}, wantOSType: types.StringValue("win11"), wantLocalTime: types.BoolValue(true)},
+		{config: map[string]interface{}{"ostype": "win11", "localtime": 0}, wantOSType: types.StringValue("win11"), wantLocalTime: types.BoolValue(false)},
+		{config: map[string]interface{}{"ostype": "l26", "localtime": 1}, wantOSType: types.StringValue("l26"), wantLocalTime: types.BoolValue(true)},
+	}
+
+	for _, tt := range tests {
+		var model vmResourceModel
+		parseVMOS(tt.config, &model)
+		if !model.OSType.Equal(tt.wantOSType) || !model.LocalTime.Equal(tt.wantLocalTime) {
+			t.Errorf("%v: got %v %v, want %v %v", tt.config, model.OSType, model.LocalTime, tt.wantOSType, tt.wantLocalTime)
+		}
+	}
+}
+
+func TestValidateVMWindows(t *testing.T) {
+	tests := []struct {
+		name        string
+		osType      types.String
+		localTime   types.Bool
+		ciType      types.String
+		wantErr     bool
+		wantWarning bool
+	}{
+		{name: "windows configdrive2", osType: types.StringValue("win11"), localTime: types.BoolNull(), ciType: types.StringValue("configdrive2")},
+		{name: "windows default type", osType: types.StringValue("win11"), localTime: types.BoolValue(true), ciType: types.StringNull()},
+		{name: "windows nocloud", osType: types.StringValue("win10"), localTime: types.BoolNull(), ciType: types.StringValue("nocloud"), wantErr: true},
+		{name: "windows in utc", osType: types.StringValue("win11"), localTime: types.BoolValue(false), ciType: types.StringNull(), wantWarning: true},
+		{name: "linux nocloud in utc", osType: types.StringValue("l26"), localTime: types.BoolValue(false), ciType: types.StringValue("nocloud")},
+	}
+
+	for _, tt := range tests {
+		config := vmResourceModel{
+			OSType:    tt.osType,
+			LocalTime: tt.localTime,
+			CloudInit: &vmCloudInitModel{Type: tt.ciType},
+		}
+		var diags diag.Diagnostics
+		validateVMWindows(config, &diags)
+		if diags.HasError() != tt.wantErr || (diags.WarningsCount() > 0) != tt.wantWarning {
+			t.Errorf("%s: got %v", tt.name, diags)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// vmProvisioningModel maps the provisioning commands of a VM.
+type vmProvisioningModel struct {
+	Commands  types.List   `tfsdk:"commands"`
+	Retries   types.Int64  `tfsdk:"retries"`
+	Timeout   types.String `tfsdk:"timeout"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	ExitCodes types.List   `tfsdk:"exit_codes"`
+}
+
+// vmProvisioningAttribute returns the schema attribute of the commands run in
+// a VM through the QEMU guest agent.
+func vmProvisioningAttribute() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Description: "Commands run in the VM through the QEMU guest agent once it is created and started, a lightweight " +
+			"alternative to remote-exec for agent-enabled images. The commands run again when they or the triggers " +
+			"change, the VM has to be running then. Requires start",
+		Attributes: map[string]schema.Attribute{
+			"commands": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "Shell commands run in order with /bin/sh -c",
+			},
+			"retries": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(3),
+				Description: "Number of times a failing command is retried. Defaults to 3",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("5m"),
+				Description: "Maximum time to wait for the agent and for each command as a Go duration. Defaults to 5m",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that run the commands again when changed",
+			},
+			"exit_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Computed:    true,
+				Description: "Exit code of each command",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// validateVMProvisioning adds an error when the provisioning commands of a
+// VM that is not started or their timeout are invalid.
+func validateVMProvisioning(provisioning *vmProvisioningModel, start types.Bool, diags *diag.Diagnostics) {
+	if provisioning == nil {
+		return
+	}
+
+	if !start.IsUnknown() && !start.ValueBool() {
+		diags.AddAttributeError(
+			path.Root("provisioning"),
+			"Invalid VM Provisioning",
+			"The provisioning commands run through the guest agent of the running VM, they require start to be true.",
+		)
+	}
+	if provisioning.Timeout.IsNull() || provisioning.Timeout.IsUnknown() {
+		return
+	}
+	if _, err := time.ParseDuration(provisioning.Timeout.ValueString()); err != nil {
+		diags.AddAttributeError(
+			path.Root("provisioning").AtName("timeout"),
+			"Invalid Timeout",
+			err.Error(),
+		)
+	}
+}
+
+// provisioningRuns reports whether the provisioning commands of plan run,
+// which they do when they are new or they or their triggers changed.
+func provisioningRuns(plan, state *vmProvisioningModel) bool {
+	if plan == nil {
+		return false
+	}
+
+	return state == nil || !plan.Commands.Equal(state.Commands) || !plan.Triggers.Equal(state.Triggers)
+}
+
+// planProvisioning plans new exit codes when an update runs the provisioning
+// commands again.
+func planProvisioning(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Created VMs run their commands anyway, and destroyed ones none.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state *vmProvisioningModel
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("provisioning"), &plan)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("provisioning"), &state)...)
+	if resp.Diagnostics.HasError() || !provisioningRuns(plan, state) {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("provisioning").AtName("exit_codes"), types.ListUnknown(types.Int64Type))...)
+}
+
+// provision runs the provisioning commands of a VM and records their exit
+// codes.
+func (c *apiClient) provision(ctx context.Context, node string, vmid int64, provisioning *vmProvisioningModel) error {
+	exitCodes, err := c.runAgentCommands(ctx, node, vmid, provisioning.Commands, provisioning.Retries.ValueInt64(), provisioning.Timeout.ValueString())
+	if err != nil {
+		return err
+	}
+	provisioning.ExitCodes = exitCodes
+
+	return nil
+}
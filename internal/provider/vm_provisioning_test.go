@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestProvisioningRuns(t *testing.T) {
+	provisioning := func(command, trigger string) *vmProvisioningModel {
+		return &vmProvisioningModel{
+			Commands: types.ListValueMust(types.StringType, []attr.Value{types.StringValue(command)}),
+			Retries:  types.Int64Value(3),
+			Triggers: types.MapValueMust(types.StringType, map[string]attr.Value{"version": types.StringValue(trigger)}),
+		}
+	}
+	retried := provisioning("apt-get install -y nginx", "1")
+	retried.Retries = types.Int64Value(5)
+
+	tests := map[string]struct {
+		plan, state *vmProvisioningModel
+		want        bool
+	}{
+		"none":             {},
+		"added":            {plan: provisioning("apt-get install -y nginx", "1"), want: true},
+		"removed":          {state: provisioning("apt-get install -y nginx", "1")},
+		"unchanged":        {plan: provisioning("apt-get install -y nginx", "1"), state: provisioning("apt-get install -y nginx", "1")},
+		"retries changed":  {plan: retried, state: provisioning("apt-get install -y nginx", "1")},
+		"commands changed": {plan: provisioning("apt-get install -y caddy", "1"), state: provisioning("apt-get install -y nginx", "1"), want: true},
+		"trigger changed":  {plan: provisioning("apt-get install -y nginx", "2"), state: provisioning("apt-get install -y nginx", "1"), want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := provisioningRuns(tt.plan, tt.state); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateVMProvisioning(t *testing.T) {
+	tests := map[string]struct {
+		start     types.Bool
+		timeout   types.String
+		wantError bool
+	}{
+		"started":         {start: types.BoolValue(true), timeout: types.StringValue("10m")},
+		"unknown start":   {start: types.BoolUnknown(), timeout: types.StringValue("10m")},
+		"not started":     {start: types.BoolNull(), timeout: types.StringValue("10m"), wantError: true},
+		"invalid timeout": {start: types.BoolValue(true), timeout: types.StringValue("ten minutes"), wantError: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateVMProvisioning(&vmProvisioningModel{Timeout: tt.timeout}, tt.start, &diags)
+			if got := diags.HasError(); got != tt.wantError {
+				t.Errorf("got error %t, want %t: %v", got, tt.wantError, diags)
+			}
+		})
+	}
+}
+
+func TestProvision(t *testing.T) {
+	api := &fakeAPI{responses: map[string]string{
+		"POST /nodes/pve/qemu/100/agent/ping":       "null",
+		"POST /nodes/pve/qemu/100/agent/exec":       `{"pid":42}`,
+		"GET /nodes/pve/qemu/100/agent/exec-status": `{"exited":1,"exitcode":0}`,
+	}}
+	provisioning := &vmProvisioningModel{
+		Commands: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("apt-get update"),
+			types.StringValue("apt-get install -y nginx"),
+		}),
+		Retries: types.Int64Value(0),
+		Timeout: types.StringValue("1m"),
+	}
+
+	err := fakeClient(t, api).provision(context.Background(), "pve", 100, provisioning)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(0), types.Int64Value(0)})
+	if !provisioning.ExitCodes.Equal(want) {
+		t.Errorf("got exit codes %v, want %v", provisioning.ExitCodes, want)
+	}
+}
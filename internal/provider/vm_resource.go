@@ -0,0 +1,1114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/luthermonson/go-proxmox"
+
+	"terraform-provider-proxmox/internal/proxmoxtf"
+	"terraform-provider-proxmox/internal/pveapi"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &vmResource{}
+	_ resource.ResourceWithConfigure      = &vmResource{}
+	_ resource.ResourceWithModifyPlan     = &vmResource{}
+	_ resource.ResourceWithValidateConfig = &vmResource{}
+)
+
+// NewVmResource is a helper function to simplify the provider implementation.
+func NewVmResource() resource.Resource {
+	return &vmResource{}
+}
+
+// vmResource is the resource implementation.
+type vmResource struct {
+	client *apiClient
+}
+
+// vmResourceModel maps the resource schema data.
+type vmResourceModel struct {
+	Node                 types.String              `tfsdk:"node"`
+	Placement            *nodePlacementModel       `tfsdk:"placement"`
+	VMID                 types.Int64               `tfsdk:"vm_id"`
+	Name                 types.String              `tfsdk:"name"`
+	UniqueName           types.Bool                `tfsdk:"unique_name"`
+	CPU                  *vmCPUModel               `tfsdk:"cpu"`
+	KVM                  types.Bool                `tfsdk:"kvm"`
+	Arch                 types.String              `tfsdk:"arch"`
+	OSType               types.String              `tfsdk:"os_type"`
+	LocalTime            types.Bool                `tfsdk:"local_time"`
+	VMStateStorage       types.String              `tfsdk:"vmstatestorage"`
+	HibernateOnMigrate   types.Bool                `tfsdk:"hibernate_on_migrate"`
+	Memory               types.Int64               `tfsdk:"memory"`
+	Disks                map[string]vmDiskModel    `tfsdk:"disks"`
+	BootOrder            types.List                `tfsdk:"boot_order"`
+	NetworkDevices       map[string]vmNetworkModel `tfsdk:"network_devices"`
+	Clone                *vmCloneModel             `tfsdk:"clone"`
+	CloudInit            *vmCloudInitModel         `tfsdk:"cloud_init"`
+	ExtraConfig          types.Map                 `tfsdk:"extra_config"`
+	Provisioning         *vmProvisioningModel      `tfsdk:"provisioning"`
+	Start                types.Bool                `tfsdk:"start"`
+	WaitForStatus        types.String              `tfsdk:"wait_for_status"`
+	WaitForStatusTimeout types.Int64               `tfsdk:"wait_for_status_timeout"`
+	BackupBeforeDestroy  types.Bool                `tfsdk:"backup_before_destroy"`
+	BackupStorage        types.String              `tfsdk:"backup_storage"`
+	SnapshotBeforeUpdate types.Bool                `tfsdk:"snapshot_before_update"`
+	LastSnapshot         types.String              `tfsdk:"last_snapshot"`
+	CurrentNode          types.String              `tfsdk:"current_node"`
+	HAManaged            types.Bool                `tfsdk:"ha_managed"`
+	MigrateBack          types.Bool                `tfsdk:"migrate_back"`
+	LastTaskUPID         types.String              `tfsdk:"last_task_upid"`
+	Status               types.String              `tfsdk:"status"`
+
+	// The memory devices and the NUMA topology are shared with
+	// proxmox_vm_memory_devices and proxmox_vm_numa.
+	vmMemoryDevicesModel
+	vmNumaModel
+}
+
+// vmCloneModel maps the source of a cloned VM.
+type vmCloneModel struct {
+	SourceVMID    types.Int64  `tfsdk:"source_vm_id"`
+	TemplateName  types.String `tfsdk:"template_name"`
+	Full          types.Bool   `tfsdk:"full"`
+	TargetStorage types.String `tfsdk:"target_storage"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *vmResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *vmResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm"
+}
+
+// Schema defines the schema for the resource.
+func (r *vmResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a QEMU VM with disks and network devices, created empty or cloned from another VM or a template. " +
+			"Changes to the CPU, memory, network and drive options of a running VM may only take effect the next time it starts. " +
+			"Disks grow in place and cannot shrink.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Node to create the VM on. Defaults to the node chosen by placement, or the default_node of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"placement": nodePlacementAttribute(),
+			"vm_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the VM, allocated with the vmid_strategy of the provider when not set",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the VM, a valid DNS name",
+			},
+			"unique_name": guestUniqueNameAttribute("name"),
+			"cpu":         vmCPUAttribute(),
+			"kvm": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+				Description: "Run the VM with KVM hardware virtualization. Disable it to fully emulate the VM, e.g. on nodes " +
+					"that are VMs themselves without nested virtualization. Defaults to true",
+			},
+			"arch": schema.StringAttribute{
+				Optional: true,
+				Description: "CPU architecture QEMU emulates, x86_64 or aarch64. Defaults to the architecture of the node. " +
+					"Only root@pam may set it",
+				Validators: []validator.String{
+					stringvalidator.OneOf("x86_64", "aarch64"),
+				},
+			},
+			"vmstatestorage": schema.StringAttribute{
+				Optional: true,
+				Description: "Storage the memory state of the VM is saved to when it is hibernated or snapshotted with its RAM. " +
+					"Defaults to a storage of the disks of the VM",
+			},
+			"hibernate_on_migrate": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "Hibernate a running VM to vmstatestorage, migrate it offline and resume it on its node when it " +
+					"is migrated back, instead of migrating it live. This keeps the memory state of VMs whose local disks or " +
+					"devices prevent a live migration. Defaults to false",
+			},
+			"memory": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(512),
+				Description: "Memory in MiB. Defaults to 512",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(16),
+				},
+			},
+			"disks":           vmDisksAttribute(),
+			"boot_order":      vmBootOrderAttribute(),
+			"network_devices": vmNetworkDevicesAttribute(),
+			"cloud_init":      vmCloudInitAttribute(),
+			"extra_config":    guestExtraConfigAttribute(),
+			"provisioning":    vmProvisioningAttribute(),
+			"clone": schema.SingleNestedAttribute{
+				Optional: true,
+				Description: "Clone the VM from another VM or a template instead of creating it empty. The name, CPU, " +
+					"memory, disks and network devices of the VM are applied to the clone",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"source_vm_id": schema.Int64Attribute{
+						Optional:    true,
+						Description: "ID of the VM or template to clone",
+						Validators: []validator.Int64{
+							int64validator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("template_name")),
+						},
+					},
+					"template_name": schema.StringAttribute{
+						Optional:    true,
+						Description: "Name of the template to clone, which must be unique in the cluster",
+					},
+					"full": schema.BoolAttribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(true),
+						Description: "Copy the disks of the source instead of creating a linked clone, which only templates support. Defaults to true",
+					},
+					"target_storage": schema.StringAttribute{
+						Optional:    true,
+						Description: "Storage the disks of a full clone are copied to. Defaults to the storage of the source",
+					},
+				},
+			},
+		},
+	}
+
+	for name, attribute := range vmOSAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range guestStartAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range guestPlacementAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range guestBackupAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range vmMemoryDeviceAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range vmNumaAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range guestSnapshotAttributes("changing its config or migrating it back to its node") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// vmCreateParams returns the parameters to create the VM of plan.
+func vmCreateParams(plan vmResourceModel) map[string]interface{} {
+	params := map[string]interface{}{
+		"vmid":   plan.VMID.ValueInt64(),
+		"memory": plan.Memory.ValueInt64(),
+	}
+	cpuParams, _ := vmCPUParams(plan.CPU, nil)
+	for key, value := range cpuParams {
+		params[key] = value
+	}
+	if !plan.KVM.IsNull() {
+		params["kvm"] = proxmoxtf.BoolToInt(plan.KVM.ValueBool())
+	}
+	if !plan.Arch.IsNull() {
+		params["arch"] = plan.Arch.ValueString()
+	}
+	if !plan.VMStateStorage.IsNull() {
+		params["vmstatestorage"] = plan.VMStateStorage.ValueString()
+	}
+	memoryParams, _ := vmMemoryDeviceParams(plan.vmMemoryDevicesModel, nil)
+	for key, value := range memoryParams {
+		params[key] = value
+	}
+	numaParams, _ := vmNumaParams(plan.vmNumaModel, nil)
+	for key, value := range numaParams {
+		params[key] = value
+	}
+	osParams, _ := vmOSParams(plan, nil)
+	for key, value := range osParams {
+		params[key] = value
+	}
+	for key, disk := range plan.Disks {
+		params[key] = newVMDisk(disk)
+	}
+	if !plan.Name.IsNull() {
+		params["name"] = plan.Name.ValueString()
+	}
+	for key, network := range plan.NetworkDevices {
+		params[key] = formatVMNetwork(network)
+	}
+	if !plan.BootOrder.IsNull() && !plan.BootOrder.IsUnknown() {
+		params["boot"] = formatBootOrder(plan.BootOrder)
+	}
+	ciParams, _ := cloudInitParams(plan.CloudInit, plan.VMID.ValueInt64(), nil)
+	for key, value := range ciParams {
+		params[key] = value
+	}
+
+	return params
+}
+
+// vmConfigParams returns the parameters of the config update that applies
+// plan to an existing VM with the current config. Disks are moved and
+// resized separately.
+func vmConfigParams(plan vmResourceModel, config map[string]interface{}) (map[string]interface{}, error) {
+	params, remove, err := vmDiskParams(plan.Disks, config)
+	if err != nil {
+		return nil, err
+	}
+	params["memory"] = plan.Memory.ValueInt64()
+	cpuParams, cpuRemove := vmCPUParams(plan.CPU, config)
+	for key, value := range cpuParams {
+		params[key] = value
+	}
+	remove = append(remove, cpuRemove...)
+	if !plan.KVM.IsNull() {
+		params["kvm"] = proxmoxtf.BoolToInt(plan.KVM.ValueBool())
+	}
+	// Only root@pam may set the arch, so it is only sent when it changes.
+	switch arch := proxmoxtf.ConfigValue(config["arch"]); {
+	case plan.Arch.IsNull() && arch != "":
+		remove = append(remove, "arch")
+	case !plan.Arch.IsNull() && plan.Arch.ValueString() != arch:
+		params["arch"] = plan.Arch.ValueString()
+	}
+	if !plan.VMStateStorage.IsNull() {
+		params["vmstatestorage"] = plan.VMStateStorage.ValueString()
+	} else if _, ok := config["vmstatestorage"]; ok {
+		remove = append(remove, "vmstatestorage")
+	}
+	memoryParams, memoryRemove := vmMemoryDeviceParams(plan.vmMemoryDevicesModel, config)
+	for key, value := range memoryParams {
+		params[key] = value
+	}
+	remove = append(remove, memoryRemove...)
+	numaParams, numaRemove := vmNumaParams(plan.vmNumaModel, config)
+	for key, value := range numaParams {
+		params[key] = value
+	}
+	remove = append(remove, numaRemove...)
+	osParams, osRemove := vmOSParams(plan, config)
+	for key, value := range osParams {
+		params[key] = value
+	}
+	remove = append(remove, osRemove...)
+
+	if plan.Name.IsNull() {
+		remove = append(remove, "name")
+	} else {
+		params["name"] = plan.Name.ValueString()
+	}
+	netParams, netRemove := vmNetworkParams(plan.NetworkDevices, config)
+	for key, value := range netParams {
+		params[key] = value
+	}
+	remove = append(remove, netRemove...)
+	// The boot order is always sent, as the API adds new devices to it.
+	if !plan.BootOrder.IsNull() && !plan.BootOrder.IsUnknown() {
+		params["boot"] = formatBootOrder(plan.BootOrder)
+	}
+	ciParams, ciRemove := cloudInitParams(plan.CloudInit, plan.VMID.ValueInt64(), config)
+	for key, value := range ciParams {
+		params[key] = value
+	}
+	remove = append(remove, ciRemove...)
+
+	if len(remove) > 0 {
+		params["delete"] = strings.Join(remove, ",")
+	}
+
+	return params, nil
+}
+
+// configInt64 returns the integer option key of a VM config, or def when
+// the option is not set.
+func configInt64(config map[string]interface{}, key string, def int64) (types.Int64, error) {
+	value := proxmoxtf.ConfigValue(config[key])
+	if value == "" {
+		return types.Int64Value(def), nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return types.Int64Null(), fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+
+	return types.Int64Value(number), nil
+}
+
+// readVM refreshes the model from the config of the VM.
+func (r *vmResource) readVM(ctx context.Context, model *vmResourceModel) error {
+	config, err := r.client.api.VMConfig(ctx, currentNode(model.Node, model.CurrentNode), model.VMID.ValueInt64())
+	if err != nil {
+		return err
+	}
+
+	model.Name = proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["name"]))
+	if model.CPU, err = parseVMCPU(config); err != nil {
+		return err
+	}
+	if model.Memory, err = configInt64(config, "memory", 512); err != nil {
+		return err
+	}
+	if model.vmMemoryDevicesModel, err = parseVMMemoryDevices(config); err != nil {
+		return err
+	}
+	if model.vmNumaModel, err = parseVMNuma(config); err != nil {
+		return err
+	}
+	model.KVM = types.BoolValue(proxmoxtf.ConfigValue(config["kvm"]) != "0")
+	model.Arch = proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["arch"]))
+	model.VMStateStorage = proxmoxtf.StringOrNull(proxmoxtf.ConfigValue(config["vmstatestorage"]))
+	parseVMOS(config, model)
+
+	// The image a disk was imported from is not part of the config. VMs
+	// booting from the network may have no disks.
+	prior := model.Disks
+	model.Disks = nil
+	for key, value := range vmDisks(config) {
+		disk, _, err := parseVMDisk(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		disk.ImportFrom = types.StringNull()
+		if p, ok := prior[key]; ok {
+			disk.ImportFrom = p.ImportFrom
+		}
+		if model.Disks == nil {
+			model.Disks = make(map[string]vmDiskModel)
+		}
+		model.Disks[key] = disk
+	}
+	if model.BootOrder, err = parseBootOrder(proxmoxtf.ConfigValue(config["boot"])); err != nil {
+		return err
+	}
+
+	if model.NetworkDevices, err = vmNetworkDevices(config); err != nil {
+		return err
+	}
+
+	if model.CloudInit != nil {
+		if model.CloudInit, err = parseCloudInit(config, model.VMID.ValueInt64(), *model.CloudInit); err != nil {
+			return fmt.Errorf("cloud-init: %w", err)
+		}
+	}
+
+	model.ExtraConfig, err = readExtraConfig(model.ExtraConfig, config)
+	return err
+}
+
+// vmCloneSource is the VM a clone is created from.
+type vmCloneSource struct {
+	node string
+	vmid int64
+}
+
+// cloneSource returns the VM the clone copies, either by its ID or by the
+// name of a template, among the guests of the cluster.
+func cloneSource(resources proxmox.ClusterResources, clone vmCloneModel) (vmCloneSource, error) {
+	var sources []vmCloneSource
+	for _, res := range resources {
+		if res.Type != "qemu" {
+			continue
+		}
+		if clone.TemplateName.IsNull() && int64(res.VMID) == clone.SourceVMID.ValueInt64() ||
+			res.Template == 1 && res.Name == clone.TemplateName.ValueString() {
+			sources = append(sources, vmCloneSource{node: res.Node, vmid: int64(res.VMID)})
+		}
+	}
+
+	switch {
+	case len(sources) == 1:
+		return sources[0], nil
+	case !clone.TemplateName.IsNull() && len(sources) > 1:
+		return vmCloneSource{}, fmt.Errorf("template name %q is used by %d templates", clone.TemplateName.ValueString(), len(sources))
+	case !clone.TemplateName.IsNull():
+		return vmCloneSource{}, fmt.Errorf("template %q not found", clone.TemplateName.ValueString())
+	default:
+		return vmCloneSource{}, fmt.Errorf("VM %d not found", clone.SourceVMID.ValueInt64())
+	}
+}
+
+// cloneVM creates the VM of plan as a clone of its source and returns the
+// UPID of the clone task.
+func (r *vmResource) cloneVM(ctx context.Context, plan vmResourceModel) (string, error) {
+	cluster, err := r.client.Cluster(ctx)
+	if err != nil {
+		return "", err
+	}
+	resources, err := cluster.Resources(ctx, "vm")
+	if err != nil {
+		return "", err
+	}
+	source, err := cloneSource(resources, *plan.Clone)
+	if err != nil {
+		return "", err
+	}
+
+	tflog.SubsystemInfo(ctx, logVM, "Cloning VM", map[string]interface{}{
+		"source_node": source.node,
+		"source_vmid": source.vmid,
+		"full":        plan.Clone.Full.ValueBool(),
+	})
+
+	req := pveapi.CloneVMRequest{
+		NewID:   plan.VMID.ValueInt64(),
+		Name:    plan.Name.ValueString(),
+		Full:    proxmoxtf.BoolToInt(plan.Clone.Full.ValueBool()),
+		Storage: plan.Clone.TargetStorage.ValueString(),
+	}
+	if source.node != plan.Node.ValueString() {
+		req.Target = plan.Node.ValueString()
+	}
+	upid, err := r.client.api.CloneVM(ctx, source.node, source.vmid, req)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return upid, err
+}
+
+// configureClone applies the config of plan to the VM cloned from its source
+// and moves, grows and removes the cloned disks as planned.
+func (r *vmResource) configureClone(ctx context.Context, plan vmResourceModel) error {
+	node, vmid := plan.Node.ValueString(), plan.VMID.ValueInt64()
+	config, err := r.client.api.VMConfig(ctx, node, vmid)
+	if err != nil {
+		return err
+	}
+	for key, value := range vmDisks(config) {
+		disk, ok := plan.Disks[key]
+		if !ok || disk.Size.IsUnknown() {
+			continue
+		}
+		cloned, _, err := parseVMDisk(value)
+		if err != nil {
+			return fmt.Errorf("%s of the clone: %w", key, err)
+		}
+		if disk.Size.ValueInt64() < cloned.Size.ValueInt64() {
+			return fmt.Errorf("the cloned %s disk has %d GiB, more than the planned size of %d GiB", key, cloned.Size.ValueInt64(), disk.Size.ValueInt64())
+		}
+	}
+
+	params, err := vmConfigParams(plan, config)
+	if err != nil {
+		return err
+	}
+	err = r.client.updateVMConfig(ctx, node, vmid, params)
+	if err == nil {
+		err = r.client.updateDisks(ctx, node, vmid, plan.Disks, config)
+	}
+
+	return err
+}
+
+// hibernateVM suspends a running VM to disk, saving its memory state to its
+// vmstatestorage. The VM is stopped afterwards and resumes when started.
+func (c *apiClient) hibernateVM(ctx context.Context, node string, vmid int64) error {
+	tflog.SubsystemInfo(ctx, logVM, "Hibernating VM")
+
+	var upid string
+	if err := c.Post(ctx, guestPath(node, "qemu", vmid)+"/status/suspend", map[string]interface{}{"todisk": 1}, &upid); err != nil {
+		return err
+	}
+	_, err := c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
+
+// migrateHibernated migrates a VM to target like migrateGuest, but a running
+// VM is hibernated and migrated offline together with its memory state, then
+// resumed on target. A VM whose migration fails is resumed where it is.
+func (c *apiClient) migrateHibernated(ctx context.Context, guestType string, vmid int64, target string) (string, error) {
+	location, found, err := c.locateGuest(ctx, guestType, vmid)
+	if err != nil || !found || location.node == target || location.status != "running" {
+		return c.migrateGuest(ctx, guestType, vmid, target)
+	}
+
+	if err := c.hibernateVM(ctx, location.node, vmid); err != nil {
+		return "", fmt.Errorf("hibernating the VM: %w", err)
+	}
+	upid, err := c.migrateGuest(ctx, guestType, vmid, target)
+	if err != nil {
+		if resumeErr := c.startGuest(ctx, location.node, guestType, vmid, "", 0); resumeErr != nil {
+			return upid, fmt.Errorf("%w, and resuming the VM on node %s failed: %v", err, location.node, resumeErr)
+		}
+		return upid, err
+	}
+
+	return upid, c.startGuest(ctx, target, guestType, vmid, "", 0)
+}
+
+// destroyVM destroys a stopped VM together with its disks and the jobs and
+// access rules that reference it.
+func (c *apiClient) destroyVM(ctx context.Context, node string, vmid int64) error {
+	tflog.SubsystemInfo(ctx, logVM, "Destroying VM")
+
+	var upid string
+	if err := c.Delete(ctx, guestPath(node, "qemu", vmid)+"?purge=1", &upid); err != nil {
+		return err
+	}
+	_, err := c.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+	return err
+}
+
+// ValidateConfig rejects disks without storage or size on VMs that are not
+// cloned, drive options their interface does not support, the host CPU
+// model without KVM, cloud-init drives Windows cannot read, VMs without
+// disks that do not boot from the network, provisioning of VMs that are not
+// started, balloon minimums the VM cannot shrink to, NUMA nodes that do not
+// add up to its memory and target storages of linked clones.
+func (r *vmResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config vmResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.CloudInit != nil {
+		for kind := range config.CloudInit.CustomData {
+			if _, ok := config.CloudInit.Custom[kind]; ok {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("cloud_init").AtName("custom_data").AtMapKey(kind),
+					"Conflicting Cloud-Init Snippets",
+					fmt.Sprintf("The %s configuration is set by both custom and custom_data, set only one of them.", kind),
+				)
+			}
+		}
+	}
+
+	validateVMDisks(config.Disks, &resp.Diagnostics)
+	validateVMCPU(config.CPU, &resp.Diagnostics)
+	validateVMKVM(config.KVM, config.CPU, &resp.Diagnostics)
+	validateVMWindows(config, &resp.Diagnostics)
+	validateVMBoot(config, &resp.Diagnostics)
+	validateVMProvisioning(config.Provisioning, config.Start, &resp.Diagnostics)
+	validateVMMemoryDevices(config.vmMemoryDevicesModel, config.Memory, &resp.Diagnostics)
+	validateVMNuma(config.vmNumaModel, config.Memory, &resp.Diagnostics)
+
+	if config.Clone != nil {
+		if !config.Clone.TargetStorage.IsNull() && !config.Clone.Full.IsNull() && !config.Clone.Full.IsUnknown() && !config.Clone.Full.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("clone").AtName("target_storage"),
+				"Invalid VM Clone Storage",
+				"Linked clones keep their disks on the storage of the template, target_storage requires a full clone.",
+			)
+		}
+		return
+	}
+
+	for key, disk := range config.Disks {
+		required := map[string]attr.Value{
+			"storage": disk.Storage,
+		}
+		if disk.ImportFrom.IsNull() {
+			required["size"] = disk.Size
+		}
+		for name, value := range required {
+			if value.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("disks").AtMapKey(key).AtName(name),
+					"Missing VM Disk Option",
+					fmt.Sprintf("The %s of the %s disk is required unless the VM is cloned or the disk imported.", name, key),
+				)
+			}
+		}
+	}
+}
+
+// vmSnapshotChanges are the attributes of a VM whose changes in place are
+// preceded by the snapshot of snapshot_before_update.
+var vmSnapshotChanges = []path.Path{
+	path.Root("name"),
+	path.Root("cpu"),
+	path.Root("kvm"),
+	path.Root("arch"),
+	path.Root("os_type"),
+	path.Root("local_time"),
+	path.Root("vmstatestorage"),
+	path.Root("memory"),
+	path.Root("ivshmem_size"),
+	path.Root("ivshmem_name"),
+	path.Root("balloon"),
+	path.Root("balloon_minimum"),
+	path.Root("hugepages"),
+	path.Root("keep_hugepages"),
+	path.Root("numa_nodes"),
+	path.Root("disks"),
+	path.Root("boot_order"),
+	path.Root("network_devices"),
+	path.Root("cloud_init"),
+	path.Root("extra_config"),
+	path.Root("current_node"),
+}
+
+// ModifyPlan fills in the default_node of the provider unless the VM is
+// placed, rejects guest IDs outside of its vmid_range, disks that shrink,
+// storages without the content the VM needs, network devices on vnets of SDN
+// zones that are not deployed to the node and PCI devices of extra_config
+// that another VM uses, and plans a new last_snapshot for updates that take
+// a snapshot and new exit codes for provisioning commands that run again.
+func (r *vmResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if !configuresPlacement(ctx, req.Config, &resp.Diagnostics) {
+		r.client.planDefaultNode(ctx, req, resp)
+	}
+	r.client.validateVMIDPlan(ctx, resp.Plan, &resp.Diagnostics)
+	planGuestNode(ctx, req, resp)
+	r.client.planUniqueName(ctx, "name", req, resp)
+	planDisks(ctx, req, resp)
+	planLocalTime(ctx, req, resp)
+	for _, storage := range vmStorages {
+		r.client.validateStorageContentPlan(ctx, req, resp, storage.expression, storage.content)
+	}
+	r.client.validateVnetNodePlan(ctx, resp.Plan, req.State, path.MatchRoot("network_devices").AtAnyMapKey().AtName("bridge"), &resp.Diagnostics)
+	r.client.validateHostPCIPlan(ctx, resp.Plan, &resp.Diagnostics)
+	planProvisioning(ctx, req, resp)
+	planSnapshotBeforeUpdate(ctx, req, resp, vmSnapshotChanges...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vmResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan vmResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.LastSnapshot = types.StringNull()
+	if plan.VMID.IsUnknown() {
+		vmid, err := r.client.nextVMID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Allocate Proxmox Guest ID",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		plan.VMID = types.Int64Value(vmid)
+	}
+
+	if plan.Node.IsUnknown() && plan.Placement != nil {
+		cpus := plan.CPU.Cores.ValueInt64() * plan.CPU.Sockets.ValueInt64()
+		if !plan.CPU.VCPUs.IsNull() {
+			cpus = plan.CPU.VCPUs.ValueInt64()
+		}
+		node, releasePlacement, err := r.client.placeGuest(ctx, *plan.Placement, uint64(plan.Memory.ValueInt64())<<20, float64(cpus))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Place Proxmox VM",
+				apiErrorDetail(err),
+			)
+			return
+		}
+		defer releasePlacement()
+		plan.Node = types.StringValue(node)
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Creating VM")
+
+	releaseName := r.client.claimUniqueName(ctx, plan.UniqueName, path.Root("name"), plan.Name, plan.VMID.ValueInt64(), &resp.Diagnostics)
+	defer releaseName()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	release := r.client.acquireNode(ctx, plan.Node.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	err := r.client.uploadSnippets(ctx, plan.Node.ValueString(), plan.VMID.ValueInt64(), plan.CloudInit)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Write Proxmox Cloud-Init Snippets",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	var upid string
+	if plan.Clone != nil {
+		upid, err = r.cloneVM(ctx, plan)
+	} else {
+		upid, err = r.client.api.CreateVM(ctx, plan.Node.ValueString(), vmCreateParams(plan))
+		if err == nil {
+			_, err = r.client.waitForTask(ctx, proxmox.UPID(upid), defaultTaskWaitTimeout)
+		}
+	}
+	plan.LastTaskUPID = types.StringValue(upid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	if plan.Clone != nil {
+		err = r.configureClone(ctx, plan)
+	} else {
+		err = r.client.growImportedDisks(ctx, plan.Node.ValueString(), plan.VMID.ValueInt64(), plan.Disks, nil)
+	}
+	if err == nil {
+		err = r.client.applyExtraConfig(ctx, plan.Node.ValueString(), "qemu", plan.VMID.ValueInt64(), extraConfigValues(plan.ExtraConfig), nil)
+	}
+	if err != nil {
+		// Keep the VM in the state, so that Terraform taints and replaces
+		// it instead of losing track of it.
+		if r.readVM(ctx, &plan) == nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Configure Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// The generated MAC address is only known once the VM exists.
+	err = r.readVM(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	if plan.Start.ValueBool() {
+		timeout := time.Duration(plan.WaitForStatusTimeout.ValueInt64()) * time.Second
+		err = r.client.startGuest(ctx, plan.Node.ValueString(), "qemu", plan.VMID.ValueInt64(), plan.WaitForStatus.ValueString(), timeout)
+		if err != nil {
+			// Keep the created guest in the state, so that Terraform
+			// taints and replaces it instead of losing track of it.
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.AddError(
+				"Unable to Start Proxmox VM",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	if plan.Provisioning != nil {
+		err = r.client.provision(ctx, plan.Node.ValueString(), plan.VMID.ValueInt64(), plan.Provisioning)
+		if err != nil {
+			// Keep the created guest in the state, so that Terraform
+			// taints and replaces it instead of losing track of it.
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.AddError(
+				"Unable to Run Commands Through Proxmox Guest Agent",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	err = r.client.observeGuest(ctx, "qemu", plan.VMID.ValueInt64(), &plan.CurrentNode, &plan.Status)
+	if err != nil {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vmResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	location, found, err := r.client.locateGuest(ctx, "qemu", state.VMID.ValueInt64())
+	if err == nil && !found {
+		// The VM was removed outside of Terraform.
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err == nil {
+		state.CurrentNode = types.StringValue(location.node)
+		state.Status = types.StringValue(location.status)
+		err = r.readVM(ctx, &state)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *vmResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var plan, state vmResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, plan.Node.ValueString())
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, plan.VMID.ValueInt64())
+	tflog.SubsystemInfo(ctx, logVM, "Updating VM")
+
+	// Only a new name is claimed, the VM keeps its current one otherwise.
+	name := plan.Name
+	if name.Equal(state.Name) {
+		name = types.StringNull()
+	}
+	releaseName := r.client.claimUniqueName(ctx, plan.UniqueName, path.Root("name"), name, plan.VMID.ValueInt64(), &resp.Diagnostics)
+	defer releaseName()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The VM is changed where it runs, which is not necessarily its
+	// configured node after an HA failover, and snapshotted there first.
+	r.client.snapshotBeforeUpdate(ctx, currentNode(state.Node, state.CurrentNode), "qemu", plan.VMID.ValueInt64(),
+		&plan.LastSnapshot, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.LastTaskUPID.IsUnknown() {
+		plan.LastTaskUPID = state.LastTaskUPID
+	}
+	if !plan.CurrentNode.IsUnknown() && !plan.CurrentNode.Equal(state.CurrentNode) {
+		migrate := r.client.migrateGuest
+		if plan.HibernateOnMigrate.ValueBool() {
+			migrate = r.client.migrateHibernated
+		}
+		upid, err := migrate(ctx, "qemu", plan.VMID.ValueInt64(), plan.CurrentNode.ValueString())
+		if upid != "" {
+			plan.LastTaskUPID = types.StringValue(upid)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Migrate Proxmox VM",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	} else {
+		plan.CurrentNode = state.CurrentNode
+	}
+	node := currentNode(plan.Node, plan.CurrentNode)
+	err := r.client.uploadSnippets(ctx, node, plan.VMID.ValueInt64(), plan.CloudInit)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Write Proxmox Cloud-Init Snippets",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	config, err := r.client.api.VMConfig(ctx, node, plan.VMID.ValueInt64())
+	var params map[string]interface{}
+	if err == nil {
+		params, err = vmConfigParams(plan, config)
+	}
+	if err == nil {
+		err = r.client.updateVMConfig(ctx, node, plan.VMID.ValueInt64(), params)
+	}
+	if err == nil {
+		err = r.client.updateDisks(ctx, node, plan.VMID.ValueInt64(), plan.Disks, config)
+	}
+	if err == nil {
+		err = r.client.applyExtraConfig(ctx, node, "qemu", plan.VMID.ValueInt64(),
+			extraConfigValues(plan.ExtraConfig), extraConfigValues(state.ExtraConfig))
+	}
+	if err == nil {
+		err = r.readVM(ctx, &plan)
+	}
+	// The observed values are kept from state unless the VM was migrated,
+	// they are refreshed on the next read.
+	if err == nil && plan.Status.IsUnknown() {
+		err = r.client.observeGuest(ctx, "qemu", plan.VMID.ValueInt64(), &plan.CurrentNode, &plan.Status)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	if provisioningRuns(plan.Provisioning, state.Provisioning) {
+		err = r.client.provision(ctx, node, plan.VMID.ValueInt64(), plan.Provisioning)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Run Commands Through Proxmox Guest Agent",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	if stale := staleSnippets(state.CloudInit, plan.CloudInit); len(stale) > 0 {
+		err = r.client.removeSnippets(ctx, node, plan.VMID.ValueInt64(), state.CloudInit.SnippetsStorage.ValueString(), stale)
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Unable to Remove Proxmox Cloud-Init Snippets",
+				apiErrorDetail(err),
+			)
+		}
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vmResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.logContext(ctx)
+
+	var state vmResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	node, vmid := currentNode(state.Node, state.CurrentNode), state.VMID.ValueInt64()
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldNode, node)
+	ctx = tflog.SubsystemSetField(ctx, logVM, logFieldVMID, vmid)
+
+	release := r.client.acquireNode(ctx, node, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	if state.BackupBeforeDestroy.ValueBool() {
+		err := r.client.backupGuest(ctx, node, vmid, state.BackupStorage.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Back Up Proxmox VM",
+				apiErrorDetail(err),
+			)
+			return
+		}
+	}
+
+	err := r.client.stopGuest(ctx, node, "qemu", vmid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Stop Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	err = r.client.destroyVM(ctx, node, vmid)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Proxmox VM",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	if stale := staleSnippets(state.CloudInit, nil); len(stale) > 0 {
+		err = r.client.removeSnippets(ctx, node, vmid, state.CloudInit.SnippetsStorage.ValueString(), stale)
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Unable to Remove Proxmox Cloud-Init Snippets",
+				apiErrorDetail(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/luthermonson/go-proxmox"
+)
+
+func TestVMCreateParams(t *testing.T) {
+	plan := vmResourceModel{
+		VMID: types.Int64Value(100),
+		Name: types.StringValue("web"),
+		CPU: &vmCPUModel{
+			Cores:   types.Int64Value(2),
+			Sockets: types.Int64Value(1),
+			Type:    types.StringValue("host"),
+			VCPUs:   types.Int64Null(),
+		},
+		Memory:         types.Int64Value(2048),
+		KVM:            types.BoolValue(true),
+		Arch:           types.StringNull(),
+		VMStateStorage: types.StringValue("local-lvm"),
+		BootOrder:      bootOrderList("net0", "scsi0"),
+		Disks: map[string]vmDiskModel{
+			"scsi0": {
+				Storage:  types.StringValue("local-lvm"),
+				Size:     types.Int64Value(32),
+				Format:   types.StringUnknown(),
+				Cache:    types.StringNull(),
+				Discard:  types.BoolValue(true),
+				SSD:      types.BoolValue(true),
+				IOThread: types.BoolValue(true),
+				Backup:   types.BoolValue(true),
+				Serial:   types.StringNull(),
+			},
+			"virtio1": {
+				Storage:  types.StringValue("local"),
+				Size:     types.Int64Value(100),
+				Format:   types.StringValue("qcow2"),
+				Cache:    types.StringValue("writeback"),
+				Discard:  types.BoolValue(false),
+				SSD:      types.BoolValue(false),
+				IOThread: types.BoolValue(false),
+				Backup:   types.BoolValue(false),
+				Serial:   types.StringValue("data"),
+			},
+		},
+		NetworkDevices: map[string]vmNetworkModel{
+			"net0": {
+				Model:    types.StringValue("virtio"),
+				Bridge:   types.StringValue("vmbr0"),
+				VLAN:     types.Int64Value(10),
+				Firewall: types.BoolValue(true),
+				MTU:      types.Int64Null(),
+				Rate:     types.Float64Null(),
+				Queues:   types.Int64Null(),
+				MAC:      types.StringUnknown(),
+			},
+		},
+	}
+
+	want := map[string]interface{}{
+		"vmid":           int64(100),
+		"name":           "web",
+		"cores":          int64(2),
+		"sockets":        int64(1),
+		"cpu":            "host",
+		"memory":         int64(2048),
+		"kvm":            1,
+		"vmstatestorage": "local-lvm",
+		"scsi0":          "local-lvm:32,discard=on,iothread=1,ssd=1",
+		"virtio1":        "local:100,backup=0,cache=writeback,format=qcow2,serial=data",
+		"net0":           "virtio,bridge=vmbr0,firewall=1,tag=10",
+		"boot":           "order=net0;scsi0",
+	}
+	if got := vmCreateParams(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestVMConfigParams(t *testing.T) {
+	plan := vmResourceModel{
+		Name: types.StringNull(),
+		CPU: &vmCPUModel{
+			Cores:   types.Int64Value(4),
+			Sockets: types.Int64Value(1),
+			Type:    types.StringNull(),
+			VCPUs:   types.Int64Null(),
+		},
+		Memory:         types.Int64Value(4096),
+		KVM:            types.BoolValue(false),
+		Arch:           types.StringNull(),
+		VMStateStorage: types.StringNull(),
+		vmMemoryDevicesModel: vmMemoryDevicesModel{
+			IvshmemSize:    types.Int64Null(),
+			IvshmemName:    types.StringNull(),
+			Balloon:        types.BoolValue(true),
+			BalloonMinimum: types.Int64Value(1024),
+		},
+	}
+
+	want := map[string]interface{}{
+		"cores":   int64(4),
+		"sockets": int64(1),
+		"memory":  int64(4096),
+		"kvm":     0,
+		"balloon": int64(1024),
+		"delete":  "scsi0,arch,vmstatestorage,ivshmem,name,net0",
+	}
+	config := map[string]interface{}{
+		"ivshmem":        "size=32",
+		"arch":           "aarch64",
+		"vmstatestorage": "local-lvm",
+		"scsi0":          "local-lvm:vm-100-disk-0,size=32G",
+		"net0":           "virtio=BC:24:11:00:00:01,bridge=vmbr0",
+	}
+	got, err := vmConfigParams(plan, config)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestCloneSource(t *testing.T) {
+	resources := proxmox.ClusterResources{
+		{Type: "lxc", VMID: 100, Node: "pve1", Name: "debian-12"},
+		{Type: "qemu", VMID: 9000, Node: "pve1", Name: "debian-12", Template: 1},
+		{Type: "qemu", VMID: 9001, Node: "pve2", Name: "ubuntu-24", Template: 1},
+		{Type: "qemu", VMID: 9002, Node: "pve2", Name: "ubuntu-24", Template: 1},
+		{Type: "qemu", VMID: 101, Node: "pve2", Name: "debian-12"},
+	}
+
+	tests := []struct {
+		clone   vmCloneModel
+		want    vmCloneSource
+		wantErr bool
+	}{
+		{clone: vmCloneModel{SourceVMID: types.Int64Value(101), TemplateName: types.StringNull()}, want: vmCloneSource{node: "pve2", vmid: 101}},
+		{clone: vmCloneModel{SourceVMID: types.Int64Value(100), TemplateName: types.StringNull()}, wantErr: true},
+		{clone: vmCloneModel{SourceVMID: types.Int64Null(), TemplateName: types.StringValue("debian-12")}, want: vmCloneSource{node: "pve1", vmid: 9000}},
+		{clone: vmCloneModel{SourceVMID: types.Int64Null(), TemplateName: types.StringValue("ubuntu-24")}, wantErr: true},
+		{clone: vmCloneModel{SourceVMID: types.Int64Null(), TemplateName: types.StringValue("fedora-40")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := cloneSource(resources, tt.clone)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("cloneSource(%v, %v) = %+v, %v, want %+v", tt.clone.SourceVMID, tt.clone.TemplateName, got, err, tt.want)
+		}
+	}
+}
+
+func TestMigrateHibernated(t *testing.T) {
+	running := `[{"type":"qemu","vmid":100,"node":"pve2","status":"running"}]`
+	hibernated := `[{"type":"qemu","vmid":100,"node":"pve2","status":"stopped"}]`
+	migrated := `[{"type":"qemu","vmid":100,"node":"pve","status":"stopped"}]`
+	upid := fmt.Sprintf("%q", testUPID)
+
+	tests := map[string]struct {
+		resources string
+		requests  []string
+	}{
+		"running": {
+			resources: running,
+			requests: []string{
+				"POST /nodes/pve2/qemu/100/status/suspend",
+				"POST /nodes/pve2/qemu/100/migrate",
+				"POST /nodes/pve/qemu/100/status/start",
+			},
+		},
+		"stopped": {
+			resources: hibernated,
+			requests: []string{
+				"POST /nodes/pve2/qemu/100/migrate",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeAPI{
+				responses: map[string]string{
+					"GET /cluster/status":                      `[]`,
+					"GET /cluster/resources":                   tt.resources,
+					"POST /nodes/pve2/qemu/100/status/suspend": upid,
+					"POST /nodes/pve2/qemu/100/migrate":        upid,
+					"POST /nodes/pve/qemu/100/status/start":    upid,
+				},
+				changes: map[string]map[string]string{
+					"POST /nodes/pve2/qemu/100/status/suspend": {"GET /cluster/resources": hibernated},
+					"POST /nodes/pve2/qemu/100/migrate":        {"GET /cluster/resources": migrated},
+				},
+			}
+
+			got, err := fakeClient(t, api).migrateHibernated(context.Background(), "qemu", 100, "pve")
+			if err != nil || got != testUPID {
+				t.Fatalf("got %q, %v, want %q", got, err, testUPID)
+			}
+
+			last := -1
+			for _, key := range tt.requests {
+				i := api.index(key)
+				if i <= last {
+					t.Fatalf("request %s not made after the previous ones in %v", key, api.requests)
+				}
+				last = i
+			}
+			if name == "stopped" && api.index("POST /nodes/pve/qemu/100/status/start") >= 0 {
+				t.Errorf("stopped VM was started: %v", api.requests)
+			}
+		})
+	}
+}
+
+func TestVMResourcePlanDiskStorage(t *testing.T) {
+	tests := map[string]struct {
+		storage   string
+		wantError bool
+	}{
+		"image storage":    {storage: "local-lvm"},
+		"template storage": {storage: "local", wantError: true},
+	}
+
+	api := &fakeAPI{responses: map[string]string{
+		"GET /storage": `[{"storage":"local","type":"dir","content":"iso,vztmpl,backup"},{"storage":"local-lvm","type":"lvmthin","content":"images,rootdir"}]`,
+	}}
+	res := configuredResource(t, "proxmox_vm", api)
+	s := resourceSchema(t, res)
+	disksType := s.Type().TerraformType(context.Background()).(tftypes.Object).AttributeTypes["disks"].(tftypes.Map)
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			disk := map[string]tftypes.Value{}
+			for attribute, attributeType := range disksType.ElementType.(tftypes.Object).AttributeTypes {
+				disk[attribute] = tftypes.NewValue(attributeType, nil)
+			}
+			disk["storage"] = tftypes.NewValue(tftypes.String, tt.storage)
+			disk["size"] = tftypes.NewValue(tftypes.Number, 32)
+			plan := tfsdk.Plan{Schema: s, Raw: resourceValue(t, s, map[string]tftypes.Value{
+				"vm_id": tftypes.NewValue(tftypes.Number, 100),
+				"disks": tftypes.NewValue(disksType, map[string]tftypes.Value{
+					"scsi0": tftypes.NewValue(disksType.ElementType, disk),
+				}),
+			})}
+
+			resp := resource.ModifyPlanResponse{Plan: plan}
+			res.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: s, Raw: plan.Raw},
+				Plan:   plan,
+				State:  tfsdk.State{Schema: s, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+			}, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Fatalf("got error %t, want %t: %v", got, tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
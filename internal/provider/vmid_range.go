@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Strategies to allocate the IDs of new guests.
+const (
+	// vmidStrategyNextID lets the cluster pick the next free ID.
+	vmidStrategyNextID = "nextid"
+
+	// vmidStrategyRange picks the lowest free ID, reusing the IDs of
+	// destroyed guests.
+	vmidStrategyRange = "range"
+
+	// vmidStrategySequential picks the ID after the highest one in use, so
+	// that IDs of destroyed guests, and their backups, are not reused.
+	vmidStrategySequential = "sequential"
+)
+
+// Bounds of the guest IDs Proxmox VE accepts.
+const (
+	minVMID = 100
+	maxVMID = 999999999
+)
+
+// resolveVMIDStrategy returns the allocation strategy for the configured
+// strategy, defaulting to range with a vmid_range and nextid without.
+func resolveVMIDStrategy(strategy string, hasRange bool) (string, error) {
+	switch strategy {
+	case "":
+		if hasRange {
+			return vmidStrategyRange, nil
+		}
+		return vmidStrategyNextID, nil
+	case vmidStrategyNextID:
+		if hasRange {
+			return "", fmt.Errorf("the %s strategy cannot allocate from a vmid_range, use %s or %s instead",
+				vmidStrategyNextID, vmidStrategyRange, vmidStrategySequential)
+		}
+	case vmidStrategyRange:
+		if !hasRange {
+			return "", fmt.Errorf("the %s strategy requires a vmid_range", vmidStrategyRange)
+		}
+	case vmidStrategySequential:
+	default:
+		return "", fmt.Errorf("unknown strategy %q, expected %s, %s or %s",
+			strategy, vmidStrategyNextID, vmidStrategyRange, vmidStrategySequential)
+	}
+
+	return strategy, nil
+}
+
+// vmidRange is the range of guest IDs a provider instance may use, bounds
+// included.
+type vmidRange struct {
+	min int64
+	max int64
+}
+
+// vmidRangeModel maps the vmid_range provider schema data.
+type vmidRangeModel struct {
+	Min types.Int64 `tfsdk:"min"`
+	Max types.Int64 `tfsdk:"max"`
+}
+
+// contains reports whether vmid lies within the range.
+func (r vmidRange) contains(vmid int64) bool {
+	return vmid >= r.min && vmid <= r.max
+}
+
+// firstFree returns the lowest ID of the range that is not in used.
+func (r vmidRange) firstFree(used map[int64]bool) (int64, bool) {
+	for vmid := r.min; vmid <= r.max; vmid++ {
+		if !used[vmid] {
+			return vmid, true
+		}
+	}
+
+	return 0, false
+}
+
+// afterHighest returns the ID after the highest one of the range in used.
+func (r vmidRange) afterHighest(used map[int64]bool) (int64, bool) {
+	next := r.min
+	for vmid := range used {
+		if r.contains(vmid) && vmid >= next {
+			next = vmid + 1
+		}
+	}
+
+	return next, next <= r.max
+}
+
+// nextVMID allocates the ID of a new guest with the vmid_strategy of the
+// provider, within the vmid_range if configured.
+func (c *apiClient) nextVMID(ctx context.Context) (int64, error) {
+	cluster, err := c.Cluster(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.vmidStrategy == vmidStrategyNextID {
+		vmid, err := cluster.NextID(ctx)
+		return int64(vmid), err
+	}
+
+	resources, err := cluster.Resources(ctx, "vm")
+	if err != nil {
+		return 0, err
+	}
+
+	used := map[int64]bool{}
+	for _, res := range resources {
+		used[int64(res.VMID)] = true
+	}
+
+	ids := vmidRange{min: minVMID, max: maxVMID}
+	if c.vmidRange != nil {
+		ids = *c.vmidRange
+	}
+
+	var vmid int64
+	var ok bool
+	if c.vmidStrategy == vmidStrategySequential {
+		vmid, ok = ids.afterHighest(used)
+	} else {
+		vmid, ok = ids.firstFree(used)
+	}
+	if !ok {
+		return 0, fmt.Errorf("no free guest ID between %d and %d with the %s strategy", ids.min, ids.max, c.vmidStrategy)
+	}
+	tflog.SubsystemDebug(ctx, logVM, "Allocated guest ID", map[string]interface{}{
+		logFieldVMID: vmid,
+		"strategy":   c.vmidStrategy,
+	})
+
+	return vmid, nil
+}
+
+// validateVMIDPlan adds an error when the planned vm_id lies outside the
+// vmid_range of the provider.
+func (c *apiClient) validateVMIDPlan(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) {
+	// Nothing to validate on destroy or before the provider is configured.
+	if c == nil || c.vmidRange == nil || plan.Raw.IsNull() {
+		return
+	}
+
+	var vmid types.Int64
+	diags.Append(plan.GetAttribute(ctx, path.Root("vm_id"), &vmid)...)
+	if diags.HasError() || vmid.IsNull() || vmid.IsUnknown() {
+		return
+	}
+
+	if !c.vmidRange.contains(vmid.ValueInt64()) {
+		diags.AddAttributeError(
+			path.Root("vm_id"),
+			"Guest ID Outside of vmid_range",
+			fmt.Sprintf("The guest ID %d lies outside of the vmid_range %d-%d of the provider configuration.",
+				vmid.ValueInt64(), c.vmidRange.min, c.vmidRange.max),
+		)
+	}
+}
@@ -0,0 +1,91 @@
+package provider
+
+import "testing"
+
+func TestVMIDRangeFirstFree(t *testing.T) {
+	r := vmidRange{min: 1000, max: 1002}
+
+	cases := map[string]struct {
+		used map[int64]bool
+		want int64
+		ok   bool
+	}{
+		"empty":   {used: map[int64]bool{}, want: 1000, ok: true},
+		"gap":     {used: map[int64]bool{1000: true, 1002: true}, want: 1001, ok: true},
+		"outside": {used: map[int64]bool{100: true, 1003: true}, want: 1000, ok: true},
+		"full":    {used: map[int64]bool{1000: true, 1001: true, 1002: true}, ok: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := r.firstFree(tc.used)
+			if ok != tc.ok || got != tc.want {
+				t.Errorf("got %d, %t, want %d, %t", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestVMIDRangeContains(t *testing.T) {
+	r := vmidRange{min: 1000, max: 1999}
+
+	for vmid, want := range map[int64]bool{999: false, 1000: true, 1999: true, 2000: false} {
+		if got := r.contains(vmid); got != want {
+			t.Errorf("contains(%d) = %t, want %t", vmid, got, want)
+		}
+	}
+}
+
+func TestVMIDRangeAfterHighest(t *testing.T) {
+	r := vmidRange{min: 1000, max: 1002}
+
+	cases := map[string]struct {
+		used map[int64]bool
+		want int64
+		ok   bool
+	}{
+		"empty":    {used: map[int64]bool{}, want: 1000, ok: true},
+		"gap":      {used: map[int64]bool{1000: true}, want: 1001, ok: true},
+		"no reuse": {used: map[int64]bool{1001: true}, want: 1002, ok: true},
+		"outside":  {used: map[int64]bool{100: true, 1003: true}, want: 1000, ok: true},
+		"full":     {used: map[int64]bool{1002: true}, ok: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := r.afterHighest(tc.used)
+			if ok != tc.ok || (ok && got != tc.want) {
+				t.Errorf("got %d, %t, want %d, %t", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestResolveVMIDStrategy(t *testing.T) {
+	cases := map[string]struct {
+		strategy string
+		hasRange bool
+		want     string
+		wantErr  bool
+	}{
+		"default":             {want: vmidStrategyNextID},
+		"default with range":  {hasRange: true, want: vmidStrategyRange},
+		"sequential":          {strategy: "sequential", want: vmidStrategySequential},
+		"sequential in range": {strategy: "sequential", hasRange: true, want: vmidStrategySequential},
+		"range without range": {strategy: "range", wantErr: true},
+		"nextid with range":   {strategy: "nextid", hasRange: true, wantErr: true},
+		"unknown":             {strategy: "random", wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveVMIDStrategy(tc.strategy, tc.hasRange)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package proxmoxtf converts between the values of the Proxmox VE API and
+// the types of the Terraform plugin framework.
+//
+// The API omits unset options or reports them as zero values, and encodes
+// booleans as 0/1. The helpers map these onto null and typed framework
+// values, so that unset options stay null in the state.
+package proxmoxtf
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// StringOrNull maps an empty string of the API to null.
+func StringOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(value)
+}
+
+// Int64OrNull maps a zero integer of the API to null.
+func Int64OrNull(value int64) types.Int64 {
+	if value == 0 {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(value)
+}
+
+// Bool converts the 0/1 representation used by the API to a bool.
+func Bool(value int) types.Bool {
+	return types.BoolValue(value == 1)
+}
+
+// BoolToInt converts a bool to the 0/1 representation used by the API.
+func BoolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// UnixTime formats a unix timestamp of the API in RFC 3339 format.
+func UnixTime(unix int64) types.String {
+	return types.StringValue(time.Unix(unix, 0).UTC().Format(time.RFC3339))
+}
+
+// UnixTimeOrNull formats a unix timestamp of the API in RFC 3339 format, or
+// returns null when the API reports no time.
+func UnixTimeOrNull(unix int64) types.String {
+	if unix == 0 {
+		return types.StringNull()
+	}
+
+	return UnixTime(unix)
+}
+
+// ConfigValue formats a value of a decoded config endpoint the way it is
+// written in the config file. Missing values are empty.
+func ConfigValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,45 @@
+package proxmoxtf
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStringOrNull(t *testing.T) {
+	if got := StringOrNull(""); !got.IsNull() {
+		t.Errorf("got %v, want null", got)
+	}
+	if got := StringOrNull("vmbr0"); !got.Equal(types.StringValue("vmbr0")) {
+		t.Errorf("got %v, want vmbr0", got)
+	}
+}
+
+func TestUnixTimeOrNull(t *testing.T) {
+	if got := UnixTimeOrNull(0); !got.IsNull() {
+		t.Errorf("got %v, want null", got)
+	}
+	if got := UnixTimeOrNull(1700000000); got.ValueString() != "2023-11-14T22:13:20Z" {
+		t.Errorf("got %v, want 2023-11-14T22:13:20Z", got)
+	}
+}
+
+func TestConfigValue(t *testing.T) {
+	cases := map[string]struct {
+		value interface{}
+		want  string
+	}{
+		"string":  {value: "virtio", want: "virtio"},
+		"integer": {value: float64(1048576), want: "1048576"},
+		"float":   {value: 1.5, want: "1.5"},
+		"missing": {value: nil, want: ""},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := ConfigValue(tc.value); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
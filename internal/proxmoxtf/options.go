@@ -0,0 +1,57 @@
+package proxmoxtf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseOptions parses an option string of the API, e.g.
+// `local-lvm:32,iothread=1` of a disk, into its properties. The leading value
+// without key is stored under defaultKey, and rejected if defaultKey is empty.
+func ParseOptions(value, defaultKey string) (map[string]string, error) {
+	options := make(map[string]string)
+
+	for i, property := range strings.Split(value, ",") {
+		if property == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(property, "=")
+		if !ok {
+			if i != 0 || defaultKey == "" {
+				return nil, fmt.Errorf("property %q has no key", property)
+			}
+			key, val = defaultKey, property
+		}
+		if _, exists := options[key]; exists {
+			return nil, fmt.Errorf("duplicate property %q", key)
+		}
+		options[key] = val
+	}
+
+	return options, nil
+}
+
+// FormatOptions formats properties as an option string of the API. The
+// property defaultKey is written first without key, the others follow sorted
+// by key.
+func FormatOptions(options map[string]string, defaultKey string) string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		if key != defaultKey || defaultKey == "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	properties := make([]string, 0, len(options))
+	if value, ok := options[defaultKey]; ok && defaultKey != "" {
+		properties = append(properties, value)
+	}
+	for _, key := range keys {
+		properties = append(properties, key+"="+options[key])
+	}
+
+	return strings.Join(properties, ",")
+}
@@ -0,0 +1,85 @@
+package proxmoxtf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	cases := map[string]struct {
+		value      string
+		defaultKey string
+		want       map[string]string
+		wantErr    bool
+	}{
+		"disk": {
+			value:      "local-lvm:32,iothread=1",
+			defaultKey: "file",
+			want:       map[string]string{"file": "local-lvm:32", "iothread": "1"},
+		},
+		"keyed only": {
+			value: "virtio=BC:24:11:00:00:01,bridge=vmbr0",
+			want:  map[string]string{"virtio": "BC:24:11:00:00:01", "bridge": "vmbr0"},
+		},
+		"empty": {
+			value: "",
+			want:  map[string]string{},
+		},
+		"no default key": {
+			value:   "local-lvm:32,iothread=1",
+			wantErr: true,
+		},
+		"value without key later": {
+			value:      "local-lvm:32,iothread",
+			defaultKey: "file",
+			wantErr:    true,
+		},
+		"duplicate": {
+			value:   "cache=none,cache=writeback",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseOptions(tc.value, tc.defaultKey)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	cases := map[string]struct {
+		options    map[string]string
+		defaultKey string
+		want       string
+	}{
+		"disk": {
+			options:    map[string]string{"iothread": "1", "file": "local-lvm:32", "discard": "on"},
+			defaultKey: "file",
+			want:       "local-lvm:32,discard=on,iothread=1",
+		},
+		"keyed only": {
+			options: map[string]string{"bridge": "vmbr0", "firewall": "1"},
+			want:    "bridge=vmbr0,firewall=1",
+		},
+		"missing default": {
+			options:    map[string]string{"bridge": "vmbr0"},
+			defaultKey: "model",
+			want:       "bridge=vmbr0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := FormatOptions(tc.options, tc.defaultKey); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
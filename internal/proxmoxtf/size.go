@@ -0,0 +1,49 @@
+package proxmoxtf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are the unit suffixes of disk and memory sizes, largest first.
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"T", 1 << 40},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+}
+
+// ParseSize parses a size of the API, e.g. `32G` of a disk, into bytes.
+// Sizes without unit are bytes.
+func ParseSize(value string) (int64, error) {
+	number, multiplier := strings.TrimSpace(value), int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(number), unit.suffix) {
+			number, multiplier = number[:len(number)-1], unit.bytes
+			break
+		}
+	}
+
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+
+	return int64(size * float64(multiplier)), nil
+}
+
+// FormatSize formats bytes with the largest unit that represents them
+// exactly, the way Proxmox VE writes sizes.
+func FormatSize(bytes int64) string {
+	for _, unit := range sizeUnits {
+		if bytes != 0 && bytes%unit.bytes == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit.bytes, unit.suffix)
+		}
+	}
+
+	return strconv.FormatInt(bytes, 10)
+}
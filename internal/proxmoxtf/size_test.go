@@ -0,0 +1,45 @@
+package proxmoxtf
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		"bytes":     {value: "4096", want: 4096},
+		"gigabytes": {value: "32G", want: 32 << 30},
+		"lowercase": {value: "512m", want: 512 << 20},
+		"fraction":  {value: "1.5T", want: 3 << 39},
+		"invalid":   {value: "big", wantErr: true},
+		"negative":  {value: "-1G", wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseSize(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	cases := map[int64]string{
+		32 << 30:   "32G",
+		1536 << 20: "1536M",
+		4097:       "4097",
+		0:          "0",
+	}
+
+	for bytes, want := range cases {
+		if got := FormatSize(bytes); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
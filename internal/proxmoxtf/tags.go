@@ -0,0 +1,26 @@
+package proxmoxtf
+
+import (
+	"sort"
+	"strings"
+)
+
+// ParseTags splits a tag list of the API. Proxmox VE writes tags separated
+// by semicolons, but also accepts commas and spaces.
+func ParseTags(value string) []string {
+	tags := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == ',' || r == ' '
+	})
+	sort.Strings(tags)
+
+	return tags
+}
+
+// FormatTags joins tags the way Proxmox VE writes them, sorted and
+// separated by semicolons.
+func FormatTags(tags []string) string {
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ";")
+}
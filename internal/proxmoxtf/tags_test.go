@@ -0,0 +1,24 @@
+package proxmoxtf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags("prod;web, backup  dmz")
+	want := []string{"backup", "dmz", "prod", "web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := ParseTags(""); len(got) != 0 {
+		t.Errorf("got %v, want no tags", got)
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	if got := FormatTags([]string{"web", "prod"}); got != "prod;web" {
+		t.Errorf("got %q, want %q", got, "prod;web")
+	}
+}
@@ -0,0 +1,91 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// BackupJob is the configuration of a scheduled backup job as returned by
+// the API. VMID is a comma separated list of guest IDs.
+type BackupJob struct {
+	ID            string `json:"id"`
+	Schedule      string `json:"schedule"`
+	Storage       string `json:"storage,omitempty"`
+	VMID          string `json:"vmid,omitempty"`
+	All           int    `json:"all,omitempty"`
+	Mode          string `json:"mode,omitempty"`
+	NotesTemplate string `json:"notes-template,omitempty"`
+	Protected     int    `json:"protected,omitempty"`
+	Enabled       *int   `json:"enabled,omitempty"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+// BackupJobRequest holds the parameters to create or update a backup job.
+// ID is only sent on creation, Delete lists the options to remove, comma
+// separated, and is only sent on updates.
+type BackupJobRequest struct {
+	ID            string `json:"id,omitempty"`
+	Schedule      string `json:"schedule"`
+	Storage       string `json:"storage"`
+	VMID          string `json:"vmid,omitempty"`
+	All           int    `json:"all"`
+	Mode          string `json:"mode,omitempty"`
+	NotesTemplate string `json:"notes-template,omitempty"`
+	Protected     int    `json:"protected"`
+	Enabled       int    `json:"enabled"`
+	Comment       string `json:"comment,omitempty"`
+	Delete        string `json:"delete,omitempty"`
+}
+
+// backupJobPath returns the API path of a backup job.
+func backupJobPath(id string) string {
+	return fmt.Sprintf("/cluster/backup/%s", escape(id))
+}
+
+// BackupJob returns the configuration of a backup job.
+func (c *Client) BackupJob(ctx context.Context, id string) (*BackupJob, error) {
+	var job BackupJob
+	if err := c.r.Get(ctx, backupJobPath(id), &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// CreateBackupJob schedules a backup job.
+func (c *Client) CreateBackupJob(ctx context.Context, req BackupJobRequest) error {
+	req.Delete = ""
+	return c.r.Post(ctx, "/cluster/backup", req, nil)
+}
+
+// UpdateBackupJob changes the configuration of a backup job.
+func (c *Client) UpdateBackupJob(ctx context.Context, id string, req BackupJobRequest) error {
+	req.ID = ""
+	return c.r.Put(ctx, backupJobPath(id), req, nil)
+}
+
+// DeleteBackupJob removes a backup job.
+func (c *Client) DeleteBackupJob(ctx context.Context, id string) error {
+	return c.r.Delete(ctx, backupJobPath(id), nil)
+}
+
+// BackupRequest holds the parameters of a one-off backup of a guest.
+type BackupRequest struct {
+	VMID          int64  `json:"vmid"`
+	Storage       string `json:"storage,omitempty"`
+	Mode          string `json:"mode,omitempty"`
+	Compress      string `json:"compress,omitempty"`
+	NotesTemplate string `json:"notes-template,omitempty"`
+	Protected     int    `json:"protected,omitempty"`
+}
+
+// BackupGuest backs up a guest of node and returns the UPID of the backup
+// task.
+func (c *Client) BackupGuest(ctx context.Context, node string, req BackupRequest) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, fmt.Sprintf("/nodes/%s/vzdump", escape(node)), req, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
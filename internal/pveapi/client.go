@@ -0,0 +1,34 @@
+// Package pveapi is a thin typed layer over the Proxmox VE REST API for
+// endpoints the go-proxmox library does not cover yet. It only depends on the
+// raw request methods of a client, so the provider can add endpoints here
+// without waiting for upstream releases.
+package pveapi
+
+import (
+	"context"
+	"net/url"
+)
+
+// Requester performs raw API requests relative to /api2/json and decodes the
+// data of the response into v. *proxmox.Client satisfies it.
+type Requester interface {
+	Get(ctx context.Context, p string, v interface{}) error
+	Post(ctx context.Context, p string, d interface{}, v interface{}) error
+	Put(ctx context.Context, p string, d interface{}, v interface{}) error
+	Delete(ctx context.Context, p string, v interface{}) error
+}
+
+// Client exposes typed API endpoints on top of a Requester.
+type Client struct {
+	r Requester
+}
+
+// New returns a Client sending its requests through r.
+func New(r Requester) *Client {
+	return &Client{r: r}
+}
+
+// escape escapes a single path segment.
+func escape(segment string) string {
+	return url.PathEscape(segment)
+}
@@ -0,0 +1,401 @@
+package pveapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeRequester records the requests made and answers them with response.
+type fakeRequester struct {
+	method   string
+	path     string
+	body     interface{}
+	response string
+}
+
+func (f *fakeRequester) do(method, p string, d, v interface{}) error {
+	f.method, f.path, f.body = method, p, d
+	if v == nil || f.response == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(f.response), v)
+}
+
+func (f *fakeRequester) Get(_ context.Context, p string, v interface{}) error {
+	return f.do("GET", p, nil, v)
+}
+
+func (f *fakeRequester) Post(_ context.Context, p string, d interface{}, v interface{}) error {
+	return f.do("POST", p, d, v)
+}
+
+func (f *fakeRequester) Put(_ context.Context, p string, d interface{}, v interface{}) error {
+	return f.do("PUT", p, d, v)
+}
+
+func (f *fakeRequester) Delete(_ context.Context, p string, v interface{}) error {
+	return f.do("DELETE", p, nil, v)
+}
+
+func TestFirewallGroup(t *testing.T) {
+	r := &fakeRequester{response: `[{"group":"web","comment":"HTTP"},{"group":"db"}]`}
+	group, err := New(r).FirewallGroup(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.path != "/cluster/firewall/groups" || group.Comment != "HTTP" {
+		t.Errorf("got %s %+v", r.path, group)
+	}
+
+	if _, err := New(r).FirewallGroup(context.Background(), "dmz"); err == nil {
+		t.Error("expected an error for a missing group")
+	}
+}
+
+func TestStorages(t *testing.T) {
+	r := &fakeRequester{response: `[{"storage":"local","type":"dir","content":"iso,vztmpl,backup","path":"/var/lib/vz"}]`}
+	storages, err := New(r).Storages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Storage{{Storage: "local", Type: "dir", Content: "iso,vztmpl,backup", Path: "/var/lib/vz"}}
+	if r.path != "/storage" || !reflect.DeepEqual(storages, want) {
+		t.Errorf("got %s %+v", r.path, storages)
+	}
+}
+
+func TestUpdateStorage(t *testing.T) {
+	r := &fakeRequester{}
+	skip := 1
+	err := New(r).UpdateStorage(context.Background(), "esxi", StorageRequest{
+		Storage:              "esxi",
+		Type:                 "esxi",
+		Username:             "root",
+		SkipCertVerification: &skip,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(r.body)
+	var got map[string]interface{}
+	_ = json.Unmarshal(body, &got)
+	want := map[string]interface{}{"username": "root", "skip-cert-verification": float64(1)}
+	if r.method != "PUT" || r.path != "/storage/esxi" || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s %s %v, want PUT /storage/esxi %v", r.method, r.path, got, want)
+	}
+}
+
+func TestCreateContainer(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve:0001:vzcreate:100:root@pam:"`}
+	upid, err := New(r).CreateContainer(context.Background(), "pve", ContainerRequest{VMID: 100, OSTemplate: "local:vztmpl/debian.tar.zst"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.path != "/nodes/pve/lxc" || upid != "UPID:pve:0001:vzcreate:100:root@pam:" {
+		t.Errorf("got %s %s", r.path, upid)
+	}
+}
+
+func TestNodeNetworks(t *testing.T) {
+	r := &fakeRequester{response: `[{"iface":"vmbr0","type":"bridge","bridge_vlan_aware":1,"bridge_vids":"2-4094"}]`}
+	networks, err := New(r).NodeNetworks(context.Background(), "pve1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []NodeNetwork{{Iface: "vmbr0", Type: "bridge", BridgeVlanAware: 1, BridgeVids: "2-4094"}}
+	if r.path != "/nodes/pve1/network" || !reflect.DeepEqual(networks, want) {
+		t.Errorf("got %s %+v", r.path, networks)
+	}
+}
+
+func TestSDNVnets(t *testing.T) {
+	r := &fakeRequester{response: `[{"vnet":"vnet10","zone":"zone1","tag":10,"type":"vnet"}]`}
+	vnets, err := New(r).SDNVnets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SDNVnet{{Vnet: "vnet10", Zone: "zone1"}}
+	if r.path != "/cluster/sdn/vnets" || !reflect.DeepEqual(vnets, want) {
+		t.Errorf("got %s %+v", r.path, vnets)
+	}
+}
+
+func TestUpdateSDNZone(t *testing.T) {
+	r := &fakeRequester{}
+	err := New(r).UpdateSDNZone(context.Background(), "zone1", map[string]interface{}{"bridge": "vmbr1", "delete": "dns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"bridge": "vmbr1", "delete": "dns"}
+	if r.method != "PUT" || r.path != "/cluster/sdn/zones/zone1" || !reflect.DeepEqual(r.body, want) {
+		t.Errorf("got %s %s %v, want PUT /cluster/sdn/zones/zone1 %v", r.method, r.path, r.body, want)
+	}
+}
+
+func TestVMConfig(t *testing.T) {
+	r := &fakeRequester{response: `{"hostpci0":"0000:01:10.0,pcie=1","memory":"4096"}`}
+	config, err := New(r).VMConfig(context.Background(), "pve1", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.path != "/nodes/pve1/qemu/100/config" || config["hostpci0"] != "0000:01:10.0,pcie=1" {
+		t.Errorf("got %s %v", r.path, config)
+	}
+}
+
+func TestUpdateBackupJob(t *testing.T) {
+	r := &fakeRequester{}
+	err := New(r).UpdateBackupJob(context.Background(), "daily", BackupJobRequest{
+		ID:       "daily",
+		Schedule: "02:00",
+		Storage:  "pbs",
+		All:      1,
+		Enabled:  1,
+		Delete:   "notes-template",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := BackupJobRequest{Schedule: "02:00", Storage: "pbs", All: 1, Enabled: 1, Delete: "notes-template"}
+	if r.method != "PUT" || r.path != "/cluster/backup/daily" || !reflect.DeepEqual(r.body, want) {
+		t.Errorf("got %s %s %+v", r.method, r.path, r.body)
+	}
+}
+
+func TestFirewallGroupRules(t *testing.T) {
+	r := &fakeRequester{response: `[{"pos":0,"type":"in","action":"ACCEPT","enable":1,"proto":"icmp","icmp-type":"echo-request"}]`}
+	rules, err := New(r).FirewallGroupRules(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FirewallRule{{Type: "in", Action: "ACCEPT", Enable: 1, Proto: "icmp", IcmpType: "echo-request"}}
+	if r.path != "/cluster/firewall/groups/web" || !reflect.DeepEqual(rules, want) {
+		t.Errorf("got %s %+v", r.path, rules)
+	}
+}
+
+func TestUpdateNodeNetwork(t *testing.T) {
+	r := &fakeRequester{}
+	err := New(r).UpdateNodeNetwork(context.Background(), "pve", "vmbr1", NodeNetworkRequest{
+		Type:    "bridge",
+		CIDR:    "10.0.1.2/24",
+		Gateway: "10.0.1.1",
+		Delete:  "gateway6",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := NodeNetworkRequest{Type: "bridge", CIDR: "10.0.1.2/24", Gateway: "10.0.1.1", Delete: "gateway6"}
+	if r.method != "PUT" || r.path != "/nodes/pve/network/vmbr1" || !reflect.DeepEqual(r.body, want) {
+		t.Errorf("got %s %s %+v", r.method, r.path, r.body)
+	}
+}
+
+func TestCreateNodeNetwork(t *testing.T) {
+	r := &fakeRequester{}
+	err := New(r).CreateNodeNetwork(context.Background(), "pve", "vmbr1", NodeNetworkRequest{
+		Type:            "bridge",
+		Autostart:       1,
+		BridgePorts:     "eno2",
+		BridgeVlanAware: 1,
+		BridgeVids:      "10 20-30",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(r.body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"iface":"vmbr1","type":"bridge","autostart":1,"bridge_ports":"eno2","bridge_vlan_aware":1,"bridge_vids":"10 20-30"}`
+	if r.method != "POST" || r.path != "/nodes/pve/network" || string(body) != want {
+		t.Errorf("got %s %s %s", r.method, r.path, body)
+	}
+}
+
+func TestJoinCluster(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve2:0001:clusterjoin::root@pam:"`}
+	upid, err := New(r).JoinCluster(context.Background(), ClusterJoinRequest{
+		Hostname:    "10.0.0.1",
+		Fingerprint: "AA:BB",
+		Password:    "secret",
+		Links:       []string{"10.0.0.2", "10.1.0.2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"hostname":    "10.0.0.1",
+		"fingerprint": "AA:BB",
+		"password":    "secret",
+		"link0":       "10.0.0.2",
+		"link1":       "10.1.0.2",
+	}
+	if r.method != "POST" || r.path != "/cluster/config/join" || !reflect.DeepEqual(r.body, want) {
+		t.Errorf("got %s %s %v, want POST /cluster/config/join %v", r.method, r.path, r.body, want)
+	}
+	if upid != "UPID:pve2:0001:clusterjoin::root@pam:" {
+		t.Errorf("got UPID %q", upid)
+	}
+}
+
+func TestCreateCluster(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve1:0001:clustercreate:lab:root@pam:"`}
+	_, err := New(r).CreateCluster(context.Background(), ClusterCreateRequest{
+		ClusterName: "lab",
+		Links:       []string{"10.0.0.1"},
+		NodeID:      1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"clustername": "lab", "link0": "10.0.0.1", "nodeid": int64(1)}
+	if r.method != "POST" || r.path != "/cluster/config" || !reflect.DeepEqual(r.body, want) {
+		t.Errorf("got %s %s %v, want POST /cluster/config %v", r.method, r.path, r.body, want)
+	}
+}
+
+func TestResizeVMDisk(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve:0001:resize:100:root@pam:"`}
+	upid, err := New(r).ResizeVMDisk(context.Background(), "pve", 100, "scsi0", "40G")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"disk": "scsi0", "size": "40G"}
+	if r.method != "PUT" || r.path != "/nodes/pve/qemu/100/resize" || !reflect.DeepEqual(r.body, want) || upid == "" {
+		t.Errorf("got %s %s %v %q", r.method, r.path, r.body, upid)
+	}
+}
+
+func TestMoveVMDisk(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve:0001:qmmove:100:root@pam:"`}
+	_, err := New(r).MoveVMDisk(context.Background(), "pve", 100, MoveVMDiskRequest{Disk: "scsi1", Storage: "ceph", Format: "raw", Delete: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(r.body)
+	var got map[string]interface{}
+	_ = json.Unmarshal(body, &got)
+	want := map[string]interface{}{"disk": "scsi1", "storage": "ceph", "format": "raw", "delete": float64(1)}
+	if r.method != "POST" || r.path != "/nodes/pve/qemu/100/move_disk" || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s %s %v, want POST /nodes/pve/qemu/100/move_disk %v", r.method, r.path, got, want)
+	}
+}
+
+func TestCloneVM(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve:0001:qmclone:9000:root@pam:"`}
+	_, err := New(r).CloneVM(context.Background(), "pve", 9000, CloneVMRequest{NewID: 100, Target: "pve2", Full: 1, Storage: "local-lvm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(r.body)
+	var got map[string]interface{}
+	_ = json.Unmarshal(body, &got)
+	want := map[string]interface{}{"newid": float64(100), "target": "pve2", "full": float64(1), "storage": "local-lvm"}
+	if r.method != "POST" || r.path != "/nodes/pve/qemu/9000/clone" || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s %s %v, want POST /nodes/pve/qemu/9000/clone %v", r.method, r.path, got, want)
+	}
+}
+
+func TestMigrateGuest(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve:0001:qmigrate:100:root@pam:"`}
+	_, err := New(r).MigrateGuest(context.Background(), "pve", "qemu", 100, MigrateRequest{Target: "pve2", Online: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(r.body)
+	var got map[string]interface{}
+	_ = json.Unmarshal(body, &got)
+	want := map[string]interface{}{"target": "pve2", "online": float64(1)}
+	if r.method != "POST" || r.path != "/nodes/pve/qemu/100/migrate" || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s %s %v, want POST /nodes/pve/qemu/100/migrate %v", r.method, r.path, got, want)
+	}
+}
+
+func TestBackupGuest(t *testing.T) {
+	r := &fakeRequester{response: `"UPID:pve:0001:vzdump:100:root@pam:"`}
+	_, err := New(r).BackupGuest(context.Background(), "pve", BackupRequest{VMID: 100, Storage: "pbs", Mode: "snapshot", Protected: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(r.body)
+	var got map[string]interface{}
+	_ = json.Unmarshal(body, &got)
+	want := map[string]interface{}{"vmid": float64(100), "storage": "pbs", "mode": "snapshot", "protected": float64(1)}
+	if r.method != "POST" || r.path != "/nodes/pve/vzdump" || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s %s %v, want POST /nodes/pve/vzdump %v", r.method, r.path, got, want)
+	}
+}
+
+func TestLXCTermProxy(t *testing.T) {
+	r := &fakeRequester{response: `{"port":5900,"ticket":"PVEVNC:abc+/=","user":"root@pam","upid":"UPID:pve:0001:vncproxy:100:root@pam:"}`}
+	term, err := New(r).LXCTermProxy(context.Background(), "pve", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.method != "POST" || r.path != "/nodes/pve/lxc/100/termproxy" || term.Port != "5900" {
+		t.Errorf("got %s %s %+v", r.method, r.path, term)
+	}
+
+	got := LXCTermWebSocketURL("https://pve:8006/", "pve", 100, term)
+	want := "wss://pve:8006/api2/json/nodes/pve/lxc/100/vncwebsocket?port=5900&vncticket=PVEVNC%3Aabc%2B%2F%3D"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestDialTerminal(t *testing.T) {
+	var login, input string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, msg, _ := conn.ReadMessage()
+		login = string(msg)
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte("OK"))
+		_, msg, _ = conn.ReadMessage()
+		input = string(msg)
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte("done"))
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	proxy := &TermProxy{Port: "5900", Ticket: "PVEVNC:abc", User: "root@pam"}
+	term, err := DialTerminal(context.Background(), websocket.DefaultDialer, LXCTermWebSocketURL(server.URL, "pve", 100, proxy), nil, proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := term.Write([]byte("ls\n")); err != nil {
+		t.Fatal(err)
+	}
+	output, err := term.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	term.Close()
+
+	if login != "root@pam:PVEVNC:abc\n" || input != "0:3:ls\n" || string(output) != "done" {
+		t.Errorf("got login %q, input %q and output %q", login, input, output)
+	}
+}
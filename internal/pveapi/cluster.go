@@ -0,0 +1,125 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// ClusterCreateRequest holds the parameters to create a cluster on the node
+// the client is connected to. Links are the corosync link addresses of the
+// node, in link order.
+type ClusterCreateRequest struct {
+	ClusterName string
+	Links       []string
+	NodeID      int64
+	Votes       int64
+}
+
+// params returns the request as API parameters, the links numbered link0
+// onwards.
+func (r ClusterCreateRequest) params() map[string]interface{} {
+	params := map[string]interface{}{
+		"clustername": r.ClusterName,
+	}
+	addLinks(params, r.Links)
+	if r.NodeID != 0 {
+		params["nodeid"] = r.NodeID
+	}
+	if r.Votes != 0 {
+		params["votes"] = r.Votes
+	}
+
+	return params
+}
+
+// ClusterJoinInfo is the information needed to join a cluster, as returned
+// by one of its members.
+type ClusterJoinInfo struct {
+	PreferredNode string              `json:"preferred_node"`
+	Nodes         []ClusterJoinMember `json:"nodelist"`
+}
+
+// ClusterJoinMember is a member of a cluster that nodes can join through.
+type ClusterJoinMember struct {
+	Name        string `json:"name"`
+	NodeID      string `json:"nodeid"`
+	Address     string `json:"pve_addr"`
+	Fingerprint string `json:"pve_fp"`
+}
+
+// addLinks adds links to params as link0 onwards.
+func addLinks(params map[string]interface{}, links []string) {
+	for i, link := range links {
+		params[fmt.Sprintf("link%d", i)] = link
+	}
+}
+
+// CreateCluster creates a cluster on the node and returns the UPID of the
+// creation task.
+func (c *Client) CreateCluster(ctx context.Context, req ClusterCreateRequest) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, "/cluster/config", req.params(), &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
+
+// ClusterJoinInfo returns the information needed to join the cluster of the
+// node.
+func (c *Client) ClusterJoinInfo(ctx context.Context) (*ClusterJoinInfo, error) {
+	var info ClusterJoinInfo
+	if err := c.r.Get(ctx, "/cluster/config/join", &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// ClusterJoinRequest holds the parameters to join the node the client is
+// connected to to an existing cluster. Hostname is the address of a cluster
+// member and Password the root password on it. Links are the corosync link
+// addresses of the joining node, in link order.
+type ClusterJoinRequest struct {
+	Hostname    string
+	Fingerprint string
+	Password    string
+	Links       []string
+	NodeID      int64
+	Votes       int64
+	Force       bool
+}
+
+// params returns the request as API parameters, the links numbered link0
+// onwards.
+func (r ClusterJoinRequest) params() map[string]interface{} {
+	params := map[string]interface{}{
+		"hostname":    r.Hostname,
+		"fingerprint": r.Fingerprint,
+		"password":    r.Password,
+	}
+	addLinks(params, r.Links)
+	if r.NodeID != 0 {
+		params["nodeid"] = r.NodeID
+	}
+	if r.Votes != 0 {
+		params["votes"] = r.Votes
+	}
+	if r.Force {
+		params["force"] = 1
+	}
+
+	return params
+}
+
+// JoinCluster joins the node to a cluster and returns the UPID of the join
+// task. The node restarts its cluster services while joining, which
+// invalidates the session of the client.
+func (c *Client) JoinCluster(ctx context.Context, req ClusterJoinRequest) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, "/cluster/config/join", req.params(), &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
@@ -0,0 +1,81 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// FirewallGroup is a cluster firewall security group.
+type FirewallGroup struct {
+	Group   string `json:"group"`
+	Comment string `json:"comment,omitempty"`
+	Digest  string `json:"digest,omitempty"`
+}
+
+// FirewallGroupRequest creates a security group, or renames and updates it
+// when Rename is set to its current name.
+type FirewallGroupRequest struct {
+	Group   string `json:"group"`
+	Comment string `json:"comment,omitempty"`
+	Rename  string `json:"rename,omitempty"`
+}
+
+// FirewallGroups lists the security groups of the cluster firewall.
+func (c *Client) FirewallGroups(ctx context.Context) ([]FirewallGroup, error) {
+	var groups []FirewallGroup
+	if err := c.r.Get(ctx, "/cluster/firewall/groups", &groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// FirewallGroup returns the security group with the given name.
+func (c *Client) FirewallGroup(ctx context.Context, name string) (*FirewallGroup, error) {
+	groups, err := c.FirewallGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if group.Group == name {
+			return &group, nil
+		}
+	}
+
+	return nil, fmt.Errorf("security group '%s' does not exist", name)
+}
+
+// CreateFirewallGroup creates or updates a security group.
+func (c *Client) CreateFirewallGroup(ctx context.Context, req FirewallGroupRequest) error {
+	return c.r.Post(ctx, "/cluster/firewall/groups", req, nil)
+}
+
+// FirewallRule is a rule of a security group or firewall. Enable is 1 for
+// enabled rules.
+type FirewallRule struct {
+	Pos      int    `json:"pos"`
+	Type     string `json:"type"`
+	Action   string `json:"action"`
+	Enable   int    `json:"enable,omitempty"`
+	Macro    string `json:"macro,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Dest     string `json:"dest,omitempty"`
+	Proto    string `json:"proto,omitempty"`
+	Sport    string `json:"sport,omitempty"`
+	Dport    string `json:"dport,omitempty"`
+	IcmpType string `json:"icmp-type,omitempty"`
+	Iface    string `json:"iface,omitempty"`
+	Log      string `json:"log,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// FirewallGroupRules lists the rules of a security group by position.
+func (c *Client) FirewallGroupRules(ctx context.Context, group string) ([]FirewallRule, error) {
+	var rules []FirewallRule
+	if err := c.r.Get(ctx, fmt.Sprintf("/cluster/firewall/groups/%s", escape(group)), &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
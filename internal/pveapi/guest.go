@@ -0,0 +1,26 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// MigrateRequest holds the parameters to migrate a guest to the Target
+// node. Online migrates a running VM live, Restart migrates a running
+// container by restarting it on the target.
+type MigrateRequest struct {
+	Target  string `json:"target"`
+	Online  int    `json:"online,omitempty"`
+	Restart int    `json:"restart,omitempty"`
+}
+
+// MigrateGuest migrates the guest vmid of guestType, either qemu or lxc, from
+// node and returns the UPID of the migration task.
+func (c *Client) MigrateGuest(ctx context.Context, node, guestType string, vmid int64, req MigrateRequest) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, fmt.Sprintf("/nodes/%s/%s/%d/migrate", escape(node), guestType, vmid), req, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
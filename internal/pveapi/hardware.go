@@ -0,0 +1,28 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// PCIDevice is a PCI device of a node as returned by the API.
+type PCIDevice struct {
+	ID         string `json:"id"`
+	Class      string `json:"class"`
+	Vendor     string `json:"vendor"`
+	Device     string `json:"device"`
+	VendorName string `json:"vendor_name,omitempty"`
+	DeviceName string `json:"device_name,omitempty"`
+	IOMMUGroup int64  `json:"iommugroup"`
+}
+
+// PCIDevices returns the PCI devices of a node, leaving out the device
+// classes the API hides by default, e.g. bridges and memory controllers.
+func (c *Client) PCIDevices(ctx context.Context, node string) ([]PCIDevice, error) {
+	var devices []PCIDevice
+	if err := c.r.Get(ctx, fmt.Sprintf("/nodes/%s/hardware/pci", escape(node)), &devices); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
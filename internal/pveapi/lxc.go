@@ -0,0 +1,53 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContainerRequest holds the parameters to create a container.
+type ContainerRequest struct {
+	VMID       int64  `json:"vmid"`
+	OSTemplate string `json:"ostemplate"`
+	Hostname   string `json:"hostname,omitempty"`
+	Storage    string `json:"storage,omitempty"`
+	Start      int    `json:"start,omitempty"`
+}
+
+// containerPath returns the API path of a container on a node.
+func containerPath(node string, vmid int64) string {
+	return fmt.Sprintf("/nodes/%s/lxc/%d", escape(node), vmid)
+}
+
+// CreateContainer creates a container on node and returns the UPID of the
+// creation task.
+func (c *Client) CreateContainer(ctx context.Context, node string, req ContainerRequest) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, fmt.Sprintf("/nodes/%s/lxc", escape(node)), req, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
+
+// ContainerConfig returns the configuration of a container as a raw map.
+func (c *Client) ContainerConfig(ctx context.Context, node string, vmid int64) (map[string]interface{}, error) {
+	var config map[string]interface{}
+	if err := c.r.Get(ctx, containerPath(node, vmid)+"/config", &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// DeleteContainer destroys a stopped container together with the jobs and
+// access rules that reference it, and returns the UPID of the destruction
+// task.
+func (c *Client) DeleteContainer(ctx context.Context, node string, vmid int64) (string, error) {
+	var upid string
+	if err := c.r.Delete(ctx, containerPath(node, vmid)+"?purge=1", &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
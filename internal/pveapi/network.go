@@ -0,0 +1,105 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// NodeNetwork is a network interface of a node as returned by the API. The
+// bridge fields are empty for interfaces that are not bridges.
+type NodeNetwork struct {
+	Iface           string `json:"iface"`
+	Type            string `json:"type"`
+	Active          int    `json:"active,omitempty"`
+	Autostart       int    `json:"autostart,omitempty"`
+	Method          string `json:"method,omitempty"`
+	CIDR            string `json:"cidr,omitempty"`
+	Gateway         string `json:"gateway,omitempty"`
+	CIDR6           string `json:"cidr6,omitempty"`
+	Gateway6        string `json:"gateway6,omitempty"`
+	MTU             int    `json:"mtu,omitempty"`
+	Comments        string `json:"comments,omitempty"`
+	BridgePorts     string `json:"bridge_ports,omitempty"`
+	BridgeVlanAware int    `json:"bridge_vlan_aware,omitempty"`
+	BridgeVids      string `json:"bridge_vids,omitempty"`
+}
+
+// NodeNetworkRequest holds the options to change on a network interface.
+// Type is required by the API and must match the current type. Delete lists
+// the options to remove, comma separated.
+type NodeNetworkRequest struct {
+	Type            string `json:"type"`
+	Autostart       int    `json:"autostart,omitempty"`
+	CIDR            string `json:"cidr,omitempty"`
+	Gateway         string `json:"gateway,omitempty"`
+	CIDR6           string `json:"cidr6,omitempty"`
+	Gateway6        string `json:"gateway6,omitempty"`
+	MTU             int64  `json:"mtu,omitempty"`
+	Comments        string `json:"comments,omitempty"`
+	BridgePorts     string `json:"bridge_ports,omitempty"`
+	BridgeVlanAware int    `json:"bridge_vlan_aware,omitempty"`
+	BridgeVids      string `json:"bridge_vids,omitempty"`
+	Delete          string `json:"delete,omitempty"`
+}
+
+// nodeNetworkCreateRequest is the request to create the network interface
+// iface.
+type nodeNetworkCreateRequest struct {
+	Iface string `json:"iface"`
+	NodeNetworkRequest
+}
+
+// nodeNetworkPath returns the API path of the network interfaces of a node.
+func nodeNetworkPath(node string) string {
+	return fmt.Sprintf("/nodes/%s/network", escape(node))
+}
+
+// NodeNetworks returns the network interfaces of a node.
+func (c *Client) NodeNetworks(ctx context.Context, node string) ([]NodeNetwork, error) {
+	var networks []NodeNetwork
+	if err := c.r.Get(ctx, nodeNetworkPath(node), &networks); err != nil {
+		return nil, err
+	}
+
+	return networks, nil
+}
+
+// NodeNetwork returns a network interface of a node, including pending
+// changes that were not applied yet.
+func (c *Client) NodeNetwork(ctx context.Context, node, iface string) (*NodeNetwork, error) {
+	var network NodeNetwork
+	if err := c.r.Get(ctx, nodeNetworkPath(node)+"/"+escape(iface), &network); err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// CreateNodeNetwork creates the network interface iface of a node. The
+// interface is pending until the network config is reloaded.
+func (c *Client) CreateNodeNetwork(ctx context.Context, node, iface string, req NodeNetworkRequest) error {
+	return c.r.Post(ctx, nodeNetworkPath(node), nodeNetworkCreateRequest{Iface: iface, NodeNetworkRequest: req}, nil)
+}
+
+// UpdateNodeNetwork changes the options of a network interface. The change
+// is pending until the network config is reloaded.
+func (c *Client) UpdateNodeNetwork(ctx context.Context, node, iface string, req NodeNetworkRequest) error {
+	return c.r.Put(ctx, nodeNetworkPath(node)+"/"+escape(iface), req, nil)
+}
+
+// DeleteNodeNetwork removes a network interface of a node. The removal is
+// pending until the network config is reloaded.
+func (c *Client) DeleteNodeNetwork(ctx context.Context, node, iface string) error {
+	return c.r.Delete(ctx, nodeNetworkPath(node)+"/"+escape(iface), nil)
+}
+
+// ReloadNodeNetwork applies the pending network changes of a node and
+// returns the UPID of the reload task.
+func (c *Client) ReloadNodeNetwork(ctx context.Context, node string) (string, error) {
+	var upid string
+	if err := c.r.Put(ctx, nodeNetworkPath(node), nil, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
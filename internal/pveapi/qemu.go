@@ -0,0 +1,98 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// VM is an entry of the VM list of a node.
+type VM struct {
+	VMID   int64  `json:"vmid"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// VMs returns the VMs of a node.
+func (c *Client) VMs(ctx context.Context, node string) ([]VM, error) {
+	var vms []VM
+	if err := c.r.Get(ctx, fmt.Sprintf("/nodes/%s/qemu", escape(node)), &vms); err != nil {
+		return nil, err
+	}
+
+	return vms, nil
+}
+
+// VMConfig returns the current config of a VM, keyed by the API name of the
+// options.
+func (c *Client) VMConfig(ctx context.Context, node string, vmid int64) (map[string]interface{}, error) {
+	var config map[string]interface{}
+	if err := c.r.Get(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/config", escape(node), vmid), &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// CreateVM creates a VM on node and returns the UPID of the creation task.
+// Devices are keyed by their numbered option names, e.g. scsi0 or net0, so
+// the parameters are passed as a map.
+func (c *Client) CreateVM(ctx context.Context, node string, params map[string]interface{}) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, fmt.Sprintf("/nodes/%s/qemu", escape(node)), params, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
+
+// ResizeVMDisk grows a disk of a VM to size, e.g. 32G. It returns the UPID
+// of the resize task, which is empty on versions that resize synchronously.
+func (c *Client) ResizeVMDisk(ctx context.Context, node string, vmid int64, disk, size string) (string, error) {
+	var upid string
+	params := map[string]interface{}{"disk": disk, "size": size}
+	if err := c.r.Put(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/resize", escape(node), vmid), params, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
+
+// MoveVMDiskRequest holds the parameters to move a disk of a VM to another
+// storage or format. Delete removes the source volume once it is copied.
+type MoveVMDiskRequest struct {
+	Disk    string `json:"disk"`
+	Storage string `json:"storage"`
+	Format  string `json:"format,omitempty"`
+	Delete  int    `json:"delete"`
+}
+
+// MoveVMDisk moves a disk of a VM and returns the UPID of the move task.
+func (c *Client) MoveVMDisk(ctx context.Context, node string, vmid int64, req MoveVMDiskRequest) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/move_disk", escape(node), vmid), req, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
+
+// CloneVMRequest holds the parameters to clone a VM or a template. Target
+// is the node of the clone when it differs from the node of the source,
+// Storage is only supported by full clones.
+type CloneVMRequest struct {
+	NewID   int64  `json:"newid"`
+	Name    string `json:"name,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Full    int    `json:"full"`
+	Storage string `json:"storage,omitempty"`
+}
+
+// CloneVM clones the VM vmid on node and returns the UPID of the clone task.
+func (c *Client) CloneVM(ctx context.Context, node string, vmid int64, req CloneVMRequest) (string, error) {
+	var upid string
+	if err := c.r.Post(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/clone", escape(node), vmid), req, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
@@ -0,0 +1,59 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// SDNZone is the configuration of an SDN zone as returned by the API. Nodes
+// is a comma separated list and empty when the zone spans all nodes.
+type SDNZone struct {
+	Zone   string `json:"zone"`
+	Type   string `json:"type"`
+	DNS    string `json:"dns,omitempty"`
+	Bridge string `json:"bridge,omitempty"`
+	Nodes  string `json:"nodes,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// CreateSDNZone creates an SDN zone with params, which include its zone and
+// type.
+func (c *Client) CreateSDNZone(ctx context.Context, params map[string]interface{}) error {
+	return c.r.Post(ctx, "/cluster/sdn/zones", params, nil)
+}
+
+// UpdateSDNZone sets the options params of an SDN zone.
+func (c *Client) UpdateSDNZone(ctx context.Context, zone string, params map[string]interface{}) error {
+	return c.r.Put(ctx, fmt.Sprintf("/cluster/sdn/zones/%s", escape(zone)), params, nil)
+}
+
+// DeleteSDNZone deletes an SDN zone.
+func (c *Client) DeleteSDNZone(ctx context.Context, zone string) error {
+	return c.r.Delete(ctx, fmt.Sprintf("/cluster/sdn/zones/%s", escape(zone)), nil)
+}
+
+// SDNZone returns the configuration of an SDN zone.
+func (c *Client) SDNZone(ctx context.Context, zone string) (*SDNZone, error) {
+	var z SDNZone
+	if err := c.r.Get(ctx, fmt.Sprintf("/cluster/sdn/zones/%s", escape(zone)), &z); err != nil {
+		return nil, err
+	}
+
+	return &z, nil
+}
+
+// SDNVnet is an SDN vnet as listed by the API.
+type SDNVnet struct {
+	Vnet string `json:"vnet"`
+	Zone string `json:"zone"`
+}
+
+// SDNVnets returns the SDN vnets of the cluster.
+func (c *Client) SDNVnets(ctx context.Context) ([]SDNVnet, error) {
+	var vnets []SDNVnet
+	if err := c.r.Get(ctx, "/cluster/sdn/vnets", &vnets); err != nil {
+		return nil, err
+	}
+
+	return vnets, nil
+}
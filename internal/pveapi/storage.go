@@ -0,0 +1,83 @@
+package pveapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// Storage is the configuration of a storage as returned by the API. Fields
+// that do not apply to the type of the storage are empty.
+type Storage struct {
+	Storage              string `json:"storage"`
+	Type                 string `json:"type"`
+	Content              string `json:"content,omitempty"`
+	Path                 string `json:"path,omitempty"`
+	Server               string `json:"server,omitempty"`
+	Username             string `json:"username,omitempty"`
+	SkipCertVerification int    `json:"skip-cert-verification,omitempty"`
+	Preallocation        string `json:"preallocation,omitempty"`
+	Datastore            string `json:"datastore,omitempty"`
+	Namespace            string `json:"namespace,omitempty"`
+	Fingerprint          string `json:"fingerprint,omitempty"`
+}
+
+// StorageRequest holds the parameters to create or update a storage. Storage
+// and Type are only sent on creation. Delete lists the options to remove,
+// comma separated, and is only sent on updates.
+type StorageRequest struct {
+	Storage              string `json:"storage,omitempty"`
+	Type                 string `json:"type,omitempty"`
+	Content              string `json:"content,omitempty"`
+	Server               string `json:"server,omitempty"`
+	Username             string `json:"username,omitempty"`
+	Password             string `json:"password,omitempty"`
+	SkipCertVerification *int   `json:"skip-cert-verification,omitempty"`
+	Preallocation        string `json:"preallocation,omitempty"`
+	Datastore            string `json:"datastore,omitempty"`
+	Namespace            string `json:"namespace,omitempty"`
+	Fingerprint          string `json:"fingerprint,omitempty"`
+	EncryptionKey        string `json:"encryption-key,omitempty"`
+	Delete               string `json:"delete,omitempty"`
+}
+
+// storagePath returns the API path of a storage.
+func storagePath(storage string) string {
+	return fmt.Sprintf("/storage/%s", escape(storage))
+}
+
+// Storages returns the configurations of the storages of the cluster.
+func (c *Client) Storages(ctx context.Context) ([]Storage, error) {
+	var storages []Storage
+	if err := c.r.Get(ctx, "/storage", &storages); err != nil {
+		return nil, err
+	}
+
+	return storages, nil
+}
+
+// Storage returns the configuration of a storage.
+func (c *Client) Storage(ctx context.Context, storage string) (*Storage, error) {
+	var s Storage
+	if err := c.r.Get(ctx, storagePath(storage), &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CreateStorage adds a storage to the cluster.
+func (c *Client) CreateStorage(ctx context.Context, req StorageRequest) error {
+	req.Delete = ""
+	return c.r.Post(ctx, "/storage", req, nil)
+}
+
+// UpdateStorage changes the configuration of a storage.
+func (c *Client) UpdateStorage(ctx context.Context, storage string, req StorageRequest) error {
+	req.Storage, req.Type = "", ""
+	return c.r.Put(ctx, storagePath(storage), req, nil)
+}
+
+// DeleteStorage removes a storage from the cluster.
+func (c *Client) DeleteStorage(ctx context.Context, storage string) error {
+	return c.r.Delete(ctx, storagePath(storage), nil)
+}
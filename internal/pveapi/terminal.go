@@ -0,0 +1,90 @@
+package pveapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// TermProxy is a terminal proxy of a guest as returned by the termproxy
+// endpoints. The terminal is reached through the vncwebsocket endpoint of the
+// guest with its port and ticket.
+type TermProxy struct {
+	Port   json.Number `json:"port"`
+	Ticket string      `json:"ticket"`
+	User   string      `json:"user"`
+	UPID   string      `json:"upid"`
+}
+
+// LXCTermProxy opens a terminal proxy of a container.
+func (c *Client) LXCTermProxy(ctx context.Context, node string, vmid int64) (*TermProxy, error) {
+	var term TermProxy
+	if err := c.r.Post(ctx, fmt.Sprintf("/nodes/%s/lxc/%d/termproxy", escape(node), vmid), nil, &term); err != nil {
+		return nil, err
+	}
+
+	return &term, nil
+}
+
+// LXCTermWebSocketURL returns the URL of the vncwebsocket endpoint of a
+// container for term, on the API at host, e.g. https://proxmox:8006.
+func LXCTermWebSocketURL(host, node string, vmid int64, term *TermProxy) string {
+	host = strings.Replace(strings.TrimSuffix(host, "/"), "https://", "wss://", 1)
+	host = strings.Replace(host, "http://", "ws://", 1)
+
+	return fmt.Sprintf("%s/api2/json/nodes/%s/lxc/%d/vncwebsocket?port=%s&vncticket=%s",
+		host, escape(node), vmid, term.Port, url.QueryEscape(term.Ticket))
+}
+
+// Terminal is a terminal session of a guest speaking the termproxy protocol
+// over a websocket.
+type Terminal struct {
+	conn *websocket.Conn
+}
+
+// DialTerminal connects to the terminal of term at the websocket URL with the
+// authentication header of the API client and logs in with its ticket.
+func DialTerminal(ctx context.Context, dialer *websocket.Dialer, wsURL string, header http.Header, term *TermProxy) (*Terminal, error) {
+	conn, resp, err := dialer.DialContext(ctx, wsURL, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("connecting to the terminal: %s: %w", resp.Status, err)
+		}
+		return nil, fmt.Errorf("connecting to the terminal: %w", err)
+	}
+
+	if err := conn.WriteMessage(websocket.BinaryMessage, []byte(term.User+":"+term.Ticket+"\n")); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("logging in to the terminal: %w", err)
+	}
+	_, msg, err := conn.ReadMessage()
+	if err != nil || string(msg) != "OK" {
+		conn.Close()
+		return nil, fmt.Errorf("logging in to the terminal: unexpected answer %q: %v", msg, err)
+	}
+
+	return &Terminal{conn: conn}, nil
+}
+
+// Write sends data to the terminal as input.
+func (t *Terminal) Write(data []byte) error {
+	frame := append([]byte(fmt.Sprintf("0:%d:", len(data))), data...)
+	return t.conn.WriteMessage(websocket.BinaryMessage, frame)
+}
+
+// Read returns the next output of the terminal.
+func (t *Terminal) Read() ([]byte, error) {
+	_, msg, err := t.conn.ReadMessage()
+	return msg, err
+}
+
+// Close closes the terminal session.
+func (t *Terminal) Close() error {
+	_ = t.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return t.conn.Close()
+}